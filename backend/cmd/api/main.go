@@ -2,20 +2,30 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/yukito/video-platform/internal/database"
 	"github.com/yukito/video-platform/internal/handler"
+	"github.com/yukito/video-platform/internal/mail"
+	"github.com/yukito/video-platform/internal/media"
 	"github.com/yukito/video-platform/internal/middleware"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/oidc"
 	"github.com/yukito/video-platform/internal/repository"
 	"github.com/yukito/video-platform/internal/service"
 	"github.com/yukito/video-platform/internal/storage"
+	"github.com/yukito/video-platform/internal/transcode"
 )
 
 func main() {
@@ -40,8 +50,20 @@ func main() {
 		defaultBannerURL = "" // Empty string means no default banner
 	}
 
+	// Base URL of the frontend, used for links in emails
+	appURL := os.Getenv("APP_URL")
+	if appURL == "" {
+		appURL = "http://localhost:3000"
+	}
+
+	// Name shown for accounts in authenticator apps
+	totpIssuer := os.Getenv("TOTP_ISSUER")
+	if totpIssuer == "" {
+		totpIssuer = "Video Platform"
+	}
+
 	// Storage configuration
-	storageType := os.Getenv("STORAGE_TYPE") // "minio" or "gcs"
+	storageType := os.Getenv("STORAGE_TYPE") // "minio", "gcs" or "local"
 	if storageType == "" {
 		storageType = "minio" // Default to MinIO for local development
 	}
@@ -53,10 +75,20 @@ func main() {
 	}
 	defer db.Close()
 
+	// Run migrations before anything touches the schema
+	// Set AUTO_MIGRATE=false when migrations are applied separately with cmd/migrate
+	if os.Getenv("AUTO_MIGRATE") != "false" {
+		if err := db.RunMigrations(context.Background()); err != nil {
+			log.Fatalf("Failed to run migrations: %v", err)
+		}
+	}
+
 	// Initialize storage based on type
 	var fileStorage storage.Storage
+	var localStorage *storage.LocalStorage
 
-	if storageType == "gcs" {
+	switch storageType {
+	case "gcs":
 		// GCP Cloud Storage
 		gcpProjectID := os.Getenv("GCP_PROJECT_ID")
 		gcpBucketName := os.Getenv("GCP_BUCKET_NAME")
@@ -69,7 +101,24 @@ func main() {
 		defer gcsStorage.Close()
 		fileStorage = gcsStorage
 		log.Printf("Using GCP Cloud Storage (bucket: %s)", gcpBucketName)
-	} else {
+	case "local":
+		// Local filesystem (offline development and tests)
+		localStorageDir := os.Getenv("LOCAL_STORAGE_DIR")
+		if localStorageDir == "" {
+			localStorageDir = "./uploads"
+		}
+		localStorageBaseURL := os.Getenv("LOCAL_STORAGE_BASE_URL")
+		if localStorageBaseURL == "" {
+			localStorageBaseURL = "http://localhost:" + port
+		}
+
+		localStorage, err = storage.NewLocalStorage(localStorageDir, localStorageBaseURL)
+		if err != nil {
+			log.Fatalf("Failed to initialize local storage: %v", err)
+		}
+		fileStorage = localStorage
+		log.Printf("Using local storage (directory: %s)", localStorage.Dir())
+	default:
 		// MinIO (default for local development)
 		minioEndpoint := os.Getenv("MINIO_ENDPOINT")
 		minioPublicEndpoint := os.Getenv("MINIO_PUBLIC_ENDPOINT")
@@ -97,31 +146,167 @@ func main() {
 	subscriptionRepo := repository.NewSubscriptionRepository(db)
 	commentRepo := repository.NewCommentRepository(db)
 	watchHistoryRepo := repository.NewWatchHistoryRepository(db)
+	uploadRepo := repository.NewUploadRepository(db)
+	transcodeRepo := repository.NewTranscodeRepository(db)
+	thumbnailRepo := repository.NewThumbnailRepository(db)
+	searchRepo := repository.NewSearchRepository(db)
+	suggestionRepo := repository.NewSuggestionRepository(db)
+	reactionRepo := repository.NewVideoReactionRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	userTokenRepo := repository.NewUserTokenRepository(db)
+	userIdentityRepo := repository.NewUserIdentityRepository(db)
+	oidcStateRepo := repository.NewOIDCStateRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	adminAuditRepo := repository.NewAdminAuditRepository(db)
+
+	// Send email through SMTP when configured; otherwise print it for local development
+	var mailer mail.Mailer
+	if smtpHost := os.Getenv("SMTP_HOST"); smtpHost != "" {
+		smtpMailer, err := mail.NewSMTPMailer(smtpHost, os.Getenv("SMTP_PORT"), os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), os.Getenv("MAIL_FROM"))
+		if err != nil {
+			log.Fatalf("Failed to configure SMTP: %v", err)
+		}
+		mailer = smtpMailer
+		log.Printf("Sending email through SMTP (%s)", smtpHost)
+	} else {
+		mailer = mail.NewLogMailer(os.Stdout)
+		log.Printf("SMTP_HOST not set; emails are printed to stdout")
+	}
+
+	// Probe uploaded files for their duration and format
+	// Without ffprobe, uploads are stored unchecked and their metadata stays empty
+	var prober media.Prober
+	ffprobe := media.NewFFProbe(os.Getenv("FFPROBE_PATH"))
+	if err := ffprobe.Available(); err != nil {
+		log.Printf("Media probing disabled: %v", err)
+	} else {
+		prober = ffprobe
+	}
 
 	// Initialize services with the storage interface
-	authService := service.NewAuthService(userRepo, profileRepo, playlistRepo, jwtSecret, defaultIconURL, defaultBannerURL)
+	twoFactorService := service.NewTwoFactorService(userRepo, recoveryCodeRepo, totpIssuer)
+	loginThrottle := service.NewLoginThrottle(loginAttemptRepo)
+	authService := service.NewAuthService(userRepo, profileRepo, playlistRepo, sessionRepo, userTokenRepo, twoFactorService, loginThrottle, mailer, jwtSecret, appURL, defaultIconURL, defaultBannerURL)
+	oidcService := service.NewOIDCService(oidcProviders(appURL), oidcStateRepo, userIdentityRepo, userRepo, authService)
 	profileService := service.NewProfileService(profileRepo, fileStorage)
-	videoService := service.NewVideoService(videoRepo, profileRepo, fileStorage)
+	videoService := service.NewVideoService(videoRepo, profileRepo, transcodeRepo, thumbnailRepo, watchHistoryRepo, prober, fileStorage)
 	playlistService := service.NewPlaylistService(playlistRepo, videoRepo, profileRepo)
-	subscriptionService := service.NewSubscriptionService(subscriptionRepo, userRepo, videoRepo)
+	subscriptionService := service.NewSubscriptionService(subscriptionRepo, userRepo)
 	commentService := service.NewCommentService(commentRepo, videoRepo)
 	watchHistoryService := service.NewWatchHistoryService(watchHistoryRepo, videoRepo)
+	uploadService := service.NewUploadService(uploadRepo, videoRepo, transcodeRepo, prober, fileStorage)
+	searchService := service.NewSearchService(searchRepo, suggestionRepo)
+	suggestionService := service.NewSuggestionService(suggestionRepo)
+	reactionService := service.NewVideoReactionService(reactionRepo, videoRepo)
+	viewService := service.NewViewService(videoRepo)
+	adminService := service.NewAdminService(userRepo, videoRepo, commentRepo, thumbnailRepo, transcodeRepo, adminAuditRepo, fileStorage)
+
+	// Transcode uploads into HLS renditions in the background
+	// Videos stay processing while their jobs are queued, so with TRANSCODE_ENABLED=false
+	// another instance has to run the worker
+	if os.Getenv("TRANSCODE_ENABLED") != "false" {
+		var transcoder transcode.Transcoder
+		ffmpeg := transcode.NewFFmpegTranscoder(os.Getenv("FFMPEG_PATH"), os.Getenv("FFPROBE_PATH"))
+		if err := ffmpeg.Available(); err != nil {
+			log.Printf("Transcoding disabled, videos are published without HLS renditions: %v", err)
+		} else {
+			transcoder = ffmpeg
+			log.Printf("Transcoding enabled")
+		}
+
+		// Thumbnail candidates are taken from frames of the upload
+		var frames media.FrameExtractor
+		frameExtractor := media.NewFFmpegFrameExtractor(os.Getenv("FFMPEG_PATH"))
+		if err := frameExtractor.Available(); err != nil {
+			log.Printf("Thumbnail generation disabled: %v", err)
+		} else {
+			frames = frameExtractor
+		}
+
+		transcodeService := service.NewTranscodeService(transcodeRepo, videoRepo, thumbnailRepo, fileStorage, transcoder, prober, frames, os.Getenv("TRANSCODE_WORK_DIR"))
+		go transcodeService.Run(context.Background())
+	}
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
+	oidcHandler := handler.NewOIDCHandler(oidcService)
+	twoFactorHandler := handler.NewTwoFactorHandler(twoFactorService)
 	profileHandler := handler.NewProfileHandler(profileService)
 	videoHandler := handler.NewVideoHandler(videoService)
 	playlistHandler := handler.NewPlaylistHandler(playlistService)
 	subscriptionHandler := handler.NewSubscriptionHandler(subscriptionService)
 	commentHandler := handler.NewCommentHandler(commentService)
 	watchHistoryHandler := handler.NewWatchHistoryHandler(watchHistoryService)
+	uploadHandler := handler.NewUploadHandler(uploadService)
+	searchHandler := handler.NewSearchHandler(searchService)
+	suggestionHandler := handler.NewSuggestionHandler(suggestionService)
+	reactionHandler := handler.NewVideoReactionHandler(reactionService)
+	viewHandler := handler.NewViewHandler(viewService)
+	adminHandler := handler.NewAdminHandler(adminService)
+
+	// Write counted views to the database in batches; stopping it flushes the last batch,
+	// so it is stopped only after the server has finished its requests
+	viewsCtx, stopViews := context.WithCancel(context.Background())
+	viewsFlushed := make(chan struct{})
+	go func() {
+		viewService.Run(viewsCtx)
+		close(viewsFlushed)
+	}()
+
+	// Remove abandoned resumable uploads and their stored parts
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			removed, err := uploadService.CleanupExpired(context.Background())
+			if err != nil {
+				log.Printf("Failed to clean up expired uploads: %v", err)
+			} else if removed > 0 {
+				log.Printf("Removed %d expired uploads", removed)
+			}
+		}
+	}()
+
+	// Publish scheduled videos once their publish_at has passed
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			published, err := videoService.PublishScheduled(context.Background())
+			if err != nil {
+				log.Printf("Failed to publish scheduled videos: %v", err)
+			} else if published > 0 {
+				log.Printf("Published %d scheduled videos", published)
+			}
+		}
+	}()
+
+	// Refresh the popular search queries used for suggestions
+	go func() {
+		ticker := time.NewTicker(15 * time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			count, err := suggestionService.RecomputePopularQueries(context.Background())
+			if err != nil {
+				log.Printf("Failed to recompute popular search queries: %v", err)
+			} else {
+				log.Printf("Recomputed %d popular search queries", count)
+			}
+		}
+	}()
 
 	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(jwtSecret)
+	authMiddleware := middleware.NewAuthMiddleware(authService)
 
 	// Setup router
 	r := gin.Default()
 
+	// Reverse proxies allowed to report the client IP (comma-separated IPs or CIDRs, none by default)
+	if err := middleware.TrustProxies(r, os.Getenv("TRUSTED_PROXIES")); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	// Get allowed origins from environment variable
 	allowedOrigins := []string{"http://localhost:3000"}
 	if originsEnv := os.Getenv("ALLOWED_ORIGINS"); originsEnv != "" {
@@ -135,9 +320,9 @@ func main() {
 	// CORS middleware
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     allowedOrigins,
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Upload-Offset"},
+		ExposeHeaders:    []string{"Content-Length", "Location", "Upload-Offset", "Upload-Length"},
 		AllowCredentials: true,
 	}))
 
@@ -146,6 +331,11 @@ func main() {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	// Serve published files when using local storage (supports Range requests); upload parts are not served
+	if localStorage != nil {
+		r.StaticFS(storage.LocalFilesRoute, localStorage.PublishedFiles())
+	}
+
 	// API routes
 	api := r.Group("/api")
 	{
@@ -154,7 +344,28 @@ func main() {
 		{
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
-			auth.POST("/logout", authHandler.Logout)
+			auth.POST("/login/mfa", authHandler.VerifyMFA)
+			auth.POST("/refresh", authHandler.Refresh)
+			auth.POST("/logout", authMiddleware.OptionalAuth(), authHandler.Logout)
+			auth.GET("/sessions", authMiddleware.RequireAuth(), authHandler.ListSessions)
+			auth.DELETE("/sessions", authMiddleware.RequireAuth(), authHandler.RevokeOtherSessions)
+			auth.DELETE("/sessions/:id", authMiddleware.RequireAuth(), authHandler.RevokeSession)
+			auth.PUT("/password", authMiddleware.RequireAuth(), authHandler.ChangePassword)
+			auth.POST("/password/forgot", authHandler.ForgotPassword)
+			auth.POST("/password/reset", authHandler.ResetPassword)
+			auth.POST("/email/verify", authHandler.VerifyEmail)
+			auth.POST("/email/verification", authMiddleware.RequireAuth(), authHandler.ResendEmailVerification)
+			auth.GET("/oidc/providers", oidcHandler.ListProviders)
+			auth.POST("/oidc/:provider/authorize", oidcHandler.Authorize)
+			auth.POST("/oidc/callback", oidcHandler.Callback)
+			auth.GET("/identities", authMiddleware.RequireAuth(), oidcHandler.ListIdentities)
+			auth.POST("/identities", authMiddleware.RequireAuth(), oidcHandler.Link)
+			auth.POST("/identities/:provider/authorize", authMiddleware.RequireAuth(), oidcHandler.AuthorizeLink)
+			auth.GET("/2fa", authMiddleware.RequireAuth(), twoFactorHandler.Status)
+			auth.POST("/2fa/totp", authMiddleware.RequireAuth(), twoFactorHandler.BeginTOTPSetup)
+			auth.POST("/2fa/totp/confirm", authMiddleware.RequireAuth(), twoFactorHandler.ConfirmTOTP)
+			auth.POST("/2fa/disable", authMiddleware.RequireAuth(), twoFactorHandler.Disable)
+			auth.POST("/2fa/recovery-codes", authMiddleware.RequireAuth(), twoFactorHandler.RegenerateRecoveryCodes)
 		}
 
 		// Profile routes
@@ -172,21 +383,39 @@ func main() {
 		// Video routes
 		videos := api.Group("/videos")
 		{
-			// Public routes
-			videos.GET("", videoHandler.List)
-			videos.GET("/:id", videoHandler.GetByID)
+			// Public routes; owners also see their videos that are not ready yet
+			videos.GET("", authMiddleware.OptionalAuth(), videoHandler.List)
+			videos.GET("/search", authMiddleware.OptionalAuth(), searchHandler.SearchVideos)
+			videos.GET("/:id", authMiddleware.OptionalAuth(), videoHandler.GetByID)
+			videos.POST("/:id/views", authMiddleware.OptionalAuth(), viewHandler.RecordView)
 
 			// Protected routes
 			videos.Use(authMiddleware.RequireAuth())
 			videos.POST("", videoHandler.Create)
 			videos.PUT("/:id", videoHandler.Update)
 			videos.DELETE("/:id", videoHandler.Delete)
+			videos.GET("/:id/status", videoHandler.GetStatus)
+			videos.GET("/:id/thumbnails", videoHandler.ListThumbnails)
+			videos.PUT("/:id/thumbnail", videoHandler.SetThumbnail)
 
 			// Like routes
-			videos.POST("/:id/like", playlistHandler.LikeVideo)
-			videos.DELETE("/:id/like", playlistHandler.UnlikeVideo)
-			videos.GET("/:id/like", playlistHandler.GetLikeStatus)
-			videos.GET("/liked", playlistHandler.GetLikedVideos)
+			videos.POST("/:id/like", reactionHandler.LikeVideo)
+			videos.POST("/:id/dislike", reactionHandler.DislikeVideo)
+			videos.DELETE("/:id/like", reactionHandler.RemoveReaction)
+			videos.GET("/:id/like", reactionHandler.GetLikeStatus)
+			videos.GET("/liked", reactionHandler.GetLikedVideos)
+		}
+
+		// Resumable upload routes
+		uploads := api.Group("/uploads")
+		{
+			uploads.Use(authMiddleware.RequireAuth())
+			uploads.POST("", uploadHandler.Create)
+			uploads.HEAD("/:id", uploadHandler.Head)
+			uploads.GET("/:id", uploadHandler.Get)
+			uploads.PATCH("/:id", uploadHandler.Patch)
+			uploads.POST("/:id/complete", uploadHandler.Complete)
+			uploads.DELETE("/:id", uploadHandler.Abort)
 		}
 
 		// Playlist routes
@@ -194,7 +423,7 @@ func main() {
 		{
 			// Public routes
 			playlists.GET("/:id", playlistHandler.GetByID)
-			playlists.GET("/:id/videos", playlistHandler.GetPlaylistVideos)
+			playlists.GET("/:id/videos", authMiddleware.OptionalAuth(), playlistHandler.GetPlaylistVideos)
 
 			// Protected routes
 			playlists.Use(authMiddleware.RequireAuth())
@@ -240,7 +469,7 @@ func main() {
 			// Public routes with optional auth for like status
 			comments.GET("/videos/:video_id", authMiddleware.OptionalAuth(), commentHandler.GetCommentsByVideoID)
 			comments.GET("/:parent_comment_id/replies", authMiddleware.OptionalAuth(), commentHandler.GetRepliesByParentID)
-			comments.GET("/videos/:video_id/count", commentHandler.GetCommentCount)
+			comments.GET("/videos/:video_id/count", authMiddleware.OptionalAuth(), commentHandler.GetCommentCount)
 
 			// Protected routes
 			comments.Use(authMiddleware.RequireAuth())
@@ -253,27 +482,93 @@ func main() {
 			comments.DELETE("/:id/like", commentHandler.UnlikeComment)
 		}
 
+		// Search suggestion routes
+		search := api.Group("/search")
+		{
+			search.GET("/suggest", authMiddleware.OptionalAuth(), suggestionHandler.Suggest)
+			search.GET("/history", authMiddleware.RequireAuth(), suggestionHandler.GetRecentQueries)
+			search.DELETE("/history", authMiddleware.RequireAuth(), suggestionHandler.ClearRecentQueries)
+		}
+
 		// Watch History routes
 		history := api.Group("/history")
 		{
 			history.Use(authMiddleware.RequireAuth())
 			history.GET("", watchHistoryHandler.GetWatchHistory)
 			history.GET("/count", watchHistoryHandler.GetHistoryCount)
+			history.GET("/continue", watchHistoryHandler.GetContinueWatching)
 			history.DELETE("", watchHistoryHandler.ClearHistory)
 			history.DELETE("/:video_id", watchHistoryHandler.RemoveFromHistory)
 		}
 
-		// Add to history route (under videos)
+		// Watch history and playback progress routes (under videos)
 		videos.POST("/:id/history", watchHistoryHandler.AddToHistory)
+		videos.POST("/:id/progress", watchHistoryHandler.SaveProgress)
+
+		// Admin routes: moderators manage users and content, admins also ban, change roles
+		// and see stats and the audit log
+		admin := api.Group("/admin")
+		{
+			admin.Use(authMiddleware.RequireAuth(), authMiddleware.RequireRole(model.RoleModerator))
+			admin.GET("/users", adminHandler.SearchUsers)
+			admin.POST("/users/:id/suspend", adminHandler.SuspendUser)
+			admin.POST("/users/:id/unban", adminHandler.UnbanUser)
+			admin.POST("/users/:id/ban", authMiddleware.RequireRole(model.RoleAdmin), adminHandler.BanUser)
+			admin.PUT("/users/:id/role", authMiddleware.RequireRole(model.RoleAdmin), adminHandler.SetRole)
+			admin.POST("/videos/:id/remove", adminHandler.RemoveVideo)
+			admin.POST("/comments/:id/remove", adminHandler.RemoveComment)
+			admin.GET("/stats", authMiddleware.RequireRole(model.RoleAdmin), adminHandler.Stats)
+			admin.GET("/audit-log", authMiddleware.RequireRole(model.RoleAdmin), adminHandler.AuditLog)
+		}
 	}
 
-	// Run migrations
-	if err := db.RunMigrations(context.Background()); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+	// Shut down gracefully on SIGINT or SIGTERM, e.g. when the container is stopped
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{Addr: ":" + port, Handler: r}
+	go func() {
+		log.Printf("Server starting on port %s", port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Printf("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Failed to shut down server: %v", err)
 	}
+	stopViews()
+	<-viewsFlushed
+}
 
-	log.Printf("Server starting on port %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+// oidcProviders configures the OpenID Connect providers named in OIDC_PROVIDERS (comma-separated)
+// Each provider <NAME> reads OIDC_<NAME>_ISSUER, OIDC_<NAME>_CLIENT_ID, OIDC_<NAME>_CLIENT_SECRET
+// and optionally OIDC_<NAME>_SCOPES; providers redirect back to the frontend's /oauth/callback
+func oidcProviders(appURL string) []*oidc.Provider {
+	var providers []*oidc.Provider
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		provider, err := oidc.NewProvider(oidc.Config{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  strings.TrimRight(appURL, "/") + "/oauth/callback",
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}, nil)
+		if err != nil {
+			log.Fatalf("Failed to configure OIDC provider: %v", err)
+		}
+		providers = append(providers, provider)
+		log.Printf("OIDC sign-in enabled for %s", name)
 	}
+	return providers
 }
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/joho/godotenv"
+	"github.com/yukito/video-platform/internal/database"
+)
+
+const usage = `Usage: migrate [-dir path] <command> [args]
+
+Commands:
+  up [N]        apply all pending migrations, or the next N
+  down [N]      roll back the latest migration, or the latest N
+  status        list migrations and whether they are applied
+  create NAME   write an empty up/down migration pair to -dir
+`
+
+func main() {
+	// Load .env file
+	_ = godotenv.Load()
+
+	dir := flag.String("dir", database.MigrationsDir, "migrations directory (used by create)")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	command := args[0]
+
+	// create only touches files, no database needed
+	if command == "create" {
+		if len(args) < 2 {
+			log.Fatal("create requires a migration name")
+		}
+		upPath, downPath, err := database.CreateMigration(*dir, args[1])
+		if err != nil {
+			log.Fatalf("Failed to create migration: %v", err)
+		}
+		fmt.Printf("Created %s\nCreated %s\n", upPath, downPath)
+		return
+	}
+
+	steps := 0
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			log.Fatalf("Invalid step count: %s", args[1])
+		}
+		steps = n
+	}
+
+	db, err := database.NewDatabase(os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		applied, err := db.MigrateUp(ctx, steps)
+		for _, m := range applied {
+			fmt.Printf("Applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("No pending migrations")
+		}
+	case "down":
+		reverted, err := db.MigrateDown(ctx, steps)
+		for _, m := range reverted {
+			fmt.Printf("Reverted %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Rollback failed: %v", err)
+		}
+		if len(reverted) == 0 {
+			fmt.Println("No applied migrations")
+		}
+	case "status":
+		statuses, err := db.MigrationStatus(ctx)
+		if err != nil {
+			log.Fatalf("Failed to get migration status: %v", err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40s %s\n", s.Version, s.Name, state)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
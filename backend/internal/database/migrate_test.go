@@ -0,0 +1,106 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestEmbeddedMigrationsAreValid(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatalf("embedded migrations failed to load: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("expected at least one embedded migration")
+	}
+
+	for i, m := range migrations {
+		if m.Version != int64(i+1) {
+			t.Errorf("expected contiguous versions, migration %d has version %d", i+1, m.Version)
+		}
+	}
+}
+
+func TestLoadMigrations_SortsAndPairs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_status.up.sql":   {Data: []byte("ALTER TABLE videos ADD COLUMN status TEXT;")},
+		"0002_add_status.down.sql": {Data: []byte("ALTER TABLE videos DROP COLUMN status;")},
+		"0001_init.up.sql":         {Data: []byte("CREATE TABLE t (id INT);")},
+		"0001_init.down.sql":       {Data: []byte("DROP TABLE t;")},
+	}
+
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("LoadMigrations returned error: %v", err)
+	}
+
+	if len(migrations) != 2 {
+		t.Fatalf("expected 2 migrations, got %d", len(migrations))
+	}
+	if migrations[0].Version != 1 || migrations[0].Name != "init" {
+		t.Errorf("unexpected first migration: %+v", migrations[0])
+	}
+	if migrations[1].Version != 2 || !strings.Contains(migrations[1].DownSQL, "DROP COLUMN status") {
+		t.Errorf("unexpected second migration: %+v", migrations[1])
+	}
+}
+
+func TestLoadMigrations_RejectsInvalidSets(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"missing down": {
+			"0001_init.up.sql": {Data: []byte("CREATE TABLE t (id INT);")},
+		},
+		"missing up": {
+			"0001_init.down.sql": {Data: []byte("DROP TABLE t;")},
+		},
+		"duplicate version": {
+			"0001_init.up.sql":    {Data: []byte("SELECT 1;")},
+			"0001_init.down.sql":  {Data: []byte("SELECT 1;")},
+			"0001_other.up.sql":   {Data: []byte("SELECT 1;")},
+			"0001_other.down.sql": {Data: []byte("SELECT 1;")},
+		},
+		"bad file name": {
+			"init.sql": {Data: []byte("SELECT 1;")},
+		},
+	}
+
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadMigrations(fsys); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestCreateMigration_UsesNextVersion(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"0001_init.up.sql":   "SELECT 1;",
+		"0001_init.down.sql": "SELECT 1;",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	upPath, downPath, err := CreateMigration(dir, "Add Video Status")
+	if err != nil {
+		t.Fatalf("CreateMigration returned error: %v", err)
+	}
+
+	if filepath.Base(upPath) != "0002_add_video_status.up.sql" {
+		t.Errorf("unexpected up file: %s", upPath)
+	}
+	if filepath.Base(downPath) != "0002_add_video_status.down.sql" {
+		t.Errorf("unexpected down file: %s", downPath)
+	}
+
+	migrations, err := LoadMigrations(os.DirFS(dir))
+	if err != nil || len(migrations) != 2 {
+		t.Fatalf("expected created migration to load, got %d migrations, err %v", len(migrations), err)
+	}
+}
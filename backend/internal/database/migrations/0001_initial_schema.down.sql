@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS watch_history;
+DROP TABLE IF EXISTS comment_likes;
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS subscriptions;
+DROP TABLE IF EXISTS playlist_videos;
+DROP TABLE IF EXISTS playlists;
+DROP TABLE IF EXISTS profiles;
+DROP TABLE IF EXISTS videos;
+DROP TABLE IF EXISTS users;
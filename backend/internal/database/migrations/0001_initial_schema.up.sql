@@ -0,0 +1,115 @@
+-- Initial schema
+-- Uses IF NOT EXISTS so databases created by the old startup migrations can adopt versioning
+
+CREATE TABLE IF NOT EXISTS users (
+	id BIGSERIAL PRIMARY KEY,
+	email VARCHAR(255) UNIQUE NOT NULL,
+	password_hash VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS videos (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	title VARCHAR(255) NOT NULL,
+	description TEXT,
+	video_url VARCHAR(500),
+	thumbnail_url VARCHAR(500),
+	duration BIGINT NOT NULL DEFAULT 0,
+	view_count BIGINT NOT NULL DEFAULT 0,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Older databases were created before the duration column existed
+ALTER TABLE videos ADD COLUMN IF NOT EXISTS duration BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_videos_user_id ON videos(user_id);
+
+CREATE TABLE IF NOT EXISTS profiles (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+	channel_name VARCHAR(255) NOT NULL DEFAULT '',
+	description TEXT DEFAULT '',
+	icon_url VARCHAR(500) DEFAULT '',
+	banner_url VARCHAR(500) DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_profiles_user_id ON profiles(user_id);
+
+CREATE TABLE IF NOT EXISTS playlists (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	title VARCHAR(255) NOT NULL,
+	description TEXT DEFAULT '',
+	visibility VARCHAR(20) NOT NULL DEFAULT 'private',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_playlists_user_id ON playlists(user_id);
+
+CREATE TABLE IF NOT EXISTS playlist_videos (
+	id BIGSERIAL PRIMARY KEY,
+	playlist_id BIGINT NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+	video_id BIGINT NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+	position INT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE(playlist_id, video_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_playlist_videos_playlist_id ON playlist_videos(playlist_id);
+
+CREATE TABLE IF NOT EXISTS subscriptions (
+	id BIGSERIAL PRIMARY KEY,
+	subscriber_user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	subscribed_to_user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE(subscriber_user_id, subscribed_to_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_subscriber ON subscriptions(subscriber_user_id);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_subscribed_to ON subscriptions(subscribed_to_user_id);
+
+CREATE TABLE IF NOT EXISTS comments (
+	id BIGSERIAL PRIMARY KEY,
+	video_id BIGINT NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+	user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	parent_comment_id BIGINT REFERENCES comments(id) ON DELETE CASCADE,
+	content TEXT NOT NULL,
+	like_count BIGINT NOT NULL DEFAULT 0,
+	is_pinned BOOLEAN NOT NULL DEFAULT FALSE,
+	is_creator_liked BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_video_id ON comments(video_id);
+CREATE INDEX IF NOT EXISTS idx_comments_user_id ON comments(user_id);
+CREATE INDEX IF NOT EXISTS idx_comments_parent_comment_id ON comments(parent_comment_id);
+
+CREATE TABLE IF NOT EXISTS comment_likes (
+	id BIGSERIAL PRIMARY KEY,
+	comment_id BIGINT NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+	user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	like_type VARCHAR(10) NOT NULL CHECK (like_type IN ('like', 'dislike')),
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE(comment_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_likes_comment_id ON comment_likes(comment_id);
+CREATE INDEX IF NOT EXISTS idx_comment_likes_user_id ON comment_likes(user_id);
+
+CREATE TABLE IF NOT EXISTS watch_history (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	video_id BIGINT NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+	watched_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE(user_id, video_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_watch_history_user_id ON watch_history(user_id);
+CREATE INDEX IF NOT EXISTS idx_watch_history_watched_at ON watch_history(watched_at);
@@ -0,0 +1,32 @@
+-- Resumable uploads: a session per file, one row per stored chunk
+
+CREATE TABLE upload_sessions (
+	id VARCHAR(36) PRIMARY KEY,
+	user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	filename VARCHAR(255) NOT NULL,
+	content_type VARCHAR(255) NOT NULL DEFAULT '',
+	upload_length BIGINT NOT NULL CHECK (upload_length > 0),
+	upload_offset BIGINT NOT NULL DEFAULT 0,
+	part_count INT NOT NULL DEFAULT 0,
+	title VARCHAR(255) NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	status VARCHAR(20) NOT NULL DEFAULT 'uploading' CHECK (status IN ('uploading', 'completing', 'completed')),
+	video_id BIGINT REFERENCES videos(id) ON DELETE SET NULL,
+	expires_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	CHECK (upload_offset <= upload_length)
+);
+
+CREATE INDEX idx_upload_sessions_user_id ON upload_sessions(user_id);
+CREATE INDEX idx_upload_sessions_expires_at ON upload_sessions(expires_at);
+
+CREATE TABLE upload_parts (
+	upload_id VARCHAR(36) NOT NULL REFERENCES upload_sessions(id) ON DELETE CASCADE,
+	part_number INT NOT NULL,
+	part_offset BIGINT NOT NULL,
+	size BIGINT NOT NULL,
+	storage_key VARCHAR(500) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (upload_id, part_number)
+);
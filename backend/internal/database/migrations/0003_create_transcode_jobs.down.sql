@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS video_renditions;
+DROP TABLE IF EXISTS transcode_jobs;
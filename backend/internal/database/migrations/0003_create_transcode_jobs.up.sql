@@ -0,0 +1,30 @@
+-- Background transcoding into HLS renditions
+
+CREATE TABLE transcode_jobs (
+	id BIGSERIAL PRIMARY KEY,
+	video_id BIGINT NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+	source_url VARCHAR(500) NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+	attempts INT NOT NULL DEFAULT 0,
+	error TEXT NOT NULL DEFAULT '',
+	master_playlist_url VARCHAR(500) NOT NULL DEFAULT '',
+	started_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_transcode_jobs_video_id ON transcode_jobs(video_id);
+CREATE INDEX idx_transcode_jobs_status ON transcode_jobs(status, created_at);
+
+CREATE TABLE video_renditions (
+	id BIGSERIAL PRIMARY KEY,
+	video_id BIGINT NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+	name VARCHAR(20) NOT NULL,
+	width INT NOT NULL,
+	height INT NOT NULL,
+	bandwidth BIGINT NOT NULL,
+	playlist_url VARCHAR(500) NOT NULL,
+	segment_urls TEXT[] NOT NULL DEFAULT '{}',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE (video_id, name)
+);
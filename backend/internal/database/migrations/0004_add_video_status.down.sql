@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_videos_status_created_at;
+
+ALTER TABLE videos
+	DROP COLUMN IF EXISTS failure_reason,
+	DROP COLUMN IF EXISTS status;
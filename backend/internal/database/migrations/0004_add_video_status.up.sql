@@ -0,0 +1,10 @@
+-- Processing lifecycle of a video: uploading -> processing -> ready | failed
+-- Existing videos are already playable, so they start out ready
+
+ALTER TABLE videos
+	ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'ready' CHECK (status IN ('uploading', 'processing', 'ready', 'failed')),
+	ADD COLUMN failure_reason TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE videos ALTER COLUMN status SET DEFAULT 'processing';
+
+CREATE INDEX idx_videos_status_created_at ON videos(status, created_at DESC);
@@ -0,0 +1,10 @@
+ALTER TABLE upload_sessions ADD COLUMN duration BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE videos
+	DROP COLUMN IF EXISTS frame_rate,
+	DROP COLUMN IF EXISTS bitrate,
+	DROP COLUMN IF EXISTS height,
+	DROP COLUMN IF EXISTS width,
+	DROP COLUMN IF EXISTS audio_codec,
+	DROP COLUMN IF EXISTS video_codec,
+	DROP COLUMN IF EXISTS container;
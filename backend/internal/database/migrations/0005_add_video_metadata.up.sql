@@ -0,0 +1,12 @@
+-- Media metadata probed by the server; duration is no longer taken from the client
+
+ALTER TABLE videos
+	ADD COLUMN container VARCHAR(100) NOT NULL DEFAULT '',
+	ADD COLUMN video_codec VARCHAR(50) NOT NULL DEFAULT '',
+	ADD COLUMN audio_codec VARCHAR(50) NOT NULL DEFAULT '',
+	ADD COLUMN width INT NOT NULL DEFAULT 0,
+	ADD COLUMN height INT NOT NULL DEFAULT 0,
+	ADD COLUMN bitrate BIGINT NOT NULL DEFAULT 0,
+	ADD COLUMN frame_rate DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+ALTER TABLE upload_sessions DROP COLUMN duration;
@@ -0,0 +1,12 @@
+-- Thumbnail candidates; videos.thumbnail_url holds the selected one
+
+CREATE TABLE video_thumbnails (
+	id BIGSERIAL PRIMARY KEY,
+	video_id BIGINT NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+	url VARCHAR(500) NOT NULL,
+	source VARCHAR(20) NOT NULL CHECK (source IN ('generated', 'custom')),
+	time_offset DOUBLE PRECISION NOT NULL DEFAULT 0,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_video_thumbnails_video_id ON video_thumbnails(video_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_videos_publish_at;
+
+ALTER TABLE videos
+	DROP COLUMN IF EXISTS publish_at,
+	DROP COLUMN IF EXISTS visibility;
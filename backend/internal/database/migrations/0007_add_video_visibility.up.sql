@@ -0,0 +1,8 @@
+-- Who can see a video; existing videos stay public
+-- publish_at is set while a private video is scheduled to become public
+
+ALTER TABLE videos
+	ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'public' CHECK (visibility IN ('public', 'unlisted', 'private')),
+	ADD COLUMN publish_at TIMESTAMP;
+
+CREATE INDEX idx_videos_publish_at ON videos(publish_at) WHERE publish_at IS NOT NULL;
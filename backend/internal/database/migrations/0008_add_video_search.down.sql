@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_profiles_channel_name_trgm;
+DROP INDEX IF EXISTS idx_videos_description_trgm;
+DROP INDEX IF EXISTS idx_videos_title_trgm;
+DROP INDEX IF EXISTS idx_videos_search_vector;
+
+DROP TRIGGER IF EXISTS profiles_search_vector ON profiles;
+DROP FUNCTION IF EXISTS profiles_search_vector_trigger();
+DROP TRIGGER IF EXISTS videos_search_vector ON videos;
+DROP FUNCTION IF EXISTS videos_search_vector_trigger();
+
+ALTER TABLE videos DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS video_search_vector(TEXT, TEXT, TEXT);
+
+-- pg_trgm is left installed; other objects may depend on it
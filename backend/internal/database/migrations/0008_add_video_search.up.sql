@@ -0,0 +1,47 @@
+-- Full-text search over video titles, channel names and descriptions
+-- The 'simple' configuration does not stem, so it works the same for Japanese and English words;
+-- Japanese text has no spaces to split words on, so search falls back to trigram substring matches
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE FUNCTION video_search_vector(title TEXT, description TEXT, channel_name TEXT) RETURNS tsvector AS $$
+	SELECT setweight(to_tsvector('simple', coalesce(title, '')), 'A')
+		|| setweight(to_tsvector('simple', coalesce(channel_name, '')), 'B')
+		|| setweight(to_tsvector('simple', coalesce(description, '')), 'C')
+$$ LANGUAGE SQL IMMUTABLE;
+
+ALTER TABLE videos ADD COLUMN search_vector tsvector NOT NULL DEFAULT ''::tsvector;
+
+UPDATE videos v
+SET search_vector = video_search_vector(v.title, v.description, (SELECT p.channel_name FROM profiles p WHERE p.user_id = v.user_id));
+
+-- Keep search_vector current when a video or its channel name changes
+CREATE FUNCTION videos_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+	NEW.search_vector := video_search_vector(NEW.title, NEW.description,
+		(SELECT channel_name FROM profiles WHERE user_id = NEW.user_id));
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER videos_search_vector
+	BEFORE INSERT OR UPDATE OF title, description, user_id ON videos
+	FOR EACH ROW EXECUTE FUNCTION videos_search_vector_trigger();
+
+CREATE FUNCTION profiles_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+	UPDATE videos
+	SET search_vector = video_search_vector(title, description, NEW.channel_name)
+	WHERE user_id = NEW.user_id;
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER profiles_search_vector
+	AFTER INSERT OR UPDATE OF channel_name ON profiles
+	FOR EACH ROW EXECUTE FUNCTION profiles_search_vector_trigger();
+
+CREATE INDEX idx_videos_search_vector ON videos USING GIN (search_vector);
+CREATE INDEX idx_videos_title_trgm ON videos USING GIN (title gin_trgm_ops);
+CREATE INDEX idx_videos_description_trgm ON videos USING GIN (description gin_trgm_ops);
+CREATE INDEX idx_profiles_channel_name_trgm ON profiles USING GIN (channel_name gin_trgm_ops);
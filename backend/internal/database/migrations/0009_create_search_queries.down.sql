@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_profiles_channel_name_prefix;
+DROP INDEX IF EXISTS idx_videos_title_prefix;
+
+DROP TABLE IF EXISTS popular_search_queries;
+DROP TABLE IF EXISTS user_search_history;
+DROP TABLE IF EXISTS search_queries;
@@ -0,0 +1,32 @@
+-- Every search, normalized; the popular query job aggregates the recent ones
+CREATE TABLE search_queries (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+	query VARCHAR(200) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_search_queries_created_at ON search_queries(created_at);
+
+-- Recent searches of each user, suggested back to them until cleared
+CREATE TABLE user_search_history (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	query VARCHAR(200) NOT NULL,
+	searched_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE(user_id, query)
+);
+
+CREATE INDEX idx_user_search_history_user_id_searched_at ON user_search_history(user_id, searched_at DESC);
+
+-- Rebuilt by the popular query job
+CREATE TABLE popular_search_queries (
+	query VARCHAR(200) PRIMARY KEY,
+	search_count BIGINT NOT NULL,
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Prefix matches for suggestions
+CREATE INDEX idx_popular_search_queries_prefix ON popular_search_queries(query text_pattern_ops);
+CREATE INDEX idx_videos_title_prefix ON videos(lower(title) text_pattern_ops);
+CREATE INDEX idx_profiles_channel_name_prefix ON profiles(lower(channel_name) text_pattern_ops);
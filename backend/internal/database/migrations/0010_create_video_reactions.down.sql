@@ -0,0 +1,20 @@
+-- Put likes back into a "高く評価した動画" playlist per user; dislikes are dropped
+INSERT INTO playlists (user_id, title, description, visibility)
+SELECT u.id, '高く評価した動画', 'いいねした動画が自動的に保存されます', 'private'
+FROM users u;
+
+INSERT INTO playlist_videos (playlist_id, video_id, position, created_at)
+SELECT p.id, r.video_id,
+	ROW_NUMBER() OVER (PARTITION BY r.user_id ORDER BY r.created_at) - 1,
+	r.created_at
+FROM video_reactions r
+JOIN playlists p ON p.id = (
+	SELECT MAX(id) FROM playlists WHERE user_id = r.user_id AND title = '高く評価した動画'
+)
+WHERE r.like_type = 'like';
+
+ALTER TABLE videos
+	DROP COLUMN IF EXISTS dislike_count,
+	DROP COLUMN IF EXISTS like_count;
+
+DROP TABLE IF EXISTS video_reactions;
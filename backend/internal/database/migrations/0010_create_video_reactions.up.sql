@@ -0,0 +1,46 @@
+-- Likes and dislikes on videos, one per user like comment_likes
+-- like_count and dislike_count are kept in step with video_reactions by the application
+
+CREATE TABLE IF NOT EXISTS video_reactions (
+	id BIGSERIAL PRIMARY KEY,
+	video_id BIGINT NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+	user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	like_type VARCHAR(10) NOT NULL CHECK (like_type IN ('like', 'dislike')),
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE(video_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_video_reactions_video_id ON video_reactions(video_id);
+CREATE INDEX IF NOT EXISTS idx_video_reactions_user_id ON video_reactions(user_id, like_type, created_at DESC);
+
+ALTER TABLE videos
+	ADD COLUMN like_count BIGINT NOT NULL DEFAULT 0,
+	ADD COLUMN dislike_count BIGINT NOT NULL DEFAULT 0;
+
+-- Likes used to be stored in each user's first "高く評価した動画" playlist, created at registration
+-- Other playlists with that title were made by users and are kept as they are
+CREATE TEMPORARY TABLE liked_playlists ON COMMIT DROP AS
+SELECT MIN(id) AS id
+FROM playlists
+WHERE title = '高く評価した動画'
+GROUP BY user_id;
+
+INSERT INTO video_reactions (video_id, user_id, like_type, created_at)
+SELECT pv.video_id, p.user_id, 'like', pv.created_at
+FROM playlist_videos pv
+JOIN playlists p ON p.id = pv.playlist_id
+WHERE p.id IN (SELECT id FROM liked_playlists)
+ON CONFLICT (video_id, user_id) DO NOTHING;
+
+DELETE FROM playlists WHERE id IN (SELECT id FROM liked_playlists);
+
+UPDATE videos v
+SET like_count = r.like_count, dislike_count = r.dislike_count
+FROM (
+	SELECT video_id,
+		COUNT(*) FILTER (WHERE like_type = 'like') AS like_count,
+		COUNT(*) FILTER (WHERE like_type = 'dislike') AS dislike_count
+	FROM video_reactions
+	GROUP BY video_id
+) r
+WHERE v.id = r.video_id;
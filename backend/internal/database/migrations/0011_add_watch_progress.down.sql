@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_watch_history_user_id_watched_at;
+
+ALTER TABLE watch_history
+	DROP COLUMN IF EXISTS progress_percent,
+	DROP COLUMN IF EXISTS position_seconds;
@@ -0,0 +1,7 @@
+-- Playback position and how much of the video has been watched, updated by player heartbeats
+ALTER TABLE watch_history
+	ADD COLUMN position_seconds DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN progress_percent DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+-- Continue watching lists the user's partially watched videos, most recently watched first
+CREATE INDEX idx_watch_history_user_id_watched_at ON watch_history(user_id, watched_at DESC, id DESC);
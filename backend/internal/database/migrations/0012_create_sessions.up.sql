@@ -0,0 +1,16 @@
+-- Sign-in sessions; each session is one refresh token family and stores the hash
+-- of its current refresh token, which is replaced on every refresh
+CREATE TABLE sessions (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	family VARCHAR(36) NOT NULL UNIQUE,
+	refresh_token_hash VARCHAR(64) NOT NULL,
+	user_agent TEXT NOT NULL DEFAULT '',
+	ip_address VARCHAR(45) NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	last_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	expires_at TIMESTAMP NOT NULL,
+	revoked_at TIMESTAMP
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
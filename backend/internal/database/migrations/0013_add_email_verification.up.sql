@@ -0,0 +1,15 @@
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+
+-- Single-use tokens sent by email for password resets and email verification;
+-- only the hash of each token is stored
+CREATE TABLE user_tokens (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	purpose VARCHAR(32) NOT NULL CHECK (purpose IN ('password_reset', 'email_verification')),
+	token_hash VARCHAR(64) NOT NULL UNIQUE,
+	expires_at TIMESTAMP NOT NULL,
+	used_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_tokens_user_id_purpose ON user_tokens(user_id, purpose);
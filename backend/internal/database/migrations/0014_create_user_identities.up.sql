@@ -0,0 +1,24 @@
+-- Accounts at OpenID Connect providers linked to users; a user can have several
+CREATE TABLE user_identities (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	provider VARCHAR(64) NOT NULL,
+	subject VARCHAR(255) NOT NULL,
+	email VARCHAR(255) NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	last_login_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE (provider, subject)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+
+-- Sign-ins started at a provider and not finished yet; the state is stored hashed
+-- together with the nonce and PKCE verifier checked when the user comes back
+CREATE TABLE oidc_login_states (
+	state_hash VARCHAR(64) PRIMARY KEY,
+	provider VARCHAR(64) NOT NULL,
+	nonce VARCHAR(128) NOT NULL,
+	code_verifier VARCHAR(128) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	expires_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,11 @@
+DELETE FROM user_tokens WHERE purpose = 'mfa_challenge';
+ALTER TABLE user_tokens DROP COLUMN IF EXISTS attempts;
+ALTER TABLE user_tokens DROP CONSTRAINT IF EXISTS user_tokens_purpose_check;
+ALTER TABLE user_tokens ADD CONSTRAINT user_tokens_purpose_check
+	CHECK (purpose IN ('password_reset', 'email_verification'));
+
+DROP TABLE IF EXISTS recovery_codes;
+
+ALTER TABLE users DROP COLUMN IF EXISTS totp_last_step;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_enabled_at;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_secret;
@@ -0,0 +1,23 @@
+-- TOTP two-factor authentication; totp_secret is set when enrollment starts and
+-- totp_enabled_at once the user confirms a code from their authenticator app.
+-- totp_last_step is the period of the last accepted code, so a code works once
+ALTER TABLE users ADD COLUMN totp_secret VARCHAR(64);
+ALTER TABLE users ADD COLUMN totp_enabled_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN totp_last_step BIGINT;
+
+-- One-time recovery codes for signing in without the authenticator app; only hashes are stored
+CREATE TABLE recovery_codes (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	code_hash VARCHAR(64) NOT NULL,
+	used_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE (user_id, code_hash)
+);
+
+-- Login challenges waiting for the second factor are user tokens too; attempts
+-- counts wrong codes so a challenge cannot be used to guess codes indefinitely
+ALTER TABLE user_tokens DROP CONSTRAINT user_tokens_purpose_check;
+ALTER TABLE user_tokens ADD CONSTRAINT user_tokens_purpose_check
+	CHECK (purpose IN ('password_reset', 'email_verification', 'mfa_challenge'));
+ALTER TABLE user_tokens ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
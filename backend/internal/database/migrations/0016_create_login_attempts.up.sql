@@ -0,0 +1,16 @@
+-- Login attempts, successful and failed, kept as an audit record; recent failures per
+-- email address and per IP address decide how long the next login has to wait.
+-- email is the address as entered, lowercased, so unknown addresses are throttled too
+CREATE TABLE login_attempts (
+	id BIGSERIAL PRIMARY KEY,
+	email VARCHAR(255) NOT NULL,
+	user_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+	ip_address VARCHAR(45) NOT NULL DEFAULT '',
+	user_agent TEXT NOT NULL DEFAULT '',
+	succeeded BOOLEAN NOT NULL,
+	failure_reason VARCHAR(32) NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_login_attempts_email_created_at ON login_attempts(email, created_at);
+CREATE INDEX idx_login_attempts_ip_address_created_at ON login_attempts(ip_address, created_at);
@@ -0,0 +1,4 @@
+-- Roles grant access beyond the user's own content; moderators can remove anyone's
+-- videos and comments and admins can also manage users
+ALTER TABLE users ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'user'
+	CHECK (role IN ('user', 'moderator', 'admin'));
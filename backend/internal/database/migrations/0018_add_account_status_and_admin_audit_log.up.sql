@@ -0,0 +1,21 @@
+-- Moderation state of accounts: suspended accounts are blocked until suspended_until
+-- and banned accounts until they are unbanned
+ALTER TABLE users ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active'
+	CHECK (status IN ('active', 'suspended', 'banned'));
+ALTER TABLE users ADD COLUMN suspended_until TIMESTAMP;
+
+-- What moderators and admins did through the admin API and why. Entries keep the
+-- IDs of removed content, and details describes it since the rows are gone
+CREATE TABLE admin_audit_log (
+	id BIGSERIAL PRIMARY KEY,
+	actor_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+	action VARCHAR(32) NOT NULL,
+	target_type VARCHAR(16) NOT NULL DEFAULT '',
+	target_id BIGINT,
+	reason TEXT NOT NULL DEFAULT '',
+	details TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_admin_audit_log_created_at ON admin_audit_log(created_at, id);
+CREATE INDEX idx_admin_audit_log_target ON admin_audit_log(target_type, target_id);
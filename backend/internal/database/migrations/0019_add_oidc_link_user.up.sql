@@ -0,0 +1,3 @@
+-- Linking a provider account is started by a signed-in user; the state records who,
+-- so the callback only links to that user. NULL means the state signs in instead
+ALTER TABLE oidc_login_states ADD COLUMN link_user_id BIGINT REFERENCES users(id) ON DELETE CASCADE;
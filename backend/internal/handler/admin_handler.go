@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/policy"
+	"github.com/yukito/video-platform/internal/service"
+)
+
+type AdminHandler struct {
+	adminService *service.AdminService
+}
+
+func NewAdminHandler(adminService *service.AdminService) *AdminHandler {
+	return &AdminHandler{adminService: adminService}
+}
+
+// SearchUsers handles GET /api/admin/users?q=&role=&status=
+func (h *AdminHandler) SearchUsers(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var filter model.UserFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	query, ok := bindPageQuery(c)
+	if !ok {
+		return
+	}
+
+	users, err := h.adminService.SearchUsers(c.Request.Context(), actor, filter, query)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// SuspendUser handles POST /api/admin/users/:id/suspend
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	var req model.SuspendUserRequest
+	actor, userID, ok := bindAdminRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	user, err := h.adminService.SuspendUser(c.Request.Context(), actor, userID, &req)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// BanUser handles POST /api/admin/users/:id/ban
+func (h *AdminHandler) BanUser(c *gin.Context) {
+	var req model.BanUserRequest
+	actor, userID, ok := bindAdminRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	user, err := h.adminService.BanUser(c.Request.Context(), actor, userID, &req)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// UnbanUser handles POST /api/admin/users/:id/unban, lifting a suspension or a ban
+func (h *AdminHandler) UnbanUser(c *gin.Context) {
+	var req model.UnbanUserRequest
+	actor, userID, ok := bindAdminRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	user, err := h.adminService.UnbanUser(c.Request.Context(), actor, userID, &req)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// SetRole handles PUT /api/admin/users/:id/role
+func (h *AdminHandler) SetRole(c *gin.Context) {
+	var req model.SetRoleRequest
+	actor, userID, ok := bindAdminRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	user, err := h.adminService.SetRole(c.Request.Context(), actor, userID, &req)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// RemoveVideo handles POST /api/admin/videos/:id/remove
+func (h *AdminHandler) RemoveVideo(c *gin.Context) {
+	var req model.RemoveContentRequest
+	actor, videoID, ok := bindAdminRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	if err := h.adminService.RemoveVideo(c.Request.Context(), actor, videoID, &req); err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "video removed"})
+}
+
+// RemoveComment handles POST /api/admin/comments/:id/remove
+func (h *AdminHandler) RemoveComment(c *gin.Context) {
+	var req model.RemoveContentRequest
+	actor, commentID, ok := bindAdminRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	if err := h.adminService.RemoveComment(c.Request.Context(), actor, commentID, &req); err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "comment removed"})
+}
+
+// Stats handles GET /api/admin/stats
+func (h *AdminHandler) Stats(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	stats, err := h.adminService.Stats(c.Request.Context(), actor)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// AuditLog handles GET /api/admin/audit-log
+func (h *AdminHandler) AuditLog(c *gin.Context) {
+	query, ok := bindPageQuery(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.adminService.AuditLog(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// bindAdminRequest reads the acting user, the :id path parameter and the JSON body
+// and responds with 401 or 400 when one of them is missing or malformed
+func bindAdminRequest(c *gin.Context, req any) (policy.Actor, int64, bool) {
+	actor, ok := currentActor(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return actor, 0, false
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return actor, 0, false
+	}
+
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return actor, 0, false
+	}
+	return actor, id, true
+}
+
+func adminErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound),
+		errors.Is(err, service.ErrVideoNotFound),
+		errors.Is(err, service.ErrCommentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrCannotManageUser),
+		errors.Is(err, service.ErrNotVideoOwner),
+		errors.Is(err, service.ErrCommentForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrInvalidRole),
+		errors.Is(err, service.ErrInvalidStatus),
+		errors.Is(err, service.ErrInvalidSuspension),
+		errors.Is(err, service.ErrInvalidCursor):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/policy"
 	"github.com/yukito/video-platform/internal/service"
 )
 
@@ -23,7 +27,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authService.Register(c.Request.Context(), &req)
+	resp, err := h.authService.Register(c.Request.Context(), &req, clientInfo(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -39,16 +43,282 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authService.Login(c.Request.Context(), &req)
+	resp, err := h.authService.Login(c.Request.Context(), &req, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		setRetryAfter(c, err)
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
 
+// VerifyMFA handles POST /api/auth/login/mfa, the second step of logins with two-factor authentication
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req model.MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.authService.VerifyMFA(c.Request.Context(), &req, clientInfo(c))
+	if err != nil {
+		setRetryAfter(c, err)
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Refresh exchanges a refresh token for a new access token and refresh token
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req model.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.authService.Refresh(c.Request.Context(), req.RefreshToken, clientInfo(c))
+	if err != nil {
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Logout revokes the session of the refresh token in the body, or else of the access token
 func (h *AuthHandler) Logout(c *gin.Context) {
-	// For stateless JWT, logout is handled on the client side by removing the token
+	var req model.LogoutRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	var err error
+	if req.RefreshToken != "" {
+		err = h.authService.LogoutRefreshToken(c.Request.Context(), req.RefreshToken)
+	} else if sessionID, exists := c.Get("session_id"); exists {
+		err = h.authService.Logout(c.Request.Context(), sessionID.(int64))
+	} else {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	if err != nil {
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})
 }
+
+// ListSessions handles GET /api/auth/sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, sessionID, ok := currentSession(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// RevokeSession handles DELETE /api/auth/sessions/:id
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, _, ok := currentSession(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
+		return
+	}
+
+	if err := h.authService.RevokeSession(c.Request.Context(), userID, id); err != nil {
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "session revoked successfully"})
+}
+
+// RevokeOtherSessions handles DELETE /api/auth/sessions, signing out every session but the current one
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	userID, sessionID, ok := currentSession(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	revoked, err := h.authService.RevokeOtherSessions(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+}
+
+// ChangePassword handles PUT /api/auth/password
+// All sessions are revoked; the response carries the tokens of a new session
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, _, ok := currentSession(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req model.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.authService.ChangePassword(c.Request.Context(), userID, &req, clientInfo(c))
+	if err != nil {
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ForgotPassword handles POST /api/auth/password/forgot
+// The response is the same whether or not an account uses the address
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req model.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authService.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "if an account uses this email, a password reset link has been sent"})
+}
+
+// ResetPassword handles POST /api/auth/password/reset
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req model.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authService.ResetPassword(c.Request.Context(), &req); err != nil {
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "password reset successfully"})
+}
+
+// VerifyEmail handles POST /api/auth/email/verify
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req model.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authService.VerifyEmail(c.Request.Context(), &req); err != nil {
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "email verified successfully"})
+}
+
+// ResendEmailVerification handles POST /api/auth/email/verification
+func (h *AuthHandler) ResendEmailVerification(c *gin.Context) {
+	userID, _, ok := currentSession(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.authService.RequestEmailVerification(c.Request.Context(), userID); err != nil {
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "verification email sent"})
+}
+
+// currentSession returns the user and session of the request's access token
+func currentSession(c *gin.Context) (userID, sessionID int64, ok bool) {
+	user, exists := c.Get("user_id")
+	if !exists {
+		return 0, 0, false
+	}
+	session, exists := c.Get("session_id")
+	if !exists {
+		return 0, 0, false
+	}
+	return user.(int64), session.(int64), true
+}
+
+// currentActor returns the signed-in user of the request and their role for policy decisions
+func currentActor(c *gin.Context) (policy.Actor, bool) {
+	user, exists := c.Get("user_id")
+	if !exists {
+		return policy.Actor{}, false
+	}
+	role, _ := c.Get("role")
+	roleName, _ := role.(string)
+	return policy.Actor{UserID: user.(int64), Role: roleName}, true
+}
+
+// clientInfo describes the client of a request for its session
+func clientInfo(c *gin.Context) model.ClientInfo {
+	return model.ClientInfo{UserAgent: c.Request.UserAgent(), IPAddress: c.ClientIP()}
+}
+
+// setRetryAfter tells throttled clients how many seconds to wait before logging in again
+func setRetryAfter(c *gin.Context, err error) {
+	var throttled *service.LoginThrottledError
+	if errors.As(err, &throttled) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	}
+}
+
+func authErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrTooManyLoginAttempts):
+		return http.StatusTooManyRequests
+	case errors.Is(err, service.ErrInvalidCredentials),
+		errors.Is(err, service.ErrInvalidToken),
+		errors.Is(err, service.ErrInvalidRefreshToken),
+		errors.Is(err, service.ErrRefreshTokenReused),
+		errors.Is(err, service.ErrInvalidMFAToken):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrIncorrectPassword),
+		errors.Is(err, service.ErrAccountBlocked):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrSessionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidResetToken),
+		errors.Is(err, service.ErrInvalidVerificationToken),
+		errors.Is(err, service.ErrInvalidMFACode):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrEmailAlreadyVerified):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -33,7 +34,7 @@ func (h *CommentHandler) Create(c *gin.Context) {
 
 	comment, err := h.commentService.Create(c.Request.Context(), userID.(int64), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -48,13 +49,9 @@ func (h *CommentHandler) GetCommentsByVideoID(c *gin.Context) {
 		return
 	}
 
-	// Parse pagination parameters
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-
-	// Limit max limit to 100
-	if limit > 100 {
-		limit = 100
+	query, ok := bindPageQuery(c)
+	if !ok {
+		return
 	}
 
 	// Get user ID if authenticated (optional)
@@ -64,13 +61,13 @@ func (h *CommentHandler) GetCommentsByVideoID(c *gin.Context) {
 		userIDPtr = &uid
 	}
 
-	comments, err := h.commentService.GetCommentsByVideoID(c.Request.Context(), videoID, userIDPtr, limit, offset)
+	comments, err := h.commentService.GetCommentsByVideoID(c.Request.Context(), videoID, userIDPtr, query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, comments)
+	writePage(c, query, comments)
 }
 
 // GetRepliesByParentID gets paginated replies for a parent comment
@@ -81,13 +78,9 @@ func (h *CommentHandler) GetRepliesByParentID(c *gin.Context) {
 		return
 	}
 
-	// Parse pagination parameters
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-
-	// Limit max limit to 100
-	if limit > 100 {
-		limit = 100
+	query, ok := bindPageQuery(c)
+	if !ok {
+		return
 	}
 
 	// Get user ID if authenticated (optional)
@@ -97,18 +90,18 @@ func (h *CommentHandler) GetRepliesByParentID(c *gin.Context) {
 		userIDPtr = &uid
 	}
 
-	replies, err := h.commentService.GetRepliesByParentID(c.Request.Context(), parentCommentID, userIDPtr, limit, offset)
+	replies, err := h.commentService.GetRepliesByParentID(c.Request.Context(), parentCommentID, userIDPtr, query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, replies)
+	writePage(c, query, replies)
 }
 
 // Update updates a comment
 func (h *CommentHandler) Update(c *gin.Context) {
-	userID, exists := c.Get("user_id")
+	actor, exists := currentActor(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
@@ -126,9 +119,9 @@ func (h *CommentHandler) Update(c *gin.Context) {
 		return
 	}
 
-	comment, err := h.commentService.Update(c.Request.Context(), userID.(int64), commentID, &req)
+	comment, err := h.commentService.Update(c.Request.Context(), actor, commentID, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -137,7 +130,7 @@ func (h *CommentHandler) Update(c *gin.Context) {
 
 // Delete deletes a comment
 func (h *CommentHandler) Delete(c *gin.Context) {
-	userID, exists := c.Get("user_id")
+	actor, exists := currentActor(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
@@ -149,8 +142,8 @@ func (h *CommentHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.commentService.Delete(c.Request.Context(), userID.(int64), commentID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.commentService.Delete(c.Request.Context(), actor, commentID); err != nil {
+		c.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -275,11 +268,29 @@ func (h *CommentHandler) GetCommentCount(c *gin.Context) {
 		return
 	}
 
-	count, err := h.commentService.GetCommentCount(c.Request.Context(), videoID)
+	// Get user ID if authenticated (optional)
+	var viewerID int64
+	if userID, exists := c.Get("user_id"); exists {
+		viewerID = userID.(int64)
+	}
+
+	count, err := h.commentService.GetCommentCount(c.Request.Context(), viewerID, videoID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"count": count})
 }
+
+// commentErrorStatus maps comment service errors to HTTP status codes
+func commentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrCommentNotFound), errors.Is(err, service.ErrVideoNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrCommentForbidden):
+		return http.StatusForbidden
+	default:
+		return pageErrorStatus(err)
+	}
+}
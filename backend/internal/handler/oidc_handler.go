@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/service"
+)
+
+type OIDCHandler struct {
+	oidcService *service.OIDCService
+}
+
+func NewOIDCHandler(oidcService *service.OIDCService) *OIDCHandler {
+	return &OIDCHandler{oidcService: oidcService}
+}
+
+// ListProviders handles GET /api/auth/oidc/providers
+func (h *OIDCHandler) ListProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": h.oidcService.Providers()})
+}
+
+// Authorize handles POST /api/auth/oidc/:provider/authorize
+func (h *OIDCHandler) Authorize(c *gin.Context) {
+	authorization, err := h.oidcService.Authorize(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		c.JSON(oidcErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, authorization)
+}
+
+// Callback handles POST /api/auth/oidc/callback
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	var req model.OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.oidcService.Callback(c.Request.Context(), &req, clientInfo(c))
+	if err != nil {
+		c.JSON(oidcErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AuthorizeLink handles POST /api/auth/identities/:provider/authorize
+func (h *OIDCHandler) AuthorizeLink(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	authorization, err := h.oidcService.AuthorizeLink(c.Request.Context(), c.Param("provider"), userID.(int64))
+	if err != nil {
+		c.JSON(oidcErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, authorization)
+}
+
+// Link handles POST /api/auth/identities with the code and state of a link started by AuthorizeLink
+func (h *OIDCHandler) Link(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req model.OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	identity, err := h.oidcService.Link(c.Request.Context(), &req, userID.(int64))
+	if err != nil {
+		c.JSON(oidcErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, identity)
+}
+
+// ListIdentities handles GET /api/auth/identities
+func (h *OIDCHandler) ListIdentities(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	identities, err := h.oidcService.Identities(c.Request.Context(), userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"identities": identities})
+}
+
+func oidcErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUnknownProvider):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidOIDCState),
+		errors.Is(err, service.ErrOIDCEmailRequired):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrOIDCLoginFailed):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrAccountBlocked):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrOIDCEmailNotVerified),
+		errors.Is(err, service.ErrIdentityLinked):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/service"
+)
+
+// bindPageQuery reads limit, cursor and the deprecated offset from the query string
+// and responds with 400 when they are malformed
+func bindPageQuery(c *gin.Context) (model.PageQuery, bool) {
+	var query model.PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return query, false
+	}
+	if query.Limit < 0 || query.Offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit and offset must not be negative"})
+		return query, false
+	}
+	return query, true
+}
+
+// writePage responds with the page envelope
+// Requests paging by offset without a cursor still get the bare list they used to,
+// marked with a Deprecation header
+func writePage[T any](c *gin.Context, query model.PageQuery, page *model.Page[T]) {
+	if query.Cursor == "" && c.Query("offset") != "" {
+		c.Header("Deprecation", "true")
+		c.JSON(http.StatusOK, page.Items)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// pageErrorStatus maps errors of paginated list services to HTTP status codes
+func pageErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidCursor):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/service"
+)
+
+type SearchHandler struct {
+	searchService *service.SearchService
+}
+
+func NewSearchHandler(searchService *service.SearchService) *SearchHandler {
+	return &SearchHandler{searchService: searchService}
+}
+
+// SearchVideos handles GET /api/videos/search
+func (h *SearchHandler) SearchVideos(c *gin.Context) {
+	var req model.VideoSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.searchService.SearchVideos(c.Request.Context(), optionalUserID(c), &req)
+	if err != nil {
+		c.JSON(searchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// searchErrorStatus maps search service errors to HTTP status codes
+func searchErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidSearch):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
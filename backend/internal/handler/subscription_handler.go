@@ -125,15 +125,16 @@ func (h *SubscriptionHandler) GetSubscriptionFeed(c *gin.Context) {
 		return
 	}
 
-	// Parse query parameters for pagination
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	query, ok := bindPageQuery(c)
+	if !ok {
+		return
+	}
 
-	videos, err := h.subscriptionService.GetSubscriptionFeed(c.Request.Context(), subscriberUserID.(int64), limit, offset)
+	videos, err := h.subscriptionService.GetSubscriptionFeed(c.Request.Context(), subscriberUserID.(int64), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(pageErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, videos)
+	writePage(c, query, videos)
 }
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yukito/video-platform/internal/service"
+)
+
+type SuggestionHandler struct {
+	suggestionService *service.SuggestionService
+}
+
+func NewSuggestionHandler(suggestionService *service.SuggestionService) *SuggestionHandler {
+	return &SuggestionHandler{suggestionService: suggestionService}
+}
+
+// Suggest handles GET /api/search/suggest
+func (h *SuggestionHandler) Suggest(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	suggestions, err := h.suggestionService.Suggest(c.Request.Context(), optionalUserID(c), c.Query("prefix"), limit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidPrefix) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"suggestions": suggestions})
+}
+
+// GetRecentQueries handles GET /api/search/history
+func (h *SuggestionHandler) GetRecentQueries(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	searches, err := h.suggestionService.GetRecentQueries(c.Request.Context(), userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"searches": searches})
+}
+
+// ClearRecentQueries handles DELETE /api/search/history
+func (h *SuggestionHandler) ClearRecentQueries(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.suggestionService.ClearRecentQueries(c.Request.Context(), userID.(int64)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "search history cleared successfully"})
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/service"
+)
+
+type TwoFactorHandler struct {
+	twoFactorService *service.TwoFactorService
+}
+
+func NewTwoFactorHandler(twoFactorService *service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{twoFactorService: twoFactorService}
+}
+
+// Status handles GET /api/auth/2fa
+func (h *TwoFactorHandler) Status(c *gin.Context) {
+	userID, _, ok := currentSession(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	status, err := h.twoFactorService.Status(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(twoFactorErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// BeginTOTPSetup handles POST /api/auth/2fa/totp, returning the secret for the authenticator app
+func (h *TwoFactorHandler) BeginTOTPSetup(c *gin.Context) {
+	userID, _, ok := currentSession(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	setup, err := h.twoFactorService.BeginTOTPSetup(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(twoFactorErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, setup)
+}
+
+// ConfirmTOTP handles POST /api/auth/2fa/totp/confirm, enabling TOTP and returning the recovery codes
+func (h *TwoFactorHandler) ConfirmTOTP(c *gin.Context) {
+	userID, _, ok := currentSession(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req model.ConfirmTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.twoFactorService.ConfirmTOTP(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		c.JSON(twoFactorErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, codes)
+}
+
+// Disable handles POST /api/auth/2fa/disable
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	userID, _, ok := currentSession(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req model.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.twoFactorService.Disable(c.Request.Context(), userID, &req); err != nil {
+		c.JSON(twoFactorErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes handles POST /api/auth/2fa/recovery-codes; earlier codes stop working
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, _, ok := currentSession(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req model.RegenerateRecoveryCodesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		c.JSON(twoFactorErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, codes)
+}
+
+func twoFactorErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidMFACode):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrIncorrectPassword):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrTwoFactorAlreadyEnabled),
+		errors.Is(err, service.ErrTwoFactorNotEnabled),
+		errors.Is(err, service.ErrTOTPSetupRequired):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/service"
+)
+
+// UploadHandler exposes resumable uploads over a tus-style protocol:
+// POST creates a session, HEAD reports the Upload-Offset to resume from,
+// PATCH appends a chunk at Upload-Offset and POST .../complete creates the video
+type UploadHandler struct {
+	uploadService *service.UploadService
+}
+
+func NewUploadHandler(uploadService *service.UploadService) *UploadHandler {
+	return &UploadHandler{uploadService: uploadService}
+}
+
+func (h *UploadHandler) Create(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req model.CreateUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.uploadService.Create(c.Request.Context(), userID.(int64), &req)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	setUploadHeaders(c, session)
+	c.Header("Location", "/api/uploads/"+session.ID)
+	c.JSON(http.StatusCreated, session)
+}
+
+// Head reports the upload progress in headers only
+func (h *UploadHandler) Head(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.Status(http.StatusUnauthorized)
+		return
+	}
+
+	session, err := h.uploadService.Get(c.Request.Context(), userID.(int64), c.Param("id"))
+	if err != nil {
+		c.Status(uploadErrorStatus(err))
+		return
+	}
+
+	setUploadHeaders(c, session)
+	c.Status(http.StatusOK)
+}
+
+func (h *UploadHandler) Get(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	session, err := h.uploadService.Get(c.Request.Context(), userID.(int64), c.Param("id"))
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	setUploadHeaders(c, session)
+	c.JSON(http.StatusOK, session)
+}
+
+// Patch appends the request body as the chunk starting at the Upload-Offset header
+func (h *UploadHandler) Patch(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid Upload-Offset header"})
+		return
+	}
+
+	size := c.Request.ContentLength
+	if size <= 0 {
+		c.JSON(http.StatusLengthRequired, gin.H{"error": "Content-Length is required"})
+		return
+	}
+	if size > service.MaxChunkSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "chunk is too large"})
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, size)
+	session, err := h.uploadService.WriteChunk(c.Request.Context(), userID.(int64), c.Param("id"), offset, body, size)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	setUploadHeaders(c, session)
+	c.Status(http.StatusNoContent)
+}
+
+// Complete creates the video from a fully uploaded session
+// An optional thumbnail can be sent as multipart/form-data
+func (h *UploadHandler) Complete(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	// Get thumbnail file (optional)
+	var thumbnailFile io.ReadCloser
+	var thumbnailFilename, thumbnailContentType string
+	var thumbnailSize int64
+
+	thumbnailFileHeader, err := c.FormFile("thumbnail")
+	if err == nil {
+		thumbnailFile, err = thumbnailFileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open thumbnail file"})
+			return
+		}
+		defer thumbnailFile.Close()
+		thumbnailFilename = thumbnailFileHeader.Filename
+		thumbnailContentType = thumbnailFileHeader.Header.Get("Content-Type")
+		thumbnailSize = thumbnailFileHeader.Size
+	}
+
+	video, err := h.uploadService.Complete(
+		c.Request.Context(),
+		userID.(int64),
+		c.Param("id"),
+		thumbnailFile,
+		thumbnailFilename,
+		thumbnailContentType,
+		thumbnailSize,
+	)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, video)
+}
+
+func (h *UploadHandler) Abort(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.uploadService.Abort(c.Request.Context(), userID.(int64), c.Param("id")); err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "upload aborted successfully"})
+}
+
+func setUploadHeaders(c *gin.Context, session *model.UploadSession) {
+	c.Header("Upload-Offset", strconv.FormatInt(session.UploadOffset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(session.UploadLength, 10))
+	c.Header("Cache-Control", "no-store")
+}
+
+// uploadErrorStatus maps upload service errors to HTTP status codes
+func uploadErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUploadNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrUploadOffsetMismatch),
+		errors.Is(err, service.ErrUploadNotActive),
+		errors.Is(err, service.ErrUploadIncomplete):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrInvalidChunk),
+		errors.Is(err, service.ErrInvalidVideo),
+		errors.Is(err, service.ErrInvalidVisibility),
+		errors.Is(err, service.ErrInvalidPublishAt):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrUploadTooLarge):
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
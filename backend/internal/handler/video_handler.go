@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yukito/video-platform/internal/model"
@@ -41,7 +43,7 @@ func (h *VideoHandler) Create(c *gin.Context) {
 
 	video, err := h.videoService.Create(c.Request.Context(), userID.(int64), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(videoErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -58,20 +60,16 @@ func (h *VideoHandler) CreateWithFiles(c *gin.Context) {
 	// Get form values
 	title := c.PostForm("title")
 	description := c.PostForm("description")
-	durationStr := c.PostForm("duration")
 
 	if title == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "title is required"})
 		return
 	}
 
-	// Parse duration (default to 0 if not provided or invalid)
-	var duration int64 = 0
-	if durationStr != "" {
-		parsedDuration, err := strconv.ParseInt(durationStr, 10, 64)
-		if err == nil {
-			duration = parsedDuration
-		}
+	publishAt, err := formPublishAt(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
 	// Get video file
@@ -112,7 +110,8 @@ func (h *VideoHandler) CreateWithFiles(c *gin.Context) {
 		userID.(int64),
 		title,
 		description,
-		duration,
+		c.PostForm("visibility"),
+		publishAt,
 		videoFile,
 		videoFileHeader.Filename,
 		videoFileHeader.Header.Get("Content-Type"),
@@ -123,7 +122,7 @@ func (h *VideoHandler) CreateWithFiles(c *gin.Context) {
 		thumbnailSize,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(videoErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -137,7 +136,7 @@ func (h *VideoHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	video, err := h.videoService.GetByID(c.Request.Context(), id)
+	video, err := h.videoService.GetByID(c.Request.Context(), optionalUserID(c), id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "video not found"})
 		return
@@ -147,26 +146,22 @@ func (h *VideoHandler) GetByID(c *gin.Context) {
 }
 
 func (h *VideoHandler) List(c *gin.Context) {
-	// Parse pagination parameters
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "15"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-
-	// Limit max limit to 100
-	if limit > 100 {
-		limit = 100
+	query, ok := bindPageQuery(c)
+	if !ok {
+		return
 	}
 
-	videos, err := h.videoService.List(c.Request.Context(), limit, offset)
+	videos, err := h.videoService.List(c.Request.Context(), optionalUserID(c), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(pageErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, videos)
+	writePage(c, query, videos)
 }
 
 func (h *VideoHandler) Update(c *gin.Context) {
-	userID, exists := c.Get("user_id")
+	actor, exists := currentActor(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
@@ -192,9 +187,9 @@ func (h *VideoHandler) Update(c *gin.Context) {
 		return
 	}
 
-	video, err := h.videoService.Update(c.Request.Context(), userID.(int64), videoID, &req)
+	video, err := h.videoService.Update(c.Request.Context(), actor, videoID, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(videoErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -202,7 +197,7 @@ func (h *VideoHandler) Update(c *gin.Context) {
 }
 
 func (h *VideoHandler) UpdateWithFiles(c *gin.Context, videoID int64) {
-	userID, exists := c.Get("user_id")
+	actor, exists := currentActor(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
@@ -217,6 +212,12 @@ func (h *VideoHandler) UpdateWithFiles(c *gin.Context, videoID int64) {
 		return
 	}
 
+	publishAt, err := formPublishAt(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Get video file (optional for update)
 	var videoFile io.ReadCloser
 	var videoFilename, videoContentType string
@@ -256,10 +257,12 @@ func (h *VideoHandler) UpdateWithFiles(c *gin.Context, videoID int64) {
 	// Update video with files
 	video, err := h.videoService.UpdateWithFiles(
 		c.Request.Context(),
-		userID.(int64),
+		actor,
 		videoID,
 		title,
 		description,
+		c.PostForm("visibility"),
+		publishAt,
 		videoFile,
 		videoFilename,
 		videoContentType,
@@ -270,7 +273,7 @@ func (h *VideoHandler) UpdateWithFiles(c *gin.Context, videoID int64) {
 		thumbnailSize,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(videoErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -278,7 +281,7 @@ func (h *VideoHandler) UpdateWithFiles(c *gin.Context, videoID int64) {
 }
 
 func (h *VideoHandler) Delete(c *gin.Context) {
-	userID, exists := c.Get("user_id")
+	actor, exists := currentActor(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
@@ -290,10 +293,155 @@ func (h *VideoHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.videoService.Delete(c.Request.Context(), userID.(int64), videoID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.videoService.Delete(c.Request.Context(), actor, videoID); err != nil {
+		c.JSON(videoErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "video deleted successfully"})
 }
+
+// GetStatus lets the owner poll a video's upload and processing status
+func (h *VideoHandler) GetStatus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	videoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid video ID"})
+		return
+	}
+
+	status, err := h.videoService.GetStatus(c.Request.Context(), userID.(int64), videoID)
+	if err != nil {
+		c.JSON(videoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// ListThumbnails returns the thumbnail candidates the owner can choose from
+func (h *VideoHandler) ListThumbnails(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	videoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid video ID"})
+		return
+	}
+
+	thumbnails, err := h.videoService.ListThumbnails(c.Request.Context(), userID.(int64), videoID)
+	if err != nil {
+		c.JSON(videoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, thumbnails)
+}
+
+// SetThumbnail selects a candidate (JSON thumbnail_id) or uploads a custom thumbnail (multipart "thumbnail")
+func (h *VideoHandler) SetThumbnail(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	videoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid video ID"})
+		return
+	}
+
+	var video *model.Video
+	contentType := c.GetHeader("Content-Type")
+	if len(contentType) > 19 && contentType[:19] == "multipart/form-data" {
+		fileHeader, err := c.FormFile("thumbnail")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "thumbnail file is required"})
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open thumbnail file"})
+			return
+		}
+		defer file.Close()
+
+		video, err = h.videoService.UploadThumbnail(
+			c.Request.Context(),
+			userID.(int64),
+			videoID,
+			file,
+			fileHeader.Filename,
+			fileHeader.Header.Get("Content-Type"),
+			fileHeader.Size,
+		)
+		if err != nil {
+			c.JSON(videoErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+	} else {
+		var req model.SelectThumbnailRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		video, err = h.videoService.SelectThumbnail(c.Request.Context(), userID.(int64), videoID, req.ThumbnailID)
+		if err != nil {
+			c.JSON(videoErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, video)
+}
+
+func videoErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrVideoNotFound),
+		errors.Is(err, service.ErrThumbnailNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrNotVideoOwner):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrInvalidVideo),
+		errors.Is(err, service.ErrInvalidThumbnail),
+		errors.Is(err, service.ErrInvalidVisibility),
+		errors.Is(err, service.ErrInvalidPublishAt),
+		errors.Is(err, service.ErrInvalidReaction):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// formPublishAt parses the optional publish_at form field as an RFC 3339 time
+func formPublishAt(c *gin.Context) (*time.Time, error) {
+	value := c.PostForm("publish_at")
+	if value == "" {
+		return nil, nil
+	}
+	publishAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.New("publish_at must be an RFC 3339 time")
+	}
+	return &publishAt, nil
+}
+
+// optionalUserID returns the user set by OptionalAuth, or 0 for anonymous requests
+func optionalUserID(c *gin.Context) int64 {
+	if userID, exists := c.Get("user_id"); exists {
+		return userID.(int64)
+	}
+	return 0
+}
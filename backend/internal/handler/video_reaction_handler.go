@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/service"
+)
+
+type VideoReactionHandler struct {
+	reactionService *service.VideoReactionService
+}
+
+func NewVideoReactionHandler(reactionService *service.VideoReactionService) *VideoReactionHandler {
+	return &VideoReactionHandler{reactionService: reactionService}
+}
+
+// LikeVideo handles POST /api/videos/:id/like
+// The optional body {"like_type": "dislike"} dislikes the video instead
+func (h *VideoReactionHandler) LikeVideo(c *gin.Context) {
+	var req model.ReactVideoRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.LikeType == "" {
+		req.LikeType = model.VideoReactionLike
+	}
+
+	h.react(c, req.LikeType)
+}
+
+// DislikeVideo handles POST /api/videos/:id/dislike
+func (h *VideoReactionHandler) DislikeVideo(c *gin.Context) {
+	h.react(c, model.VideoReactionDislike)
+}
+
+func (h *VideoReactionHandler) react(c *gin.Context, likeType string) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	videoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid video ID"})
+		return
+	}
+
+	if err := h.reactionService.React(c.Request.Context(), userID.(int64), videoID, likeType); err != nil {
+		c.JSON(videoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "video " + likeType + "d successfully"})
+}
+
+// RemoveReaction handles DELETE /api/videos/:id/like
+func (h *VideoReactionHandler) RemoveReaction(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	videoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid video ID"})
+		return
+	}
+
+	if err := h.reactionService.RemoveReaction(c.Request.Context(), userID.(int64), videoID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "video unliked successfully"})
+}
+
+// GetLikeStatus handles GET /api/videos/:id/like
+func (h *VideoReactionHandler) GetLikeStatus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	videoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid video ID"})
+		return
+	}
+
+	likeType, err := h.reactionService.GetReaction(c.Request.Context(), userID.(int64), videoID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// like_type is "like", "dislike" or null
+	var userLikeType *string
+	if likeType != "" {
+		userLikeType = &likeType
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"is_liked":  likeType == model.VideoReactionLike,
+		"like_type": userLikeType,
+	})
+}
+
+// GetLikedVideos handles GET /api/videos/liked
+func (h *VideoReactionHandler) GetLikedVideos(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	query, ok := bindPageQuery(c)
+	if !ok {
+		return
+	}
+
+	videos, err := h.reactionService.GetLikedVideos(c.Request.Context(), userID.(int64), query)
+	if err != nil {
+		c.JSON(pageErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	writePage(c, query, videos)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/service"
+)
+
+type ViewHandler struct {
+	viewService *service.ViewService
+}
+
+func NewViewHandler(viewService *service.ViewService) *ViewHandler {
+	return &ViewHandler{viewService: viewService}
+}
+
+// RecordView handles POST /api/videos/:id/views
+// Players send it periodically during playback; {"counted": true} marks the event that counted the view
+func (h *ViewHandler) RecordView(c *gin.Context) {
+	videoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid video ID"})
+		return
+	}
+
+	var req model.RecordViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	viewer := model.Viewer{
+		UserID:    optionalUserID(c),
+		IP:        c.ClientIP(),
+		SessionID: req.SessionID,
+	}
+	counted, err := h.viewService.RecordView(c.Request.Context(), viewer, videoID, &req)
+	if err != nil {
+		c.JSON(viewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"counted": counted})
+}
+
+// viewErrorStatus maps view service errors to HTTP status codes
+func viewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidViewEvent):
+		return http.StatusBadRequest
+	default:
+		return videoErrorStatus(err)
+	}
+}
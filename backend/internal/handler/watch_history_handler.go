@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yukito/video-platform/internal/model"
 	"github.com/yukito/video-platform/internal/service"
 )
 
@@ -38,6 +40,68 @@ func (h *WatchHistoryHandler) AddToHistory(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "added to history successfully"})
 }
 
+// SaveProgress handles POST /api/videos/:id/progress
+// Players send it periodically with the current position; it also adds the video to the history
+func (h *WatchHistoryHandler) SaveProgress(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	videoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid video ID"})
+		return
+	}
+
+	var req model.SaveProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	progress, err := h.historyService.SaveProgress(c.Request.Context(), userID.(int64), videoID, &req)
+	if err != nil {
+		c.JSON(progressErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// GetContinueWatching handles GET /api/history/continue
+func (h *WatchHistoryHandler) GetContinueWatching(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	query, ok := bindPageQuery(c)
+	if !ok {
+		return
+	}
+
+	history, err := h.historyService.GetContinueWatching(c.Request.Context(), userID.(int64), query)
+	if err != nil {
+		c.JSON(pageErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	writePage(c, query, history)
+}
+
+// progressErrorStatus maps playback progress errors to HTTP status codes
+func progressErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidProgress):
+		return http.StatusBadRequest
+	default:
+		return videoErrorStatus(err)
+	}
+}
+
 // GetWatchHistory handles GET /api/history
 func (h *WatchHistoryHandler) GetWatchHistory(c *gin.Context) {
 	userID, exists := c.Get("user_id")
@@ -46,22 +110,18 @@ func (h *WatchHistoryHandler) GetWatchHistory(c *gin.Context) {
 		return
 	}
 
-	// Parse pagination parameters
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-
-	// Limit max limit to 100
-	if limit > 100 {
-		limit = 100
+	query, ok := bindPageQuery(c)
+	if !ok {
+		return
 	}
 
-	history, err := h.historyService.GetWatchHistory(c.Request.Context(), userID.(int64), limit, offset)
+	history, err := h.historyService.GetWatchHistory(c.Request.Context(), userID.(int64), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(pageErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, history)
+	writePage(c, query, history)
 }
 
 // RemoveFromHistory handles DELETE /api/history/:video_id
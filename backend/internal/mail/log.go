@@ -0,0 +1,38 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// LogMailer writes messages to a writer instead of sending them
+// It is meant for local development, where links in the messages can be copied from the log
+type LogMailer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewLogMailer returns a LogMailer writing to w, or to stdout if w is nil
+func NewLogMailer(w io.Writer) *LogMailer {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &LogMailer{w: w}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, err := fmt.Fprintf(m.w, "----- mail -----\nTo: %s\nSubject: %s\n\n%s\n----------------\n", msg.To, msg.Subject, msg.Body)
+	if err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Mailer sends email to users
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+var ErrInvalidMessage = errors.New("invalid message")
+
+// validate rejects messages without a recipient and header values that would
+// let a caller inject extra headers
+func (m Message) validate() error {
+	if m.To == "" || strings.ContainsAny(m.To, "\r\n") || strings.ContainsAny(m.Subject, "\r\n") {
+		return ErrInvalidMessage
+	}
+	return nil
+}
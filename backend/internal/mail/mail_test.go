@@ -0,0 +1,96 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildMessage_EncodesUTF8(t *testing.T) {
+	msg := Message{
+		To:      "alice@example.com",
+		Subject: "パスワードの再設定",
+		Body:    "こんにちは\n以下のリンクを開いてください: https://example.com/reset?token=abc",
+	}
+	data := buildMessage("no-reply@example.com", msg, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to parse message: %v", err)
+	}
+	if got := parsed.Header.Get("To"); got != msg.To {
+		t.Errorf("expected To %q, got %q", msg.To, got)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != msg.Subject {
+		t.Errorf("expected subject %q, got %q (%v)", msg.Subject, subject, err)
+	}
+	if got := parsed.Header.Get("Content-Type"); got != "text/plain; charset=UTF-8" {
+		t.Errorf("unexpected Content-Type %q", got)
+	}
+
+	body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	if err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if got := strings.ReplaceAll(string(body), "\r\n", "\n"); got != msg.Body {
+		t.Errorf("expected body %q, got %q", msg.Body, got)
+	}
+}
+
+func TestLogMailer_WritesMessage(t *testing.T) {
+	var out bytes.Buffer
+	m := NewLogMailer(&out)
+
+	err := m.Send(context.Background(), Message{To: "alice@example.com", Subject: "hello", Body: "link: https://example.com"})
+	if err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+	for _, want := range []string{"To: alice@example.com", "Subject: hello", "link: https://example.com"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected output to contain %q, got %q", want, out.String())
+		}
+	}
+}
+
+func TestSend_RejectsHeaderInjection(t *testing.T) {
+	mailers := map[string]Mailer{
+		"log":    NewLogMailer(io.Discard),
+		"memory": NewMemoryMailer(),
+	}
+	messages := []Message{
+		{To: "", Subject: "hello"},
+		{To: "alice@example.com\r\nBcc: eve@example.com", Subject: "hello"},
+		{To: "alice@example.com", Subject: "hello\nBcc: eve@example.com"},
+	}
+	for name, m := range mailers {
+		for _, msg := range messages {
+			if err := m.Send(context.Background(), msg); !errors.Is(err, ErrInvalidMessage) {
+				t.Errorf("%s: expected ErrInvalidMessage for %+v, got %v", name, msg, err)
+			}
+		}
+	}
+}
+
+func TestNewSMTPMailer_RequiresHostAndFrom(t *testing.T) {
+	if _, err := NewSMTPMailer("", "587", "", "", "no-reply@example.com"); err == nil {
+		t.Error("expected an error without a host")
+	}
+	if _, err := NewSMTPMailer("smtp.example.com", "", "", "", ""); err == nil {
+		t.Error("expected an error without a from address")
+	}
+	m, err := NewSMTPMailer("smtp.example.com", "", "", "", "no-reply@example.com")
+	if err != nil {
+		t.Fatalf("NewSMTPMailer returned error: %v", err)
+	}
+	if m.addr != "smtp.example.com:587" {
+		t.Errorf("expected the default port, got %q", m.addr)
+	}
+}
@@ -0,0 +1,46 @@
+package mail
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryMailer records sent messages instead of sending them
+// It is meant for tests: a failure can be injected with Fail
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []Message
+	err  error
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// Sent returns the messages sent so far
+func (m *MemoryMailer) Sent() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.sent...)
+}
+
+// Fail makes Send return err until cleared with a nil err
+func (m *MemoryMailer) Fail(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
@@ -0,0 +1,111 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FakeProber returns fixed metadata without running ffprobe
+// It is meant for tests: every call is recorded and a failure can be injected
+type FakeProber struct {
+	mu       sync.Mutex
+	metadata Metadata
+	err      error
+	calls    int
+}
+
+// NewFakeProber reports every file as a 10 second 1280x720 H.264 video
+func NewFakeProber() *FakeProber {
+	return &FakeProber{metadata: Metadata{
+		Container:  "mov,mp4,m4a,3gp,3g2,mj2",
+		VideoCodec: "h264",
+		AudioCodec: "aac",
+		Width:      1280,
+		Height:     720,
+		Bitrate:    2500000,
+		Duration:   10.2,
+		FrameRate:  30,
+	}}
+}
+
+// SetMetadata changes what subsequent probes return
+func (p *FakeProber) SetMetadata(meta Metadata) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.metadata = meta
+}
+
+// Fail makes every Probe call return err; passing nil clears the failure
+func (p *FakeProber) Fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+// Calls returns how many files have been probed
+func (p *FakeProber) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+func (p *FakeProber) Probe(ctx context.Context, path string) (*Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls++
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	meta := p.metadata
+	return &meta, nil
+}
+
+// FakeFrameExtractor writes placeholder images without running ffmpeg
+// Each image contains the offset and the source file content, e.g. "frame@5.00:<source>"
+type FakeFrameExtractor struct {
+	mu    sync.Mutex
+	err   error
+	calls int
+}
+
+func NewFakeFrameExtractor() *FakeFrameExtractor {
+	return &FakeFrameExtractor{}
+}
+
+// Fail makes every ExtractFrame call return err; passing nil clears the failure
+func (e *FakeFrameExtractor) Fail(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.err = err
+}
+
+// Calls returns how many frames have been requested
+func (e *FakeFrameExtractor) Calls() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.calls
+}
+
+func (e *FakeFrameExtractor) ExtractFrame(ctx context.Context, path string, at float64, outputPath string) error {
+	e.mu.Lock()
+	e.calls++
+	err := e.err
+	e.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read source: %w", err)
+	}
+	return os.WriteFile(outputPath, []byte(fmt.Sprintf("frame@%.2f:%s", at, source)), 0o644)
+}
@@ -0,0 +1,53 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// maxFrameWidth bounds the width of extracted frames; smaller videos keep their size
+const maxFrameWidth = 1280
+
+// FFmpegFrameExtractor extracts frames by running the ffmpeg binary
+type FFmpegFrameExtractor struct {
+	path string
+}
+
+// NewFFmpegFrameExtractor creates an extractor; an empty path falls back to "ffmpeg" on PATH
+func NewFFmpegFrameExtractor(path string) *FFmpegFrameExtractor {
+	if path == "" {
+		path = "ffmpeg"
+	}
+	return &FFmpegFrameExtractor{path: path}
+}
+
+// Available reports whether the ffmpeg binary can be found
+func (e *FFmpegFrameExtractor) Available() error {
+	if _, err := exec.LookPath(e.path); err != nil {
+		return fmt.Errorf("%s not found: %w", e.path, err)
+	}
+	return nil
+}
+
+func (e *FFmpegFrameExtractor) ExtractFrame(ctx context.Context, path string, at float64, outputPath string) error {
+	cmd := exec.CommandContext(ctx, e.path,
+		"-hide_banner", "-loglevel", "error", "-y",
+		// Seeking before the input is fast and accurate enough for thumbnails
+		"-ss", strconv.FormatFloat(at, 'f', 3, 64),
+		"-i", path,
+		"-frames:v", "1",
+		"-vf", fmt.Sprintf("scale='min(%d,iw)':-2", maxFrameWidth),
+		"-q:v", "3",
+		outputPath,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to extract frame at %.1fs: %w: %s", at, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
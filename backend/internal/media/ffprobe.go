@@ -0,0 +1,119 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// FFProbe probes files by running the ffprobe binary
+type FFProbe struct {
+	path string
+}
+
+// NewFFProbe creates a prober; an empty path falls back to "ffprobe" on PATH
+func NewFFProbe(path string) *FFProbe {
+	if path == "" {
+		path = "ffprobe"
+	}
+	return &FFProbe{path: path}
+}
+
+// Available reports whether the ffprobe binary can be found
+func (p *FFProbe) Available() error {
+	if _, err := exec.LookPath(p.path); err != nil {
+		return fmt.Errorf("%s not found: %w", p.path, err)
+	}
+	return nil
+}
+
+func (p *FFProbe) Probe(ctx context.Context, path string) (*Metadata, error) {
+	cmd := exec.CommandContext(ctx, p.path,
+		"-v", "error",
+		"-show_format",
+		"-show_streams",
+		"-of", "json",
+		path,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		// ffprobe exits with an error for files it cannot parse
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && ctx.Err() == nil {
+			return nil, fmt.Errorf("%w: %s", ErrNotVideo, strings.TrimSpace(stderr.String()))
+		}
+		return nil, fmt.Errorf("failed to run ffprobe: %w", err)
+	}
+
+	return parseFFProbeOutput(out)
+}
+
+type ffprobeOutput struct {
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"format"`
+	Streams []struct {
+		CodecType    string `json:"codec_type"`
+		CodecName    string `json:"codec_name"`
+		Width        int    `json:"width"`
+		Height       int    `json:"height"`
+		AvgFrameRate string `json:"avg_frame_rate"`
+		RFrameRate   string `json:"r_frame_rate"`
+		Duration     string `json:"duration"`
+		Disposition  struct {
+			AttachedPic int `json:"attached_pic"`
+		} `json:"disposition"`
+	} `json:"streams"`
+}
+
+// parseFFProbeOutput converts `ffprobe -show_format -show_streams -of json` output
+func parseFFProbeOutput(out []byte) (*Metadata, error) {
+	var probe ffprobeOutput
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	meta := &Metadata{Container: probe.Format.FormatName}
+	meta.Duration, _ = strconv.ParseFloat(probe.Format.Duration, 64)
+	meta.Bitrate, _ = strconv.ParseInt(probe.Format.BitRate, 10, 64)
+
+	hasVideo := false
+	for _, stream := range probe.Streams {
+		switch stream.CodecType {
+		case "video":
+			// Cover art in audio files is reported as a single-frame video stream
+			if hasVideo || stream.Disposition.AttachedPic == 1 || stream.Width <= 0 || stream.Height <= 0 {
+				continue
+			}
+			hasVideo = true
+			meta.VideoCodec = stream.CodecName
+			meta.Width = stream.Width
+			meta.Height = stream.Height
+			meta.FrameRate = parseRate(stream.AvgFrameRate)
+			if meta.FrameRate == 0 {
+				meta.FrameRate = parseRate(stream.RFrameRate)
+			}
+			if meta.Duration == 0 {
+				meta.Duration, _ = strconv.ParseFloat(stream.Duration, 64)
+			}
+		case "audio":
+			if meta.AudioCodec == "" {
+				meta.AudioCodec = stream.CodecName
+			}
+		}
+	}
+
+	if !hasVideo {
+		return nil, fmt.Errorf("%w: no video stream", ErrNotVideo)
+	}
+	return meta, nil
+}
@@ -0,0 +1,69 @@
+package media
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+const mp4Probe = `{
+	"streams": [
+		{"codec_type": "video", "codec_name": "h264", "width": 1920, "height": 1080,
+		 "avg_frame_rate": "30000/1001", "r_frame_rate": "30000/1001", "duration": "12.012000",
+		 "disposition": {"attached_pic": 0}},
+		{"codec_type": "audio", "codec_name": "aac", "duration": "12.000000"}
+	],
+	"format": {"format_name": "mov,mp4,m4a,3gp,3g2,mj2", "duration": "12.034000", "bit_rate": "4817201"}
+}`
+
+func TestParseFFProbeOutput(t *testing.T) {
+	meta, err := parseFFProbeOutput([]byte(mp4Probe))
+	if err != nil {
+		t.Fatalf("parseFFProbeOutput returned error: %v", err)
+	}
+
+	want := Metadata{
+		Container:  "mov,mp4,m4a,3gp,3g2,mj2",
+		VideoCodec: "h264",
+		AudioCodec: "aac",
+		Width:      1920,
+		Height:     1080,
+		Bitrate:    4817201,
+		Duration:   12.034,
+	}
+	got := *meta
+	got.FrameRate = 0
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if math.Abs(meta.FrameRate-29.97) > 0.01 {
+		t.Errorf("expected 29.97 fps, got %v", meta.FrameRate)
+	}
+	if meta.DurationSeconds() != 12 {
+		t.Errorf("expected 12 seconds, got %d", meta.DurationSeconds())
+	}
+}
+
+func TestParseFFProbeOutput_RejectsFilesWithoutVideo(t *testing.T) {
+	// An MP3 whose cover art shows up as a video stream
+	mp3 := `{
+		"streams": [
+			{"codec_type": "audio", "codec_name": "mp3"},
+			{"codec_type": "video", "codec_name": "mjpeg", "width": 500, "height": 500, "disposition": {"attached_pic": 1}}
+		],
+		"format": {"format_name": "mp3", "duration": "180.0"}
+	}`
+
+	if _, err := parseFFProbeOutput([]byte(mp3)); !errors.Is(err, ErrNotVideo) {
+		t.Errorf("expected ErrNotVideo, got %v", err)
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := map[string]float64{"25/1": 25, "60": 60, "0/0": 0, "": 0}
+	for input, want := range tests {
+		if got := parseRate(input); got != want {
+			t.Errorf("parseRate(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Package media inspects uploaded files.
+//
+// A Prober reads the container and stream information of a local file, so
+// the server learns a video's duration, resolution and codecs itself instead
+// of trusting what the client sends. A FrameExtractor grabs still frames
+// that are offered as thumbnails.
+package media
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrNotVideo is returned by a Prober for files that do not contain a decodable video stream
+var ErrNotVideo = errors.New("file is not a decodable video")
+
+// Metadata describes a probed video file
+type Metadata struct {
+	Container  string  // e.g. "mov,mp4,m4a,3gp,3g2,mj2"
+	VideoCodec string  // e.g. "h264"
+	AudioCodec string  // empty when the file has no audio
+	Width      int     // pixels
+	Height     int     // pixels
+	Bitrate    int64   // bits per second of the whole file
+	Duration   float64 // seconds
+	FrameRate  float64 // frames per second
+}
+
+// DurationSeconds is the duration rounded to whole seconds
+func (m *Metadata) DurationSeconds() int64 {
+	return int64(m.Duration + 0.5)
+}
+
+// Prober reads the metadata of a local file
+type Prober interface {
+	// Probe returns ErrNotVideo (possibly wrapped) when the file is not a decodable video
+	Probe(ctx context.Context, path string) (*Metadata, error)
+}
+
+// FrameExtractor writes a single frame of a local video file as a JPEG image
+type FrameExtractor interface {
+	// ExtractFrame writes the frame at the given offset in seconds to outputPath
+	ExtractFrame(ctx context.Context, path string, at float64, outputPath string) error
+}
+
+// parseRate parses a rational such as "30000/1001" or a plain number; it returns 0 when unknown
+func parseRate(s string) float64 {
+	num, den, found := strings.Cut(s, "/")
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0
+	}
+	if !found {
+		return n
+	}
+
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil || d == 0 {
+		return 0
+	}
+	return n / d
+}
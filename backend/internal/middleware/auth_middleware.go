@@ -1,19 +1,26 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/policy"
 )
 
+// TokenValidator checks access tokens, including whether their session has been revoked
+type TokenValidator interface {
+	ValidateToken(ctx context.Context, token string) (*model.AccessClaims, error)
+}
+
 type AuthMiddleware struct {
-	jwtSecret string
+	validator TokenValidator
 }
 
-func NewAuthMiddleware(jwtSecret string) *AuthMiddleware {
-	return &AuthMiddleware{jwtSecret: jwtSecret}
+func NewAuthMiddleware(validator TokenValidator) *AuthMiddleware {
+	return &AuthMiddleware{validator: validator}
 }
 
 func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
@@ -33,32 +40,40 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 			return
 		}
 
-		tokenString := parts[1]
-
-		// Parse and validate token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(m.jwtSecret), nil
-		})
-
-		if err != nil || !token.Valid {
+		// Validate the token and its session
+		claims, err := m.validator.ValidateToken(c.Request.Context(), parts[1])
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
 			c.Abort()
 			return
 		}
-
-		// Extract user ID from claims
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			userID := int64(claims["user_id"].(float64))
-			c.Set("user_id", userID)
-		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token claims"})
+		if claims.AccountStatus != model.AccountStatusActive {
+			c.JSON(http.StatusForbidden, gin.H{"error": "account is " + claims.AccountStatus})
 			c.Abort()
 			return
 		}
 
+		c.Set("user_id", claims.UserID)
+		c.Set("session_id", claims.SessionID)
+		c.Set("role", claims.Role)
+		c.Next()
+	}
+}
+
+// RequireRole allows only users with at least the given role; it runs after RequireAuth
+func (m *AuthMiddleware) RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		current, ok := c.Get("role")
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			c.Abort()
+			return
+		}
+		if currentRole, _ := current.(string); !policy.HasRole(currentRole, role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient role"})
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
@@ -81,28 +96,16 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 			return
 		}
 
-		tokenString := parts[1]
-
-		// Parse and validate token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(m.jwtSecret), nil
-		})
-
-		if err != nil || !token.Valid {
-			// Invalid token, continue without user_id
+		claims, err := m.validator.ValidateToken(c.Request.Context(), parts[1])
+		if err != nil || claims.AccountStatus != model.AccountStatusActive {
+			// Invalid, expired or revoked token, or a blocked account; continue without user_id
 			c.Next()
 			return
 		}
 
-		// Extract user ID from claims
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			userID := int64(claims["user_id"].(float64))
-			c.Set("user_id", userID)
-		}
-
+		c.Set("user_id", claims.UserID)
+		c.Set("session_id", claims.SessionID)
+		c.Set("role", claims.Role)
 		c.Next()
 	}
 }
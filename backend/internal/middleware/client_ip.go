@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrustProxies sets the reverse proxies (comma-separated IPs or CIDRs) whose X-Forwarded-For
+// and X-Real-IP headers c.ClientIP() believes. With none, the client IP is the address of the
+// connection, so clients cannot pick the IP that login throttling, view counting and the
+// session list see
+func TrustProxies(r *gin.Engine, proxies string) error {
+	var trusted []string
+	for _, proxy := range strings.Split(proxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trusted = append(trusted, proxy)
+		}
+	}
+	return r.SetTrustedProxies(trusted)
+}
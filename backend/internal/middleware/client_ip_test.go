@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// clientIP returns the client IP gin reports for a request from remoteAddr with an X-Forwarded-For header
+func clientIP(t *testing.T, proxies, remoteAddr, forwardedFor string) string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	if err := TrustProxies(r, proxies); err != nil {
+		t.Fatalf("TrustProxies returned error: %v", err)
+	}
+	r.GET("/", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = remoteAddr
+	req.Header.Set("X-Forwarded-For", forwardedFor)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Body.String()
+}
+
+func TestTrustProxies_IgnoresSpoofedForwardedFor(t *testing.T) {
+	if got := clientIP(t, "", "203.0.113.5:4321", "198.51.100.1"); got != "203.0.113.5" {
+		t.Errorf("expected the connection's address without trusted proxies, got %s", got)
+	}
+	if got := clientIP(t, "10.0.0.0/8", "203.0.113.5:4321", "198.51.100.1"); got != "203.0.113.5" {
+		t.Errorf("expected the header of an untrusted peer to be ignored, got %s", got)
+	}
+}
+
+func TestTrustProxies_ReadsForwardedForFromTrustedProxy(t *testing.T) {
+	if got := clientIP(t, "10.0.0.0/8, 192.0.2.10", "10.1.2.3:4321", "198.51.100.1"); got != "198.51.100.1" {
+		t.Errorf("expected the address forwarded by the proxy, got %s", got)
+	}
+	// Entries the client prepended are skipped: the last address not added by a trusted proxy wins
+	if got := clientIP(t, "10.0.0.0/8", "10.1.2.3:4321", "1.2.3.4, 198.51.100.1"); got != "198.51.100.1" {
+		t.Errorf("expected the address the proxy saw, got %s", got)
+	}
+}
+
+func TestTrustProxies_RejectsInvalidProxy(t *testing.T) {
+	if err := TrustProxies(gin.New(), "not-an-ip"); err == nil {
+		t.Error("expected an error for an invalid proxy")
+	}
+}
@@ -0,0 +1,91 @@
+package model
+
+import "time"
+
+// Actions recorded in the admin audit log
+const (
+	AdminActionSearchUsers   = "user.search"
+	AdminActionSuspendUser   = "user.suspend"
+	AdminActionBanUser       = "user.ban"
+	AdminActionUnbanUser     = "user.unban"
+	AdminActionSetRole       = "user.set_role"
+	AdminActionRemoveVideo   = "video.remove"
+	AdminActionRemoveComment = "comment.remove"
+	AdminActionViewStats     = "stats.view"
+)
+
+// Kinds of targets of admin audit log entries
+const (
+	AdminTargetUser    = "user"
+	AdminTargetVideo   = "video"
+	AdminTargetComment = "comment"
+)
+
+// AdminAuditEntry records one action taken through the admin API
+// ActorID is NULL once the actor's account is deleted; Details describes the target,
+// such as the title of a removed video
+type AdminAuditEntry struct {
+	ID         int64     `json:"id"`
+	ActorID    *int64    `json:"actor_id"`
+	Action     string    `json:"action"`
+	TargetType string    `json:"target_type,omitempty"`
+	TargetID   *int64    `json:"target_id,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	Details    string    `json:"details,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// UserFilter narrows the user search; empty fields match every user
+// Query matches part of the email address, ignoring case
+type UserFilter struct {
+	Query  string `form:"q"`
+	Role   string `form:"role"`
+	Status string `form:"status"`
+}
+
+type SuspendUserRequest struct {
+	Until  time.Time `json:"until" binding:"required"`
+	Reason string    `json:"reason" binding:"required,max=1000"`
+}
+
+type BanUserRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+type UnbanUserRequest struct {
+	Reason string `json:"reason" binding:"max=1000"`
+}
+
+type SetRoleRequest struct {
+	Role   string `json:"role" binding:"required"`
+	Reason string `json:"reason" binding:"max=1000"`
+}
+
+type RemoveContentRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+// UserStats counts users by account status; suspensions that have ended count as active
+type UserStats struct {
+	Total     int64 `json:"total"`
+	Active    int64 `json:"active"`
+	Suspended int64 `json:"suspended"`
+	Banned    int64 `json:"banned"`
+}
+
+// VideoStats counts videos by processing status
+type VideoStats struct {
+	Total      int64 `json:"total"`
+	Uploading  int64 `json:"uploading"`
+	Processing int64 `json:"processing"`
+	Ready      int64 `json:"ready"`
+	Failed     int64 `json:"failed"`
+}
+
+// PlatformStats is the overview shown to admins; StorageBytes is everything in file
+// storage, including renditions, thumbnails and unfinished uploads
+type PlatformStats struct {
+	Users        UserStats  `json:"users"`
+	Videos       VideoStats `json:"videos"`
+	StorageBytes int64      `json:"storage_bytes"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Reasons login attempts fail
+const (
+	LoginFailureInvalidCredentials = "invalid_credentials"
+	LoginFailureInvalidMFACode     = "invalid_mfa_code"
+	LoginFailureThrottled          = "throttled" // Rejected without checking the password
+	LoginFailureAccountBlocked     = "account_blocked"
+)
+
+// LoginAttempt is the audit record of one login attempt
+// Email is the address as entered; UserID is set when it belongs to an account
+type LoginAttempt struct {
+	ID            int64     `json:"id"`
+	Email         string    `json:"email"`
+	UserID        *int64    `json:"user_id,omitempty"`
+	IPAddress     string    `json:"ip_address"`
+	UserAgent     string    `json:"user_agent"`
+	Succeeded     bool      `json:"succeeded"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// LoginFailures summarizes the failed attempts counted against an email or IP address
+type LoginFailures struct {
+	Count int
+	Last  time.Time
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// PageQuery holds the pagination query string of list endpoints
+// Offset is deprecated and only used by requests without a cursor
+type PageQuery struct {
+	Limit  int    `form:"limit"`
+	Cursor string `form:"cursor"`
+	Offset int    `form:"offset"`
+}
+
+// PageCursor is the sort key of the last item of a page: its time and ID,
+// plus whether it is pinned for lists that show pinned items first
+type PageCursor struct {
+	Pinned bool      `json:"p,omitempty"`
+	Time   time.Time `json:"t"`
+	ID     int64     `json:"id"`
+}
+
+// PageRequest is a validated page passed to the repositories: the rows after After,
+// or the rows from Offset when After is nil
+type PageRequest struct {
+	Limit  int
+	Offset int
+	After  *PageCursor
+}
+
+// Page is one page of a list with the cursor of the next page
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
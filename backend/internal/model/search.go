@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// VideoSearchRequest holds the query string of GET /api/videos/search
+type VideoSearchRequest struct {
+	Query       string `form:"q"`
+	MinDuration int64  `form:"min_duration"` // Seconds
+	MaxDuration int64  `form:"max_duration"` // Seconds
+	UploadDate  string `form:"upload_date"`  // 'hour', 'today', 'week', 'month' or 'year'
+	ChannelID   int64  `form:"channel_id"`   // User ID of the channel
+	Limit       int    `form:"limit"`
+	Cursor      string `form:"cursor"`
+}
+
+// VideoSearchParams is a validated search passed to the repository
+type VideoSearchParams struct {
+	Query         string
+	Terms         []string // Words of Query, each of which has to match
+	MinDuration   int64    // 0 for no lower bound
+	MaxDuration   int64    // 0 for no upper bound
+	UploadedAfter *time.Time
+	ChannelID     int64 // 0 for every channel
+	Limit         int
+	After         *SearchCursor // Last result of the previous page
+}
+
+// SearchCursor is the position of a result in rank order
+type SearchCursor struct {
+	Rank float64 `json:"r"`
+	ID   int64   `json:"id"`
+}
+
+type VideoSearchResult struct {
+	Video          *VideoWithProfile `json:"video"`
+	Rank           float64           `json:"rank"`
+	TitleHighlight string            `json:"title_highlight"` // HTML-escaped title with matches wrapped in <mark>
+	Snippet        string            `json:"snippet"`         // Excerpt of the description, highlighted the same way
+}
+
+type VideoSearchResponse struct {
+	Results    []*VideoSearchResult `json:"results"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+	HasMore    bool                 `json:"has_more"`
+}
+
+// Where a search suggestion comes from
+const (
+	SuggestionSourceRecent  = "recent" // The user's own recent searches
+	SuggestionSourcePopular = "popular"
+	SuggestionSourceTitle   = "title"
+	SuggestionSourceChannel = "channel"
+)
+
+type SearchSuggestion struct {
+	Text   string `json:"text"`
+	Source string `json:"source"`
+}
+
+type RecentSearch struct {
+	Query      string    `json:"query"`
+	SearchedAt time.Time `json:"searched_at"`
+}
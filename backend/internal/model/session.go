@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// Session is a signed-in device; it is one refresh token family, and only the hash
+// of the family's current refresh token is stored
+type Session struct {
+	ID               int64      `json:"id"`
+	UserID           int64      `json:"user_id"`
+	Family           string     `json:"-"`
+	RefreshTokenHash string     `json:"-"`
+	UserAgent        string     `json:"user_agent"`
+	IPAddress        string     `json:"ip_address"`
+	CreatedAt        time.Time  `json:"created_at"`
+	LastSeenAt       time.Time  `json:"last_seen_at"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RevokedAt        *time.Time `json:"-"`
+	Current          bool       `json:"current"` // Whether this is the session of the request
+}
+
+// ClientInfo describes the device a session is created or refreshed from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// AccessClaims are the claims of a validated access token
+// AccountStatus is the user's current status, so suspended and banned users can be
+// turned away while their token is still valid
+type AccessClaims struct {
+	UserID        int64
+	SessionID     int64
+	Role          string
+	AccountStatus string
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest optionally names the session to end by its refresh token,
+// so clients can log out after their access token has expired
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Thumbnail sources
+const (
+	ThumbnailSourceGenerated = "generated" // Extracted from the video while it is processed
+	ThumbnailSourceCustom    = "custom"    // Uploaded by the owner
+)
+
+// VideoThumbnail is a thumbnail the owner can choose for a video
+// The chosen one is copied to the video's thumbnail_url
+type VideoThumbnail struct {
+	ID         int64     `json:"id"`
+	VideoID    int64     `json:"video_id"`
+	URL        string    `json:"url"`
+	Source     string    `json:"source"`
+	TimeOffset float64   `json:"time_offset"` // Seconds into the video the frame was taken from
+	Selected   bool      `json:"selected"`    // Not stored; set when listing
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type SelectThumbnailRequest struct {
+	ThumbnailID int64 `json:"thumbnail_id" binding:"required"`
+}
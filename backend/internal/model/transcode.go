@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// Transcode job statuses
+const (
+	TranscodeStatusPending   = "pending"
+	TranscodeStatusRunning   = "running"
+	TranscodeStatusCompleted = "completed"
+	TranscodeStatusFailed    = "failed"
+)
+
+// TranscodeJob converts a video's source file into HLS renditions
+type TranscodeJob struct {
+	ID                int64      `json:"id"`
+	VideoID           int64      `json:"video_id"`
+	SourceURL         string     `json:"source_url"`
+	Status            string     `json:"status"`
+	Attempts          int        `json:"attempts"`
+	Error             string     `json:"error"`
+	MasterPlaylistURL string     `json:"master_playlist_url"`
+	StartedAt         *time.Time `json:"started_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// VideoRendition is one HLS quality level of a video
+type VideoRendition struct {
+	ID          int64     `json:"id"`
+	VideoID     int64     `json:"video_id"`
+	Name        string    `json:"name"` // e.g. "720p"
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	Bandwidth   int64     `json:"bandwidth"` // bits per second
+	PlaylistURL string    `json:"playlist_url"`
+	SegmentURLs []string  `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
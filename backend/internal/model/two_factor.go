@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// TwoFactorStatus describes a user's two-factor authentication
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+// TOTPSetup is returned when TOTP enrollment starts; ProvisioningURI is shown as a QR code
+// and Secret is for entering the key by hand
+type TOTPSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// RecoveryCodes are shown to the user once, when they are generated
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+type ConfirmTOTPRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableTwoFactorRequest needs the account's password, if it has one, and a code
+// from the authenticator app or a recovery code
+type DisableTwoFactorRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type RegenerateRecoveryCodesRequest struct {
+	Code string `json:"code" binding:"required"`
+}
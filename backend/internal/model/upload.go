@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// Upload session statuses
+const (
+	UploadStatusUploading  = "uploading"  // accepting chunks
+	UploadStatusCompleting = "completing" // parts are being composed into the video file
+	UploadStatusCompleted  = "completed"  // video row created
+)
+
+// UploadSession tracks a resumable upload of one video file
+type UploadSession struct {
+	ID           string    `json:"id"`
+	UserID       int64     `json:"user_id"`
+	Filename     string    `json:"filename"`
+	ContentType  string    `json:"content_type"`
+	UploadLength int64     `json:"upload_length"` // Total file size in bytes
+	UploadOffset int64     `json:"upload_offset"` // Bytes received so far
+	PartCount    int       `json:"part_count"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Status       string    `json:"status"`
+	VideoID      *int64    `json:"video_id"` // The video the file is uploaded for
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// UploadPart is one stored chunk of an upload session
+type UploadPart struct {
+	UploadID   string    `json:"upload_id"`
+	PartNumber int       `json:"part_number"` // 1-based, in upload order
+	PartOffset int64     `json:"part_offset"` // Offset of the first byte in the file
+	Size       int64     `json:"size"`
+	StorageKey string    `json:"storage_key"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CreateUploadRequest struct {
+	Filename    string     `json:"filename" binding:"required"`
+	ContentType string     `json:"content_type"`
+	Size        int64      `json:"size" binding:"required,gt=0"`
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description"`
+	Visibility  string     `json:"visibility"` // Applied to the video; default: 'public'
+	PublishAt   *time.Time `json:"publish_at"`
+}
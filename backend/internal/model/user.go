@@ -2,12 +2,33 @@ package model
 
 import "time"
 
+// Roles of users, from least to most privileged; each role can do everything the previous one can
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Account statuses; suspended accounts are active again once SuspendedUntil has passed
+const (
+	AccountStatusActive    = "active"
+	AccountStatusSuspended = "suspended"
+	AccountStatusBanned    = "banned"
+)
+
 type User struct {
-	ID           int64     `json:"id"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID              int64      `json:"id"`
+	Email           string     `json:"email"`
+	Role            string     `json:"role"`
+	Status          string     `json:"status"`
+	SuspendedUntil  *time.Time `json:"suspended_until,omitempty"` // Set while Status is suspended
+	PasswordHash    string     `json:"-"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	TOTPSecret      string     `json:"-"` // Set from the start of enrollment; empty without TOTP
+	TOTPEnabledAt   *time.Time `json:"totp_enabled_at"`
+	TOTPLastStep    int64      `json:"-"` // Period of the last accepted code
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
 type RegisterRequest struct {
@@ -20,7 +41,40 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// MFALoginRequest completes a login that requires a second factor
+// Code is a code from the authenticator app or a recovery code
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// AuthResponse is returned when a session starts or is refreshed
+// Token is the short-lived access token; RefreshToken replaces the one used to refresh.
+// When the account has two-factor authentication, login returns only MFARequired and
+// MFAToken, and ExpiresIn is the lifetime of the MFA token
 type AuthResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in"` // Access token lifetime in seconds
+	User         *User  `json:"user,omitempty"`
+	MFARequired  bool   `json:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
 }
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// UserIdentity links an account at an OpenID Connect provider to a user
+type UserIdentity struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	Provider    string    `json:"provider"`
+	Subject     string    `json:"-"`
+	Email       string    `json:"email"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+// OIDCLoginState is a sign-in started at a provider, found again by the hash of its state
+// LinkUserID is set when a signed-in user started linking the provider account instead
+type OIDCLoginState struct {
+	StateHash    string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	LinkUserID   *int64
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// OIDCAuthorization is where to send the user to sign in at a provider
+// The client keeps the state to check that the provider redirects back to the sign-in it started
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+}
+
+// OIDCCallbackRequest carries the code and state the provider redirected back with
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Purposes of user tokens
+const (
+	UserTokenPasswordReset     = "password_reset"
+	UserTokenEmailVerification = "email_verification"
+	UserTokenMFAChallenge      = "mfa_challenge"
+)
+
+// UserToken is a single-use token sent to a user by email, or handed out by login while
+// the second factor is pending; only its hash is stored
+type UserToken struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id"`
+	Purpose   string     `json:"purpose"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	Attempts  int        `json:"attempts"` // Wrong codes entered for an MFA challenge
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -2,17 +2,47 @@ package model
 
 import "time"
 
+// Video statuses; only ready videos are shown to users other than the owner
+const (
+	VideoStatusUploading  = "uploading"
+	VideoStatusProcessing = "processing"
+	VideoStatusReady      = "ready"
+	VideoStatusFailed     = "failed"
+)
+
+// Video visibilities; unlisted videos can be opened by ID but are left out of lists and feeds
+// A scheduled video is private with a PublishAt and becomes public at that time
+const (
+	VideoVisibilityPublic   = "public"
+	VideoVisibilityUnlisted = "unlisted"
+	VideoVisibilityPrivate  = "private"
+)
+
 type Video struct {
-	ID           int64     `json:"id"`
-	UserID       int64     `json:"user_id"`
-	Title        string    `json:"title"`
-	Description  string    `json:"description"`
-	VideoURL     string    `json:"video_url"`
-	ThumbnailURL string    `json:"thumbnail_url"`
-	Duration     int64     `json:"duration"` // Duration in seconds
-	ViewCount    int64     `json:"view_count"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           int64  `json:"id"`
+	UserID       int64  `json:"user_id"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	VideoURL     string `json:"video_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	Duration     int64  `json:"duration"` // Duration in seconds
+	ViewCount    int64  `json:"view_count"`
+	LikeCount    int64  `json:"like_count"`
+	DislikeCount int64  `json:"dislike_count"`
+	// Media metadata probed from the video file; zero until it has been probed
+	Container     string     `json:"container"`
+	VideoCodec    string     `json:"video_codec"`
+	AudioCodec    string     `json:"audio_codec"`
+	Width         int        `json:"width"`
+	Height        int        `json:"height"`
+	Bitrate       int64      `json:"bitrate"`    // Bits per second
+	FrameRate     float64    `json:"frame_rate"` // Frames per second
+	Status        string     `json:"status"`
+	FailureReason string     `json:"failure_reason,omitempty"` // Set when Status is failed
+	Visibility    string     `json:"visibility"`
+	PublishAt     *time.Time `json:"publish_at,omitempty"` // Set while the video is scheduled
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 type VideoWithProfile struct {
@@ -25,21 +55,44 @@ type VideoWithProfile struct {
 	Duration     int64     `json:"duration"` // Duration in seconds
 	ViewCount    int64     `json:"view_count"`
 	LikeCount    int64     `json:"like_count"` // Total number of likes
+	DislikeCount int64     `json:"dislike_count"`
+	Width        int       `json:"width,omitempty"`
+	Height       int       `json:"height,omitempty"`
+	Status       string    `json:"status,omitempty"`
+	Visibility   string    `json:"visibility,omitempty"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	Profile      *Profile  `json:"profile"`
+	// HLS master playlist and its renditions; empty until transcoding has finished
+	HLSURL     string            `json:"hls_url,omitempty"`
+	Renditions []*VideoRendition `json:"renditions,omitempty"`
+	// Where a signed-in viewer left off; 0 once they finished the video, nil without progress
+	ResumePositionSeconds *float64 `json:"resume_position_seconds,omitempty"`
 }
 
 type CreateVideoRequest struct {
-	Title        string `json:"title" binding:"required"`
-	Description  string `json:"description"`
-	VideoURL     string `json:"video_url"`
-	ThumbnailURL string `json:"thumbnail_url"`
+	Title        string     `json:"title" binding:"required"`
+	Description  string     `json:"description"`
+	VideoURL     string     `json:"video_url"`
+	ThumbnailURL string     `json:"thumbnail_url"`
+	Visibility   string     `json:"visibility"` // default: 'public'
+	PublishAt    *time.Time `json:"publish_at"` // schedules a public release
 }
 
 type UpdateVideoRequest struct {
-	Title        string `json:"title"`
-	Description  string `json:"description"`
-	VideoURL     string `json:"video_url"`
-	ThumbnailURL string `json:"thumbnail_url"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	VideoURL     string     `json:"video_url"`
+	ThumbnailURL string     `json:"thumbnail_url"`
+	Visibility   string     `json:"visibility"`
+	PublishAt    *time.Time `json:"publish_at"`
+}
+
+// VideoStatus is what the owner polls while a video is being uploaded and processed
+type VideoStatus struct {
+	VideoID       int64         `json:"video_id"`
+	Status        string        `json:"status"`
+	FailureReason string        `json:"failure_reason,omitempty"`
+	Transcode     *TranscodeJob `json:"transcode,omitempty"` // Latest transcode job, if any
+	UpdatedAt     time.Time     `json:"updated_at"`
 }
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Video reaction types
+const (
+	VideoReactionLike    = "like"
+	VideoReactionDislike = "dislike"
+)
+
+type VideoReaction struct {
+	ID        int64     `json:"id"`
+	VideoID   int64     `json:"video_id"`
+	UserID    int64     `json:"user_id"`
+	LikeType  string    `json:"like_type"` // "like" or "dislike"
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LikedVideo is an entry of the user's liked videos
+type LikedVideo struct {
+	VideoID int64             `json:"video_id"`
+	LikedAt time.Time         `json:"liked_at"`
+	Video   *VideoWithProfile `json:"video"`
+}
+
+type ReactVideoRequest struct {
+	LikeType string `json:"like_type"` // "like" or "dislike"; default: "like"
+}
@@ -0,0 +1,17 @@
+package model
+
+// RecordViewRequest is a playback event reported by the player while a video is watched
+// SessionID identifies the playback session of anonymous viewers; WatchedSeconds is
+// how long the video has been played in this session so far
+type RecordViewRequest struct {
+	SessionID      string  `json:"session_id" binding:"max=128"`
+	WatchedSeconds float64 `json:"watched_seconds" binding:"gte=0"`
+}
+
+// Viewer identifies who reported a view: a signed-in user, or an anonymous
+// viewer by IP address and playback session
+type Viewer struct {
+	UserID    int64
+	IP        string
+	SessionID string
+}
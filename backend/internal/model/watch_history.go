@@ -3,13 +3,29 @@ package model
 import "time"
 
 type WatchHistory struct {
-	ID        int64     `json:"id"`
-	UserID    int64     `json:"user_id"`
-	VideoID   int64     `json:"video_id"`
-	WatchedAt time.Time `json:"watched_at"`
-	Video     *VideoWithProfile `json:"video,omitempty"`
+	ID              int64             `json:"id"`
+	UserID          int64             `json:"user_id"`
+	VideoID         int64             `json:"video_id"`
+	WatchedAt       time.Time         `json:"watched_at"`
+	PositionSeconds float64           `json:"position_seconds"`
+	ProgressPercent float64           `json:"progress_percent"`
+	Video           *VideoWithProfile `json:"video,omitempty"`
 }
 
 type AddToHistoryRequest struct {
 	VideoID int64 `json:"video_id" binding:"required"`
 }
+
+// SaveProgressRequest is a playback heartbeat with the player's current position
+type SaveProgressRequest struct {
+	PositionSeconds float64 `json:"position_seconds" binding:"gte=0"`
+}
+
+// WatchProgress is how far a user has watched a video
+// ProgressPercent is 0 for videos whose duration is not known yet
+type WatchProgress struct {
+	VideoID         int64     `json:"video_id"`
+	PositionSeconds float64   `json:"position_seconds"`
+	ProgressPercent float64   `json:"progress_percent"`
+	WatchedAt       time.Time `json:"watched_at"`
+}
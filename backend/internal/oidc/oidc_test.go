@@ -0,0 +1,152 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/yukito/video-platform/internal/oidc/oidctest"
+	"golang.org/x/oauth2"
+)
+
+const redirectURL = "https://app.example.com/oauth/callback"
+
+func newProvider(t *testing.T, idp *oidctest.Server, clientID string) *Provider {
+	t.Helper()
+	p, err := NewProvider(Config{
+		Name:         "test",
+		Issuer:       idp.Issuer(),
+		ClientID:     clientID,
+		ClientSecret: "secret",
+		RedirectURL:  redirectURL,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewProvider returned error: %v", err)
+	}
+	return p
+}
+
+// signIn runs the authorization code flow up to the code
+func signIn(t *testing.T, idp *oidctest.Server, p *Provider, nonce, verifier string) string {
+	t.Helper()
+	authURL, err := p.AuthCodeURL(context.Background(), "state-1", nonce, verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL returned error: %v", err)
+	}
+	code, state, err := idp.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize returned error: %v", err)
+	}
+	if state != "state-1" {
+		t.Fatalf("expected the state back, got %q", state)
+	}
+	return code
+}
+
+func TestExchange_ReturnsVerifiedClaims(t *testing.T) {
+	idp := oidctest.NewServer("client", "secret")
+	defer idp.Close()
+	idp.SetUser(oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true, Name: "Alice"})
+	p := newProvider(t, idp, "client")
+
+	verifier := oauth2.GenerateVerifier()
+	code := signIn(t, idp, p, "nonce-1", verifier)
+
+	claims, err := p.Exchange(context.Background(), code, verifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange returned error: %v", err)
+	}
+	want := Claims{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true, Name: "Alice"}
+	if *claims != want {
+		t.Errorf("expected %+v, got %+v", want, *claims)
+	}
+
+	// Codes work once
+	if _, err := p.Exchange(context.Background(), code, verifier, "nonce-1"); err == nil {
+		t.Error("expected a used code to be rejected")
+	}
+}
+
+func TestExchange_RequiresMatchingVerifier(t *testing.T) {
+	idp := oidctest.NewServer("client", "secret")
+	defer idp.Close()
+	idp.SetUser(oidctest.User{Subject: "sub-1"})
+	p := newProvider(t, idp, "client")
+
+	code := signIn(t, idp, p, "nonce-1", oauth2.GenerateVerifier())
+	if _, err := p.Exchange(context.Background(), code, oauth2.GenerateVerifier(), "nonce-1"); err == nil {
+		t.Error("expected the exchange to fail with another verifier")
+	}
+}
+
+func TestExchange_RejectsTokensWithWrongClaims(t *testing.T) {
+	idp := oidctest.NewServer("client", "secret")
+	defer idp.Close()
+	idp.SetUser(oidctest.User{Subject: "sub-1"})
+	p := newProvider(t, idp, "client")
+
+	tests := map[string]struct {
+		tamper func(jwt.MapClaims)
+		want   error
+	}{
+		"nonce":    {func(c jwt.MapClaims) { c["nonce"] = "replayed-nonce" }, ErrNonceMismatch},
+		"no nonce": {func(c jwt.MapClaims) { delete(c, "nonce") }, ErrNonceMismatch},
+		"audience": {func(c jwt.MapClaims) { c["aud"] = "other-client" }, ErrInvalidIDToken},
+		"issuer":   {func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }, ErrInvalidIDToken},
+		"expired":  {func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }, ErrInvalidIDToken},
+		"subject":  {func(c jwt.MapClaims) { delete(c, "sub") }, ErrInvalidIDToken},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			idp.Tamper(tt.tamper)
+			defer idp.Tamper(nil)
+
+			verifier := oauth2.GenerateVerifier()
+			code := signIn(t, idp, p, "nonce-1", verifier)
+			if _, err := p.Exchange(context.Background(), code, verifier, "nonce-1"); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestVerifyIDToken_FetchesRotatedKeys(t *testing.T) {
+	idp := oidctest.NewServer("client", "secret")
+	defer idp.Close()
+	idp.SetUser(oidctest.User{Subject: "sub-1"})
+	p := newProvider(t, idp, "client")
+	now := time.Now()
+	p.now = func() time.Time { return now }
+
+	verifier := oauth2.GenerateVerifier()
+	if _, err := p.Exchange(context.Background(), signIn(t, idp, p, "n", verifier), verifier, "n"); err != nil {
+		t.Fatalf("Exchange returned error: %v", err)
+	}
+
+	// Keys were just fetched, so a new key ID is not looked up again right away
+	idp.RotateKey()
+	verifier = oauth2.GenerateVerifier()
+	if _, err := p.Exchange(context.Background(), signIn(t, idp, p, "n", verifier), verifier, "n"); !errors.Is(err, ErrInvalidIDToken) {
+		t.Errorf("expected ErrInvalidIDToken right after fetching keys, got %v", err)
+	}
+
+	now = now.Add(minKeyRefreshInterval)
+	verifier = oauth2.GenerateVerifier()
+	if _, err := p.Exchange(context.Background(), signIn(t, idp, p, "n", verifier), verifier, "n"); err != nil {
+		t.Errorf("expected the rotated key to be fetched, got %v", err)
+	}
+}
+
+func TestVerifyIDToken_RejectsForgedToken(t *testing.T) {
+	idp := oidctest.NewServer("client", "secret")
+	defer idp.Close()
+	p := newProvider(t, idp, "client")
+
+	for _, token := range []string{"", "not-a-jwt", "eyJhbGciOiJub25lIn0.eyJzdWIiOiJzdWItMSJ9."} {
+		if _, err := p.VerifyIDToken(context.Background(), token, "n"); !errors.Is(err, ErrInvalidIDToken) {
+			t.Errorf("VerifyIDToken(%q): expected ErrInvalidIDToken, got %v", token, err)
+		}
+	}
+}
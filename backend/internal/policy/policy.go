@@ -0,0 +1,100 @@
+// Package policy decides what users may do with content. Services ask it instead of
+// comparing owner IDs themselves, so the rules for owners, moderators and admins
+// live in one place.
+package policy
+
+import "github.com/yukito/video-platform/internal/model"
+
+// Actor is the user performing an action; the zero Actor is an anonymous visitor
+type Actor struct {
+	UserID int64
+	Role   string
+}
+
+// roleRanks orders the roles; unknown roles rank below every known one
+var roleRanks = map[string]int{
+	model.RoleUser:      1,
+	model.RoleModerator: 2,
+	model.RoleAdmin:     3,
+}
+
+// ValidRole reports whether role is one of the known roles
+func ValidRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// HasRole reports whether role grants at least the minimum role
+func HasRole(role, minimum string) bool {
+	rank, ok := roleRanks[role]
+	return ok && rank >= roleRanks[minimum]
+}
+
+// Is reports whether the actor is signed in with at least the minimum role
+func (a Actor) Is(minimum string) bool {
+	return a.UserID != 0 && HasRole(a.Role, minimum)
+}
+
+// owns reports whether the actor is the signed-in user ownerID
+func (a Actor) owns(ownerID int64) bool {
+	return a.UserID != 0 && a.UserID == ownerID
+}
+
+// CanWatchVideo reports whether the actor can open the video: ready videos that are
+// not private, and any of the actor's own videos
+func CanWatchVideo(a Actor, video *model.Video) bool {
+	if a.owns(video.UserID) {
+		return true
+	}
+	return video.Status == model.VideoStatusReady && video.Visibility != model.VideoVisibilityPrivate
+}
+
+// CanEditVideo reports whether the actor can change the video's details and files
+func CanEditVideo(a Actor, video *model.Video) bool {
+	return a.owns(video.UserID)
+}
+
+// CanDeleteVideo reports whether the actor can delete the video: its owner or a moderator
+func CanDeleteVideo(a Actor, video *model.Video) bool {
+	return a.owns(video.UserID) || a.Is(model.RoleModerator)
+}
+
+// CanEditComment reports whether the actor can change the comment's text
+func CanEditComment(a Actor, comment *model.Comment) bool {
+	return a.owns(comment.UserID)
+}
+
+// CanDeleteComment reports whether the actor can delete the comment: its author or a moderator
+func CanDeleteComment(a Actor, comment *model.Comment) bool {
+	return a.owns(comment.UserID) || a.Is(model.RoleModerator)
+}
+
+// CanCurateComments reports whether the actor can pin and heart comments on the video,
+// which only its creator does
+func CanCurateComments(a Actor, video *model.Video) bool {
+	return a.owns(video.UserID)
+}
+
+// CanEditPlaylist reports whether the actor can change, delete or add videos to the
+// playlist, which only its owner does
+func CanEditPlaylist(a Actor, playlist *model.Playlist) bool {
+	return a.owns(playlist.UserID)
+}
+
+// CanManageUser reports whether the actor can suspend the user or lift a suspension:
+// moderators act on plain users and admins on moderators too, but nobody on themselves
+// or on users with the same role
+func CanManageUser(a Actor, target *model.User) bool {
+	return a.Is(model.RoleModerator) && a.UserID != target.ID && roleRanks[a.Role] > roleRanks[target.Role]
+}
+
+// CanBanUser reports whether the actor can ban the user or lift a ban, which only admins do
+func CanBanUser(a Actor, target *model.User) bool {
+	return a.Is(model.RoleAdmin) && CanManageUser(a, target)
+}
+
+// CanSetRole reports whether the actor can change the user's role: admins, for anyone
+// but themselves, so the last admin cannot demote themselves by accident
+func CanSetRole(a Actor, target *model.User) bool {
+	return a.Is(model.RoleAdmin) && a.UserID != target.ID
+}
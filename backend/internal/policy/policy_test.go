@@ -0,0 +1,111 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/yukito/video-platform/internal/model"
+)
+
+func TestHasRole_IsHierarchical(t *testing.T) {
+	tests := []struct {
+		role, minimum string
+		want          bool
+	}{
+		{model.RoleUser, model.RoleUser, true},
+		{model.RoleUser, model.RoleModerator, false},
+		{model.RoleModerator, model.RoleUser, true},
+		{model.RoleModerator, model.RoleAdmin, false},
+		{model.RoleAdmin, model.RoleModerator, true},
+		{"", model.RoleUser, false},
+		{"owner", model.RoleUser, false},
+	}
+	for _, tt := range tests {
+		if got := HasRole(tt.role, tt.minimum); got != tt.want {
+			t.Errorf("HasRole(%q, %q) = %v, want %v", tt.role, tt.minimum, got, tt.want)
+		}
+	}
+}
+
+func TestContentRules(t *testing.T) {
+	owner := Actor{UserID: 1, Role: model.RoleUser}
+	other := Actor{UserID: 2, Role: model.RoleUser}
+	moderator := Actor{UserID: 3, Role: model.RoleModerator}
+	admin := Actor{UserID: 4, Role: model.RoleAdmin}
+	anonymous := Actor{}
+
+	video := &model.Video{UserID: 1, Status: model.VideoStatusReady, Visibility: model.VideoVisibilityPrivate}
+	comment := &model.Comment{UserID: 1}
+	playlist := &model.Playlist{UserID: 1}
+
+	tests := []struct {
+		name  string
+		check func(Actor) bool
+		want  map[Actor]bool
+	}{
+		{"CanWatchVideo", func(a Actor) bool { return CanWatchVideo(a, video) },
+			map[Actor]bool{owner: true, other: false, moderator: false, admin: false, anonymous: false}},
+		{"CanEditVideo", func(a Actor) bool { return CanEditVideo(a, video) },
+			map[Actor]bool{owner: true, other: false, moderator: false, admin: false, anonymous: false}},
+		{"CanDeleteVideo", func(a Actor) bool { return CanDeleteVideo(a, video) },
+			map[Actor]bool{owner: true, other: false, moderator: true, admin: true, anonymous: false}},
+		{"CanEditComment", func(a Actor) bool { return CanEditComment(a, comment) },
+			map[Actor]bool{owner: true, other: false, moderator: false, admin: false, anonymous: false}},
+		{"CanDeleteComment", func(a Actor) bool { return CanDeleteComment(a, comment) },
+			map[Actor]bool{owner: true, other: false, moderator: true, admin: true, anonymous: false}},
+		{"CanCurateComments", func(a Actor) bool { return CanCurateComments(a, video) },
+			map[Actor]bool{owner: true, other: false, moderator: false, admin: false, anonymous: false}},
+		{"CanEditPlaylist", func(a Actor) bool { return CanEditPlaylist(a, playlist) },
+			map[Actor]bool{owner: true, other: false, moderator: false, admin: false, anonymous: false}},
+	}
+	for _, tt := range tests {
+		for actor, want := range tt.want {
+			if got := tt.check(actor); got != want {
+				t.Errorf("%s(%+v) = %v, want %v", tt.name, actor, got, want)
+			}
+		}
+	}
+}
+
+func TestCanWatchVideo_PublicReadyVideo(t *testing.T) {
+	video := &model.Video{UserID: 1, Status: model.VideoStatusReady, Visibility: model.VideoVisibilityPublic}
+	if !CanWatchVideo(Actor{}, video) {
+		t.Error("anonymous visitors should watch public videos")
+	}
+	video.Status = model.VideoStatusProcessing
+	if CanWatchVideo(Actor{UserID: 2}, video) {
+		t.Error("videos still processing should only be visible to their owner")
+	}
+}
+
+func TestUserManagementRules(t *testing.T) {
+	moderator := Actor{UserID: 3, Role: model.RoleModerator}
+	admin := Actor{UserID: 4, Role: model.RoleAdmin}
+
+	user := &model.User{ID: 1, Role: model.RoleUser}
+	otherModerator := &model.User{ID: 5, Role: model.RoleModerator}
+	otherAdmin := &model.User{ID: 6, Role: model.RoleAdmin}
+	self := &model.User{ID: 4, Role: model.RoleAdmin}
+
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"moderator manages user", CanManageUser(moderator, user), true},
+		{"moderator manages moderator", CanManageUser(moderator, otherModerator), false},
+		{"admin manages moderator", CanManageUser(admin, otherModerator), true},
+		{"admin manages admin", CanManageUser(admin, otherAdmin), false},
+		{"user manages user", CanManageUser(Actor{UserID: 2, Role: model.RoleUser}, user), false},
+		{"moderator bans user", CanBanUser(moderator, user), false},
+		{"admin bans user", CanBanUser(admin, user), true},
+		{"admin bans self", CanBanUser(admin, self), false},
+		{"moderator sets role", CanSetRole(moderator, user), false},
+		{"admin sets role of admin", CanSetRole(admin, otherAdmin), true},
+		{"admin sets own role", CanSetRole(admin, self), false},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
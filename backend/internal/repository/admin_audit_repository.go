@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/database"
+	"github.com/yukito/video-platform/internal/model"
+)
+
+// AdminAuditStore persists the admin audit log; entries are never changed or deleted
+// The admin actions that change data are made here too, each in one transaction with
+// its entry, so neither is saved without the other
+// AdminAuditRepository is the PostgreSQL implementation
+type AdminAuditStore interface {
+	Create(ctx context.Context, entry *model.AdminAuditEntry) error
+	List(ctx context.Context, page model.PageRequest) ([]*model.AdminAuditEntry, error)
+	SetUserStatus(ctx context.Context, userID int64, status string, suspendedUntil *time.Time, entry *model.AdminAuditEntry) error
+	SetUserRole(ctx context.Context, userID int64, role string, entry *model.AdminAuditEntry) error
+	DeleteVideo(ctx context.Context, videoID int64, entry *model.AdminAuditEntry) error
+	DeleteComment(ctx context.Context, commentID int64, entry *model.AdminAuditEntry) error
+}
+
+var _ AdminAuditStore = (*AdminAuditRepository)(nil)
+
+type AdminAuditRepository struct {
+	db *database.Database
+}
+
+func NewAdminAuditRepository(db *database.Database) *AdminAuditRepository {
+	return &AdminAuditRepository{db: db}
+}
+
+// Create records an entry at its CreatedAt
+func (r *AdminAuditRepository) Create(ctx context.Context, entry *model.AdminAuditEntry) error {
+	return insertAuditEntry(ctx, r.db.Pool, entry)
+}
+
+// SetUserStatus changes the account status and records entry
+// suspendedUntil is nil unless the status is suspended
+func (r *AdminAuditRepository) SetUserStatus(ctx context.Context, userID int64, status string, suspendedUntil *time.Time, entry *model.AdminAuditEntry) error {
+	return r.record(ctx, entry, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			UPDATE users SET status = $2, suspended_until = $3, updated_at = NOW()
+			WHERE id = $1
+		`, userID, status, suspendedUntil)
+		if err != nil {
+			return fmt.Errorf("failed to set account status: %w", err)
+		}
+		return nil
+	})
+}
+
+// SetUserRole changes the user's role and records entry
+func (r *AdminAuditRepository) SetUserRole(ctx context.Context, userID int64, role string, entry *model.AdminAuditEntry) error {
+	return r.record(ctx, entry, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			UPDATE users SET role = $2, updated_at = NOW()
+			WHERE id = $1
+		`, userID, role)
+		if err != nil {
+			return fmt.Errorf("failed to set role: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteVideo deletes the video and records entry
+func (r *AdminAuditRepository) DeleteVideo(ctx context.Context, videoID int64, entry *model.AdminAuditEntry) error {
+	return r.record(ctx, entry, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			DELETE FROM videos WHERE id = $1
+		`, videoID)
+		if err != nil {
+			return fmt.Errorf("failed to delete video: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteComment deletes the comment and records entry
+func (r *AdminAuditRepository) DeleteComment(ctx context.Context, commentID int64, entry *model.AdminAuditEntry) error {
+	return r.record(ctx, entry, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			DELETE FROM comments WHERE id = $1
+		`, commentID)
+		if err != nil {
+			return fmt.Errorf("failed to delete comment: %w", err)
+		}
+		return nil
+	})
+}
+
+// record makes change and inserts entry in one transaction
+func (r *AdminAuditRepository) record(ctx context.Context, entry *model.AdminAuditEntry, change func(tx pgx.Tx) error) error {
+	return pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		if err := change(tx); err != nil {
+			return err
+		}
+		return insertAuditEntry(ctx, tx, entry)
+	})
+}
+
+// rowQuerier is the pool or a transaction
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// insertAuditEntry inserts entry at its CreatedAt
+func insertAuditEntry(ctx context.Context, q rowQuerier, entry *model.AdminAuditEntry) error {
+	err := q.QueryRow(ctx, `
+		INSERT INTO admin_audit_log (actor_id, action, target_type, target_id, reason, details, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, entry.ActorID, entry.Action, entry.TargetType, entry.TargetID, entry.Reason,
+		entry.Details, entry.CreatedAt).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed to record admin action: %w", err)
+	}
+	return nil
+}
+
+// List returns the entries, newest first; the page cursor holds the time and the entry ID
+func (r *AdminAuditRepository) List(ctx context.Context, page model.PageRequest) ([]*model.AdminAuditEntry, error) {
+	order := keyset{time: "created_at", id: "id", desc: true}
+	var args []any
+	query := `
+		SELECT id, actor_id, action, target_type, target_id, reason, details, created_at
+		FROM admin_audit_log
+		WHERE ` + order.after(page.After, &args) + `
+		ORDER BY ` + order.orderBy() + `
+		` + limitOffset(page, &args)
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list admin actions: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*model.AdminAuditEntry{}
+	for rows.Next() {
+		var entry model.AdminAuditEntry
+		err := rows.Scan(&entry.ID, &entry.ActorID, &entry.Action, &entry.TargetType, &entry.TargetID,
+			&entry.Reason, &entry.Details, &entry.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan admin action: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, rows.Err()
+}
@@ -8,6 +8,24 @@ import (
 	"github.com/yukito/video-platform/internal/model"
 )
 
+// CommentStore persists comments and comment likes
+// CommentRepository is the PostgreSQL implementation
+type CommentStore interface {
+	Create(ctx context.Context, comment *model.Comment) (*model.Comment, error)
+	FindByID(ctx context.Context, id int64) (*model.Comment, error)
+	FindByVideoIDWithProfile(ctx context.Context, videoID int64, userID *int64, page model.PageRequest) ([]*model.CommentWithProfile, error)
+	FindRepliesByParentIDWithProfile(ctx context.Context, parentCommentID int64, userID *int64, page model.PageRequest) ([]*model.CommentWithProfile, error)
+	Update(ctx context.Context, comment *model.Comment) (*model.Comment, error)
+	Delete(ctx context.Context, id int64) error
+	PinComment(ctx context.Context, commentID int64, isPinned bool) error
+	SetCreatorLiked(ctx context.Context, commentID int64, isCreatorLiked bool) error
+	LikeComment(ctx context.Context, commentID, userID int64, likeType string) error
+	UnlikeComment(ctx context.Context, commentID, userID int64) error
+	GetCommentCount(ctx context.Context, videoID int64) (int64, error)
+}
+
+var _ CommentStore = (*CommentRepository)(nil)
+
 type CommentRepository struct {
 	db *database.Database
 }
@@ -63,8 +81,13 @@ func (r *CommentRepository) FindByID(ctx context.Context, id int64) (*model.Comm
 	return comment, nil
 }
 
-// FindByVideoIDWithProfile returns paginated top-level comments with profile info
-func (r *CommentRepository) FindByVideoIDWithProfile(ctx context.Context, videoID int64, userID *int64, limit, offset int) ([]*model.CommentWithProfile, error) {
+// FindByVideoIDWithProfile returns paginated top-level comments with profile info, pinned comments first
+func (r *CommentRepository) FindByVideoIDWithProfile(ctx context.Context, videoID int64, userID *int64, page model.PageRequest) ([]*model.CommentWithProfile, error) {
+	order := keyset{pinned: "c.is_pinned", time: "c.created_at", id: "c.id", desc: true}
+	args := []any{videoID, int64(0)}
+	if userID != nil {
+		args[1] = *userID
+	}
 	query := `
 		SELECT
 			c.id, c.video_id, c.user_id, c.parent_comment_id, c.content,
@@ -78,18 +101,14 @@ func (r *CommentRepository) FindByVideoIDWithProfile(ctx context.Context, videoI
 		LEFT JOIN videos v ON c.video_id = v.id
 		LEFT JOIN comment_likes cl ON c.id = cl.comment_id AND cl.user_id = $2
 		WHERE c.video_id = $1 AND c.parent_comment_id IS NULL
-		ORDER BY c.is_pinned DESC, c.created_at DESC
-		LIMIT $3 OFFSET $4
-	`
+			AND ` + order.after(page.After, &args) + `
+		ORDER BY ` + order.orderBy() + `
+		` + limitOffset(page, &args)
 
 	var rows interface{ Close() }
 	var err error
 
-	if userID != nil {
-		rows, err = r.db.Pool.Query(ctx, query, videoID, *userID, limit, offset)
-	} else {
-		rows, err = r.db.Pool.Query(ctx, query, videoID, 0, limit, offset)
-	}
+	rows, err = r.db.Pool.Query(ctx, query, args...)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to find comments: %w", err)
@@ -144,8 +163,13 @@ func (r *CommentRepository) FindByVideoIDWithProfile(ctx context.Context, videoI
 	return comments, nil
 }
 
-// FindRepliesByParentIDWithProfile returns paginated replies for a parent comment
-func (r *CommentRepository) FindRepliesByParentIDWithProfile(ctx context.Context, parentCommentID int64, userID *int64, limit, offset int) ([]*model.CommentWithProfile, error) {
+// FindRepliesByParentIDWithProfile returns paginated replies for a parent comment, oldest first
+func (r *CommentRepository) FindRepliesByParentIDWithProfile(ctx context.Context, parentCommentID int64, userID *int64, page model.PageRequest) ([]*model.CommentWithProfile, error) {
+	order := keyset{time: "c.created_at", id: "c.id"}
+	args := []any{parentCommentID, int64(0)}
+	if userID != nil {
+		args[1] = *userID
+	}
 	query := `
 		SELECT
 			c.id, c.video_id, c.user_id, c.parent_comment_id, c.content,
@@ -159,18 +183,14 @@ func (r *CommentRepository) FindRepliesByParentIDWithProfile(ctx context.Context
 		LEFT JOIN videos v ON c.video_id = v.id
 		LEFT JOIN comment_likes cl ON c.id = cl.comment_id AND cl.user_id = $2
 		WHERE c.parent_comment_id = $1
-		ORDER BY c.created_at ASC
-		LIMIT $3 OFFSET $4
-	`
+			AND ` + order.after(page.After, &args) + `
+		ORDER BY ` + order.orderBy() + `
+		` + limitOffset(page, &args)
 
 	var rows interface{ Close() }
 	var err error
 
-	if userID != nil {
-		rows, err = r.db.Pool.Query(ctx, query, parentCommentID, *userID, limit, offset)
-	} else {
-		rows, err = r.db.Pool.Query(ctx, query, parentCommentID, 0, limit, offset)
-	}
+	rows, err = r.db.Pool.Query(ctx, query, args...)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to find replies: %w", err)
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yukito/video-platform/internal/database"
+	"github.com/yukito/video-platform/internal/model"
+)
+
+// LoginAttemptStore persists the audit record of login attempts and counts recent failures
+// Attempts rejected by throttling are recorded but not counted as failures
+// LoginAttemptRepository is the PostgreSQL implementation
+type LoginAttemptStore interface {
+	Create(ctx context.Context, attempt *model.LoginAttempt) error
+	CountFailuresByEmail(ctx context.Context, email string, since time.Time) (*model.LoginFailures, error)
+	CountFailuresByIP(ctx context.Context, ipAddress string, since time.Time) (*model.LoginFailures, error)
+}
+
+var _ LoginAttemptStore = (*LoginAttemptRepository)(nil)
+
+type LoginAttemptRepository struct {
+	db *database.Database
+}
+
+func NewLoginAttemptRepository(db *database.Database) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// Create records an attempt at its CreatedAt, so its time matches the clock that throttles logins
+func (r *LoginAttemptRepository) Create(ctx context.Context, attempt *model.LoginAttempt) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO login_attempts (email, user_id, ip_address, user_agent, succeeded, failure_reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, attempt.Email, attempt.UserID, attempt.IPAddress, attempt.UserAgent, attempt.Succeeded,
+		attempt.FailureReason, attempt.CreatedAt).Scan(&attempt.ID)
+	if err != nil {
+		return fmt.Errorf("failed to record login attempt: %w", err)
+	}
+	return nil
+}
+
+// CountFailuresByEmail counts the failures for an email address since the given time
+// and its last successful login, whichever is later
+func (r *LoginAttemptRepository) CountFailuresByEmail(ctx context.Context, email string, since time.Time) (*model.LoginFailures, error) {
+	return r.countFailures(ctx, `
+		SELECT COUNT(*), MAX(created_at)
+		FROM login_attempts
+		WHERE email = $1 AND NOT succeeded AND failure_reason <> $3
+			AND created_at > GREATEST($2, (SELECT MAX(created_at) FROM login_attempts WHERE email = $1 AND succeeded))
+	`, email, since, model.LoginFailureThrottled)
+}
+
+// CountFailuresByIP counts the failures from an IP address since the given time
+// Successful logins do not reset the count, since an attacker can log in to their own account
+func (r *LoginAttemptRepository) CountFailuresByIP(ctx context.Context, ipAddress string, since time.Time) (*model.LoginFailures, error) {
+	return r.countFailures(ctx, `
+		SELECT COUNT(*), MAX(created_at)
+		FROM login_attempts
+		WHERE ip_address = $1 AND NOT succeeded AND failure_reason <> $3 AND created_at > $2
+	`, ipAddress, since, model.LoginFailureThrottled)
+}
+
+func (r *LoginAttemptRepository) countFailures(ctx context.Context, query string, args ...any) (*model.LoginFailures, error) {
+	failures := &model.LoginFailures{}
+	var last *time.Time
+	if err := r.db.Pool.QueryRow(ctx, query, args...).Scan(&failures.Count, &last); err != nil {
+		return nil, fmt.Errorf("failed to count login failures: %w", err)
+	}
+	if last != nil {
+		failures.Last = *last
+	}
+	return failures, nil
+}
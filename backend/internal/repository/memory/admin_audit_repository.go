@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type AdminAuditRepository struct {
+	db *DB
+}
+
+var _ repository.AdminAuditStore = (*AdminAuditRepository)(nil)
+
+func NewAdminAuditRepository(db *DB) *AdminAuditRepository {
+	return &AdminAuditRepository{db: db}
+}
+
+// Create records an entry at its CreatedAt
+func (r *AdminAuditRepository) Create(ctx context.Context, entry *model.AdminAuditEntry) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("AdminAuditRepository.Create"); err != nil {
+		return fmt.Errorf("failed to record admin action: %w", err)
+	}
+
+	r.db.insertAuditEntry(entry)
+	return nil
+}
+
+// SetUserStatus changes the account status and records entry
+func (r *AdminAuditRepository) SetUserStatus(ctx context.Context, userID int64, status string, suspendedUntil *time.Time, entry *model.AdminAuditEntry) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("AdminAuditRepository.SetUserStatus"); err != nil {
+		return fmt.Errorf("failed to set account status: %w", err)
+	}
+
+	if user, ok := r.db.users[userID]; ok {
+		user.Status = status
+		user.SuspendedUntil = suspendedUntil
+		user.UpdatedAt = r.db.now()
+	}
+	r.db.insertAuditEntry(entry)
+	return nil
+}
+
+// SetUserRole changes the user's role and records entry
+func (r *AdminAuditRepository) SetUserRole(ctx context.Context, userID int64, role string, entry *model.AdminAuditEntry) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("AdminAuditRepository.SetUserRole"); err != nil {
+		return fmt.Errorf("failed to set role: %w", err)
+	}
+
+	if user, ok := r.db.users[userID]; ok {
+		user.Role = role
+		user.UpdatedAt = r.db.now()
+	}
+	r.db.insertAuditEntry(entry)
+	return nil
+}
+
+// DeleteVideo deletes the video and records entry
+func (r *AdminAuditRepository) DeleteVideo(ctx context.Context, videoID int64, entry *model.AdminAuditEntry) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("AdminAuditRepository.DeleteVideo"); err != nil {
+		return fmt.Errorf("failed to delete video: %w", err)
+	}
+
+	r.db.deleteVideo(videoID)
+	r.db.insertAuditEntry(entry)
+	return nil
+}
+
+// DeleteComment deletes the comment and records entry
+func (r *AdminAuditRepository) DeleteComment(ctx context.Context, commentID int64, entry *model.AdminAuditEntry) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("AdminAuditRepository.DeleteComment"); err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	r.db.deleteComment(commentID)
+	r.db.insertAuditEntry(entry)
+	return nil
+}
+
+// List returns the entries, newest first
+func (r *AdminAuditRepository) List(ctx context.Context, p model.PageRequest) ([]*model.AdminAuditEntry, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("AdminAuditRepository.List"); err != nil {
+		return nil, fmt.Errorf("failed to list admin actions: %w", err)
+	}
+
+	// ORDER BY created_at DESC, id DESC
+	key := func(entry *model.AdminAuditEntry) model.PageCursor {
+		return model.PageCursor{Time: entry.CreatedAt, ID: entry.ID}
+	}
+	rows := append([]*model.AdminAuditEntry(nil), r.db.adminAudit...)
+	sort.Slice(rows, func(i, j int) bool {
+		return compareKeys(key(rows[i]), key(rows[j])) > 0
+	})
+
+	entries := []*model.AdminAuditEntry{}
+	for _, entry := range pageAfter(rows, p, true, key) {
+		e := *entry
+		entries = append(entries, &e)
+	}
+	return entries, nil
+}
+
+// insertAuditEntry stores a copy of entry; callers must hold db.mu
+func (db *DB) insertAuditEntry(entry *model.AdminAuditEntry) {
+	entry.ID = db.nextID("admin_audit_log")
+	stored := *entry
+	db.adminAudit = append(db.adminAudit, &stored)
+}
@@ -0,0 +1,321 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type CommentRepository struct {
+	db *DB
+}
+
+var _ repository.CommentStore = (*CommentRepository)(nil)
+
+func NewCommentRepository(db *DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+func (r *CommentRepository) Create(ctx context.Context, comment *model.Comment) (*model.Comment, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("CommentRepository.Create"); err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	comment.ID = r.db.nextID("comments")
+	comment.CreatedAt = r.db.now()
+	comment.UpdatedAt = comment.CreatedAt
+
+	stored := *comment
+	r.db.comments[comment.ID] = &stored
+	return comment, nil
+}
+
+func (r *CommentRepository) FindByID(ctx context.Context, id int64) (*model.Comment, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("CommentRepository.FindByID"); err != nil {
+		return nil, fmt.Errorf("failed to find comment: %w", err)
+	}
+
+	comment, ok := r.db.comments[id]
+	if !ok {
+		return nil, notFound("comment")
+	}
+	found := *comment
+	return &found, nil
+}
+
+// FindByVideoIDWithProfile returns paginated top-level comments with profile info
+func (r *CommentRepository) FindByVideoIDWithProfile(ctx context.Context, videoID int64, userID *int64, p model.PageRequest) ([]*model.CommentWithProfile, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("CommentRepository.FindByVideoIDWithProfile"); err != nil {
+		return nil, fmt.Errorf("failed to find comments: %w", err)
+	}
+
+	var matched []*model.Comment
+	for _, comment := range r.db.comments {
+		if comment.VideoID == videoID && comment.ParentCommentID == nil {
+			matched = append(matched, comment)
+		}
+	}
+
+	// ORDER BY is_pinned DESC, created_at DESC, id DESC
+	key := func(comment *model.Comment) model.PageCursor {
+		return model.PageCursor{Pinned: comment.IsPinned, Time: comment.CreatedAt, ID: comment.ID}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return compareKeys(key(matched[i]), key(matched[j])) > 0
+	})
+
+	comments := []*model.CommentWithProfile{}
+	for _, comment := range pageAfter(matched, p, true, key) {
+		comments = append(comments, r.db.commentWithProfile(comment, userID, true))
+	}
+	return comments, nil
+}
+
+// FindRepliesByParentIDWithProfile returns paginated replies for a parent comment
+func (r *CommentRepository) FindRepliesByParentIDWithProfile(ctx context.Context, parentCommentID int64, userID *int64, p model.PageRequest) ([]*model.CommentWithProfile, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("CommentRepository.FindRepliesByParentIDWithProfile"); err != nil {
+		return nil, fmt.Errorf("failed to find replies: %w", err)
+	}
+
+	var matched []*model.Comment
+	for _, comment := range r.db.comments {
+		if comment.ParentCommentID != nil && *comment.ParentCommentID == parentCommentID {
+			matched = append(matched, comment)
+		}
+	}
+
+	// ORDER BY created_at ASC, id ASC
+	key := func(comment *model.Comment) model.PageCursor {
+		return model.PageCursor{Time: comment.CreatedAt, ID: comment.ID}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return compareKeys(key(matched[i]), key(matched[j])) < 0
+	})
+
+	replies := []*model.CommentWithProfile{}
+	for _, comment := range pageAfter(matched, p, false, key) {
+		replies = append(replies, r.db.commentWithProfile(comment, userID, false))
+	}
+	return replies, nil
+}
+
+func (r *CommentRepository) Update(ctx context.Context, comment *model.Comment) (*model.Comment, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("CommentRepository.Update"); err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	stored, ok := r.db.comments[comment.ID]
+	if !ok {
+		return nil, fmt.Errorf("failed to update comment: %w", notFound("comment"))
+	}
+	stored.Content = comment.Content
+	stored.UpdatedAt = r.db.now()
+
+	*comment = *stored
+	return comment, nil
+}
+
+func (r *CommentRepository) Delete(ctx context.Context, id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("CommentRepository.Delete"); err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	r.db.deleteComment(id)
+	return nil
+}
+
+func (r *CommentRepository) PinComment(ctx context.Context, commentID int64, isPinned bool) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("CommentRepository.PinComment"); err != nil {
+		return fmt.Errorf("failed to pin comment: %w", err)
+	}
+
+	if comment, ok := r.db.comments[commentID]; ok {
+		comment.IsPinned = isPinned
+		comment.UpdatedAt = r.db.now()
+	}
+	return nil
+}
+
+func (r *CommentRepository) SetCreatorLiked(ctx context.Context, commentID int64, isCreatorLiked bool) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("CommentRepository.SetCreatorLiked"); err != nil {
+		return fmt.Errorf("failed to set creator liked: %w", err)
+	}
+
+	if comment, ok := r.db.comments[commentID]; ok {
+		comment.IsCreatorLiked = isCreatorLiked
+		comment.UpdatedAt = r.db.now()
+	}
+	return nil
+}
+
+// LikeComment adds or updates a like/dislike on a comment
+func (r *CommentRepository) LikeComment(ctx context.Context, commentID, userID int64, likeType string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("CommentRepository.LikeComment"); err != nil {
+		return fmt.Errorf("failed to like comment: %w", err)
+	}
+
+	comment, ok := r.db.comments[commentID]
+	if !ok {
+		return fmt.Errorf("failed to create comment like: %w", notFound("comment"))
+	}
+
+	if like := r.db.findCommentLike(commentID, userID); like != nil {
+		if like.likeType == "like" && likeType == "dislike" {
+			comment.LikeCount--
+		} else if like.likeType == "dislike" && likeType == "like" {
+			comment.LikeCount++
+		}
+		like.likeType = likeType
+		return nil
+	}
+
+	r.db.commentLikes = append(r.db.commentLikes, &commentLikeRow{commentID: commentID, userID: userID, likeType: likeType})
+	if likeType == "like" {
+		comment.LikeCount++
+	}
+	return nil
+}
+
+// UnlikeComment removes a like/dislike from a comment
+func (r *CommentRepository) UnlikeComment(ctx context.Context, commentID, userID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("CommentRepository.UnlikeComment"); err != nil {
+		return fmt.Errorf("failed to unlike comment: %w", err)
+	}
+
+	like := r.db.findCommentLike(commentID, userID)
+	if like == nil {
+		return notFound("comment like")
+	}
+
+	likes := r.db.commentLikes[:0]
+	for _, l := range r.db.commentLikes {
+		if l != like {
+			likes = append(likes, l)
+		}
+	}
+	r.db.commentLikes = likes
+
+	if comment, ok := r.db.comments[commentID]; ok && like.likeType == "like" && comment.LikeCount > 0 {
+		comment.LikeCount--
+	}
+	return nil
+}
+
+// GetCommentCount returns the total number of comments for a video (including replies)
+func (r *CommentRepository) GetCommentCount(ctx context.Context, videoID int64) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("CommentRepository.GetCommentCount"); err != nil {
+		return 0, fmt.Errorf("failed to get comment count: %w", err)
+	}
+
+	var count int64
+	for _, comment := range r.db.comments {
+		if comment.VideoID == videoID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// findCommentLike returns the user's like row on a comment; callers must hold db.mu
+func (db *DB) findCommentLike(commentID, userID int64) *commentLikeRow {
+	for _, like := range db.commentLikes {
+		if like.commentID == commentID && like.userID == userID {
+			return like
+		}
+	}
+	return nil
+}
+
+// deleteComment removes a comment, its replies and likes (ON DELETE CASCADE);
+// callers must hold db.mu
+func (db *DB) deleteComment(id int64) {
+	delete(db.comments, id)
+
+	for replyID, reply := range db.comments {
+		if reply.ParentCommentID != nil && *reply.ParentCommentID == id {
+			db.deleteComment(replyID)
+		}
+	}
+
+	likes := db.commentLikes[:0]
+	for _, like := range db.commentLikes {
+		if like.commentID != id {
+			likes = append(likes, like)
+		}
+	}
+	db.commentLikes = likes
+}
+
+// commentWithProfile builds the joined row returned by the comment queries; callers must hold db.mu
+func (db *DB) commentWithProfile(comment *model.Comment, userID *int64, countReplies bool) *model.CommentWithProfile {
+	result := &model.CommentWithProfile{
+		ID:              comment.ID,
+		VideoID:         comment.VideoID,
+		UserID:          comment.UserID,
+		ParentCommentID: comment.ParentCommentID,
+		Content:         comment.Content,
+		LikeCount:       comment.LikeCount,
+		IsPinned:        comment.IsPinned,
+		IsCreatorLiked:  comment.IsCreatorLiked,
+		CreatedAt:       comment.CreatedAt,
+		UpdatedAt:       comment.UpdatedAt,
+		Profile:         db.profileOrEmpty(comment.UserID),
+	}
+
+	if countReplies {
+		for _, reply := range db.comments {
+			if reply.ParentCommentID != nil && *reply.ParentCommentID == comment.ID {
+				result.ReplyCount++
+			}
+		}
+	}
+
+	if userID != nil {
+		if like := db.findCommentLike(comment.ID, *userID); like != nil {
+			likeType := like.likeType
+			result.UserLikeType = &likeType
+		}
+	}
+
+	if video, ok := db.videos[comment.VideoID]; ok {
+		result.IsVideoCreator = video.UserID == comment.UserID
+	}
+
+	return result
+}
@@ -0,0 +1,254 @@
+// Package memory provides in-memory implementations of the repository
+// interfaces so services can be tested without PostgreSQL.
+//
+// All repositories created from the same DB share its tables, which keeps
+// joins (profiles on comments, videos in playlists, ...) consistent the same
+// way the SQL implementations are.
+package memory
+
+import (
+	"cmp"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/model"
+)
+
+type playlistVideoRow struct {
+	id         int64
+	playlistID int64
+	videoID    int64
+	position   int
+	createdAt  time.Time
+}
+
+type subscriptionRow struct {
+	id                 int64
+	subscriberUserID   int64
+	subscribedToUserID int64
+	createdAt          time.Time
+}
+
+type commentLikeRow struct {
+	commentID int64
+	userID    int64
+	likeType  string
+}
+
+type watchHistoryRow struct {
+	id        int64
+	userID    int64
+	videoID   int64
+	watchedAt time.Time
+	position  float64
+	progress  float64
+}
+
+type searchQueryRow struct {
+	id        int64
+	userID    int64 // 0 for anonymous searches
+	query     string
+	createdAt time.Time
+}
+
+type recentSearchRow struct {
+	id         int64
+	userID     int64
+	query      string
+	searchedAt time.Time
+}
+
+type popularQueryRow struct {
+	query       string
+	searchCount int64
+}
+
+type recoveryCodeRow struct {
+	id        int64
+	userID    int64
+	codeHash  string
+	usedAt    *time.Time
+	createdAt time.Time
+}
+
+// DB holds the tables shared by the in-memory repositories
+type DB struct {
+	mu sync.Mutex
+
+	users          map[int64]*model.User
+	profiles       map[int64]*model.Profile // keyed by user ID
+	videos         map[int64]*model.Video
+	playlists      map[int64]*model.Playlist
+	playlistVideos []*playlistVideoRow
+	subscriptions  []*subscriptionRow
+	comments       map[int64]*model.Comment
+	commentLikes   []*commentLikeRow
+	videoReactions []*model.VideoReaction
+	watchHistory   []*watchHistoryRow
+	uploads        map[string]*model.UploadSession
+	uploadParts    []*model.UploadPart
+	transcodeJobs  map[int64]*model.TranscodeJob
+	renditions     []*model.VideoRendition
+	thumbnails     []*model.VideoThumbnail
+	searchQueries  []*searchQueryRow
+	recentSearches []*recentSearchRow
+	popularQueries []*popularQueryRow
+	sessions       map[int64]*model.Session
+	userTokens     map[int64]*model.UserToken
+	userIdentities map[int64]*model.UserIdentity
+	oidcStates     map[string]*model.OIDCLoginState // keyed by state hash
+	recoveryCodes  []*recoveryCodeRow
+	loginAttempts  []*model.LoginAttempt
+	adminAudit     []*model.AdminAuditEntry
+
+	sequences map[string]int64
+	failures  map[string]error
+	calls     int
+	lastTime  time.Time
+}
+
+func NewDB() *DB {
+	return &DB{
+		users:          make(map[int64]*model.User),
+		profiles:       make(map[int64]*model.Profile),
+		videos:         make(map[int64]*model.Video),
+		playlists:      make(map[int64]*model.Playlist),
+		comments:       make(map[int64]*model.Comment),
+		uploads:        make(map[string]*model.UploadSession),
+		transcodeJobs:  make(map[int64]*model.TranscodeJob),
+		sessions:       make(map[int64]*model.Session),
+		userTokens:     make(map[int64]*model.UserToken),
+		userIdentities: make(map[int64]*model.UserIdentity),
+		oidcStates:     make(map[string]*model.OIDCLoginState),
+		sequences:      make(map[string]int64),
+		failures:       make(map[string]error),
+	}
+}
+
+// Fail makes the named repository method return err until cleared with a nil err
+// Methods are named "<Repository>.<Method>", e.g. "VideoRepository.Create"
+func (db *DB) Fail(method string, err error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if err == nil {
+		delete(db.failures, method)
+		return
+	}
+	db.failures[method] = err
+}
+
+// failure returns the injected error for method; callers must hold db.mu
+// Every repository method calls it first, so it also counts the calls
+func (db *DB) failure(method string) error {
+	db.calls++
+	return db.failures[method]
+}
+
+// Calls returns how many repository methods have been called, standing in for
+// database round trips
+func (db *DB) Calls() int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return db.calls
+}
+
+// nextID returns the next value of a table's BIGSERIAL; callers must hold db.mu
+func (db *DB) nextID(table string) int64 {
+	db.sequences[table]++
+	return db.sequences[table]
+}
+
+// now returns a strictly increasing timestamp so ordering by time is deterministic;
+// callers must hold db.mu
+func (db *DB) now() time.Time {
+	t := time.Now()
+	if !t.After(db.lastTime) {
+		t = db.lastTime.Add(time.Microsecond)
+	}
+	db.lastTime = t
+	return t
+}
+
+// notFound mirrors the error returned by the SQL repositories when no row matches
+func notFound(what string) error {
+	return fmt.Errorf("failed to find %s: %w", what, pgx.ErrNoRows)
+}
+
+// page applies LIMIT/OFFSET semantics to a slice length
+func page(n, limit, offset int) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > n {
+		offset = n
+	}
+	end := n
+	if limit >= 0 && offset+limit < n {
+		end = offset + limit
+	}
+	return offset, end
+}
+
+// pageAfter mirrors the keyset condition and LIMIT/OFFSET of a paginated SQL list:
+// rows must already be in the list's order and key returns the sort key of a row
+func pageAfter[T any](rows []T, p model.PageRequest, desc bool, key func(T) model.PageCursor) []T {
+	if p.After != nil {
+		var after []T
+		for _, row := range rows {
+			c := compareKeys(key(row), *p.After)
+			if (desc && c < 0) || (!desc && c > 0) {
+				after = append(after, row)
+			}
+		}
+		rows = after
+	}
+	start, end := page(len(rows), p.Limit, p.Offset)
+	return rows[start:end]
+}
+
+// compareKeys compares sort keys like the SQL row comparison (pinned, time, id)
+func compareKeys(a, b model.PageCursor) int {
+	if a.Pinned != b.Pinned {
+		if a.Pinned {
+			return 1
+		}
+		return -1
+	}
+	if c := a.Time.Compare(b.Time); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
+
+// profileOrEmpty returns a copy of the user's profile, or an empty profile like a LEFT JOIN miss;
+// callers must hold db.mu
+func (db *DB) profileOrEmpty(userID int64) *model.Profile {
+	if profile, ok := db.profiles[userID]; ok {
+		p := *profile
+		return &p
+	}
+	return &model.Profile{}
+}
+
+// videoWithProfile converts a stored video the way the SQL joins do; callers must hold db.mu
+func (db *DB) videoWithProfile(video *model.Video) *model.VideoWithProfile {
+	return &model.VideoWithProfile{
+		ID:           video.ID,
+		UserID:       video.UserID,
+		Title:        video.Title,
+		Description:  video.Description,
+		VideoURL:     video.VideoURL,
+		ThumbnailURL: video.ThumbnailURL,
+		Duration:     video.Duration,
+		ViewCount:    video.ViewCount,
+		LikeCount:    video.LikeCount,
+		DislikeCount: video.DislikeCount,
+		CreatedAt:    video.CreatedAt,
+		UpdatedAt:    video.UpdatedAt,
+		Profile:      db.profileOrEmpty(video.UserID),
+	}
+}
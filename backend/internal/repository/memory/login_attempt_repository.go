@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type LoginAttemptRepository struct {
+	db *DB
+}
+
+var _ repository.LoginAttemptStore = (*LoginAttemptRepository)(nil)
+
+func NewLoginAttemptRepository(db *DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// Create records an attempt at its CreatedAt
+func (r *LoginAttemptRepository) Create(ctx context.Context, attempt *model.LoginAttempt) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("LoginAttemptRepository.Create"); err != nil {
+		return fmt.Errorf("failed to record login attempt: %w", err)
+	}
+
+	attempt.ID = r.db.nextID("login_attempts")
+	stored := *attempt
+	r.db.loginAttempts = append(r.db.loginAttempts, &stored)
+	return nil
+}
+
+// CountFailuresByEmail counts the failures for an email address since the given time
+// and its last successful login, whichever is later
+func (r *LoginAttemptRepository) CountFailuresByEmail(ctx context.Context, email string, since time.Time) (*model.LoginFailures, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("LoginAttemptRepository.CountFailuresByEmail"); err != nil {
+		return nil, fmt.Errorf("failed to count login failures: %w", err)
+	}
+
+	for _, attempt := range r.db.loginAttempts {
+		if attempt.Email == email && attempt.Succeeded && attempt.CreatedAt.After(since) {
+			since = attempt.CreatedAt
+		}
+	}
+	return r.db.countLoginFailures(func(attempt *model.LoginAttempt) bool {
+		return attempt.Email == email && attempt.CreatedAt.After(since)
+	}), nil
+}
+
+// CountFailuresByIP counts the failures from an IP address since the given time
+func (r *LoginAttemptRepository) CountFailuresByIP(ctx context.Context, ipAddress string, since time.Time) (*model.LoginFailures, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("LoginAttemptRepository.CountFailuresByIP"); err != nil {
+		return nil, fmt.Errorf("failed to count login failures: %w", err)
+	}
+
+	return r.db.countLoginFailures(func(attempt *model.LoginAttempt) bool {
+		return attempt.IPAddress == ipAddress && attempt.CreatedAt.After(since)
+	}), nil
+}
+
+// countLoginFailures counts the failed, not throttled attempts that match; callers must hold db.mu
+func (db *DB) countLoginFailures(match func(*model.LoginAttempt) bool) *model.LoginFailures {
+	failures := &model.LoginFailures{}
+	for _, attempt := range db.loginAttempts {
+		if attempt.Succeeded || attempt.FailureReason == model.LoginFailureThrottled || !match(attempt) {
+			continue
+		}
+		failures.Count++
+		if attempt.CreatedAt.After(failures.Last) {
+			failures.Last = attempt.CreatedAt
+		}
+	}
+	return failures
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type OIDCStateRepository struct {
+	db *DB
+}
+
+var _ repository.OIDCStateStore = (*OIDCStateRepository)(nil)
+
+func NewOIDCStateRepository(db *DB) *OIDCStateRepository {
+	return &OIDCStateRepository{db: db}
+}
+
+// Create stores a sign-in started now and drops the expired ones of abandoned sign-ins
+func (r *OIDCStateRepository) Create(ctx context.Context, state *model.OIDCLoginState, now time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("OIDCStateRepository.Create"); err != nil {
+		return fmt.Errorf("failed to create oidc login state: %w", err)
+	}
+
+	for hash, s := range r.db.oidcStates {
+		if !s.ExpiresAt.After(now) {
+			delete(r.db.oidcStates, hash)
+		}
+	}
+	if _, ok := r.db.oidcStates[state.StateHash]; ok {
+		return fmt.Errorf("failed to create oidc login state: duplicate state")
+	}
+
+	stored := *state
+	stored.CreatedAt = now
+	r.db.oidcStates[state.StateHash] = &stored
+	return nil
+}
+
+// Consume removes a sign-in and returns it if it has not expired
+func (r *OIDCStateRepository) Consume(ctx context.Context, stateHash string, now time.Time) (*model.OIDCLoginState, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("OIDCStateRepository.Consume"); err != nil {
+		return nil, fmt.Errorf("failed to consume oidc login state: %w", err)
+	}
+
+	state, ok := r.db.oidcStates[stateHash]
+	if !ok || !state.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("failed to consume oidc login state: %w", notFound("oidc login state"))
+	}
+	delete(r.db.oidcStates, stateHash)
+	return state, nil
+}
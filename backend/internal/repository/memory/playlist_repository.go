@@ -0,0 +1,266 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type PlaylistRepository struct {
+	db *DB
+}
+
+var _ repository.PlaylistStore = (*PlaylistRepository)(nil)
+
+func NewPlaylistRepository(db *DB) *PlaylistRepository {
+	return &PlaylistRepository{db: db}
+}
+
+// Create creates a new playlist
+func (r *PlaylistRepository) Create(ctx context.Context, playlist *model.Playlist) (*model.Playlist, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("PlaylistRepository.Create"); err != nil {
+		return nil, err
+	}
+
+	playlist.ID = r.db.nextID("playlists")
+	playlist.CreatedAt = r.db.now()
+	playlist.UpdatedAt = playlist.CreatedAt
+
+	stored := *playlist
+	stored.VideoCount = 0
+	r.db.playlists[playlist.ID] = &stored
+	return playlist, nil
+}
+
+// FindByID finds a playlist by ID
+func (r *PlaylistRepository) FindByID(ctx context.Context, id int64) (*model.Playlist, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("PlaylistRepository.FindByID"); err != nil {
+		return nil, err
+	}
+
+	playlist, ok := r.db.playlists[id]
+	if !ok {
+		return nil, notFound("playlist")
+	}
+	return r.db.playlistWithCount(playlist), nil
+}
+
+// FindByUserID finds all playlists for a user
+func (r *PlaylistRepository) FindByUserID(ctx context.Context, userID int64) ([]*model.Playlist, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("PlaylistRepository.FindByUserID"); err != nil {
+		return nil, err
+	}
+
+	var playlists []*model.Playlist
+	for _, playlist := range r.db.playlists {
+		if playlist.UserID == userID {
+			playlists = append(playlists, r.db.playlistWithCount(playlist))
+		}
+	}
+
+	// ORDER BY updated_at DESC
+	sort.Slice(playlists, func(i, j int) bool {
+		return playlists[i].UpdatedAt.After(playlists[j].UpdatedAt)
+	})
+	return playlists, nil
+}
+
+// Update updates a playlist
+func (r *PlaylistRepository) Update(ctx context.Context, playlist *model.Playlist) (*model.Playlist, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("PlaylistRepository.Update"); err != nil {
+		return nil, err
+	}
+
+	stored, ok := r.db.playlists[playlist.ID]
+	if !ok {
+		return nil, notFound("playlist")
+	}
+	stored.Title = playlist.Title
+	stored.Description = playlist.Description
+	stored.Visibility = playlist.Visibility
+	stored.UpdatedAt = r.db.now()
+
+	playlist.UpdatedAt = stored.UpdatedAt
+	return playlist, nil
+}
+
+// Delete deletes a playlist
+func (r *PlaylistRepository) Delete(ctx context.Context, id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("PlaylistRepository.Delete"); err != nil {
+		return err
+	}
+
+	delete(r.db.playlists, id)
+
+	pvs := r.db.playlistVideos[:0]
+	for _, pv := range r.db.playlistVideos {
+		if pv.playlistID != id {
+			pvs = append(pvs, pv)
+		}
+	}
+	r.db.playlistVideos = pvs
+	return nil
+}
+
+// AddVideo adds a video to a playlist
+func (r *PlaylistRepository) AddVideo(ctx context.Context, playlistID, videoID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("PlaylistRepository.AddVideo"); err != nil {
+		return err
+	}
+
+	playlist, ok := r.db.playlists[playlistID]
+	if !ok {
+		return fmt.Errorf("playlist %d does not exist", playlistID)
+	}
+
+	position := 0
+	for _, pv := range r.db.playlistVideos {
+		if pv.playlistID != playlistID {
+			continue
+		}
+		if pv.videoID == videoID {
+			// ON CONFLICT DO NOTHING, but the playlist is still touched
+			playlist.UpdatedAt = r.db.now()
+			return nil
+		}
+		if pv.position >= position {
+			position = pv.position + 1
+		}
+	}
+
+	r.db.playlistVideos = append(r.db.playlistVideos, &playlistVideoRow{
+		id:         r.db.nextID("playlist_videos"),
+		playlistID: playlistID,
+		videoID:    videoID,
+		position:   position,
+		createdAt:  r.db.now(),
+	})
+	playlist.UpdatedAt = r.db.now()
+	return nil
+}
+
+// RemoveVideo removes a video from a playlist
+func (r *PlaylistRepository) RemoveVideo(ctx context.Context, playlistID, videoID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("PlaylistRepository.RemoveVideo"); err != nil {
+		return err
+	}
+
+	pvs := r.db.playlistVideos[:0]
+	for _, pv := range r.db.playlistVideos {
+		if pv.playlistID != playlistID || pv.videoID != videoID {
+			pvs = append(pvs, pv)
+		}
+	}
+	r.db.playlistVideos = pvs
+	return nil
+}
+
+// IsVideoInPlaylist checks if a video is in a playlist
+func (r *PlaylistRepository) IsVideoInPlaylist(ctx context.Context, playlistID, videoID int64) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("PlaylistRepository.IsVideoInPlaylist"); err != nil {
+		return false, err
+	}
+
+	for _, pv := range r.db.playlistVideos {
+		if pv.playlistID == playlistID && pv.videoID == videoID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetPlaylistVideos gets the videos in a playlist that viewerID can watch
+func (r *PlaylistRepository) GetPlaylistVideos(ctx context.Context, viewerID, playlistID int64) ([]*model.PlaylistVideo, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("PlaylistRepository.GetPlaylistVideos"); err != nil {
+		return nil, err
+	}
+
+	var rows []*playlistVideoRow
+	for _, pv := range r.db.playlistVideos {
+		if video, ok := r.db.videos[pv.videoID]; pv.playlistID == playlistID && ok && isWatchableBy(video, viewerID) {
+			rows = append(rows, pv)
+		}
+	}
+
+	// ORDER BY pv.created_at DESC
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].createdAt.After(rows[j].createdAt)
+	})
+
+	var playlistVideos []*model.PlaylistVideo
+	for _, pv := range rows {
+		video := r.db.videoWithProfile(r.db.videos[pv.videoID])
+		// The SQL query doesn't join profiles; the service fills them in
+		video.Profile = nil
+
+		playlistVideos = append(playlistVideos, &model.PlaylistVideo{
+			ID:         pv.id,
+			PlaylistID: pv.playlistID,
+			VideoID:    pv.videoID,
+			Position:   pv.position,
+			CreatedAt:  pv.createdAt,
+			Video:      video,
+		})
+	}
+	return playlistVideos, nil
+}
+
+// GetPlaylistsContainingVideo gets all playlists that contain a specific video for a user
+func (r *PlaylistRepository) GetPlaylistsContainingVideo(ctx context.Context, userID, videoID int64) ([]int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("PlaylistRepository.GetPlaylistsContainingVideo"); err != nil {
+		return nil, err
+	}
+
+	var playlistIDs []int64
+	for _, pv := range r.db.playlistVideos {
+		if playlist, ok := r.db.playlists[pv.playlistID]; ok && playlist.UserID == userID && pv.videoID == videoID {
+			playlistIDs = append(playlistIDs, playlist.ID)
+		}
+	}
+	return playlistIDs, nil
+}
+
+// playlistWithCount copies a playlist with its video count filled in; callers must hold db.mu
+func (db *DB) playlistWithCount(playlist *model.Playlist) *model.Playlist {
+	found := *playlist
+	found.VideoCount = 0
+	for _, pv := range db.playlistVideos {
+		if pv.playlistID == playlist.ID {
+			found.VideoCount++
+		}
+	}
+	return &found
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type ProfileRepository struct {
+	db *DB
+}
+
+var _ repository.ProfileStore = (*ProfileRepository)(nil)
+
+func NewProfileRepository(db *DB) *ProfileRepository {
+	return &ProfileRepository{db: db}
+}
+
+func (r *ProfileRepository) Create(ctx context.Context, userID int64, email, defaultIconURL, defaultBannerURL string) (*model.Profile, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("ProfileRepository.Create"); err != nil {
+		return nil, fmt.Errorf("failed to create profile: %w", err)
+	}
+	if _, ok := r.db.profiles[userID]; ok {
+		return nil, fmt.Errorf("failed to create profile: profile for user %d already exists", userID)
+	}
+
+	// Extract channel name from email (part before @)
+	channelName := email
+	if atIndex := strings.Index(email, "@"); atIndex != -1 {
+		channelName = email[:atIndex]
+	}
+
+	profile := &model.Profile{
+		ID:          r.db.nextID("profiles"),
+		UserID:      userID,
+		ChannelName: channelName,
+		Description: "このチャンネルの説明はありません。",
+		IconURL:     defaultIconURL,
+		BannerURL:   defaultBannerURL,
+	}
+	profile.CreatedAt = r.db.now()
+	profile.UpdatedAt = profile.CreatedAt
+
+	stored := *profile
+	r.db.profiles[userID] = &stored
+	return profile, nil
+}
+
+func (r *ProfileRepository) FindByUserID(ctx context.Context, userID int64) (*model.Profile, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("ProfileRepository.FindByUserID"); err != nil {
+		return nil, fmt.Errorf("failed to find profile: %w", err)
+	}
+
+	profile, ok := r.db.profiles[userID]
+	if !ok {
+		return nil, notFound("profile")
+	}
+	found := *profile
+	return &found, nil
+}
+
+// FindByUserIDs loads the profiles of several users, keyed by user ID
+func (r *ProfileRepository) FindByUserIDs(ctx context.Context, userIDs []int64) (map[int64]*model.Profile, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("ProfileRepository.FindByUserIDs"); err != nil {
+		return nil, fmt.Errorf("failed to find profiles: %w", err)
+	}
+
+	profiles := make(map[int64]*model.Profile, len(userIDs))
+	for _, userID := range userIDs {
+		if profile, ok := r.db.profiles[userID]; ok {
+			found := *profile
+			profiles[userID] = &found
+		}
+	}
+	return profiles, nil
+}
+
+func (r *ProfileRepository) Update(ctx context.Context, profile *model.Profile) (*model.Profile, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("ProfileRepository.Update"); err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	stored, ok := r.db.profiles[profile.UserID]
+	if !ok {
+		return nil, fmt.Errorf("failed to update profile: %w", notFound("profile"))
+	}
+	stored.ChannelName = profile.ChannelName
+	stored.Description = profile.Description
+	stored.IconURL = profile.IconURL
+	stored.BannerURL = profile.BannerURL
+	stored.UpdatedAt = r.db.now()
+
+	*profile = *stored
+	return profile, nil
+}
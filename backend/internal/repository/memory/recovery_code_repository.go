@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type RecoveryCodeRepository struct {
+	db *DB
+}
+
+var _ repository.RecoveryCodeStore = (*RecoveryCodeRepository)(nil)
+
+func NewRecoveryCodeRepository(db *DB) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{db: db}
+}
+
+// Replace deletes the user's recovery codes and stores new ones
+func (r *RecoveryCodeRepository) Replace(ctx context.Context, userID int64, codeHashes []string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("RecoveryCodeRepository.Replace"); err != nil {
+		return fmt.Errorf("failed to replace recovery codes: %w", err)
+	}
+
+	r.db.deleteRecoveryCodes(userID)
+	now := r.db.now()
+	for _, hash := range codeHashes {
+		r.db.recoveryCodes = append(r.db.recoveryCodes, &recoveryCodeRow{
+			id:        r.db.nextID("recovery_codes"),
+			userID:    userID,
+			codeHash:  hash,
+			createdAt: now,
+		})
+	}
+	return nil
+}
+
+// Consume marks an unused recovery code of the user as used
+func (r *RecoveryCodeRepository) Consume(ctx context.Context, userID int64, codeHash string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("RecoveryCodeRepository.Consume"); err != nil {
+		return fmt.Errorf("failed to consume recovery code: %w", err)
+	}
+
+	for _, code := range r.db.recoveryCodes {
+		if code.userID == userID && code.codeHash == codeHash && code.usedAt == nil {
+			usedAt := r.db.now()
+			code.usedAt = &usedAt
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to consume recovery code: %w", notFound("recovery code"))
+}
+
+// CountUnused returns how many of the user's recovery codes have not been used
+func (r *RecoveryCodeRepository) CountUnused(ctx context.Context, userID int64) (int, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("RecoveryCodeRepository.CountUnused"); err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+
+	count := 0
+	for _, code := range r.db.recoveryCodes {
+		if code.userID == userID && code.usedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *RecoveryCodeRepository) DeleteByUserID(ctx context.Context, userID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("RecoveryCodeRepository.DeleteByUserID"); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	r.db.deleteRecoveryCodes(userID)
+	return nil
+}
+
+// deleteRecoveryCodes removes the user's recovery codes; callers must hold db.mu
+func (db *DB) deleteRecoveryCodes(userID int64) {
+	codes := db.recoveryCodes[:0]
+	for _, code := range db.recoveryCodes {
+		if code.userID != userID {
+			codes = append(codes, code)
+		}
+	}
+	db.recoveryCodes = codes
+}
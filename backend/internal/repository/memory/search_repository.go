@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+// Weights of a term found in each field, the ts_rank_cd defaults for the A, B and C labels
+const (
+	titleRankWeight       = 1.0
+	channelRankWeight     = 0.4
+	descriptionRankWeight = 0.2
+)
+
+type SearchRepository struct {
+	db *DB
+}
+
+var _ repository.SearchStore = (*SearchRepository)(nil)
+
+func NewSearchRepository(db *DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// SearchVideos returns ready public videos containing every term in their title, description
+// or channel name; the rank approximates the SQL one by weighting the field each term is found in
+func (r *SearchRepository) SearchVideos(ctx context.Context, params *model.VideoSearchParams) ([]*model.VideoSearchResult, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SearchRepository.SearchVideos"); err != nil {
+		return nil, fmt.Errorf("failed to search videos: %w", err)
+	}
+
+	var results []*model.VideoSearchResult
+	for _, video := range r.db.videos {
+		if !isListed(video) || !matchesSearchFilters(video, params) {
+			continue
+		}
+
+		channelName := strings.ToLower(r.db.profileOrEmpty(video.UserID).ChannelName)
+		title := strings.ToLower(video.Title)
+		description := strings.ToLower(video.Description)
+
+		var rank float64
+		matched := true
+		for _, term := range params.Terms {
+			term = strings.ToLower(term)
+			found := false
+			if strings.Contains(title, term) {
+				rank += titleRankWeight
+				found = true
+			}
+			if strings.Contains(channelName, term) {
+				rank += channelRankWeight
+				found = true
+			}
+			if strings.Contains(description, term) {
+				rank += descriptionRankWeight
+				found = true
+			}
+			if !found {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		if after := params.After; after != nil && (rank > after.Rank || (rank == after.Rank && video.ID >= after.ID)) {
+			continue
+		}
+
+		withProfile := r.db.videoWithProfile(video)
+		withProfile.Width = video.Width
+		withProfile.Height = video.Height
+		results = append(results, &model.VideoSearchResult{Video: withProfile, Rank: rank})
+	}
+
+	// ORDER BY rank DESC, v.id DESC
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank == results[j].Rank {
+			return results[i].Video.ID > results[j].Video.ID
+		}
+		return results[i].Rank > results[j].Rank
+	})
+
+	start, end := page(len(results), params.Limit, 0)
+	return append([]*model.VideoSearchResult{}, results[start:end]...), nil
+}
+
+// matchesSearchFilters applies the duration, upload date and channel filters
+func matchesSearchFilters(video *model.Video, params *model.VideoSearchParams) bool {
+	if params.MinDuration > 0 && video.Duration < params.MinDuration {
+		return false
+	}
+	if params.MaxDuration > 0 && video.Duration > params.MaxDuration {
+		return false
+	}
+	if params.UploadedAfter != nil && video.CreatedAt.Before(*params.UploadedAfter) {
+		return false
+	}
+	if params.ChannelID != 0 && video.UserID != params.ChannelID {
+		return false
+	}
+	return true
+}
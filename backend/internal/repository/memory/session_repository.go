@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type SessionRepository struct {
+	db *DB
+}
+
+var _ repository.SessionStore = (*SessionRepository)(nil)
+
+func NewSessionRepository(db *DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Create starts a session at its CreatedAt, which is also when it was last seen
+func (r *SessionRepository) Create(ctx context.Context, session *model.Session) (*model.Session, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SessionRepository.Create"); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	for _, s := range r.db.sessions {
+		if s.Family == session.Family {
+			return nil, fmt.Errorf("failed to create session: family %s already exists", session.Family)
+		}
+	}
+
+	session.ID = r.db.nextID("sessions")
+	session.LastSeenAt = session.CreatedAt
+	session.RevokedAt = nil
+
+	stored := *session
+	r.db.sessions[session.ID] = &stored
+	return session, nil
+}
+
+func (r *SessionRepository) FindByID(ctx context.Context, id int64) (*model.Session, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SessionRepository.FindByID"); err != nil {
+		return nil, fmt.Errorf("failed to find session: %w", err)
+	}
+
+	session, ok := r.db.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("failed to find session: %w", notFound("session"))
+	}
+	found := *session
+	return &found, nil
+}
+
+// FindByFamily returns the session of a refresh token family, revoked or not
+func (r *SessionRepository) FindByFamily(ctx context.Context, family string) (*model.Session, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SessionRepository.FindByFamily"); err != nil {
+		return nil, fmt.Errorf("failed to find session: %w", err)
+	}
+
+	for _, session := range r.db.sessions {
+		if session.Family == family {
+			found := *session
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to find session: %w", notFound("session"))
+}
+
+// Rotate replaces the session's refresh token hash if it is still oldHash and the session is active
+func (r *SessionRepository) Rotate(ctx context.Context, id int64, oldHash, newHash string, now, expiresAt time.Time, client model.ClientInfo) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SessionRepository.Rotate"); err != nil {
+		return false, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	session, ok := r.db.sessions[id]
+	if !ok || session.RefreshTokenHash != oldHash || session.RevokedAt != nil || !session.ExpiresAt.After(now) {
+		return false, nil
+	}
+	session.RefreshTokenHash = newHash
+	session.ExpiresAt = expiresAt
+	session.UserAgent = client.UserAgent
+	session.IPAddress = client.IPAddress
+	session.LastSeenAt = now
+	return true, nil
+}
+
+// Revoke ends a session; revoking it again keeps the original revocation time
+func (r *SessionRepository) Revoke(ctx context.Context, id int64, now time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SessionRepository.Revoke"); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	if session, ok := r.db.sessions[id]; ok && session.RevokedAt == nil {
+		session.RevokedAt = &now
+	}
+	return nil
+}
+
+// FindActiveByUserID returns the user's sessions that are neither revoked nor expired,
+// most recently seen first
+func (r *SessionRepository) FindActiveByUserID(ctx context.Context, userID int64, now time.Time) ([]*model.Session, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SessionRepository.FindActiveByUserID"); err != nil {
+		return nil, fmt.Errorf("failed to find sessions: %w", err)
+	}
+
+	sessions := []*model.Session{}
+	for _, session := range r.db.sessions {
+		if session.UserID == userID && session.RevokedAt == nil && session.ExpiresAt.After(now) {
+			found := *session
+			sessions = append(sessions, &found)
+		}
+	}
+
+	// ORDER BY last_seen_at DESC, id DESC
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].LastSeenAt.Equal(sessions[j].LastSeenAt) {
+			return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+		}
+		return sessions[i].ID > sessions[j].ID
+	})
+	return sessions, nil
+}
+
+// Touch records that an active session was just used
+func (r *SessionRepository) Touch(ctx context.Context, id int64, now time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SessionRepository.Touch"); err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+
+	if session, ok := r.db.sessions[id]; ok && session.RevokedAt == nil {
+		session.LastSeenAt = now
+	}
+	return nil
+}
+
+// RevokeAllByUserID ends all of the user's active sessions except exceptID (0 for none)
+// and returns how many were revoked
+func (r *SessionRepository) RevokeAllByUserID(ctx context.Context, userID, exceptID int64, now time.Time) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SessionRepository.RevokeAllByUserID"); err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	var revoked int64
+	for _, session := range r.db.sessions {
+		if session.UserID == userID && session.ID != exceptID && session.RevokedAt == nil && session.ExpiresAt.After(now) {
+			revokedAt := now
+			session.RevokedAt = &revokedAt
+			revoked++
+		}
+	}
+	return revoked, nil
+}
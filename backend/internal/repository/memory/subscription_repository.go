@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type SubscriptionRepository struct {
+	db *DB
+}
+
+var _ repository.SubscriptionStore = (*SubscriptionRepository)(nil)
+
+func NewSubscriptionRepository(db *DB) *SubscriptionRepository {
+	return &SubscriptionRepository{db: db}
+}
+
+// Subscribe creates a subscription relationship
+func (r *SubscriptionRepository) Subscribe(ctx context.Context, subscriberUserID, subscribedToUserID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SubscriptionRepository.Subscribe"); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	if r.db.findSubscription(subscriberUserID, subscribedToUserID) != nil {
+		// ON CONFLICT DO NOTHING
+		return nil
+	}
+
+	r.db.subscriptions = append(r.db.subscriptions, &subscriptionRow{
+		id:                 r.db.nextID("subscriptions"),
+		subscriberUserID:   subscriberUserID,
+		subscribedToUserID: subscribedToUserID,
+		createdAt:          r.db.now(),
+	})
+	return nil
+}
+
+// Unsubscribe removes a subscription relationship
+func (r *SubscriptionRepository) Unsubscribe(ctx context.Context, subscriberUserID, subscribedToUserID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SubscriptionRepository.Unsubscribe"); err != nil {
+		return fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+
+	subs := r.db.subscriptions[:0]
+	for _, sub := range r.db.subscriptions {
+		if sub.subscriberUserID != subscriberUserID || sub.subscribedToUserID != subscribedToUserID {
+			subs = append(subs, sub)
+		}
+	}
+	r.db.subscriptions = subs
+	return nil
+}
+
+// IsSubscribed checks if a user is subscribed to another user
+func (r *SubscriptionRepository) IsSubscribed(ctx context.Context, subscriberUserID, subscribedToUserID int64) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SubscriptionRepository.IsSubscribed"); err != nil {
+		return false, fmt.Errorf("failed to check subscription: %w", err)
+	}
+
+	return r.db.findSubscription(subscriberUserID, subscribedToUserID) != nil, nil
+}
+
+// GetSubscriberCount returns the number of subscribers for a user
+func (r *SubscriptionRepository) GetSubscriberCount(ctx context.Context, userID int64) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SubscriptionRepository.GetSubscriberCount"); err != nil {
+		return 0, fmt.Errorf("failed to get subscriber count: %w", err)
+	}
+
+	var count int64
+	for _, sub := range r.db.subscriptions {
+		if sub.subscribedToUserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetSubscribedChannels returns the list of channels a user is subscribed to
+func (r *SubscriptionRepository) GetSubscribedChannels(ctx context.Context, subscriberUserID int64) ([]*model.SubscriptionWithProfile, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SubscriptionRepository.GetSubscribedChannels"); err != nil {
+		return nil, fmt.Errorf("failed to get subscribed channels: %w", err)
+	}
+
+	var subscriptions []*model.SubscriptionWithProfile
+	for _, sub := range r.db.subscriptions {
+		if sub.subscriberUserID != subscriberUserID {
+			continue
+		}
+		subscriptions = append(subscriptions, &model.SubscriptionWithProfile{
+			Subscription: model.Subscription{
+				ID:                 sub.id,
+				SubscriberUserID:   sub.subscriberUserID,
+				SubscribedToUserID: sub.subscribedToUserID,
+				CreatedAt:          sub.createdAt,
+			},
+			Profile: r.db.profileOrEmpty(sub.subscribedToUserID),
+		})
+	}
+
+	// ORDER BY s.created_at DESC
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreatedAt.After(subscriptions[j].CreatedAt)
+	})
+	return subscriptions, nil
+}
+
+// GetSubscriptionFeed returns ready public videos from channels the user is subscribed to
+func (r *SubscriptionRepository) GetSubscriptionFeed(ctx context.Context, subscriberUserID int64, p model.PageRequest) ([]*model.VideoWithProfile, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SubscriptionRepository.GetSubscriptionFeed"); err != nil {
+		return nil, fmt.Errorf("failed to get subscription feed: %w", err)
+	}
+
+	var matched []*model.Video
+	for _, video := range r.db.videos {
+		if isListed(video) && r.db.findSubscription(subscriberUserID, video.UserID) != nil {
+			matched = append(matched, video)
+		}
+	}
+	sortVideosNewestFirst(matched)
+
+	var videos []*model.VideoWithProfile
+	for _, video := range pageAfter(matched, p, true, videoKey) {
+		videos = append(videos, r.db.videoWithProfile(video))
+	}
+	return videos, nil
+}
+
+// findSubscription returns the subscription row if it exists; callers must hold db.mu
+func (db *DB) findSubscription(subscriberUserID, subscribedToUserID int64) *subscriptionRow {
+	for _, sub := range db.subscriptions {
+		if sub.subscriberUserID == subscriberUserID && sub.subscribedToUserID == subscribedToUserID {
+			return sub
+		}
+	}
+	return nil
+}
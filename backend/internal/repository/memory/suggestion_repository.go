@@ -0,0 +1,271 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type SuggestionRepository struct {
+	db *DB
+}
+
+var _ repository.SuggestionStore = (*SuggestionRepository)(nil)
+
+func NewSuggestionRepository(db *DB) *SuggestionRepository {
+	return &SuggestionRepository{db: db}
+}
+
+func (r *SuggestionRepository) RecordQuery(ctx context.Context, userID int64, query string, keepRecent int, now time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SuggestionRepository.RecordQuery"); err != nil {
+		return fmt.Errorf("failed to record search query: %w", err)
+	}
+
+	r.db.searchQueries = append(r.db.searchQueries, &searchQueryRow{
+		id:        r.db.nextID("search_queries"),
+		userID:    userID,
+		query:     query,
+		createdAt: now,
+	})
+
+	// ON CONFLICT (user_id, query) DO UPDATE SET searched_at = EXCLUDED.searched_at
+	var mine []*recentSearchRow
+	found := false
+	for _, row := range r.db.recentSearches {
+		if row.userID == userID && row.query == query {
+			row.searchedAt = now
+			found = true
+		}
+	}
+	if !found {
+		r.db.recentSearches = append(r.db.recentSearches, &recentSearchRow{
+			id:         r.db.nextID("user_search_history"),
+			userID:     userID,
+			query:      query,
+			searchedAt: now,
+		})
+	}
+
+	// Keep only the keepRecent latest searches of the user
+	for _, row := range r.db.recentSearches {
+		if row.userID == userID {
+			mine = append(mine, row)
+		}
+	}
+	sort.Slice(mine, func(i, j int) bool { return mine[i].searchedAt.After(mine[j].searchedAt) })
+	if len(mine) > keepRecent {
+		stale := make(map[*recentSearchRow]bool)
+		for _, row := range mine[keepRecent:] {
+			stale[row] = true
+		}
+		kept := r.db.recentSearches[:0]
+		for _, row := range r.db.recentSearches {
+			if !stale[row] {
+				kept = append(kept, row)
+			}
+		}
+		r.db.recentSearches = kept
+	}
+	return nil
+}
+
+func (r *SuggestionRepository) FindRecentQueries(ctx context.Context, userID int64, prefix string, limit int) ([]*model.RecentSearch, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SuggestionRepository.FindRecentQueries"); err != nil {
+		return nil, fmt.Errorf("failed to find recent searches: %w", err)
+	}
+
+	var rows []*recentSearchRow
+	for _, row := range r.db.recentSearches {
+		if row.userID == userID && hasPrefixFold(row.query, prefix) {
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].searchedAt.After(rows[j].searchedAt) })
+
+	start, end := page(len(rows), limit, 0)
+	searches := []*model.RecentSearch{}
+	for _, row := range rows[start:end] {
+		searches = append(searches, &model.RecentSearch{Query: row.query, SearchedAt: row.searchedAt})
+	}
+	return searches, nil
+}
+
+func (r *SuggestionRepository) ClearRecentQueries(ctx context.Context, userID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SuggestionRepository.ClearRecentQueries"); err != nil {
+		return fmt.Errorf("failed to clear recent searches: %w", err)
+	}
+
+	kept := r.db.recentSearches[:0]
+	for _, row := range r.db.recentSearches {
+		if row.userID != userID {
+			kept = append(kept, row)
+		}
+	}
+	r.db.recentSearches = kept
+	return nil
+}
+
+func (r *SuggestionRepository) FindPopularQueries(ctx context.Context, prefix string, limit int) ([]string, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SuggestionRepository.FindPopularQueries"); err != nil {
+		return nil, fmt.Errorf("failed to find suggestions: %w", err)
+	}
+
+	var rows []*popularQueryRow
+	for _, row := range r.db.popularQueries {
+		if hasPrefixFold(row.query, prefix) {
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].searchCount == rows[j].searchCount {
+			return rows[i].query < rows[j].query
+		}
+		return rows[i].searchCount > rows[j].searchCount
+	})
+
+	start, end := page(len(rows), limit, 0)
+	queries := []string{}
+	for _, row := range rows[start:end] {
+		queries = append(queries, row.query)
+	}
+	return queries, nil
+}
+
+func (r *SuggestionRepository) FindTitles(ctx context.Context, prefix string, limit int) ([]string, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SuggestionRepository.FindTitles"); err != nil {
+		return nil, fmt.Errorf("failed to find suggestions: %w", err)
+	}
+
+	var videos []*model.Video
+	for _, video := range r.db.videos {
+		if isListed(video) && hasPrefixFold(video.Title, prefix) {
+			videos = append(videos, video)
+		}
+	}
+	// ORDER BY view_count DESC, id DESC
+	sort.Slice(videos, func(i, j int) bool {
+		if videos[i].ViewCount == videos[j].ViewCount {
+			return videos[i].ID > videos[j].ID
+		}
+		return videos[i].ViewCount > videos[j].ViewCount
+	})
+
+	start, end := page(len(videos), limit, 0)
+	titles := []string{}
+	for _, video := range videos[start:end] {
+		titles = append(titles, video.Title)
+	}
+	return titles, nil
+}
+
+func (r *SuggestionRepository) FindChannelNames(ctx context.Context, prefix string, limit int) ([]string, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SuggestionRepository.FindChannelNames"); err != nil {
+		return nil, fmt.Errorf("failed to find suggestions: %w", err)
+	}
+
+	subscribers := make(map[int64]int)
+	for _, sub := range r.db.subscriptions {
+		subscribers[sub.subscribedToUserID]++
+	}
+
+	var profiles []*model.Profile
+	for _, profile := range r.db.profiles {
+		if profile.ChannelName != "" && hasPrefixFold(profile.ChannelName, prefix) {
+			profiles = append(profiles, profile)
+		}
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		a, b := subscribers[profiles[i].UserID], subscribers[profiles[j].UserID]
+		if a == b {
+			return profiles[i].ID < profiles[j].ID
+		}
+		return a > b
+	})
+
+	start, end := page(len(profiles), limit, 0)
+	names := []string{}
+	for _, profile := range profiles[start:end] {
+		names = append(names, profile.ChannelName)
+	}
+	return names, nil
+}
+
+func (r *SuggestionRepository) RecomputePopularQueries(ctx context.Context, since time.Time, minSearches int64, limit int, now time.Time) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("SuggestionRepository.RecomputePopularQueries"); err != nil {
+		return 0, fmt.Errorf("failed to recompute popular queries: %w", err)
+	}
+
+	kept := r.db.searchQueries[:0]
+	for _, row := range r.db.searchQueries {
+		if !row.createdAt.Before(since) {
+			kept = append(kept, row)
+		}
+	}
+	r.db.searchQueries = kept
+
+	// COUNT(DISTINCT user_id): users count once per query, deleted users (NULL) not at all
+	counts := make(map[string]int64)
+	seen := make(map[string]map[int64]bool)
+	for _, row := range r.db.searchQueries {
+		if row.userID == 0 {
+			continue
+		}
+		if seen[row.query] == nil {
+			seen[row.query] = make(map[int64]bool)
+		}
+		if seen[row.query][row.userID] {
+			continue
+		}
+		seen[row.query][row.userID] = true
+		counts[row.query]++
+	}
+
+	var popular []*popularQueryRow
+	for query, count := range counts {
+		if count >= minSearches {
+			popular = append(popular, &popularQueryRow{query: query, searchCount: count})
+		}
+	}
+	sort.Slice(popular, func(i, j int) bool {
+		if popular[i].searchCount == popular[j].searchCount {
+			return popular[i].query < popular[j].query
+		}
+		return popular[i].searchCount > popular[j].searchCount
+	})
+	if len(popular) > limit {
+		popular = popular[:limit]
+	}
+	r.db.popularQueries = popular
+	return int64(len(popular)), nil
+}
+
+// hasPrefixFold mirrors `lower(s) LIKE lower(prefix) || '%'`
+func hasPrefixFold(s, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(s), strings.ToLower(prefix))
+}
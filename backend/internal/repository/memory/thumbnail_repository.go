@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type ThumbnailRepository struct {
+	db *DB
+}
+
+var _ repository.ThumbnailStore = (*ThumbnailRepository)(nil)
+
+func NewThumbnailRepository(db *DB) *ThumbnailRepository {
+	return &ThumbnailRepository{db: db}
+}
+
+func (r *ThumbnailRepository) ReplaceGenerated(ctx context.Context, videoID int64, thumbnails []*model.VideoThumbnail) ([]*model.VideoThumbnail, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("ThumbnailRepository.ReplaceGenerated"); err != nil {
+		return nil, fmt.Errorf("failed to replace thumbnails: %w", err)
+	}
+
+	return r.db.replaceThumbnails(videoID, model.ThumbnailSourceGenerated, thumbnails), nil
+}
+
+func (r *ThumbnailRepository) ReplaceCustom(ctx context.Context, thumbnail *model.VideoThumbnail) ([]*model.VideoThumbnail, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("ThumbnailRepository.ReplaceCustom"); err != nil {
+		return nil, fmt.Errorf("failed to replace thumbnail: %w", err)
+	}
+
+	return r.db.replaceThumbnails(thumbnail.VideoID, model.ThumbnailSourceCustom, []*model.VideoThumbnail{thumbnail}), nil
+}
+
+func (r *ThumbnailRepository) FindByID(ctx context.Context, id int64) (*model.VideoThumbnail, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("ThumbnailRepository.FindByID"); err != nil {
+		return nil, fmt.Errorf("failed to find thumbnail: %w", err)
+	}
+
+	for _, thumbnail := range r.db.thumbnails {
+		if thumbnail.ID == id {
+			found := *thumbnail
+			return &found, nil
+		}
+	}
+	return nil, notFound("thumbnail")
+}
+
+// FindByVideoID orders like the SQL implementation: generated by time offset, then custom
+func (r *ThumbnailRepository) FindByVideoID(ctx context.Context, videoID int64) ([]*model.VideoThumbnail, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("ThumbnailRepository.FindByVideoID"); err != nil {
+		return nil, fmt.Errorf("failed to find thumbnails: %w", err)
+	}
+
+	thumbnails := []*model.VideoThumbnail{}
+	for _, thumbnail := range r.db.thumbnails {
+		if thumbnail.VideoID == videoID {
+			t := *thumbnail
+			thumbnails = append(thumbnails, &t)
+		}
+	}
+	sort.Slice(thumbnails, func(i, j int) bool {
+		a, b := thumbnails[i], thumbnails[j]
+		if a.Source != b.Source {
+			return a.Source > b.Source
+		}
+		if a.TimeOffset != b.TimeOffset {
+			return a.TimeOffset < b.TimeOffset
+		}
+		return a.ID < b.ID
+	})
+	return thumbnails, nil
+}
+
+// replaceThumbnails deletes the video's thumbnails of one source, inserts new ones
+// and returns the deleted rows; callers must hold db.mu
+func (db *DB) replaceThumbnails(videoID int64, source string, thumbnails []*model.VideoThumbnail) []*model.VideoThumbnail {
+	var removed []*model.VideoThumbnail
+	kept := db.thumbnails[:0]
+	for _, thumbnail := range db.thumbnails {
+		if thumbnail.VideoID == videoID && thumbnail.Source == source {
+			removed = append(removed, thumbnail)
+			continue
+		}
+		kept = append(kept, thumbnail)
+	}
+	db.thumbnails = kept
+
+	for _, thumbnail := range thumbnails {
+		thumbnail.ID = db.nextID("video_thumbnails")
+		thumbnail.VideoID = videoID
+		thumbnail.Source = source
+		thumbnail.CreatedAt = db.now()
+
+		stored := *thumbnail
+		db.thumbnails = append(db.thumbnails, &stored)
+	}
+	return removed
+}
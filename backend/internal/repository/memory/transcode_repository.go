@@ -0,0 +1,200 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type TranscodeRepository struct {
+	db *DB
+}
+
+var _ repository.TranscodeStore = (*TranscodeRepository)(nil)
+
+func NewTranscodeRepository(db *DB) *TranscodeRepository {
+	return &TranscodeRepository{db: db}
+}
+
+func (r *TranscodeRepository) CreateJob(ctx context.Context, videoID int64, sourceURL string) (*model.TranscodeJob, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("TranscodeRepository.CreateJob"); err != nil {
+		return nil, fmt.Errorf("failed to create transcode job: %w", err)
+	}
+
+	job := &model.TranscodeJob{
+		ID:        r.db.nextID("transcode_jobs"),
+		VideoID:   videoID,
+		SourceURL: sourceURL,
+		Status:    model.TranscodeStatusPending,
+		CreatedAt: r.db.now(),
+	}
+	job.UpdatedAt = job.CreatedAt
+
+	r.db.transcodeJobs[job.ID] = job
+	return copyTranscodeJob(job), nil
+}
+
+// ClaimNext mirrors the SQL implementation: oldest pending job, or a stale running one
+func (r *TranscodeRepository) ClaimNext(ctx context.Context, staleBefore time.Time) (*model.TranscodeJob, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("TranscodeRepository.ClaimNext"); err != nil {
+		return nil, fmt.Errorf("failed to claim transcode job: %w", err)
+	}
+
+	var next *model.TranscodeJob
+	for _, job := range r.db.transcodeJobs {
+		claimable := job.Status == model.TranscodeStatusPending ||
+			(job.Status == model.TranscodeStatusRunning && job.StartedAt != nil && job.StartedAt.Before(staleBefore))
+		if claimable && (next == nil || job.CreatedAt.Before(next.CreatedAt)) {
+			next = job
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	startedAt := r.db.now()
+	next.Status = model.TranscodeStatusRunning
+	next.Attempts++
+	next.StartedAt = &startedAt
+	next.UpdatedAt = startedAt
+	return copyTranscodeJob(next), nil
+}
+
+func (r *TranscodeRepository) CompleteJob(ctx context.Context, jobID, videoID int64, masterPlaylistURL string, renditions []*model.VideoRendition) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("TranscodeRepository.CompleteJob"); err != nil {
+		return fmt.Errorf("failed to complete transcode job: %w", err)
+	}
+
+	kept := r.db.renditions[:0]
+	for _, rendition := range r.db.renditions {
+		if rendition.VideoID != videoID {
+			kept = append(kept, rendition)
+		}
+	}
+	r.db.renditions = kept
+
+	for _, rendition := range renditions {
+		rendition.ID = r.db.nextID("video_renditions")
+		rendition.VideoID = videoID
+		rendition.CreatedAt = r.db.now()
+		stored := *rendition
+		stored.SegmentURLs = append([]string(nil), rendition.SegmentURLs...)
+		r.db.renditions = append(r.db.renditions, &stored)
+	}
+
+	if job, ok := r.db.transcodeJobs[jobID]; ok {
+		job.Status = model.TranscodeStatusCompleted
+		job.Error = ""
+		job.MasterPlaylistURL = masterPlaylistURL
+		job.UpdatedAt = r.db.now()
+	}
+	return nil
+}
+
+func (r *TranscodeRepository) FailJob(ctx context.Context, jobID int64, errMsg string, retry bool) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("TranscodeRepository.FailJob"); err != nil {
+		return fmt.Errorf("failed to update transcode job: %w", err)
+	}
+
+	if job, ok := r.db.transcodeJobs[jobID]; ok {
+		job.Status = model.TranscodeStatusFailed
+		if retry {
+			job.Status = model.TranscodeStatusPending
+		}
+		job.Error = errMsg
+		job.UpdatedAt = r.db.now()
+	}
+	return nil
+}
+
+func (r *TranscodeRepository) FindLatestCompletedJob(ctx context.Context, videoID int64) (*model.TranscodeJob, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("TranscodeRepository.FindLatestCompletedJob"); err != nil {
+		return nil, fmt.Errorf("failed to find transcode job: %w", err)
+	}
+
+	var latest *model.TranscodeJob
+	for _, job := range r.db.transcodeJobs {
+		if job.VideoID == videoID && job.Status == model.TranscodeStatusCompleted &&
+			(latest == nil || job.UpdatedAt.After(latest.UpdatedAt)) {
+			latest = job
+		}
+	}
+	if latest == nil {
+		return nil, notFound("transcode job")
+	}
+	return copyTranscodeJob(latest), nil
+}
+
+func (r *TranscodeRepository) FindLatestJob(ctx context.Context, videoID int64) (*model.TranscodeJob, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("TranscodeRepository.FindLatestJob"); err != nil {
+		return nil, fmt.Errorf("failed to find transcode job: %w", err)
+	}
+
+	// IDs increase with created_at
+	var latest *model.TranscodeJob
+	for _, job := range r.db.transcodeJobs {
+		if job.VideoID == videoID && (latest == nil || job.ID > latest.ID) {
+			latest = job
+		}
+	}
+	if latest == nil {
+		return nil, notFound("transcode job")
+	}
+	return copyTranscodeJob(latest), nil
+}
+
+func (r *TranscodeRepository) FindRenditions(ctx context.Context, videoID int64) ([]*model.VideoRendition, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("TranscodeRepository.FindRenditions"); err != nil {
+		return nil, fmt.Errorf("failed to find renditions: %w", err)
+	}
+
+	renditions := []*model.VideoRendition{}
+	for _, rendition := range r.db.renditions {
+		if rendition.VideoID == videoID {
+			rd := *rendition
+			rd.SegmentURLs = append([]string(nil), rendition.SegmentURLs...)
+			renditions = append(renditions, &rd)
+		}
+	}
+
+	// ORDER BY height DESC
+	sort.Slice(renditions, func(i, j int) bool {
+		return renditions[i].Height > renditions[j].Height
+	})
+	return renditions, nil
+}
+
+// copyTranscodeJob returns a copy that does not share the StartedAt pointer
+func copyTranscodeJob(job *model.TranscodeJob) *model.TranscodeJob {
+	j := *job
+	if job.StartedAt != nil {
+		startedAt := *job.StartedAt
+		j.StartedAt = &startedAt
+	}
+	return &j
+}
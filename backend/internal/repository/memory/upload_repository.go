@@ -0,0 +1,194 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type UploadRepository struct {
+	db *DB
+}
+
+var _ repository.UploadStore = (*UploadRepository)(nil)
+
+func NewUploadRepository(db *DB) *UploadRepository {
+	return &UploadRepository{db: db}
+}
+
+func (r *UploadRepository) Create(ctx context.Context, session *model.UploadSession) (*model.UploadSession, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UploadRepository.Create"); err != nil {
+		return nil, fmt.Errorf("failed to create upload session: %w", err)
+	}
+	if _, ok := r.db.uploads[session.ID]; ok {
+		return nil, fmt.Errorf("failed to create upload session: duplicate id %s", session.ID)
+	}
+
+	session.UploadOffset = 0
+	session.PartCount = 0
+	session.CreatedAt = r.db.now()
+	session.UpdatedAt = session.CreatedAt
+
+	r.db.uploads[session.ID] = copyUploadSession(session)
+	return session, nil
+}
+
+func (r *UploadRepository) FindByID(ctx context.Context, id string) (*model.UploadSession, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UploadRepository.FindByID"); err != nil {
+		return nil, fmt.Errorf("failed to find upload session: %w", err)
+	}
+
+	session, ok := r.db.uploads[id]
+	if !ok {
+		return nil, notFound("upload session")
+	}
+	return copyUploadSession(session), nil
+}
+
+// AddPart mirrors the conditional UPDATE of the SQL implementation
+func (r *UploadRepository) AddPart(ctx context.Context, part *model.UploadPart, expiresAt time.Time) (*model.UploadSession, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UploadRepository.AddPart"); err != nil {
+		return nil, fmt.Errorf("failed to add upload part: %w", err)
+	}
+
+	session, ok := r.db.uploads[part.UploadID]
+	if !ok ||
+		session.Status != model.UploadStatusUploading ||
+		session.UploadOffset != part.PartOffset ||
+		session.PartCount != part.PartNumber-1 ||
+		session.UploadOffset+part.Size > session.UploadLength {
+		return nil, fmt.Errorf("failed to add upload part: %w", repository.ErrUploadConflict)
+	}
+
+	session.UploadOffset += part.Size
+	session.PartCount++
+	session.ExpiresAt = expiresAt
+	session.UpdatedAt = r.db.now()
+
+	part.CreatedAt = session.UpdatedAt
+	stored := *part
+	r.db.uploadParts = append(r.db.uploadParts, &stored)
+
+	return copyUploadSession(session), nil
+}
+
+func (r *UploadRepository) ListParts(ctx context.Context, uploadID string) ([]*model.UploadPart, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UploadRepository.ListParts"); err != nil {
+		return nil, fmt.Errorf("failed to list upload parts: %w", err)
+	}
+
+	parts := []*model.UploadPart{}
+	for _, part := range r.db.uploadParts {
+		if part.UploadID == uploadID {
+			p := *part
+			parts = append(parts, &p)
+		}
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+	return parts, nil
+}
+
+func (r *UploadRepository) UpdateStatus(ctx context.Context, id, from, to string) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UploadRepository.UpdateStatus"); err != nil {
+		return false, fmt.Errorf("failed to update upload status: %w", err)
+	}
+
+	session, ok := r.db.uploads[id]
+	if !ok || session.Status != from {
+		return false, nil
+	}
+	session.Status = to
+	session.UpdatedAt = r.db.now()
+	return true, nil
+}
+
+func (r *UploadRepository) Complete(ctx context.Context, id string, videoID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UploadRepository.Complete"); err != nil {
+		return fmt.Errorf("failed to complete upload: %w", err)
+	}
+
+	if session, ok := r.db.uploads[id]; ok {
+		session.Status = model.UploadStatusCompleted
+		session.VideoID = &videoID
+		session.UpdatedAt = r.db.now()
+	}
+	return nil
+}
+
+func (r *UploadRepository) FindExpired(ctx context.Context, before time.Time, limit int) ([]*model.UploadSession, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UploadRepository.FindExpired"); err != nil {
+		return nil, fmt.Errorf("failed to find expired uploads: %w", err)
+	}
+
+	sessions := []*model.UploadSession{}
+	for _, session := range r.db.uploads {
+		if session.ExpiresAt.Before(before) {
+			sessions = append(sessions, copyUploadSession(session))
+		}
+	}
+
+	// ORDER BY expires_at ASC
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].ExpiresAt.Before(sessions[j].ExpiresAt)
+	})
+
+	start, end := page(len(sessions), limit, 0)
+	return sessions[start:end], nil
+}
+
+func (r *UploadRepository) Delete(ctx context.Context, id string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UploadRepository.Delete"); err != nil {
+		return fmt.Errorf("failed to delete upload session: %w", err)
+	}
+
+	delete(r.db.uploads, id)
+
+	parts := r.db.uploadParts[:0]
+	for _, part := range r.db.uploadParts {
+		if part.UploadID != id {
+			parts = append(parts, part)
+		}
+	}
+	r.db.uploadParts = parts
+	return nil
+}
+
+// copyUploadSession returns a copy that does not share the VideoID pointer
+func copyUploadSession(session *model.UploadSession) *model.UploadSession {
+	s := *session
+	if session.VideoID != nil {
+		videoID := *session.VideoID
+		s.VideoID = &videoID
+	}
+	return &s
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type UserIdentityRepository struct {
+	db *DB
+}
+
+var _ repository.UserIdentityStore = (*UserIdentityRepository)(nil)
+
+func NewUserIdentityRepository(db *DB) *UserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+func (r *UserIdentityRepository) Create(ctx context.Context, identity *model.UserIdentity) (*model.UserIdentity, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserIdentityRepository.Create"); err != nil {
+		return nil, fmt.Errorf("failed to create user identity: %w", err)
+	}
+	if r.db.findUserIdentity(identity.Provider, identity.Subject) != nil {
+		return nil, fmt.Errorf("failed to create user identity: %s identity %s already exists", identity.Provider, identity.Subject)
+	}
+
+	identity.ID = r.db.nextID("user_identities")
+	identity.CreatedAt = r.db.now()
+	identity.LastLoginAt = identity.CreatedAt
+
+	stored := *identity
+	r.db.userIdentities[identity.ID] = &stored
+	return identity, nil
+}
+
+func (r *UserIdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserIdentityRepository.FindByProviderSubject"); err != nil {
+		return nil, fmt.Errorf("failed to find user identity: %w", err)
+	}
+
+	identity := r.db.findUserIdentity(provider, subject)
+	if identity == nil {
+		return nil, fmt.Errorf("failed to find user identity: %w", notFound("user identity"))
+	}
+	found := *identity
+	return &found, nil
+}
+
+// FindByUserID returns the provider accounts linked to the user, oldest first
+func (r *UserIdentityRepository) FindByUserID(ctx context.Context, userID int64) ([]*model.UserIdentity, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserIdentityRepository.FindByUserID"); err != nil {
+		return nil, fmt.Errorf("failed to find user identities: %w", err)
+	}
+
+	identities := []*model.UserIdentity{}
+	for _, identity := range r.db.userIdentities {
+		if identity.UserID == userID {
+			found := *identity
+			identities = append(identities, &found)
+		}
+	}
+
+	// ORDER BY created_at, id
+	sort.Slice(identities, func(i, j int) bool {
+		return compareKeys(
+			model.PageCursor{Time: identities[i].CreatedAt, ID: identities[i].ID},
+			model.PageCursor{Time: identities[j].CreatedAt, ID: identities[j].ID},
+		) < 0
+	})
+	return identities, nil
+}
+
+// RecordLogin records a sign-in with the identity and the email the provider reported
+func (r *UserIdentityRepository) RecordLogin(ctx context.Context, id int64, email string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserIdentityRepository.RecordLogin"); err != nil {
+		return fmt.Errorf("failed to record login: %w", err)
+	}
+
+	if identity, ok := r.db.userIdentities[id]; ok {
+		identity.Email = email
+		identity.LastLoginAt = r.db.now()
+	}
+	return nil
+}
+
+// findUserIdentity returns the stored identity of a provider account; callers must hold db.mu
+func (db *DB) findUserIdentity(provider, subject string) *model.UserIdentity {
+	for _, identity := range db.userIdentities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity
+		}
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type UserRepository struct {
+	db *DB
+}
+
+var _ repository.UserStore = (*UserRepository)(nil)
+
+func NewUserRepository(db *DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) Create(ctx context.Context, email, passwordHash string) (*model.User, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserRepository.Create"); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	if r.db.findUserByEmail(email) != nil {
+		return nil, fmt.Errorf("failed to create user: email %s already exists", email)
+	}
+
+	user := &model.User{
+		ID:           r.db.nextID("users"),
+		Email:        email,
+		Role:         model.RoleUser,
+		Status:       model.AccountStatusActive,
+		PasswordHash: passwordHash,
+	}
+	user.CreatedAt = r.db.now()
+	user.UpdatedAt = user.CreatedAt
+
+	stored := *user
+	r.db.users[user.ID] = &stored
+	return user, nil
+}
+
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserRepository.FindByEmail"); err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	user := r.db.findUserByEmail(email)
+	if user == nil {
+		return nil, notFound("user")
+	}
+	found := *user
+	return &found, nil
+}
+
+func (r *UserRepository) FindByID(ctx context.Context, id int64) (*model.User, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserRepository.FindByID"); err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	user, ok := r.db.users[id]
+	if !ok {
+		return nil, notFound("user")
+	}
+	found := *user
+	return &found, nil
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserRepository.UpdatePassword"); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	user, ok := r.db.users[id]
+	if !ok {
+		return fmt.Errorf("failed to update password: %w", notFound("user"))
+	}
+	user.PasswordHash = passwordHash
+	user.UpdatedAt = r.db.now()
+	return nil
+}
+
+// SetEmailVerified marks the user's email address as verified; verifying it again keeps the original time
+func (r *UserRepository) SetEmailVerified(ctx context.Context, id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserRepository.SetEmailVerified"); err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	user, ok := r.db.users[id]
+	if !ok {
+		return fmt.Errorf("failed to verify email: %w", notFound("user"))
+	}
+	now := r.db.now()
+	if user.EmailVerifiedAt == nil {
+		user.EmailVerifiedAt = &now
+	}
+	user.UpdatedAt = now
+	return nil
+}
+
+// SetTOTPSecret stores the secret of a TOTP enrollment that has not been confirmed yet
+func (r *UserRepository) SetTOTPSecret(ctx context.Context, id int64, secret string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserRepository.SetTOTPSecret"); err != nil {
+		return fmt.Errorf("failed to set TOTP secret: %w", err)
+	}
+
+	user, ok := r.db.users[id]
+	if !ok {
+		return fmt.Errorf("failed to set TOTP secret: %w", notFound("user"))
+	}
+	user.TOTPSecret = secret
+	user.TOTPEnabledAt = nil
+	user.TOTPLastStep = 0
+	user.UpdatedAt = r.db.now()
+	return nil
+}
+
+// EnableTOTP turns on TOTP with the stored secret; step is the period of the code that confirmed it
+func (r *UserRepository) EnableTOTP(ctx context.Context, id int64, step int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserRepository.EnableTOTP"); err != nil {
+		return fmt.Errorf("failed to enable TOTP: %w", err)
+	}
+
+	user, ok := r.db.users[id]
+	if !ok || user.TOTPSecret == "" {
+		return fmt.Errorf("failed to enable TOTP: %w", notFound("user"))
+	}
+	now := r.db.now()
+	user.TOTPEnabledAt = &now
+	user.TOTPLastStep = step
+	user.UpdatedAt = now
+	return nil
+}
+
+// DisableTOTP turns off TOTP and forgets the secret
+func (r *UserRepository) DisableTOTP(ctx context.Context, id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserRepository.DisableTOTP"); err != nil {
+		return fmt.Errorf("failed to disable TOTP: %w", err)
+	}
+
+	if user, ok := r.db.users[id]; ok {
+		user.TOTPSecret = ""
+		user.TOTPEnabledAt = nil
+		user.TOTPLastStep = 0
+		user.UpdatedAt = r.db.now()
+	}
+	return nil
+}
+
+// UseTOTPStep records that a code of the step was accepted and reports whether the step
+// is later than the last one used
+func (r *UserRepository) UseTOTPStep(ctx context.Context, id int64, step int64) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserRepository.UseTOTPStep"); err != nil {
+		return false, fmt.Errorf("failed to use TOTP code: %w", err)
+	}
+
+	user, ok := r.db.users[id]
+	if !ok || user.TOTPEnabledAt == nil || user.TOTPLastStep >= step {
+		return false, nil
+	}
+	user.TOTPLastStep = step
+	return true, nil
+}
+
+// findUserByEmail returns the stored user with the email; callers must hold db.mu
+func (db *DB) findUserByEmail(email string) *model.User {
+	for _, user := range db.users {
+		if user.Email == email {
+			return user
+		}
+	}
+	return nil
+}
+
+func (r *UserRepository) SetRole(ctx context.Context, id int64, role string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserRepository.SetRole"); err != nil {
+		return fmt.Errorf("failed to set role: %w", err)
+	}
+
+	if user, ok := r.db.users[id]; ok {
+		user.Role = role
+		user.UpdatedAt = r.db.now()
+	}
+	return nil
+}
+
+// SetStatus changes the account status; suspendedUntil is nil unless the status is suspended
+func (r *UserRepository) SetStatus(ctx context.Context, id int64, status string, suspendedUntil *time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserRepository.SetStatus"); err != nil {
+		return fmt.Errorf("failed to set account status: %w", err)
+	}
+
+	if user, ok := r.db.users[id]; ok {
+		user.Status = status
+		user.SuspendedUntil = suspendedUntil
+		user.UpdatedAt = r.db.now()
+	}
+	return nil
+}
+
+// Search returns the users matching the filter, newest first
+func (r *UserRepository) Search(ctx context.Context, filter model.UserFilter, p model.PageRequest) ([]*model.User, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserRepository.Search"); err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	query := strings.ToLower(filter.Query)
+	var matched []*model.User
+	for _, user := range r.db.users {
+		if !strings.Contains(strings.ToLower(user.Email), query) ||
+			(filter.Role != "" && user.Role != filter.Role) ||
+			(filter.Status != "" && user.Status != filter.Status) {
+			continue
+		}
+		matched = append(matched, user)
+	}
+
+	// ORDER BY created_at DESC, id DESC
+	key := func(user *model.User) model.PageCursor {
+		return model.PageCursor{Time: user.CreatedAt, ID: user.ID}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return compareKeys(key(matched[i]), key(matched[j])) > 0
+	})
+
+	users := []*model.User{}
+	for _, user := range pageAfter(matched, p, true, key) {
+		u := *user
+		users = append(users, &u)
+	}
+	return users, nil
+}
+
+// Stats counts the users by account status as of now
+func (r *UserRepository) Stats(ctx context.Context, now time.Time) (*model.UserStats, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserRepository.Stats"); err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	stats := &model.UserStats{Total: int64(len(r.db.users))}
+	for _, user := range r.db.users {
+		switch {
+		case user.Status == model.AccountStatusBanned:
+			stats.Banned++
+		case user.Status == model.AccountStatusSuspended && user.SuspendedUntil != nil && user.SuspendedUntil.After(now):
+			stats.Suspended++
+		default:
+			stats.Active++
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type UserTokenRepository struct {
+	db *DB
+}
+
+var _ repository.UserTokenStore = (*UserTokenRepository)(nil)
+
+func NewUserTokenRepository(db *DB) *UserTokenRepository {
+	return &UserTokenRepository{db: db}
+}
+
+// Create stores a token issued at its CreatedAt
+func (r *UserTokenRepository) Create(ctx context.Context, token *model.UserToken) (*model.UserToken, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserTokenRepository.Create"); err != nil {
+		return nil, fmt.Errorf("failed to create user token: %w", err)
+	}
+	for _, t := range r.db.userTokens {
+		if t.TokenHash == token.TokenHash {
+			return nil, fmt.Errorf("failed to create user token: duplicate token hash")
+		}
+	}
+
+	token.ID = r.db.nextID("user_tokens")
+	token.UsedAt = nil
+	token.Attempts = 0
+
+	stored := *token
+	r.db.userTokens[token.ID] = &stored
+	return token, nil
+}
+
+// Find returns an unused, unexpired token
+func (r *UserTokenRepository) Find(ctx context.Context, purpose, tokenHash string, now time.Time) (*model.UserToken, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserTokenRepository.Find"); err != nil {
+		return nil, fmt.Errorf("failed to find user token: %w", err)
+	}
+
+	for _, token := range r.db.userTokens {
+		if token.TokenHash == tokenHash && token.Purpose == purpose && token.UsedAt == nil && token.ExpiresAt.After(now) {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, notFound("user token")
+}
+
+// Consume marks an unused, unexpired token as used and returns it
+func (r *UserTokenRepository) Consume(ctx context.Context, purpose, tokenHash string, now time.Time) (*model.UserToken, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserTokenRepository.Consume"); err != nil {
+		return nil, fmt.Errorf("failed to consume user token: %w", err)
+	}
+
+	for _, token := range r.db.userTokens {
+		if token.TokenHash == tokenHash && token.Purpose == purpose && token.UsedAt == nil && token.ExpiresAt.After(now) {
+			usedAt := now
+			token.UsedAt = &usedAt
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to consume user token: %w", notFound("user token"))
+}
+
+// RecordFailedAttempt counts a wrong code entered for a token and marks the token as used
+// once maxAttempts have been counted
+func (r *UserTokenRepository) RecordFailedAttempt(ctx context.Context, id int64, maxAttempts int, now time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserTokenRepository.RecordFailedAttempt"); err != nil {
+		return fmt.Errorf("failed to record failed attempt: %w", err)
+	}
+
+	token, ok := r.db.userTokens[id]
+	if !ok || token.UsedAt != nil {
+		return nil
+	}
+	token.Attempts++
+	if token.Attempts >= maxAttempts {
+		usedAt := now
+		token.UsedAt = &usedAt
+	}
+	return nil
+}
+
+// InvalidateByUserID marks the user's unused tokens of a purpose as used
+func (r *UserTokenRepository) InvalidateByUserID(ctx context.Context, userID int64, purpose string, now time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("UserTokenRepository.InvalidateByUserID"); err != nil {
+		return fmt.Errorf("failed to invalidate user tokens: %w", err)
+	}
+
+	for _, token := range r.db.userTokens {
+		if token.UserID == userID && token.Purpose == purpose && token.UsedAt == nil {
+			usedAt := now
+			token.UsedAt = &usedAt
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type VideoReactionRepository struct {
+	db *DB
+}
+
+var _ repository.VideoReactionStore = (*VideoReactionRepository)(nil)
+
+func NewVideoReactionRepository(db *DB) *VideoReactionRepository {
+	return &VideoReactionRepository{db: db}
+}
+
+// SetReaction adds or changes the user's reaction to a video
+func (r *VideoReactionRepository) SetReaction(ctx context.Context, videoID, userID int64, likeType string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("VideoReactionRepository.SetReaction"); err != nil {
+		return fmt.Errorf("failed to set video reaction: %w", err)
+	}
+
+	video, ok := r.db.videos[videoID]
+	if !ok {
+		return fmt.Errorf("failed to set video reaction: %w", notFound("video"))
+	}
+
+	if reaction := r.db.findVideoReaction(videoID, userID); reaction != nil {
+		if reaction.LikeType == likeType {
+			return nil
+		}
+		updateReactionCounts(video, reaction.LikeType, likeType)
+		reaction.LikeType = likeType
+		reaction.CreatedAt = r.db.now()
+		return nil
+	}
+
+	r.db.videoReactions = append(r.db.videoReactions, &model.VideoReaction{
+		ID:        r.db.nextID("video_reactions"),
+		VideoID:   videoID,
+		UserID:    userID,
+		LikeType:  likeType,
+		CreatedAt: r.db.now(),
+	})
+	updateReactionCounts(video, "", likeType)
+	return nil
+}
+
+// RemoveReaction removes the user's reaction to a video, if any
+func (r *VideoReactionRepository) RemoveReaction(ctx context.Context, videoID, userID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("VideoReactionRepository.RemoveReaction"); err != nil {
+		return fmt.Errorf("failed to remove video reaction: %w", err)
+	}
+
+	reaction := r.db.findVideoReaction(videoID, userID)
+	if reaction == nil {
+		return nil
+	}
+
+	reactions := r.db.videoReactions[:0]
+	for _, rr := range r.db.videoReactions {
+		if rr != reaction {
+			reactions = append(reactions, rr)
+		}
+	}
+	r.db.videoReactions = reactions
+
+	if video, ok := r.db.videos[videoID]; ok {
+		updateReactionCounts(video, reaction.LikeType, "")
+	}
+	return nil
+}
+
+// FindReaction returns the user's reaction to a video, or nil if there is none
+func (r *VideoReactionRepository) FindReaction(ctx context.Context, videoID, userID int64) (*model.VideoReaction, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("VideoReactionRepository.FindReaction"); err != nil {
+		return nil, fmt.Errorf("failed to find video reaction: %w", err)
+	}
+
+	reaction := r.db.findVideoReaction(videoID, userID)
+	if reaction == nil {
+		return nil, nil
+	}
+	found := *reaction
+	return &found, nil
+}
+
+// FindLikedVideos returns the videos the user has liked, most recently liked first
+func (r *VideoReactionRepository) FindLikedVideos(ctx context.Context, userID int64, p model.PageRequest) ([]*model.LikedVideo, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("VideoReactionRepository.FindLikedVideos"); err != nil {
+		return nil, fmt.Errorf("failed to find liked videos: %w", err)
+	}
+
+	var rows []*model.VideoReaction
+	for _, reaction := range r.db.videoReactions {
+		if reaction.UserID != userID || reaction.LikeType != model.VideoReactionLike {
+			continue
+		}
+		if video, ok := r.db.videos[reaction.VideoID]; ok && isWatchableBy(video, userID) {
+			rows = append(rows, reaction)
+		}
+	}
+
+	// ORDER BY r.created_at DESC, r.video_id DESC
+	key := func(reaction *model.VideoReaction) model.PageCursor {
+		return model.PageCursor{Time: reaction.CreatedAt, ID: reaction.VideoID}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return compareKeys(key(rows[i]), key(rows[j])) > 0
+	})
+
+	liked := []*model.LikedVideo{}
+	for _, reaction := range pageAfter(rows, p, true, key) {
+		liked = append(liked, &model.LikedVideo{
+			VideoID: reaction.VideoID,
+			LikedAt: reaction.CreatedAt,
+			Video:   r.db.videoWithProfile(r.db.videos[reaction.VideoID]),
+		})
+	}
+	return liked, nil
+}
+
+// findVideoReaction returns the user's reaction to a video; callers must hold db.mu
+func (db *DB) findVideoReaction(videoID, userID int64) *model.VideoReaction {
+	for _, reaction := range db.videoReactions {
+		if reaction.VideoID == videoID && reaction.UserID == userID {
+			return reaction
+		}
+	}
+	return nil
+}
+
+// updateReactionCounts moves a video's counters from the previous reaction to the next one ("" for none)
+func updateReactionCounts(video *model.Video, previous, next string) {
+	switch previous {
+	case model.VideoReactionLike:
+		video.LikeCount = max(video.LikeCount-1, 0)
+	case model.VideoReactionDislike:
+		video.DislikeCount = max(video.DislikeCount-1, 0)
+	}
+	switch next {
+	case model.VideoReactionLike:
+		video.LikeCount++
+	case model.VideoReactionDislike:
+		video.DislikeCount++
+	}
+}
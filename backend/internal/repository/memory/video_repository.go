@@ -0,0 +1,329 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type VideoRepository struct {
+	db *DB
+}
+
+var _ repository.VideoStore = (*VideoRepository)(nil)
+
+func NewVideoRepository(db *DB) *VideoRepository {
+	return &VideoRepository{db: db}
+}
+
+func (r *VideoRepository) Create(ctx context.Context, video *model.Video) (*model.Video, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("VideoRepository.Create"); err != nil {
+		return nil, fmt.Errorf("failed to create video: %w", err)
+	}
+
+	video.ID = r.db.nextID("videos")
+	video.CreatedAt = r.db.now()
+	video.UpdatedAt = video.CreatedAt
+
+	stored := *video
+	r.db.videos[video.ID] = &stored
+	return video, nil
+}
+
+func (r *VideoRepository) FindByID(ctx context.Context, id int64) (*model.Video, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("VideoRepository.FindByID"); err != nil {
+		return nil, fmt.Errorf("failed to find video: %w", err)
+	}
+
+	video, ok := r.db.videos[id]
+	if !ok {
+		return nil, notFound("video")
+	}
+	found := *video
+	return &found, nil
+}
+
+// FindAll returns ready public videos plus the viewer's own, like the SQL implementation
+func (r *VideoRepository) FindAll(ctx context.Context, viewerID int64, p model.PageRequest) ([]*model.Video, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("VideoRepository.FindAll"); err != nil {
+		return nil, fmt.Errorf("failed to find videos: %w", err)
+	}
+
+	all := make([]*model.Video, 0, len(r.db.videos))
+	for _, video := range r.db.videos {
+		if isListed(video) || video.UserID == viewerID {
+			all = append(all, video)
+		}
+	}
+	sortVideosNewestFirst(all)
+
+	videos := []*model.Video{}
+	for _, video := range pageAfter(all, p, true, videoKey) {
+		v := *video
+		videos = append(videos, &v)
+	}
+	return videos, nil
+}
+
+func (r *VideoRepository) Update(ctx context.Context, video *model.Video) (*model.Video, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("VideoRepository.Update"); err != nil {
+		return nil, fmt.Errorf("failed to update video: %w", err)
+	}
+
+	stored, ok := r.db.videos[video.ID]
+	if !ok {
+		return nil, fmt.Errorf("failed to update video: %w", notFound("video"))
+	}
+	stored.Title = video.Title
+	stored.Description = video.Description
+	stored.VideoURL = video.VideoURL
+	stored.ThumbnailURL = video.ThumbnailURL
+	stored.Duration = video.Duration
+	copyMetadata(stored, video)
+	stored.Status = video.Status
+	stored.FailureReason = video.FailureReason
+	stored.Visibility = video.Visibility
+	stored.PublishAt = video.PublishAt
+	stored.UpdatedAt = r.db.now()
+
+	*video = *stored
+	return video, nil
+}
+
+func (r *VideoRepository) UpdateMetadata(ctx context.Context, video *model.Video) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("VideoRepository.UpdateMetadata"); err != nil {
+		return fmt.Errorf("failed to update video metadata: %w", err)
+	}
+
+	if stored, ok := r.db.videos[video.ID]; ok {
+		stored.Duration = video.Duration
+		copyMetadata(stored, video)
+		stored.UpdatedAt = r.db.now()
+	}
+	return nil
+}
+
+func (r *VideoRepository) UpdateThumbnail(ctx context.Context, id int64, thumbnailURL string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("VideoRepository.UpdateThumbnail"); err != nil {
+		return fmt.Errorf("failed to update thumbnail: %w", err)
+	}
+
+	if video, ok := r.db.videos[id]; ok {
+		video.ThumbnailURL = thumbnailURL
+		video.UpdatedAt = r.db.now()
+	}
+	return nil
+}
+
+func (r *VideoRepository) PublishScheduled(ctx context.Context, now time.Time) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("VideoRepository.PublishScheduled"); err != nil {
+		return 0, fmt.Errorf("failed to publish scheduled videos: %w", err)
+	}
+
+	var published int64
+	for _, video := range r.db.videos {
+		if video.Visibility == model.VideoVisibilityPrivate && video.PublishAt != nil && !video.PublishAt.After(now) {
+			video.Visibility = model.VideoVisibilityPublic
+			video.PublishAt = nil
+			video.UpdatedAt = r.db.now()
+			published++
+		}
+	}
+	return published, nil
+}
+
+// copyMetadata copies the probed media columns
+func copyMetadata(dst, src *model.Video) {
+	dst.Container = src.Container
+	dst.VideoCodec = src.VideoCodec
+	dst.AudioCodec = src.AudioCodec
+	dst.Width = src.Width
+	dst.Height = src.Height
+	dst.Bitrate = src.Bitrate
+	dst.FrameRate = src.FrameRate
+}
+
+func (r *VideoRepository) UpdateStatus(ctx context.Context, id int64, status, failureReason string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("VideoRepository.UpdateStatus"); err != nil {
+		return fmt.Errorf("failed to update video status: %w", err)
+	}
+
+	if video, ok := r.db.videos[id]; ok {
+		video.Status = status
+		video.FailureReason = failureReason
+		video.UpdatedAt = r.db.now()
+	}
+	return nil
+}
+
+func (r *VideoRepository) Delete(ctx context.Context, id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("VideoRepository.Delete"); err != nil {
+		return fmt.Errorf("failed to delete video: %w", err)
+	}
+
+	r.db.deleteVideo(id)
+	return nil
+}
+
+// AddViewCounts adds buffered views to the videos' view counts; deleted videos are skipped
+func (r *VideoRepository) AddViewCounts(ctx context.Context, views map[int64]int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("VideoRepository.AddViewCounts"); err != nil {
+		return fmt.Errorf("failed to add view counts: %w", err)
+	}
+
+	for id, count := range views {
+		if video, ok := r.db.videos[id]; ok {
+			video.ViewCount += count
+		}
+	}
+	return nil
+}
+
+// deleteVideo removes a video and the rows that reference it (ON DELETE CASCADE);
+// callers must hold db.mu
+func (db *DB) deleteVideo(id int64) {
+	delete(db.videos, id)
+
+	for commentID, comment := range db.comments {
+		if comment.VideoID == id {
+			db.deleteComment(commentID)
+		}
+	}
+
+	pvs := db.playlistVideos[:0]
+	for _, pv := range db.playlistVideos {
+		if pv.videoID != id {
+			pvs = append(pvs, pv)
+		}
+	}
+	db.playlistVideos = pvs
+
+	reactions := db.videoReactions[:0]
+	for _, reaction := range db.videoReactions {
+		if reaction.VideoID != id {
+			reactions = append(reactions, reaction)
+		}
+	}
+	db.videoReactions = reactions
+
+	history := db.watchHistory[:0]
+	for _, h := range db.watchHistory {
+		if h.videoID != id {
+			history = append(history, h)
+		}
+	}
+	db.watchHistory = history
+
+	for jobID, job := range db.transcodeJobs {
+		if job.VideoID == id {
+			delete(db.transcodeJobs, jobID)
+		}
+	}
+
+	renditions := db.renditions[:0]
+	for _, rendition := range db.renditions {
+		if rendition.VideoID != id {
+			renditions = append(renditions, rendition)
+		}
+	}
+	db.renditions = renditions
+
+	thumbnails := db.thumbnails[:0]
+	for _, thumbnail := range db.thumbnails {
+		if thumbnail.VideoID != id {
+			thumbnails = append(thumbnails, thumbnail)
+		}
+	}
+	db.thumbnails = thumbnails
+
+	// upload_sessions.video_id is ON DELETE SET NULL
+	for _, session := range db.uploads {
+		if session.VideoID != nil && *session.VideoID == id {
+			session.VideoID = nil
+		}
+	}
+}
+
+// isListed mirrors `status = 'ready' AND visibility = 'public'`
+func isListed(video *model.Video) bool {
+	return video.Status == model.VideoStatusReady && video.Visibility == model.VideoVisibilityPublic
+}
+
+// isWatchableBy mirrors `(status = 'ready' AND visibility <> 'private') OR user_id = viewerID`
+func isWatchableBy(video *model.Video, viewerID int64) bool {
+	return (video.Status == model.VideoStatusReady && video.Visibility != model.VideoVisibilityPrivate) || video.UserID == viewerID
+}
+
+// videoKey is the sort key of videos listed by created_at
+func videoKey(video *model.Video) model.PageCursor {
+	return model.PageCursor{Time: video.CreatedAt, ID: video.ID}
+}
+
+// sortVideosNewestFirst orders by created_at DESC, breaking ties by ID
+// Stats counts the videos by processing status
+func (r *VideoRepository) Stats(ctx context.Context) (*model.VideoStats, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("VideoRepository.Stats"); err != nil {
+		return nil, fmt.Errorf("failed to count videos: %w", err)
+	}
+
+	stats := &model.VideoStats{Total: int64(len(r.db.videos))}
+	for _, video := range r.db.videos {
+		switch video.Status {
+		case model.VideoStatusUploading:
+			stats.Uploading++
+		case model.VideoStatusProcessing:
+			stats.Processing++
+		case model.VideoStatusReady:
+			stats.Ready++
+		case model.VideoStatusFailed:
+			stats.Failed++
+		}
+	}
+	return stats, nil
+}
+
+func sortVideosNewestFirst(videos []*model.Video) {
+	sort.Slice(videos, func(i, j int) bool {
+		if videos[i].CreatedAt.Equal(videos[j].CreatedAt) {
+			return videos[i].ID > videos[j].ID
+		}
+		return videos[i].CreatedAt.After(videos[j].CreatedAt)
+	})
+}
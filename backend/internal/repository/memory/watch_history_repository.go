@@ -0,0 +1,223 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+type WatchHistoryRepository struct {
+	db *DB
+}
+
+var _ repository.WatchHistoryStore = (*WatchHistoryRepository)(nil)
+
+func NewWatchHistoryRepository(db *DB) *WatchHistoryRepository {
+	return &WatchHistoryRepository{db: db}
+}
+
+// AddToHistory adds or updates a video in user's watch history
+func (r *WatchHistoryRepository) AddToHistory(ctx context.Context, userID, videoID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("WatchHistoryRepository.AddToHistory"); err != nil {
+		return fmt.Errorf("failed to add to history: %w", err)
+	}
+
+	if h := r.db.findHistoryRow(userID, videoID); h != nil {
+		h.watchedAt = r.db.now()
+		return nil
+	}
+
+	r.db.watchHistory = append(r.db.watchHistory, &watchHistoryRow{
+		id:        r.db.nextID("watch_history"),
+		userID:    userID,
+		videoID:   videoID,
+		watchedAt: r.db.now(),
+	})
+	return nil
+}
+
+// GetWatchHistory returns user's watch history with pagination
+func (r *WatchHistoryRepository) GetWatchHistory(ctx context.Context, userID int64, p model.PageRequest) ([]*model.WatchHistory, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("WatchHistoryRepository.GetWatchHistory"); err != nil {
+		return nil, fmt.Errorf("failed to get watch history: %w", err)
+	}
+
+	return r.db.findHistory(userID, func(*watchHistoryRow) bool { return true }, p), nil
+}
+
+// GetContinueWatching returns the videos the user has started but not finished, most recently watched first
+func (r *WatchHistoryRepository) GetContinueWatching(ctx context.Context, userID int64, minPosition, maxPercent float64, p model.PageRequest) ([]*model.WatchHistory, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("WatchHistoryRepository.GetContinueWatching"); err != nil {
+		return nil, fmt.Errorf("failed to get continue watching: %w", err)
+	}
+
+	return r.db.findHistory(userID, func(h *watchHistoryRow) bool {
+		return h.position >= minPosition && h.progress < maxPercent
+	}, p), nil
+}
+
+// findHistory returns a page of the user's watch history matching filter; callers must hold db.mu
+func (db *DB) findHistory(userID int64, filter func(*watchHistoryRow) bool, p model.PageRequest) []*model.WatchHistory {
+	var rows []*watchHistoryRow
+	for _, h := range db.watchHistory {
+		if video, ok := db.videos[h.videoID]; h.userID == userID && ok && isWatchableBy(video, userID) && filter(h) {
+			rows = append(rows, h)
+		}
+	}
+
+	// ORDER BY wh.watched_at DESC, wh.id DESC
+	key := func(h *watchHistoryRow) model.PageCursor {
+		return model.PageCursor{Time: h.watchedAt, ID: h.id}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return compareKeys(key(rows[i]), key(rows[j])) > 0
+	})
+
+	var history []*model.WatchHistory
+	for _, h := range pageAfter(rows, p, true, key) {
+		history = append(history, &model.WatchHistory{
+			ID:              h.id,
+			UserID:          h.userID,
+			VideoID:         h.videoID,
+			WatchedAt:       h.watchedAt,
+			PositionSeconds: h.position,
+			ProgressPercent: h.progress,
+			Video:           db.videoWithProfile(db.videos[h.videoID]),
+		})
+	}
+	return history
+}
+
+// SaveProgress records the user's playback position in the video and moves it to the top of the history
+func (r *WatchHistoryRepository) SaveProgress(ctx context.Context, userID, videoID int64, position float64) (*model.WatchProgress, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("WatchHistoryRepository.SaveProgress"); err != nil {
+		return nil, fmt.Errorf("failed to save watch progress: %w", err)
+	}
+
+	video, ok := r.db.videos[videoID]
+	if !ok || !isWatchableBy(video, userID) {
+		return nil, fmt.Errorf("failed to save watch progress: %w", notFound("video"))
+	}
+
+	var progress float64
+	if video.Duration > 0 {
+		position = min(position, float64(video.Duration))
+		progress = min(position/float64(video.Duration)*100, 100)
+	}
+
+	row := r.db.findHistoryRow(userID, videoID)
+	if row == nil {
+		row = &watchHistoryRow{id: r.db.nextID("watch_history"), userID: userID, videoID: videoID}
+		r.db.watchHistory = append(r.db.watchHistory, row)
+	}
+	row.watchedAt = r.db.now()
+	row.position = position
+	row.progress = progress
+	return row.watchProgress(), nil
+}
+
+// FindProgress returns how far the user has watched the video, or nil if it is not in their history
+func (r *WatchHistoryRepository) FindProgress(ctx context.Context, userID, videoID int64) (*model.WatchProgress, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("WatchHistoryRepository.FindProgress"); err != nil {
+		return nil, fmt.Errorf("failed to find watch progress: %w", err)
+	}
+
+	row := r.db.findHistoryRow(userID, videoID)
+	if row == nil {
+		return nil, nil
+	}
+	return row.watchProgress(), nil
+}
+
+// findHistoryRow returns the user's history row of a video; callers must hold db.mu
+func (db *DB) findHistoryRow(userID, videoID int64) *watchHistoryRow {
+	for _, h := range db.watchHistory {
+		if h.userID == userID && h.videoID == videoID {
+			return h
+		}
+	}
+	return nil
+}
+
+func (h *watchHistoryRow) watchProgress() *model.WatchProgress {
+	return &model.WatchProgress{
+		VideoID:         h.videoID,
+		PositionSeconds: h.position,
+		ProgressPercent: h.progress,
+		WatchedAt:       h.watchedAt,
+	}
+}
+
+// RemoveFromHistory removes a specific video from user's watch history
+func (r *WatchHistoryRepository) RemoveFromHistory(ctx context.Context, userID, videoID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("WatchHistoryRepository.RemoveFromHistory"); err != nil {
+		return fmt.Errorf("failed to remove from history: %w", err)
+	}
+
+	history := r.db.watchHistory[:0]
+	for _, h := range r.db.watchHistory {
+		if h.userID != userID || h.videoID != videoID {
+			history = append(history, h)
+		}
+	}
+	r.db.watchHistory = history
+	return nil
+}
+
+// ClearHistory removes all watch history for a user
+func (r *WatchHistoryRepository) ClearHistory(ctx context.Context, userID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("WatchHistoryRepository.ClearHistory"); err != nil {
+		return fmt.Errorf("failed to clear history: %w", err)
+	}
+
+	history := r.db.watchHistory[:0]
+	for _, h := range r.db.watchHistory {
+		if h.userID != userID {
+			history = append(history, h)
+		}
+	}
+	r.db.watchHistory = history
+	return nil
+}
+
+// GetHistoryCount returns the total number of videos in user's watch history
+func (r *WatchHistoryRepository) GetHistoryCount(ctx context.Context, userID int64) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.failure("WatchHistoryRepository.GetHistoryCount"); err != nil {
+		return 0, fmt.Errorf("failed to get history count: %w", err)
+	}
+
+	var count int64
+	for _, h := range r.db.watchHistory {
+		if video, ok := r.db.videos[h.videoID]; h.userID == userID && ok && isWatchableBy(video, userID) {
+			count++
+		}
+	}
+	return count, nil
+}
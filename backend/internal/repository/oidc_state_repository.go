@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yukito/video-platform/internal/database"
+	"github.com/yukito/video-platform/internal/model"
+)
+
+// OIDCStateStore persists sign-ins started at OpenID Connect providers
+// Expiry is checked against the caller's clock, the one that set ExpiresAt
+// OIDCStateRepository is the PostgreSQL implementation
+type OIDCStateStore interface {
+	Create(ctx context.Context, state *model.OIDCLoginState, now time.Time) error
+	Consume(ctx context.Context, stateHash string, now time.Time) (*model.OIDCLoginState, error)
+}
+
+var _ OIDCStateStore = (*OIDCStateRepository)(nil)
+
+type OIDCStateRepository struct {
+	db *database.Database
+}
+
+func NewOIDCStateRepository(db *database.Database) *OIDCStateRepository {
+	return &OIDCStateRepository{db: db}
+}
+
+// Create stores a sign-in started now and drops the expired ones of abandoned sign-ins
+func (r *OIDCStateRepository) Create(ctx context.Context, state *model.OIDCLoginState, now time.Time) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		WITH expired AS (
+			DELETE FROM oidc_login_states WHERE expires_at <= $7
+		)
+		INSERT INTO oidc_login_states (state_hash, provider, nonce, code_verifier, link_user_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, state.StateHash, state.Provider, state.Nonce, state.CodeVerifier, state.LinkUserID, state.ExpiresAt, now)
+	if err != nil {
+		return fmt.Errorf("failed to create oidc login state: %w", err)
+	}
+	return nil
+}
+
+// Consume removes a sign-in and returns it if it has not expired
+// It returns pgx.ErrNoRows if there is no such sign-in, so each state works once
+func (r *OIDCStateRepository) Consume(ctx context.Context, stateHash string, now time.Time) (*model.OIDCLoginState, error) {
+	state := &model.OIDCLoginState{}
+	err := r.db.Pool.QueryRow(ctx, `
+		DELETE FROM oidc_login_states
+		WHERE state_hash = $1 AND expires_at > $2
+		RETURNING state_hash, provider, nonce, code_verifier, link_user_id, created_at, expires_at
+	`, stateHash, now).Scan(&state.StateHash, &state.Provider, &state.Nonce, &state.CodeVerifier, &state.LinkUserID,
+		&state.CreatedAt, &state.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume oidc login state: %w", err)
+	}
+	return state, nil
+}
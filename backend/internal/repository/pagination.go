@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/yukito/video-platform/internal/model"
+)
+
+// keyset names the columns a list is ordered by; pinned is empty for lists without pinned rows
+type keyset struct {
+	pinned string
+	time   string
+	id     string
+	desc   bool
+}
+
+// after returns the condition selecting the rows that come after the cursor in the keyset's order
+// and appends its arguments to args; rows are ordered by (pinned, time, id), all in the same direction
+func (k keyset) after(cursor *model.PageCursor, args *[]any) string {
+	if cursor == nil {
+		return "TRUE"
+	}
+
+	arg := func(value any) string {
+		*args = append(*args, value)
+		return "$" + strconv.Itoa(len(*args))
+	}
+	op := ">"
+	if k.desc {
+		op = "<"
+	}
+
+	if k.pinned != "" {
+		return fmt.Sprintf("(%s, %s, %s) %s (%s, %s, %s)",
+			k.pinned, k.time, k.id, op, arg(cursor.Pinned), arg(cursor.Time), arg(cursor.ID))
+	}
+	return fmt.Sprintf("(%s, %s) %s (%s, %s)", k.time, k.id, op, arg(cursor.Time), arg(cursor.ID))
+}
+
+// orderBy returns the ORDER BY list of the keyset
+func (k keyset) orderBy() string {
+	dir := " ASC"
+	if k.desc {
+		dir = " DESC"
+	}
+
+	order := k.time + dir + ", " + k.id + dir
+	if k.pinned != "" {
+		order = k.pinned + dir + ", " + order
+	}
+	return order
+}
+
+// limitOffset returns the LIMIT and deprecated OFFSET of a page and appends their arguments to args
+func limitOffset(page model.PageRequest, args *[]any) string {
+	*args = append(*args, page.Limit, page.Offset)
+	return fmt.Sprintf("LIMIT $%d OFFSET $%d", len(*args)-1, len(*args))
+}
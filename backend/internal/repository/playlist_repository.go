@@ -8,6 +8,23 @@ import (
 	"github.com/yukito/video-platform/internal/model"
 )
 
+// PlaylistStore persists playlists and their videos
+// PlaylistRepository is the PostgreSQL implementation
+type PlaylistStore interface {
+	Create(ctx context.Context, playlist *model.Playlist) (*model.Playlist, error)
+	FindByID(ctx context.Context, id int64) (*model.Playlist, error)
+	FindByUserID(ctx context.Context, userID int64) ([]*model.Playlist, error)
+	Update(ctx context.Context, playlist *model.Playlist) (*model.Playlist, error)
+	Delete(ctx context.Context, id int64) error
+	AddVideo(ctx context.Context, playlistID, videoID int64) error
+	RemoveVideo(ctx context.Context, playlistID, videoID int64) error
+	IsVideoInPlaylist(ctx context.Context, playlistID, videoID int64) (bool, error)
+	GetPlaylistVideos(ctx context.Context, viewerID, playlistID int64) ([]*model.PlaylistVideo, error)
+	GetPlaylistsContainingVideo(ctx context.Context, userID, videoID int64) ([]int64, error)
+}
+
+var _ PlaylistStore = (*PlaylistRepository)(nil)
+
 type PlaylistRepository struct {
 	db *database.Database
 }
@@ -189,18 +206,21 @@ func (r *PlaylistRepository) IsVideoInPlaylist(ctx context.Context, playlistID,
 	return exists, err
 }
 
-// GetPlaylistVideos gets all videos in a playlist
-func (r *PlaylistRepository) GetPlaylistVideos(ctx context.Context, playlistID int64) ([]*model.PlaylistVideo, error) {
+// GetPlaylistVideos gets the videos in a playlist that viewerID can watch:
+// ready videos that are not private, plus the viewer's own
+func (r *PlaylistRepository) GetPlaylistVideos(ctx context.Context, viewerID, playlistID int64) ([]*model.PlaylistVideo, error) {
 	query := `
 		SELECT pv.id, pv.playlist_id, pv.video_id, pv.position, pv.created_at,
-		       v.id, v.user_id, v.title, v.description, v.video_url, v.thumbnail_url, v.view_count, v.created_at, v.updated_at
+		       v.id, v.user_id, v.title, v.description, v.video_url, v.thumbnail_url, v.view_count,
+		       v.like_count, v.dislike_count, v.created_at, v.updated_at
 		FROM playlist_videos pv
 		JOIN videos v ON pv.video_id = v.id
 		WHERE pv.playlist_id = $1
+			AND ((v.status = 'ready' AND v.visibility <> 'private') OR v.user_id = $2)
 		ORDER BY pv.created_at DESC
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, playlistID)
+	rows, err := r.db.Pool.Query(ctx, query, playlistID, viewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -224,6 +244,8 @@ func (r *PlaylistRepository) GetPlaylistVideos(ctx context.Context, playlistID i
 			&video.VideoURL,
 			&video.ThumbnailURL,
 			&video.ViewCount,
+			&video.LikeCount,
+			&video.DislikeCount,
 			&video.CreatedAt,
 			&video.UpdatedAt,
 		)
@@ -240,6 +262,8 @@ func (r *PlaylistRepository) GetPlaylistVideos(ctx context.Context, playlistID i
 			VideoURL:     video.VideoURL,
 			ThumbnailURL: video.ThumbnailURL,
 			ViewCount:    video.ViewCount,
+			LikeCount:    video.LikeCount,
+			DislikeCount: video.DislikeCount,
 			CreatedAt:    video.CreatedAt,
 			UpdatedAt:    video.UpdatedAt,
 		}
@@ -277,30 +301,3 @@ func (r *PlaylistRepository) GetPlaylistsContainingVideo(ctx context.Context, us
 
 	return playlistIDs, nil
 }
-
-// FindLikedPlaylistByUserID finds the "Liked Videos" playlist for a user
-func (r *PlaylistRepository) FindLikedPlaylistByUserID(ctx context.Context, userID int64) (*model.Playlist, error) {
-	query := `
-		SELECT id, user_id, title, description, visibility, created_at, updated_at
-		FROM playlists
-		WHERE user_id = $1 AND title = '高く評価した動画'
-		LIMIT 1
-	`
-
-	var playlist model.Playlist
-	err := r.db.Pool.QueryRow(ctx, query, userID).Scan(
-		&playlist.ID,
-		&playlist.UserID,
-		&playlist.Title,
-		&playlist.Description,
-		&playlist.Visibility,
-		&playlist.CreatedAt,
-		&playlist.UpdatedAt,
-	)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return &playlist, nil
-}
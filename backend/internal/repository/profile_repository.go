@@ -9,6 +9,17 @@ import (
 	"github.com/yukito/video-platform/internal/model"
 )
 
+// ProfileStore persists channel profiles
+// ProfileRepository is the PostgreSQL implementation
+type ProfileStore interface {
+	Create(ctx context.Context, userID int64, email, defaultIconURL, defaultBannerURL string) (*model.Profile, error)
+	FindByUserID(ctx context.Context, userID int64) (*model.Profile, error)
+	FindByUserIDs(ctx context.Context, userIDs []int64) (map[int64]*model.Profile, error)
+	Update(ctx context.Context, profile *model.Profile) (*model.Profile, error)
+}
+
+var _ ProfileStore = (*ProfileRepository)(nil)
+
 type ProfileRepository struct {
 	db *database.Database
 }
@@ -70,6 +81,45 @@ func (r *ProfileRepository) FindByUserID(ctx context.Context, userID int64) (*mo
 	return profile, nil
 }
 
+// FindByUserIDs loads the profiles of several users in one query, keyed by user ID
+// Users without a profile are missing from the map
+func (r *ProfileRepository) FindByUserIDs(ctx context.Context, userIDs []int64) (map[int64]*model.Profile, error) {
+	profiles := make(map[int64]*model.Profile, len(userIDs))
+	if len(userIDs) == 0 {
+		return profiles, nil
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, user_id, channel_name, description, icon_url, banner_url, created_at, updated_at
+		FROM profiles
+		WHERE user_id = ANY($1)
+	`, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find profiles: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		profile := &model.Profile{}
+		err := rows.Scan(
+			&profile.ID,
+			&profile.UserID,
+			&profile.ChannelName,
+			&profile.Description,
+			&profile.IconURL,
+			&profile.BannerURL,
+			&profile.CreatedAt,
+			&profile.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan profile: %w", err)
+		}
+		profiles[profile.UserID] = profile
+	}
+
+	return profiles, rows.Err()
+}
+
 func (r *ProfileRepository) Update(ctx context.Context, profile *model.Profile) (*model.Profile, error) {
 	err := r.db.Pool.QueryRow(ctx, `
 		UPDATE profiles
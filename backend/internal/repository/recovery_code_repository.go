@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/database"
+)
+
+// RecoveryCodeStore persists the hashes of users' one-time recovery codes
+// RecoveryCodeRepository is the PostgreSQL implementation
+type RecoveryCodeStore interface {
+	Replace(ctx context.Context, userID int64, codeHashes []string) error
+	Consume(ctx context.Context, userID int64, codeHash string) error
+	CountUnused(ctx context.Context, userID int64) (int, error)
+	DeleteByUserID(ctx context.Context, userID int64) error
+}
+
+var _ RecoveryCodeStore = (*RecoveryCodeRepository)(nil)
+
+type RecoveryCodeRepository struct {
+	db *database.Database
+}
+
+func NewRecoveryCodeRepository(db *database.Database) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{db: db}
+}
+
+// Replace deletes the user's recovery codes and stores new ones
+func (r *RecoveryCodeRepository) Replace(ctx context.Context, userID int64, codeHashes []string) error {
+	err := pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO recovery_codes (user_id, code_hash)
+			SELECT $1, UNNEST($2::TEXT[])
+		`, userID, codeHashes)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replace recovery codes: %w", err)
+	}
+	return nil
+}
+
+// Consume marks an unused recovery code of the user as used
+// It returns pgx.ErrNoRows if the user has no such code, so a code works only once
+func (r *RecoveryCodeRepository) Consume(ctx context.Context, userID int64, codeHash string) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE recovery_codes SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`, userID, codeHash)
+	if err != nil {
+		return fmt.Errorf("failed to consume recovery code: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("failed to consume recovery code: %w", pgx.ErrNoRows)
+	}
+	return nil
+}
+
+// CountUnused returns how many of the user's recovery codes have not been used
+func (r *RecoveryCodeRepository) CountUnused(ctx context.Context, userID int64) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM recovery_codes WHERE user_id = $1 AND used_at IS NULL
+	`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+	return count, nil
+}
+
+func (r *RecoveryCodeRepository) DeleteByUserID(ctx context.Context, userID int64) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	return nil
+}
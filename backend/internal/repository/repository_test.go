@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yukito/video-platform/internal/database"
+	"github.com/yukito/video-platform/internal/model"
+)
+
+// newTestDatabase connects to TEST_DATABASE_URL and applies the migrations
+// Tests against PostgreSQL are skipped when it is not set
+func newTestDatabase(t *testing.T) *database.Database {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := database.NewDatabase(url)
+	if err != nil {
+		t.Fatalf("NewDatabase returned error: %v", err)
+	}
+	t.Cleanup(db.Close)
+	if err := db.RunMigrations(context.Background()); err != nil {
+		t.Fatalf("RunMigrations returned error: %v", err)
+	}
+	return db
+}
+
+// createTestUser stores a user with a unique email
+func createTestUser(t *testing.T, db *database.Database) *model.User {
+	t.Helper()
+	user, err := NewUserRepository(db).Create(context.Background(), uuid.New().String()+"@example.com", "hash")
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	return user
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yukito/video-platform/internal/database"
+	"github.com/yukito/video-platform/internal/model"
+)
+
+// SearchStore searches videos
+// SearchRepository is the PostgreSQL implementation
+type SearchStore interface {
+	SearchVideos(ctx context.Context, params *model.VideoSearchParams) ([]*model.VideoSearchResult, error)
+}
+
+var _ SearchStore = (*SearchRepository)(nil)
+
+type SearchRepository struct {
+	db *database.Database
+}
+
+func NewSearchRepository(db *database.Database) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// searchRankExpr scores a video against the query in $1: the weighted full-text rank
+// plus the trigram similarity of the title, which is what ranks Japanese substring matches
+const searchRankExpr = `(ts_rank_cd(v.search_vector, q.query) + similarity(v.title, $1))::float8`
+
+// SearchVideos returns ready public videos matching every term of the query, best match first
+// A video matches through search_vector, or through a substring match of each term
+// in its title, description or channel name
+func (r *SearchRepository) SearchVideos(ctx context.Context, params *model.VideoSearchParams) ([]*model.VideoSearchResult, error) {
+	args := []any{params.Query}
+	arg := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	var termConditions []string
+	for _, term := range params.Terms {
+		pattern := arg("%" + escapeLike(term) + "%")
+		termConditions = append(termConditions, fmt.Sprintf(
+			"(v.title ILIKE %[1]s OR v.description ILIKE %[1]s OR p.channel_name ILIKE %[1]s)", pattern))
+	}
+
+	conditions := []string{
+		"v.status = 'ready'",
+		"v.visibility = 'public'",
+		"(v.search_vector @@ q.query OR (" + strings.Join(termConditions, " AND ") + "))",
+	}
+	if params.MinDuration > 0 {
+		conditions = append(conditions, "v.duration >= "+arg(params.MinDuration))
+	}
+	if params.MaxDuration > 0 {
+		conditions = append(conditions, "v.duration <= "+arg(params.MaxDuration))
+	}
+	if params.UploadedAfter != nil {
+		conditions = append(conditions, "v.created_at >= "+arg(*params.UploadedAfter))
+	}
+	if params.ChannelID != 0 {
+		conditions = append(conditions, "v.user_id = "+arg(params.ChannelID))
+	}
+	if params.After != nil {
+		rank, id := arg(params.After.Rank), arg(params.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(%[1]s < %[2]s OR (%[1]s = %[2]s AND v.id < %[3]s))", searchRankExpr, rank, id))
+	}
+
+	query := `
+		SELECT
+			v.id, v.user_id, v.title, v.description, v.video_url, v.thumbnail_url, v.duration, v.view_count,
+			v.like_count, v.dislike_count, v.width, v.height, v.created_at, v.updated_at,
+			p.id, p.user_id, p.channel_name, p.description, p.icon_url, p.banner_url, p.created_at, p.updated_at,
+			` + searchRankExpr + `
+		FROM videos v
+		LEFT JOIN profiles p ON v.user_id = p.user_id
+		CROSS JOIN websearch_to_tsquery('simple', $1) AS q(query)
+		WHERE ` + strings.Join(conditions, "\n\t\t\tAND ") + `
+		ORDER BY ` + searchRankExpr + ` DESC, v.id DESC
+		LIMIT ` + arg(params.Limit)
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search videos: %w", err)
+	}
+	defer rows.Close()
+
+	results := []*model.VideoSearchResult{}
+	for rows.Next() {
+		var video model.VideoWithProfile
+		var profile model.Profile
+		var result model.VideoSearchResult
+
+		err := rows.Scan(
+			&video.ID, &video.UserID, &video.Title, &video.Description, &video.VideoURL, &video.ThumbnailURL,
+			&video.Duration, &video.ViewCount, &video.LikeCount, &video.DislikeCount, &video.Width, &video.Height, &video.CreatedAt, &video.UpdatedAt,
+			&profile.ID, &profile.UserID, &profile.ChannelName, &profile.Description,
+			&profile.IconURL, &profile.BannerURL, &profile.CreatedAt, &profile.UpdatedAt,
+			&result.Rank,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+
+		video.Profile = &profile
+		result.Video = &video
+		results = append(results, &result)
+	}
+
+	return results, rows.Err()
+}
+
+// escapeLike escapes the LIKE wildcards in s
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
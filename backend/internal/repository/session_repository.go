@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/database"
+	"github.com/yukito/video-platform/internal/model"
+)
+
+// SessionStore persists sign-in sessions and their refresh token hashes
+// Times come from the caller's clock, which also checks expiry and last use, rather than
+// the database's NOW(); the columns have no time zone, so the two clocks must not mix
+// SessionRepository is the PostgreSQL implementation
+type SessionStore interface {
+	Create(ctx context.Context, session *model.Session) (*model.Session, error)
+	FindByID(ctx context.Context, id int64) (*model.Session, error)
+	FindByFamily(ctx context.Context, family string) (*model.Session, error)
+	Rotate(ctx context.Context, id int64, oldHash, newHash string, now, expiresAt time.Time, client model.ClientInfo) (bool, error)
+	Revoke(ctx context.Context, id int64, now time.Time) error
+	FindActiveByUserID(ctx context.Context, userID int64, now time.Time) ([]*model.Session, error)
+	Touch(ctx context.Context, id int64, now time.Time) error
+	RevokeAllByUserID(ctx context.Context, userID, exceptID int64, now time.Time) (int64, error)
+}
+
+var _ SessionStore = (*SessionRepository)(nil)
+
+type SessionRepository struct {
+	db *database.Database
+}
+
+func NewSessionRepository(db *database.Database) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+const sessionColumns = `id, user_id, family, refresh_token_hash, user_agent, ip_address,
+	created_at, last_seen_at, expires_at, revoked_at`
+
+func scanSession(row pgx.Row, session *model.Session) error {
+	return row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.Family,
+		&session.RefreshTokenHash,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+	)
+}
+
+// Create starts a session at its CreatedAt, which is also when it was last seen
+func (r *SessionRepository) Create(ctx context.Context, session *model.Session) (*model.Session, error) {
+	err := scanSession(r.db.Pool.QueryRow(ctx, `
+		INSERT INTO sessions (user_id, family, refresh_token_hash, user_agent, ip_address, expires_at, created_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		RETURNING `+sessionColumns,
+		session.UserID, session.Family, session.RefreshTokenHash, session.UserAgent, session.IPAddress, session.ExpiresAt,
+		session.CreatedAt,
+	), session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	return session, nil
+}
+
+func (r *SessionRepository) FindByID(ctx context.Context, id int64) (*model.Session, error) {
+	session := &model.Session{}
+	err := scanSession(r.db.Pool.QueryRow(ctx, `
+		SELECT `+sessionColumns+`
+		FROM sessions
+		WHERE id = $1
+	`, id), session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find session: %w", err)
+	}
+	return session, nil
+}
+
+// FindByFamily returns the session of a refresh token family, revoked or not
+func (r *SessionRepository) FindByFamily(ctx context.Context, family string) (*model.Session, error) {
+	session := &model.Session{}
+	err := scanSession(r.db.Pool.QueryRow(ctx, `
+		SELECT `+sessionColumns+`
+		FROM sessions
+		WHERE family = $1
+	`, family), session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find session: %w", err)
+	}
+	return session, nil
+}
+
+// Rotate replaces the session's refresh token hash if it is still oldHash and the session
+// is active, and records the client as last seen; it reports whether the token was replaced,
+// so of two concurrent refreshes with the same token only one succeeds
+func (r *SessionRepository) Rotate(ctx context.Context, id int64, oldHash, newHash string, now, expiresAt time.Time, client model.ClientInfo) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE sessions
+		SET refresh_token_hash = $3, expires_at = $4, user_agent = $5, ip_address = $6, last_seen_at = $7
+		WHERE id = $1 AND refresh_token_hash = $2 AND revoked_at IS NULL AND expires_at > $7
+	`, id, oldHash, newHash, expiresAt, client.UserAgent, client.IPAddress, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// Revoke ends a session; revoking it again keeps the original revocation time
+func (r *SessionRepository) Revoke(ctx context.Context, id int64, now time.Time) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE sessions SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL
+	`, id, now)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// FindActiveByUserID returns the user's sessions that are neither revoked nor expired,
+// most recently seen first
+func (r *SessionRepository) FindActiveByUserID(ctx context.Context, userID int64, now time.Time) ([]*model.Session, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+sessionColumns+`
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_seen_at DESC, id DESC
+	`, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []*model.Session{}
+	for rows.Next() {
+		session := &model.Session{}
+		if err := scanSession(rows, session); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// Touch records that an active session was just used
+func (r *SessionRepository) Touch(ctx context.Context, id int64, now time.Time) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE sessions SET last_seen_at = $2 WHERE id = $1 AND revoked_at IS NULL
+	`, id, now)
+	if err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+	return nil
+}
+
+// RevokeAllByUserID ends all of the user's active sessions except exceptID (0 for none)
+// and returns how many were revoked
+func (r *SessionRepository) RevokeAllByUserID(ctx context.Context, userID, exceptID int64, now time.Time) (int64, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE sessions SET revoked_at = $3
+		WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL AND expires_at > $3
+	`, userID, exceptID, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
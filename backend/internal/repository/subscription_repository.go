@@ -8,6 +8,19 @@ import (
 	"github.com/yukito/video-platform/internal/model"
 )
 
+// SubscriptionStore persists channel subscriptions
+// SubscriptionRepository is the PostgreSQL implementation
+type SubscriptionStore interface {
+	Subscribe(ctx context.Context, subscriberUserID, subscribedToUserID int64) error
+	Unsubscribe(ctx context.Context, subscriberUserID, subscribedToUserID int64) error
+	IsSubscribed(ctx context.Context, subscriberUserID, subscribedToUserID int64) (bool, error)
+	GetSubscriberCount(ctx context.Context, userID int64) (int64, error)
+	GetSubscribedChannels(ctx context.Context, subscriberUserID int64) ([]*model.SubscriptionWithProfile, error)
+	GetSubscriptionFeed(ctx context.Context, subscriberUserID int64, page model.PageRequest) ([]*model.VideoWithProfile, error)
+}
+
+var _ SubscriptionStore = (*SubscriptionRepository)(nil)
+
 type SubscriptionRepository struct {
 	db *database.Database
 }
@@ -121,21 +134,24 @@ func (r *SubscriptionRepository) GetSubscribedChannels(ctx context.Context, subs
 	return subscriptions, nil
 }
 
-// GetSubscriptionFeed returns videos from channels the user is subscribed to
-func (r *SubscriptionRepository) GetSubscriptionFeed(ctx context.Context, subscriberUserID int64, limit, offset int) ([]*model.VideoWithProfile, error) {
+// GetSubscriptionFeed returns ready public videos from channels the user is subscribed to
+func (r *SubscriptionRepository) GetSubscriptionFeed(ctx context.Context, subscriberUserID int64, page model.PageRequest) ([]*model.VideoWithProfile, error) {
+	order := keyset{time: "v.created_at", id: "v.id", desc: true}
+	args := []any{subscriberUserID}
 	query := `
 		SELECT
-			v.id, v.user_id, v.title, v.description, v.video_url, v.thumbnail_url, v.duration, v.view_count, v.created_at, v.updated_at,
+			v.id, v.user_id, v.title, v.description, v.video_url, v.thumbnail_url, v.duration, v.view_count,
+			v.like_count, v.dislike_count, v.created_at, v.updated_at,
 			p.id, p.user_id, p.channel_name, p.description, p.icon_url, p.banner_url, p.created_at, p.updated_at
 		FROM videos v
 		INNER JOIN subscriptions s ON v.user_id = s.subscribed_to_user_id
 		LEFT JOIN profiles p ON v.user_id = p.user_id
-		WHERE s.subscriber_user_id = $1
-		ORDER BY v.created_at DESC
-		LIMIT $2 OFFSET $3
-	`
+		WHERE s.subscriber_user_id = $1 AND v.status = 'ready' AND v.visibility = 'public'
+			AND ` + order.after(page.After, &args) + `
+		ORDER BY ` + order.orderBy() + `
+		` + limitOffset(page, &args)
 
-	rows, err := r.db.Pool.Query(ctx, query, subscriberUserID, limit, offset)
+	rows, err := r.db.Pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get subscription feed: %w", err)
 	}
@@ -155,6 +171,8 @@ func (r *SubscriptionRepository) GetSubscriptionFeed(ctx context.Context, subscr
 			&video.ThumbnailURL,
 			&video.Duration,
 			&video.ViewCount,
+			&video.LikeCount,
+			&video.DislikeCount,
 			&video.CreatedAt,
 			&video.UpdatedAt,
 			&profile.ID,
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/database"
+	"github.com/yukito/video-platform/internal/model"
+)
+
+// SuggestionStore persists logged search queries and finds prefix matches for suggestions
+// SuggestionRepository is the PostgreSQL implementation
+// Queries are stored normalized, see service.normalizeQuery; times come from the caller's clock,
+// which also sets the window popular queries are counted over
+type SuggestionStore interface {
+	RecordQuery(ctx context.Context, userID int64, query string, keepRecent int, now time.Time) error
+	FindRecentQueries(ctx context.Context, userID int64, prefix string, limit int) ([]*model.RecentSearch, error)
+	ClearRecentQueries(ctx context.Context, userID int64) error
+	FindPopularQueries(ctx context.Context, prefix string, limit int) ([]string, error)
+	FindTitles(ctx context.Context, prefix string, limit int) ([]string, error)
+	FindChannelNames(ctx context.Context, prefix string, limit int) ([]string, error)
+	RecomputePopularQueries(ctx context.Context, since time.Time, minSearches int64, limit int, now time.Time) (int64, error)
+}
+
+var _ SuggestionStore = (*SuggestionRepository)(nil)
+
+type SuggestionRepository struct {
+	db *database.Database
+}
+
+func NewSuggestionRepository(db *database.Database) *SuggestionRepository {
+	return &SuggestionRepository{db: db}
+}
+
+// prefixPattern makes a LIKE pattern matching lowercase text that starts with prefix
+func prefixPattern(prefix string) string {
+	return escapeLike(strings.ToLower(prefix)) + "%"
+}
+
+// RecordQuery logs a signed-in user's search and adds it to their recent searches,
+// keeping only the keepRecent latest
+func (r *SuggestionRepository) RecordQuery(ctx context.Context, userID int64, query string, keepRecent int, now time.Time) error {
+	err := pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO search_queries (user_id, query, created_at) VALUES ($1, $2, $3)
+		`, userID, query, now); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO user_search_history (user_id, query, searched_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, query)
+			DO UPDATE SET searched_at = EXCLUDED.searched_at
+		`, userID, query, now); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `
+			DELETE FROM user_search_history
+			WHERE user_id = $1 AND id NOT IN (
+				SELECT id FROM user_search_history
+				WHERE user_id = $1
+				ORDER BY searched_at DESC
+				LIMIT $2
+			)
+		`, userID, keepRecent)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record search query: %w", err)
+	}
+	return nil
+}
+
+// FindRecentQueries returns the user's recent searches starting with prefix, newest first
+func (r *SuggestionRepository) FindRecentQueries(ctx context.Context, userID int64, prefix string, limit int) ([]*model.RecentSearch, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT query, searched_at
+		FROM user_search_history
+		WHERE user_id = $1 AND query LIKE $2
+		ORDER BY searched_at DESC
+		LIMIT $3
+	`, userID, prefixPattern(prefix), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find recent searches: %w", err)
+	}
+	defer rows.Close()
+
+	searches := []*model.RecentSearch{}
+	for rows.Next() {
+		var search model.RecentSearch
+		if err := rows.Scan(&search.Query, &search.SearchedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan recent search: %w", err)
+		}
+		searches = append(searches, &search)
+	}
+	return searches, rows.Err()
+}
+
+// ClearRecentQueries removes all recent searches of a user
+func (r *SuggestionRepository) ClearRecentQueries(ctx context.Context, userID int64) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM user_search_history WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to clear recent searches: %w", err)
+	}
+	return nil
+}
+
+// FindPopularQueries returns popular queries starting with prefix, most searched first
+func (r *SuggestionRepository) FindPopularQueries(ctx context.Context, prefix string, limit int) ([]string, error) {
+	return r.findStrings(ctx, `
+		SELECT query
+		FROM popular_search_queries
+		WHERE query LIKE $1
+		ORDER BY search_count DESC, query
+		LIMIT $2
+	`, prefix, limit)
+}
+
+// FindTitles returns titles of ready public videos starting with prefix, most viewed first
+func (r *SuggestionRepository) FindTitles(ctx context.Context, prefix string, limit int) ([]string, error) {
+	return r.findStrings(ctx, `
+		SELECT title
+		FROM videos
+		WHERE lower(title) LIKE $1 AND status = 'ready' AND visibility = 'public'
+		ORDER BY view_count DESC, id DESC
+		LIMIT $2
+	`, prefix, limit)
+}
+
+// FindChannelNames returns channel names starting with prefix, most subscribed first
+func (r *SuggestionRepository) FindChannelNames(ctx context.Context, prefix string, limit int) ([]string, error) {
+	return r.findStrings(ctx, `
+		SELECT p.channel_name
+		FROM profiles p
+		WHERE lower(p.channel_name) LIKE $1 AND p.channel_name <> ''
+		ORDER BY (SELECT COUNT(*) FROM subscriptions s WHERE s.subscribed_to_user_id = p.user_id) DESC, p.id
+		LIMIT $2
+	`, prefix, limit)
+}
+
+func (r *SuggestionRepository) findStrings(ctx context.Context, query, prefix string, limit int) ([]string, error) {
+	rows, err := r.db.Pool.Query(ctx, query, prefixPattern(prefix), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find suggestions: %w", err)
+	}
+	defer rows.Close()
+
+	values := []string{}
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("failed to scan suggestion: %w", err)
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// RecomputePopularQueries rebuilds popular_search_queries from the searches since the given time
+// and drops older searches. Each user counts once per query so one user cannot push a
+// query up by repeating it; searches of deleted users no longer count
+func (r *SuggestionRepository) RecomputePopularQueries(ctx context.Context, since time.Time, minSearches int64, limit int, now time.Time) (int64, error) {
+	var count int64
+	err := pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM search_queries WHERE created_at < $1`, since); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM popular_search_queries`); err != nil {
+			return err
+		}
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO popular_search_queries (query, search_count, updated_at)
+			SELECT query, COUNT(DISTINCT user_id) AS searches, $3::timestamp
+			FROM search_queries
+			GROUP BY query
+			HAVING COUNT(DISTINCT user_id) >= $1
+			ORDER BY searches DESC
+			LIMIT $2
+		`, minSearches, limit, now)
+		count = tag.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to recompute popular queries: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/database"
+	"github.com/yukito/video-platform/internal/model"
+)
+
+// ThumbnailStore persists the thumbnail candidates of videos
+// ThumbnailRepository is the PostgreSQL implementation
+type ThumbnailStore interface {
+	ReplaceGenerated(ctx context.Context, videoID int64, thumbnails []*model.VideoThumbnail) ([]*model.VideoThumbnail, error)
+	ReplaceCustom(ctx context.Context, thumbnail *model.VideoThumbnail) ([]*model.VideoThumbnail, error)
+	FindByID(ctx context.Context, id int64) (*model.VideoThumbnail, error)
+	FindByVideoID(ctx context.Context, videoID int64) ([]*model.VideoThumbnail, error)
+}
+
+var _ ThumbnailStore = (*ThumbnailRepository)(nil)
+
+type ThumbnailRepository struct {
+	db *database.Database
+}
+
+func NewThumbnailRepository(db *database.Database) *ThumbnailRepository {
+	return &ThumbnailRepository{db: db}
+}
+
+const thumbnailColumns = `id, video_id, url, source, time_offset, created_at`
+
+func scanThumbnail(row pgx.Row, thumbnail *model.VideoThumbnail) error {
+	return row.Scan(
+		&thumbnail.ID,
+		&thumbnail.VideoID,
+		&thumbnail.URL,
+		&thumbnail.Source,
+		&thumbnail.TimeOffset,
+		&thumbnail.CreatedAt,
+	)
+}
+
+// ReplaceGenerated swaps the video's generated candidates for new ones in one transaction
+// It returns the removed candidates so their files can be deleted
+func (r *ThumbnailRepository) ReplaceGenerated(ctx context.Context, videoID int64, thumbnails []*model.VideoThumbnail) ([]*model.VideoThumbnail, error) {
+	var removed []*model.VideoThumbnail
+	err := pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		var err error
+		removed, err = r.replace(ctx, tx, videoID, model.ThumbnailSourceGenerated, thumbnails)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to replace thumbnails: %w", err)
+	}
+	return removed, nil
+}
+
+// ReplaceCustom stores an uploaded thumbnail in place of the video's previous upload
+// It returns the removed thumbnail, if any, so its file can be deleted
+func (r *ThumbnailRepository) ReplaceCustom(ctx context.Context, thumbnail *model.VideoThumbnail) ([]*model.VideoThumbnail, error) {
+	var removed []*model.VideoThumbnail
+	err := pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		var err error
+		removed, err = r.replace(ctx, tx, thumbnail.VideoID, model.ThumbnailSourceCustom, []*model.VideoThumbnail{thumbnail})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to replace thumbnail: %w", err)
+	}
+	return removed, nil
+}
+
+// replace deletes the video's thumbnails of one source and inserts new ones
+func (r *ThumbnailRepository) replace(ctx context.Context, tx pgx.Tx, videoID int64, source string, thumbnails []*model.VideoThumbnail) ([]*model.VideoThumbnail, error) {
+	rows, err := tx.Query(ctx, `
+		DELETE FROM video_thumbnails WHERE video_id = $1 AND source = $2
+		RETURNING `+thumbnailColumns,
+		videoID, source,
+	)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := collectThumbnails(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, thumbnail := range thumbnails {
+		err := scanThumbnail(tx.QueryRow(ctx, `
+			INSERT INTO video_thumbnails (video_id, url, source, time_offset)
+			VALUES ($1, $2, $3, $4)
+			RETURNING `+thumbnailColumns,
+			videoID, thumbnail.URL, source, thumbnail.TimeOffset,
+		), thumbnail)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return removed, nil
+}
+
+func (r *ThumbnailRepository) FindByID(ctx context.Context, id int64) (*model.VideoThumbnail, error) {
+	thumbnail := &model.VideoThumbnail{}
+	err := scanThumbnail(r.db.Pool.QueryRow(ctx, `
+		SELECT `+thumbnailColumns+`
+		FROM video_thumbnails
+		WHERE id = $1
+	`, id), thumbnail)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find thumbnail: %w", err)
+	}
+	return thumbnail, nil
+}
+
+// FindByVideoID returns generated candidates in video order, then the custom upload
+func (r *ThumbnailRepository) FindByVideoID(ctx context.Context, videoID int64) ([]*model.VideoThumbnail, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+thumbnailColumns+`
+		FROM video_thumbnails
+		WHERE video_id = $1
+		ORDER BY source DESC, time_offset ASC, id ASC
+	`, videoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find thumbnails: %w", err)
+	}
+
+	thumbnails, err := collectThumbnails(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan thumbnail: %w", err)
+	}
+	return thumbnails, nil
+}
+
+func collectThumbnails(rows pgx.Rows) ([]*model.VideoThumbnail, error) {
+	defer rows.Close()
+
+	thumbnails := []*model.VideoThumbnail{}
+	for rows.Next() {
+		thumbnail := &model.VideoThumbnail{}
+		if err := scanThumbnail(rows, thumbnail); err != nil {
+			return nil, err
+		}
+		thumbnails = append(thumbnails, thumbnail)
+	}
+	return thumbnails, rows.Err()
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/database"
+	"github.com/yukito/video-platform/internal/model"
+)
+
+// TranscodeStore persists transcode jobs and the renditions they produce
+// TranscodeRepository is the PostgreSQL implementation
+type TranscodeStore interface {
+	CreateJob(ctx context.Context, videoID int64, sourceURL string) (*model.TranscodeJob, error)
+	ClaimNext(ctx context.Context, staleBefore time.Time) (*model.TranscodeJob, error)
+	CompleteJob(ctx context.Context, jobID, videoID int64, masterPlaylistURL string, renditions []*model.VideoRendition) error
+	FailJob(ctx context.Context, jobID int64, errMsg string, retry bool) error
+	FindLatestCompletedJob(ctx context.Context, videoID int64) (*model.TranscodeJob, error)
+	FindLatestJob(ctx context.Context, videoID int64) (*model.TranscodeJob, error)
+	FindRenditions(ctx context.Context, videoID int64) ([]*model.VideoRendition, error)
+}
+
+var _ TranscodeStore = (*TranscodeRepository)(nil)
+
+type TranscodeRepository struct {
+	db *database.Database
+}
+
+func NewTranscodeRepository(db *database.Database) *TranscodeRepository {
+	return &TranscodeRepository{db: db}
+}
+
+const transcodeJobColumns = `id, video_id, source_url, status, attempts, error, master_playlist_url, started_at, created_at, updated_at`
+
+func scanTranscodeJob(row pgx.Row, job *model.TranscodeJob) error {
+	return row.Scan(
+		&job.ID,
+		&job.VideoID,
+		&job.SourceURL,
+		&job.Status,
+		&job.Attempts,
+		&job.Error,
+		&job.MasterPlaylistURL,
+		&job.StartedAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+}
+
+func (r *TranscodeRepository) CreateJob(ctx context.Context, videoID int64, sourceURL string) (*model.TranscodeJob, error) {
+	job := &model.TranscodeJob{}
+	err := scanTranscodeJob(r.db.Pool.QueryRow(ctx, `
+		INSERT INTO transcode_jobs (video_id, source_url)
+		VALUES ($1, $2)
+		RETURNING `+transcodeJobColumns,
+		videoID, sourceURL,
+	), job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transcode job: %w", err)
+	}
+	return job, nil
+}
+
+// ClaimNext marks the oldest pending job as running and returns it
+// Jobs left running since before staleBefore (e.g. by a crashed worker) are claimed again
+// It returns nil when there is nothing to do
+func (r *TranscodeRepository) ClaimNext(ctx context.Context, staleBefore time.Time) (*model.TranscodeJob, error) {
+	job := &model.TranscodeJob{}
+	err := scanTranscodeJob(r.db.Pool.QueryRow(ctx, `
+		UPDATE transcode_jobs
+		SET status = 'running', attempts = attempts + 1, started_at = NOW(), updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM transcode_jobs
+			WHERE status = 'pending' OR (status = 'running' AND started_at < $1)
+			ORDER BY created_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+transcodeJobColumns,
+		staleBefore,
+	), job)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim transcode job: %w", err)
+	}
+	return job, nil
+}
+
+// CompleteJob replaces the video's renditions and marks the job completed in one transaction
+func (r *TranscodeRepository) CompleteJob(ctx context.Context, jobID, videoID int64, masterPlaylistURL string, renditions []*model.VideoRendition) error {
+	err := pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM video_renditions WHERE video_id = $1`, videoID); err != nil {
+			return err
+		}
+
+		for _, rendition := range renditions {
+			err := tx.QueryRow(ctx, `
+				INSERT INTO video_renditions (video_id, name, width, height, bandwidth, playlist_url, segment_urls)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				RETURNING id, created_at
+			`, videoID, rendition.Name, rendition.Width, rendition.Height, rendition.Bandwidth, rendition.PlaylistURL, rendition.SegmentURLs).Scan(
+				&rendition.ID,
+				&rendition.CreatedAt,
+			)
+			if err != nil {
+				return err
+			}
+			rendition.VideoID = videoID
+		}
+
+		_, err := tx.Exec(ctx, `
+			UPDATE transcode_jobs
+			SET status = 'completed', error = '', master_playlist_url = $1, updated_at = NOW()
+			WHERE id = $2
+		`, masterPlaylistURL, jobID)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete transcode job: %w", err)
+	}
+	return nil
+}
+
+// FailJob records the error and either queues the job again or marks it failed
+func (r *TranscodeRepository) FailJob(ctx context.Context, jobID int64, errMsg string, retry bool) error {
+	status := model.TranscodeStatusFailed
+	if retry {
+		status = model.TranscodeStatusPending
+	}
+
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE transcode_jobs SET status = $1, error = $2, updated_at = NOW() WHERE id = $3
+	`, status, errMsg, jobID)
+	if err != nil {
+		return fmt.Errorf("failed to update transcode job: %w", err)
+	}
+	return nil
+}
+
+// FindLatestCompletedJob returns the job whose renditions the video currently uses
+func (r *TranscodeRepository) FindLatestCompletedJob(ctx context.Context, videoID int64) (*model.TranscodeJob, error) {
+	job := &model.TranscodeJob{}
+	err := scanTranscodeJob(r.db.Pool.QueryRow(ctx, `
+		SELECT `+transcodeJobColumns+`
+		FROM transcode_jobs
+		WHERE video_id = $1 AND status = 'completed'
+		ORDER BY updated_at DESC
+		LIMIT 1
+	`, videoID), job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transcode job: %w", err)
+	}
+	return job, nil
+}
+
+// FindLatestJob returns the most recently queued job of the video in any status
+func (r *TranscodeRepository) FindLatestJob(ctx context.Context, videoID int64) (*model.TranscodeJob, error) {
+	job := &model.TranscodeJob{}
+	err := scanTranscodeJob(r.db.Pool.QueryRow(ctx, `
+		SELECT `+transcodeJobColumns+`
+		FROM transcode_jobs
+		WHERE video_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`, videoID), job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transcode job: %w", err)
+	}
+	return job, nil
+}
+
+// FindRenditions returns the video's renditions, highest quality first
+func (r *TranscodeRepository) FindRenditions(ctx context.Context, videoID int64) ([]*model.VideoRendition, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, video_id, name, width, height, bandwidth, playlist_url, segment_urls, created_at
+		FROM video_renditions
+		WHERE video_id = $1
+		ORDER BY height DESC
+	`, videoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find renditions: %w", err)
+	}
+	defer rows.Close()
+
+	renditions := []*model.VideoRendition{}
+	for rows.Next() {
+		rendition := &model.VideoRendition{}
+		err := rows.Scan(
+			&rendition.ID,
+			&rendition.VideoID,
+			&rendition.Name,
+			&rendition.Width,
+			&rendition.Height,
+			&rendition.Bandwidth,
+			&rendition.PlaylistURL,
+			&rendition.SegmentURLs,
+			&rendition.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rendition: %w", err)
+		}
+		renditions = append(renditions, rendition)
+	}
+
+	return renditions, rows.Err()
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/database"
+	"github.com/yukito/video-platform/internal/model"
+)
+
+// ErrUploadConflict is returned by AddPart when the session is no longer at the
+// part's offset or no longer accepts chunks, e.g. after a concurrent write
+var ErrUploadConflict = errors.New("upload offset conflict")
+
+// UploadStore persists resumable upload sessions and their parts
+// UploadRepository is the PostgreSQL implementation
+type UploadStore interface {
+	Create(ctx context.Context, session *model.UploadSession) (*model.UploadSession, error)
+	FindByID(ctx context.Context, id string) (*model.UploadSession, error)
+	AddPart(ctx context.Context, part *model.UploadPart, expiresAt time.Time) (*model.UploadSession, error)
+	ListParts(ctx context.Context, uploadID string) ([]*model.UploadPart, error)
+	UpdateStatus(ctx context.Context, id, from, to string) (bool, error)
+	Complete(ctx context.Context, id string, videoID int64) error
+	FindExpired(ctx context.Context, before time.Time, limit int) ([]*model.UploadSession, error)
+	Delete(ctx context.Context, id string) error
+}
+
+var _ UploadStore = (*UploadRepository)(nil)
+
+type UploadRepository struct {
+	db *database.Database
+}
+
+func NewUploadRepository(db *database.Database) *UploadRepository {
+	return &UploadRepository{db: db}
+}
+
+const uploadSessionColumns = `id, user_id, filename, content_type, upload_length, upload_offset, part_count,
+	title, description, status, video_id, expires_at, created_at, updated_at`
+
+func scanUploadSession(row pgx.Row, session *model.UploadSession) error {
+	return row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.Filename,
+		&session.ContentType,
+		&session.UploadLength,
+		&session.UploadOffset,
+		&session.PartCount,
+		&session.Title,
+		&session.Description,
+		&session.Status,
+		&session.VideoID,
+		&session.ExpiresAt,
+		&session.CreatedAt,
+		&session.UpdatedAt,
+	)
+}
+
+func (r *UploadRepository) Create(ctx context.Context, session *model.UploadSession) (*model.UploadSession, error) {
+	err := scanUploadSession(r.db.Pool.QueryRow(ctx, `
+		INSERT INTO upload_sessions (id, user_id, filename, content_type, upload_length, title, description, status, video_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING `+uploadSessionColumns,
+		session.ID, session.UserID, session.Filename, session.ContentType, session.UploadLength,
+		session.Title, session.Description, session.Status, session.VideoID, session.ExpiresAt,
+	), session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload session: %w", err)
+	}
+	return session, nil
+}
+
+func (r *UploadRepository) FindByID(ctx context.Context, id string) (*model.UploadSession, error) {
+	session := &model.UploadSession{}
+	err := scanUploadSession(r.db.Pool.QueryRow(ctx, `
+		SELECT `+uploadSessionColumns+`
+		FROM upload_sessions
+		WHERE id = $1
+	`, id), session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find upload session: %w", err)
+	}
+	return session, nil
+}
+
+// AddPart records a stored chunk and advances the session offset in one transaction
+// The session must still be uploading and at part.PartOffset, otherwise ErrUploadConflict is returned
+func (r *UploadRepository) AddPart(ctx context.Context, part *model.UploadPart, expiresAt time.Time) (*model.UploadSession, error) {
+	session := &model.UploadSession{}
+
+	err := pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		err := scanUploadSession(tx.QueryRow(ctx, `
+			UPDATE upload_sessions
+			SET upload_offset = upload_offset + $1, part_count = part_count + 1, expires_at = $2, updated_at = NOW()
+			WHERE id = $3 AND status = 'uploading' AND upload_offset = $4 AND part_count = $5 - 1
+				AND upload_offset + $1 <= upload_length
+			RETURNING `+uploadSessionColumns,
+			part.Size, expiresAt, part.UploadID, part.PartOffset, part.PartNumber,
+		), session)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUploadConflict
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.QueryRow(ctx, `
+			INSERT INTO upload_parts (upload_id, part_number, part_offset, size, storage_key)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING created_at
+		`, part.UploadID, part.PartNumber, part.PartOffset, part.Size, part.StorageKey).Scan(&part.CreatedAt)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add upload part: %w", err)
+	}
+	return session, nil
+}
+
+// ListParts returns the parts of an upload in order
+func (r *UploadRepository) ListParts(ctx context.Context, uploadID string) ([]*model.UploadPart, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT upload_id, part_number, part_offset, size, storage_key, created_at
+		FROM upload_parts
+		WHERE upload_id = $1
+		ORDER BY part_number ASC
+	`, uploadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list upload parts: %w", err)
+	}
+	defer rows.Close()
+
+	parts := []*model.UploadPart{}
+	for rows.Next() {
+		part := &model.UploadPart{}
+		if err := rows.Scan(&part.UploadID, &part.PartNumber, &part.PartOffset, &part.Size, &part.StorageKey, &part.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan upload part: %w", err)
+		}
+		parts = append(parts, part)
+	}
+
+	return parts, rows.Err()
+}
+
+// UpdateStatus moves a session from one status to another
+// It reports false when the session was not in the from status
+func (r *UploadRepository) UpdateStatus(ctx context.Context, id, from, to string) (bool, error) {
+	result, err := r.db.Pool.Exec(ctx, `
+		UPDATE upload_sessions SET status = $1, updated_at = NOW() WHERE id = $2 AND status = $3
+	`, to, id, from)
+	if err != nil {
+		return false, fmt.Errorf("failed to update upload status: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// Complete marks a session as completed and links the created video
+func (r *UploadRepository) Complete(ctx context.Context, id string, videoID int64) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE upload_sessions SET status = 'completed', video_id = $1, updated_at = NOW() WHERE id = $2
+	`, videoID, id)
+	if err != nil {
+		return fmt.Errorf("failed to complete upload: %w", err)
+	}
+	return nil
+}
+
+// FindExpired returns up to limit sessions whose expires_at is before the given time
+func (r *UploadRepository) FindExpired(ctx context.Context, before time.Time, limit int) ([]*model.UploadSession, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+uploadSessionColumns+`
+		FROM upload_sessions
+		WHERE expires_at < $1
+		ORDER BY expires_at ASC
+		LIMIT $2
+	`, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired uploads: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []*model.UploadSession{}
+	for rows.Next() {
+		session := &model.UploadSession{}
+		if err := scanUploadSession(rows, session); err != nil {
+			return nil, fmt.Errorf("failed to scan upload session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// Delete removes a session and its part rows
+func (r *UploadRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		DELETE FROM upload_sessions WHERE id = $1
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete upload session: %w", err)
+	}
+	return nil
+}
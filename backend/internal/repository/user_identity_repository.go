@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/database"
+	"github.com/yukito/video-platform/internal/model"
+)
+
+// UserIdentityStore persists the provider accounts linked to users
+// UserIdentityRepository is the PostgreSQL implementation
+type UserIdentityStore interface {
+	Create(ctx context.Context, identity *model.UserIdentity) (*model.UserIdentity, error)
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error)
+	FindByUserID(ctx context.Context, userID int64) ([]*model.UserIdentity, error)
+	RecordLogin(ctx context.Context, id int64, email string) error
+}
+
+var _ UserIdentityStore = (*UserIdentityRepository)(nil)
+
+type UserIdentityRepository struct {
+	db *database.Database
+}
+
+func NewUserIdentityRepository(db *database.Database) *UserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+const userIdentityColumns = `id, user_id, provider, subject, email, created_at, last_login_at`
+
+func scanUserIdentity(row pgx.Row, identity *model.UserIdentity) error {
+	return row.Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+		&identity.LastLoginAt,
+	)
+}
+
+func (r *UserIdentityRepository) Create(ctx context.Context, identity *model.UserIdentity) (*model.UserIdentity, error) {
+	err := scanUserIdentity(r.db.Pool.QueryRow(ctx, `
+		INSERT INTO user_identities (user_id, provider, subject, email)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+userIdentityColumns,
+		identity.UserID, identity.Provider, identity.Subject, identity.Email,
+	), identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user identity: %w", err)
+	}
+	return identity, nil
+}
+
+func (r *UserIdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error) {
+	identity := &model.UserIdentity{}
+	err := scanUserIdentity(r.db.Pool.QueryRow(ctx, `
+		SELECT `+userIdentityColumns+`
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2
+	`, provider, subject), identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user identity: %w", err)
+	}
+	return identity, nil
+}
+
+// FindByUserID returns the provider accounts linked to the user, oldest first
+func (r *UserIdentityRepository) FindByUserID(ctx context.Context, userID int64) ([]*model.UserIdentity, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+userIdentityColumns+`
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at, id
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user identities: %w", err)
+	}
+	defer rows.Close()
+
+	identities := []*model.UserIdentity{}
+	for rows.Next() {
+		identity := &model.UserIdentity{}
+		if err := scanUserIdentity(rows, identity); err != nil {
+			return nil, fmt.Errorf("failed to scan user identity: %w", err)
+		}
+		identities = append(identities, identity)
+	}
+	return identities, rows.Err()
+}
+
+// RecordLogin records a sign-in with the identity and the email the provider reported
+func (r *UserIdentityRepository) RecordLogin(ctx context.Context, id int64, email string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE user_identities SET email = $2, last_login_at = NOW() WHERE id = $1
+	`, id, email)
+	if err != nil {
+		return fmt.Errorf("failed to record login: %w", err)
+	}
+	return nil
+}
@@ -3,11 +3,35 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/yukito/video-platform/internal/database"
 	"github.com/yukito/video-platform/internal/model"
 )
 
+// UserStore persists user accounts
+// UserRepository is the PostgreSQL implementation
+type UserStore interface {
+	Create(ctx context.Context, email, passwordHash string) (*model.User, error)
+	FindByEmail(ctx context.Context, email string) (*model.User, error)
+	FindByID(ctx context.Context, id int64) (*model.User, error)
+	UpdatePassword(ctx context.Context, id int64, passwordHash string) error
+	SetEmailVerified(ctx context.Context, id int64) error
+	SetTOTPSecret(ctx context.Context, id int64, secret string) error
+	EnableTOTP(ctx context.Context, id int64, step int64) error
+	DisableTOTP(ctx context.Context, id int64) error
+	UseTOTPStep(ctx context.Context, id int64, step int64) (bool, error)
+	SetRole(ctx context.Context, id int64, role string) error
+	SetStatus(ctx context.Context, id int64, status string, suspendedUntil *time.Time) error
+	Search(ctx context.Context, filter model.UserFilter, page model.PageRequest) ([]*model.User, error)
+	Stats(ctx context.Context, now time.Time) (*model.UserStats, error)
+}
+
+var _ UserStore = (*UserRepository)(nil)
+
 type UserRepository struct {
 	db *database.Database
 }
@@ -16,19 +40,34 @@ func NewUserRepository(db *database.Database) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-func (r *UserRepository) Create(ctx context.Context, email, passwordHash string) (*model.User, error) {
-	user := &model.User{}
-	err := r.db.Pool.QueryRow(ctx, `
-		INSERT INTO users (email, password_hash)
-		VALUES ($1, $2)
-		RETURNING id, email, password_hash, created_at, updated_at
-	`, email, passwordHash).Scan(
+const userColumns = `id, email, role, status, suspended_until, password_hash, email_verified_at,
+	COALESCE(totp_secret, ''), totp_enabled_at, COALESCE(totp_last_step, 0), created_at, updated_at`
+
+func scanUser(row pgx.Row, user *model.User) error {
+	return row.Scan(
 		&user.ID,
 		&user.Email,
+		&user.Role,
+		&user.Status,
+		&user.SuspendedUntil,
 		&user.PasswordHash,
+		&user.EmailVerifiedAt,
+		&user.TOTPSecret,
+		&user.TOTPEnabledAt,
+		&user.TOTPLastStep,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
+}
+
+func (r *UserRepository) Create(ctx context.Context, email, passwordHash string) (*model.User, error) {
+	user := &model.User{}
+	err := scanUser(r.db.Pool.QueryRow(ctx, `
+		INSERT INTO users (email, password_hash)
+		VALUES ($1, $2)
+		RETURNING `+userColumns,
+		email, passwordHash,
+	), user)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
@@ -37,17 +76,11 @@ func (r *UserRepository) Create(ctx context.Context, email, passwordHash string)
 
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
 	user := &model.User{}
-	err := r.db.Pool.QueryRow(ctx, `
-		SELECT id, email, password_hash, created_at, updated_at
+	err := scanUser(r.db.Pool.QueryRow(ctx, `
+		SELECT `+userColumns+`
 		FROM users
 		WHERE email = $1
-	`, email).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	`, email), user)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
@@ -56,19 +89,181 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*model.
 
 func (r *UserRepository) FindByID(ctx context.Context, id int64) (*model.User, error) {
 	user := &model.User{}
-	err := r.db.Pool.QueryRow(ctx, `
-		SELECT id, email, password_hash, created_at, updated_at
+	err := scanUser(r.db.Pool.QueryRow(ctx, `
+		SELECT `+userColumns+`
 		FROM users
 		WHERE id = $1
-	`, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	`, id), user)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 	return user, nil
 }
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE users SET password_hash = $2, updated_at = NOW() WHERE id = $1
+	`, id, passwordHash)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("failed to update password: %w", pgx.ErrNoRows)
+	}
+	return nil
+}
+
+// SetEmailVerified marks the user's email address as verified; verifying it again keeps the original time
+func (r *UserRepository) SetEmailVerified(ctx context.Context, id int64) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE users SET email_verified_at = COALESCE(email_verified_at, NOW()), updated_at = NOW() WHERE id = $1
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("failed to verify email: %w", pgx.ErrNoRows)
+	}
+	return nil
+}
+
+// SetTOTPSecret stores the secret of a TOTP enrollment that has not been confirmed yet
+func (r *UserRepository) SetTOTPSecret(ctx context.Context, id int64, secret string) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE users SET totp_secret = $2, totp_enabled_at = NULL, totp_last_step = NULL, updated_at = NOW()
+		WHERE id = $1
+	`, id, secret)
+	if err != nil {
+		return fmt.Errorf("failed to set TOTP secret: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("failed to set TOTP secret: %w", pgx.ErrNoRows)
+	}
+	return nil
+}
+
+// EnableTOTP turns on TOTP with the stored secret; step is the period of the code that confirmed it
+func (r *UserRepository) EnableTOTP(ctx context.Context, id int64, step int64) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE users SET totp_enabled_at = NOW(), totp_last_step = $2, updated_at = NOW()
+		WHERE id = $1 AND totp_secret IS NOT NULL
+	`, id, step)
+	if err != nil {
+		return fmt.Errorf("failed to enable TOTP: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("failed to enable TOTP: %w", pgx.ErrNoRows)
+	}
+	return nil
+}
+
+// DisableTOTP turns off TOTP and forgets the secret
+func (r *UserRepository) DisableTOTP(ctx context.Context, id int64) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = NULL, updated_at = NOW()
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to disable TOTP: %w", err)
+	}
+	return nil
+}
+
+// UseTOTPStep records that a code of the step was accepted and reports whether the step
+// is later than the last one used, so each code works once even when presented concurrently
+func (r *UserRepository) UseTOTPStep(ctx context.Context, id int64, step int64) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE users SET totp_last_step = $2
+		WHERE id = $1 AND totp_enabled_at IS NOT NULL AND (totp_last_step IS NULL OR totp_last_step < $2)
+	`, id, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to use TOTP code: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *UserRepository) SetRole(ctx context.Context, id int64, role string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE users SET role = $2, updated_at = NOW()
+		WHERE id = $1
+	`, id, role)
+	if err != nil {
+		return fmt.Errorf("failed to set role: %w", err)
+	}
+	return nil
+}
+
+// SetStatus changes the account status; suspendedUntil is nil unless the status is suspended
+func (r *UserRepository) SetStatus(ctx context.Context, id int64, status string, suspendedUntil *time.Time) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE users SET status = $2, suspended_until = $3, updated_at = NOW()
+		WHERE id = $1
+	`, id, status, suspendedUntil)
+	if err != nil {
+		return fmt.Errorf("failed to set account status: %w", err)
+	}
+	return nil
+}
+
+// Search returns the users matching the filter, newest first
+// The page cursor holds the creation time and the user ID
+func (r *UserRepository) Search(ctx context.Context, filter model.UserFilter, page model.PageRequest) ([]*model.User, error) {
+	order := keyset{time: "created_at", id: "id", desc: true}
+	var args []any
+	arg := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	var conditions []string
+	if filter.Query != "" {
+		conditions = append(conditions, "email ILIKE "+arg("%"+escapeLike(filter.Query)+"%"))
+	}
+	if filter.Role != "" {
+		conditions = append(conditions, "role = "+arg(filter.Role))
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = "+arg(filter.Status))
+	}
+	conditions = append(conditions, order.after(page.After, &args))
+
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + order.orderBy() + `
+		` + limitOffset(page, &args)
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []*model.User{}
+	for rows.Next() {
+		user := &model.User{}
+		if err := scanUser(rows, user); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// Stats counts the users by account status as of now
+func (r *UserRepository) Stats(ctx context.Context, now time.Time) (*model.UserStats, error) {
+	stats := &model.UserStats{}
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = 'active' OR (status = 'suspended' AND suspended_until <= $1)),
+			COUNT(*) FILTER (WHERE status = 'suspended' AND suspended_until > $1),
+			COUNT(*) FILTER (WHERE status = 'banned')
+		FROM users
+	`, now).Scan(&stats.Total, &stats.Active, &stats.Suspended, &stats.Banned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+	return stats, nil
+}
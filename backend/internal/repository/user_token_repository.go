@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/database"
+	"github.com/yukito/video-platform/internal/model"
+)
+
+// UserTokenStore persists single-use user tokens: links sent by email and pending MFA challenges
+// Expiry is checked against the caller's clock, the one that set ExpiresAt
+// UserTokenRepository is the PostgreSQL implementation
+type UserTokenStore interface {
+	Create(ctx context.Context, token *model.UserToken) (*model.UserToken, error)
+	Find(ctx context.Context, purpose, tokenHash string, now time.Time) (*model.UserToken, error)
+	Consume(ctx context.Context, purpose, tokenHash string, now time.Time) (*model.UserToken, error)
+	RecordFailedAttempt(ctx context.Context, id int64, maxAttempts int, now time.Time) error
+	InvalidateByUserID(ctx context.Context, userID int64, purpose string, now time.Time) error
+}
+
+var _ UserTokenStore = (*UserTokenRepository)(nil)
+
+type UserTokenRepository struct {
+	db *database.Database
+}
+
+func NewUserTokenRepository(db *database.Database) *UserTokenRepository {
+	return &UserTokenRepository{db: db}
+}
+
+const userTokenColumns = `id, user_id, purpose, token_hash, expires_at, used_at, attempts, created_at`
+
+func scanUserToken(row pgx.Row, token *model.UserToken) error {
+	return row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Purpose,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.Attempts,
+		&token.CreatedAt,
+	)
+}
+
+// Create stores a token issued at its CreatedAt
+func (r *UserTokenRepository) Create(ctx context.Context, token *model.UserToken) (*model.UserToken, error) {
+	err := scanUserToken(r.db.Pool.QueryRow(ctx, `
+		INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+userTokenColumns,
+		token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt, token.CreatedAt,
+	), token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user token: %w", err)
+	}
+	return token, nil
+}
+
+// Find returns an unused, unexpired token, or pgx.ErrNoRows if there is none
+func (r *UserTokenRepository) Find(ctx context.Context, purpose, tokenHash string, now time.Time) (*model.UserToken, error) {
+	token := &model.UserToken{}
+	err := scanUserToken(r.db.Pool.QueryRow(ctx, `
+		SELECT `+userTokenColumns+`
+		FROM user_tokens
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > $3
+	`, tokenHash, purpose, now), token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user token: %w", err)
+	}
+	return token, nil
+}
+
+// Consume marks an unused, unexpired token as used and returns it
+// It returns pgx.ErrNoRows if there is no such token, so a token works only once
+// even when it is presented concurrently
+func (r *UserTokenRepository) Consume(ctx context.Context, purpose, tokenHash string, now time.Time) (*model.UserToken, error) {
+	token := &model.UserToken{}
+	err := scanUserToken(r.db.Pool.QueryRow(ctx, `
+		UPDATE user_tokens SET used_at = $3
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > $3
+		RETURNING `+userTokenColumns,
+		tokenHash, purpose, now,
+	), token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume user token: %w", err)
+	}
+	return token, nil
+}
+
+// RecordFailedAttempt counts a wrong code entered for a token and marks the token as used
+// once maxAttempts have been counted
+func (r *UserTokenRepository) RecordFailedAttempt(ctx context.Context, id int64, maxAttempts int, now time.Time) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE user_tokens
+		SET attempts = attempts + 1, used_at = CASE WHEN attempts + 1 >= $2 THEN $3 ELSE used_at END
+		WHERE id = $1 AND used_at IS NULL
+	`, id, maxAttempts, now)
+	if err != nil {
+		return fmt.Errorf("failed to record failed attempt: %w", err)
+	}
+	return nil
+}
+
+// InvalidateByUserID marks the user's unused tokens of a purpose as used
+func (r *UserTokenRepository) InvalidateByUserID(ctx context.Context, userID int64, purpose string, now time.Time) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE user_tokens SET used_at = $3 WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+	`, userID, purpose, now)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate user tokens: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/database"
+	"github.com/yukito/video-platform/internal/model"
+)
+
+// VideoReactionStore persists likes and dislikes on videos together with the videos' counters
+// VideoReactionRepository is the PostgreSQL implementation
+type VideoReactionStore interface {
+	SetReaction(ctx context.Context, videoID, userID int64, likeType string) error
+	RemoveReaction(ctx context.Context, videoID, userID int64) error
+	FindReaction(ctx context.Context, videoID, userID int64) (*model.VideoReaction, error)
+	FindLikedVideos(ctx context.Context, userID int64, page model.PageRequest) ([]*model.LikedVideo, error)
+}
+
+var _ VideoReactionStore = (*VideoReactionRepository)(nil)
+
+type VideoReactionRepository struct {
+	db *database.Database
+}
+
+func NewVideoReactionRepository(db *database.Database) *VideoReactionRepository {
+	return &VideoReactionRepository{db: db}
+}
+
+// SetReaction adds or changes the user's reaction to a video
+// Changing the reaction moves its created_at, so liked videos are ordered by when they were liked
+// The video row is locked first: a reaction that does not exist yet cannot be locked, and the
+// counters lock the row anyway, so concurrent first reactions are counted once
+func (r *VideoReactionRepository) SetReaction(ctx context.Context, videoID, userID int64, likeType string) error {
+	err := pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		if err := lockVideo(ctx, tx, videoID); err != nil {
+			return err
+		}
+
+		var previous string
+		err := tx.QueryRow(ctx, `
+			SELECT like_type FROM video_reactions WHERE video_id = $1 AND user_id = $2
+		`, videoID, userID).Scan(&previous)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		if previous == likeType {
+			return nil
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO video_reactions (video_id, user_id, like_type) VALUES ($1, $2, $3)
+			ON CONFLICT (video_id, user_id) DO UPDATE SET like_type = EXCLUDED.like_type, created_at = NOW()
+		`, videoID, userID, likeType)
+		if err != nil {
+			return err
+		}
+
+		return updateReactionCounts(ctx, tx, videoID, previous, likeType)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set video reaction: %w", err)
+	}
+	return nil
+}
+
+// RemoveReaction removes the user's reaction to a video, if any
+// Like SetReaction it locks the video row first, so the two never wait on each other's locks
+func (r *VideoReactionRepository) RemoveReaction(ctx context.Context, videoID, userID int64) error {
+	err := pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		if err := lockVideo(ctx, tx, videoID); err != nil {
+			return err
+		}
+
+		var previous string
+		err := tx.QueryRow(ctx, `
+			DELETE FROM video_reactions WHERE video_id = $1 AND user_id = $2 RETURNING like_type
+		`, videoID, userID).Scan(&previous)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return updateReactionCounts(ctx, tx, videoID, previous, "")
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove video reaction: %w", err)
+	}
+	return nil
+}
+
+// lockVideo locks the video row for the rest of the transaction
+// Reaction changes take it before touching video_reactions, so they always lock in the same order
+func lockVideo(ctx context.Context, tx pgx.Tx, videoID int64) error {
+	_, err := tx.Exec(ctx, `
+		SELECT 1 FROM videos WHERE id = $1 FOR UPDATE
+	`, videoID)
+	return err
+}
+
+// updateReactionCounts moves a video's counters from the previous reaction to the next one ("" for none)
+func updateReactionCounts(ctx context.Context, tx pgx.Tx, videoID int64, previous, next string) error {
+	var likes, dislikes int64
+	count := func(likeType string, delta int64) {
+		switch likeType {
+		case model.VideoReactionLike:
+			likes += delta
+		case model.VideoReactionDislike:
+			dislikes += delta
+		}
+	}
+	count(previous, -1)
+	count(next, 1)
+
+	_, err := tx.Exec(ctx, `
+		UPDATE videos
+		SET like_count = GREATEST(like_count + $1, 0), dislike_count = GREATEST(dislike_count + $2, 0)
+		WHERE id = $3
+	`, likes, dislikes, videoID)
+	return err
+}
+
+// FindReaction returns the user's reaction to a video, or nil if there is none
+func (r *VideoReactionRepository) FindReaction(ctx context.Context, videoID, userID int64) (*model.VideoReaction, error) {
+	var reaction model.VideoReaction
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT id, video_id, user_id, like_type, created_at
+		FROM video_reactions
+		WHERE video_id = $1 AND user_id = $2
+	`, videoID, userID).Scan(&reaction.ID, &reaction.VideoID, &reaction.UserID, &reaction.LikeType, &reaction.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find video reaction: %w", err)
+	}
+	return &reaction, nil
+}
+
+// FindLikedVideos returns the videos the user has liked, most recently liked first
+// Videos the user can no longer watch are left out; the page cursor holds the time liked and the video ID
+func (r *VideoReactionRepository) FindLikedVideos(ctx context.Context, userID int64, page model.PageRequest) ([]*model.LikedVideo, error) {
+	order := keyset{time: "r.created_at", id: "r.video_id", desc: true}
+	args := []any{userID}
+	query := `
+		SELECT
+			r.video_id, r.created_at,
+			v.id, v.user_id, v.title, v.description, v.video_url, v.thumbnail_url, v.duration, v.view_count,
+			v.like_count, v.dislike_count, v.created_at, v.updated_at,
+			p.id, p.user_id, p.channel_name, p.description, p.icon_url, p.banner_url, p.created_at, p.updated_at
+		FROM video_reactions r
+		JOIN videos v ON r.video_id = v.id
+		LEFT JOIN profiles p ON v.user_id = p.user_id
+		WHERE r.user_id = $1 AND r.like_type = 'like'
+			AND ((v.status = 'ready' AND v.visibility <> 'private') OR v.user_id = $1)
+			AND ` + order.after(page.After, &args) + `
+		ORDER BY ` + order.orderBy() + `
+		` + limitOffset(page, &args)
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find liked videos: %w", err)
+	}
+	defer rows.Close()
+
+	liked := []*model.LikedVideo{}
+	for rows.Next() {
+		var l model.LikedVideo
+		var video model.VideoWithProfile
+		var profile model.Profile
+
+		err := rows.Scan(
+			&l.VideoID, &l.LikedAt,
+			&video.ID, &video.UserID, &video.Title, &video.Description,
+			&video.VideoURL, &video.ThumbnailURL, &video.Duration, &video.ViewCount,
+			&video.LikeCount, &video.DislikeCount, &video.CreatedAt, &video.UpdatedAt,
+			&profile.ID, &profile.UserID, &profile.ChannelName, &profile.Description,
+			&profile.IconURL, &profile.BannerURL, &profile.CreatedAt, &profile.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan liked video: %w", err)
+		}
+
+		video.Profile = &profile
+		l.Video = &video
+		liked = append(liked, &l)
+	}
+
+	return liked, rows.Err()
+}
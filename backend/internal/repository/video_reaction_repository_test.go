@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/yukito/video-platform/internal/model"
+)
+
+func TestVideoReactions_ConcurrentSetAndRemove(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	videos := NewVideoRepository(db)
+	reactions := NewVideoReactionRepository(db)
+
+	video, err := videos.Create(ctx, &model.Video{
+		UserID:     createTestUser(t, db).ID,
+		Title:      "clip",
+		Status:     model.VideoStatusReady,
+		Visibility: model.VideoVisibilityPublic,
+	})
+	if err != nil {
+		t.Fatalf("failed to create video: %v", err)
+	}
+
+	// Every user likes and unlikes at the same time as the others; a lock order
+	// mismatch between the two shows up as a deadlock error
+	const users, rounds = 8, 20
+	var wg sync.WaitGroup
+	errs := make(chan error, users*(rounds*2+1))
+	for range users {
+		userID := createTestUser(t, db).ID
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range rounds {
+				errs <- reactions.SetReaction(ctx, video.ID, userID, model.VideoReactionLike)
+				errs <- reactions.RemoveReaction(ctx, video.ID, userID)
+			}
+			errs <- reactions.SetReaction(ctx, video.ID, userID, model.VideoReactionLike)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("reaction change failed: %v", err)
+		}
+	}
+
+	stored, err := videos.FindByID(ctx, video.ID)
+	if err != nil {
+		t.Fatalf("FindByID returned error: %v", err)
+	}
+	if stored.LikeCount != users || stored.DislikeCount != 0 {
+		t.Errorf("expected %d likes and no dislikes, got %d and %d", users, stored.LikeCount, stored.DislikeCount)
+	}
+}
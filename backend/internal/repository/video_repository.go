@@ -3,11 +3,31 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/yukito/video-platform/internal/database"
 	"github.com/yukito/video-platform/internal/model"
 )
 
+// VideoStore persists videos
+// VideoRepository is the PostgreSQL implementation
+type VideoStore interface {
+	Create(ctx context.Context, video *model.Video) (*model.Video, error)
+	FindByID(ctx context.Context, id int64) (*model.Video, error)
+	FindAll(ctx context.Context, viewerID int64, page model.PageRequest) ([]*model.Video, error)
+	Update(ctx context.Context, video *model.Video) (*model.Video, error)
+	UpdateStatus(ctx context.Context, id int64, status, failureReason string) error
+	UpdateMetadata(ctx context.Context, video *model.Video) error
+	UpdateThumbnail(ctx context.Context, id int64, thumbnailURL string) error
+	PublishScheduled(ctx context.Context, now time.Time) (int64, error)
+	Delete(ctx context.Context, id int64) error
+	AddViewCounts(ctx context.Context, views map[int64]int64) error
+	Stats(ctx context.Context) (*model.VideoStats, error)
+}
+
+var _ VideoStore = (*VideoRepository)(nil)
+
 type VideoRepository struct {
 	db *database.Database
 }
@@ -16,12 +36,12 @@ func NewVideoRepository(db *database.Database) *VideoRepository {
 	return &VideoRepository{db: db}
 }
 
-func (r *VideoRepository) Create(ctx context.Context, video *model.Video) (*model.Video, error) {
-	err := r.db.Pool.QueryRow(ctx, `
-		INSERT INTO videos (user_id, title, description, video_url, thumbnail_url, duration, view_count)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, user_id, title, description, video_url, thumbnail_url, duration, view_count, created_at, updated_at
-	`, video.UserID, video.Title, video.Description, video.VideoURL, video.ThumbnailURL, video.Duration, video.ViewCount).Scan(
+const videoColumns = `id, user_id, title, description, video_url, thumbnail_url, duration, view_count, like_count, dislike_count,
+	container, video_codec, audio_codec, width, height, bitrate, frame_rate, status, failure_reason,
+	visibility, publish_at, created_at, updated_at`
+
+func scanVideo(row pgx.Row, video *model.Video) error {
+	return row.Scan(
 		&video.ID,
 		&video.UserID,
 		&video.Title,
@@ -30,9 +50,35 @@ func (r *VideoRepository) Create(ctx context.Context, video *model.Video) (*mode
 		&video.ThumbnailURL,
 		&video.Duration,
 		&video.ViewCount,
+		&video.LikeCount,
+		&video.DislikeCount,
+		&video.Container,
+		&video.VideoCodec,
+		&video.AudioCodec,
+		&video.Width,
+		&video.Height,
+		&video.Bitrate,
+		&video.FrameRate,
+		&video.Status,
+		&video.FailureReason,
+		&video.Visibility,
+		&video.PublishAt,
 		&video.CreatedAt,
 		&video.UpdatedAt,
 	)
+}
+
+func (r *VideoRepository) Create(ctx context.Context, video *model.Video) (*model.Video, error) {
+	err := scanVideo(r.db.Pool.QueryRow(ctx, `
+		INSERT INTO videos (user_id, title, description, video_url, thumbnail_url, duration, view_count,
+			container, video_codec, audio_codec, width, height, bitrate, frame_rate, status, failure_reason,
+			visibility, publish_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		RETURNING `+videoColumns,
+		video.UserID, video.Title, video.Description, video.VideoURL, video.ThumbnailURL, video.Duration, video.ViewCount,
+		video.Container, video.VideoCodec, video.AudioCodec, video.Width, video.Height, video.Bitrate, video.FrameRate, video.Status, video.FailureReason,
+		video.Visibility, video.PublishAt,
+	), video)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create video: %w", err)
 	}
@@ -41,37 +87,30 @@ func (r *VideoRepository) Create(ctx context.Context, video *model.Video) (*mode
 
 func (r *VideoRepository) FindByID(ctx context.Context, id int64) (*model.Video, error) {
 	video := &model.Video{}
-	err := r.db.Pool.QueryRow(ctx, `
-		SELECT id, user_id, title, description, video_url, thumbnail_url, duration, view_count, created_at, updated_at
+	err := scanVideo(r.db.Pool.QueryRow(ctx, `
+		SELECT `+videoColumns+`
 		FROM videos
 		WHERE id = $1
-	`, id).Scan(
-		&video.ID,
-		&video.UserID,
-		&video.Title,
-		&video.Description,
-		&video.VideoURL,
-		&video.ThumbnailURL,
-		&video.Duration,
-		&video.ViewCount,
-		&video.CreatedAt,
-		&video.UpdatedAt,
-	)
+	`, id), video)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find video: %w", err)
 	}
 	return video, nil
 }
 
-func (r *VideoRepository) FindAll(ctx context.Context, limit, offset int) ([]*model.Video, error) {
+// FindAll returns ready public videos, plus the viewer's own videos in any status and visibility (viewerID 0 for anonymous)
+func (r *VideoRepository) FindAll(ctx context.Context, viewerID int64, page model.PageRequest) ([]*model.Video, error) {
+	order := keyset{time: "created_at", id: "id", desc: true}
+	args := []any{viewerID}
 	query := `
-		SELECT id, user_id, title, description, video_url, thumbnail_url, duration, view_count, created_at, updated_at
+		SELECT ` + videoColumns + `
 		FROM videos
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
+		WHERE ((status = 'ready' AND visibility = 'public') OR user_id = $1)
+			AND ` + order.after(page.After, &args) + `
+		ORDER BY ` + order.orderBy() + `
+		` + limitOffset(page, &args)
 
-	rows, err := r.db.Pool.Query(ctx, query, limit, offset)
+	rows, err := r.db.Pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find videos: %w", err)
 	}
@@ -80,51 +119,81 @@ func (r *VideoRepository) FindAll(ctx context.Context, limit, offset int) ([]*mo
 	videos := []*model.Video{}
 	for rows.Next() {
 		video := &model.Video{}
-		err := rows.Scan(
-			&video.ID,
-			&video.UserID,
-			&video.Title,
-			&video.Description,
-			&video.VideoURL,
-			&video.ThumbnailURL,
-			&video.Duration,
-			&video.ViewCount,
-			&video.CreatedAt,
-			&video.UpdatedAt,
-		)
-		if err != nil {
+		if err := scanVideo(rows, video); err != nil {
 			return nil, fmt.Errorf("failed to scan video: %w", err)
 		}
 		videos = append(videos, video)
 	}
 
-	return videos, nil
+	return videos, rows.Err()
 }
 
 func (r *VideoRepository) Update(ctx context.Context, video *model.Video) (*model.Video, error) {
-	err := r.db.Pool.QueryRow(ctx, `
+	err := scanVideo(r.db.Pool.QueryRow(ctx, `
 		UPDATE videos
-		SET title = $1, description = $2, video_url = $3, thumbnail_url = $4, duration = $5, updated_at = NOW()
-		WHERE id = $6
-		RETURNING id, user_id, title, description, video_url, thumbnail_url, duration, view_count, created_at, updated_at
-	`, video.Title, video.Description, video.VideoURL, video.ThumbnailURL, video.Duration, video.ID).Scan(
-		&video.ID,
-		&video.UserID,
-		&video.Title,
-		&video.Description,
-		&video.VideoURL,
-		&video.ThumbnailURL,
-		&video.Duration,
-		&video.ViewCount,
-		&video.CreatedAt,
-		&video.UpdatedAt,
-	)
+		SET title = $1, description = $2, video_url = $3, thumbnail_url = $4, duration = $5,
+			container = $6, video_codec = $7, audio_codec = $8, width = $9, height = $10, bitrate = $11, frame_rate = $12,
+			status = $13, failure_reason = $14, visibility = $15, publish_at = $16, updated_at = NOW()
+		WHERE id = $17
+		RETURNING `+videoColumns,
+		video.Title, video.Description, video.VideoURL, video.ThumbnailURL, video.Duration,
+		video.Container, video.VideoCodec, video.AudioCodec, video.Width, video.Height, video.Bitrate, video.FrameRate,
+		video.Status, video.FailureReason, video.Visibility, video.PublishAt, video.ID,
+	), video)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update video: %w", err)
 	}
 	return video, nil
 }
 
+// UpdateMetadata stores the probed media metadata and duration of a video
+func (r *VideoRepository) UpdateMetadata(ctx context.Context, video *model.Video) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE videos
+		SET duration = $1, container = $2, video_codec = $3, audio_codec = $4, width = $5, height = $6, bitrate = $7, frame_rate = $8, updated_at = NOW()
+		WHERE id = $9
+	`, video.Duration, video.Container, video.VideoCodec, video.AudioCodec, video.Width, video.Height, video.Bitrate, video.FrameRate, video.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update video metadata: %w", err)
+	}
+	return nil
+}
+
+// UpdateThumbnail sets the thumbnail shown for a video
+func (r *VideoRepository) UpdateThumbnail(ctx context.Context, id int64, thumbnailURL string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE videos SET thumbnail_url = $1, updated_at = NOW() WHERE id = $2
+	`, thumbnailURL, id)
+	if err != nil {
+		return fmt.Errorf("failed to update thumbnail: %w", err)
+	}
+	return nil
+}
+
+// PublishScheduled makes scheduled videos whose publish_at has passed public
+// and returns how many were published
+func (r *VideoRepository) PublishScheduled(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE videos SET visibility = 'public', publish_at = NULL, updated_at = NOW()
+		WHERE visibility = 'private' AND publish_at <= $1
+	`, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to publish scheduled videos: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// UpdateStatus moves a video to another processing status without touching the rest of the row
+func (r *VideoRepository) UpdateStatus(ctx context.Context, id int64, status, failureReason string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE videos SET status = $1, failure_reason = $2, updated_at = NOW() WHERE id = $3
+	`, status, failureReason, id)
+	if err != nil {
+		return fmt.Errorf("failed to update video status: %w", err)
+	}
+	return nil
+}
+
 func (r *VideoRepository) Delete(ctx context.Context, id int64) error {
 	_, err := r.db.Pool.Exec(ctx, `
 		DELETE FROM videos WHERE id = $1
@@ -135,28 +204,45 @@ func (r *VideoRepository) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
-func (r *VideoRepository) IncrementViewCount(ctx context.Context, id int64) error {
+// AddViewCounts adds buffered views to the videos' view counts in one statement
+// views maps video IDs to the number of views to add; deleted videos are skipped
+func (r *VideoRepository) AddViewCounts(ctx context.Context, views map[int64]int64) error {
+	if len(views) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(views))
+	counts := make([]int64, 0, len(views))
+	for id, count := range views {
+		ids = append(ids, id)
+		counts = append(counts, count)
+	}
+
 	_, err := r.db.Pool.Exec(ctx, `
-		UPDATE videos SET view_count = view_count + 1 WHERE id = $1
-	`, id)
+		UPDATE videos v SET view_count = v.view_count + c.views
+		FROM unnest($1::bigint[], $2::bigint[]) AS c(id, views)
+		WHERE v.id = c.id
+	`, ids, counts)
 	if err != nil {
-		return fmt.Errorf("failed to increment view count: %w", err)
+		return fmt.Errorf("failed to add view counts: %w", err)
 	}
 	return nil
 }
 
-// GetLikeCount counts how many users have liked the video
-// by counting how many "Liked Videos" playlists contain this video
-func (r *VideoRepository) GetLikeCount(ctx context.Context, videoID int64) (int64, error) {
-	var count int64
+// Stats counts the videos by processing status
+func (r *VideoRepository) Stats(ctx context.Context) (*model.VideoStats, error) {
+	stats := &model.VideoStats{}
 	err := r.db.Pool.QueryRow(ctx, `
-		SELECT COUNT(DISTINCT pv.playlist_id)
-		FROM playlist_videos pv
-		JOIN playlists p ON p.id = pv.playlist_id
-		WHERE pv.video_id = $1 AND p.title = '高く評価した動画'
-	`, videoID).Scan(&count)
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = 'uploading'),
+			COUNT(*) FILTER (WHERE status = 'processing'),
+			COUNT(*) FILTER (WHERE status = 'ready'),
+			COUNT(*) FILTER (WHERE status = 'failed')
+		FROM videos
+	`).Scan(&stats.Total, &stats.Uploading, &stats.Processing, &stats.Ready, &stats.Failed)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get like count: %w", err)
+		return nil, fmt.Errorf("failed to count videos: %w", err)
 	}
-	return count, nil
+	return stats, nil
 }
@@ -2,12 +2,29 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/yukito/video-platform/internal/database"
 	"github.com/yukito/video-platform/internal/model"
 )
 
+// WatchHistoryStore persists watch history and playback progress
+// WatchHistoryRepository is the PostgreSQL implementation
+type WatchHistoryStore interface {
+	AddToHistory(ctx context.Context, userID, videoID int64) error
+	GetWatchHistory(ctx context.Context, userID int64, page model.PageRequest) ([]*model.WatchHistory, error)
+	GetContinueWatching(ctx context.Context, userID int64, minPosition, maxPercent float64, page model.PageRequest) ([]*model.WatchHistory, error)
+	SaveProgress(ctx context.Context, userID, videoID int64, position float64) (*model.WatchProgress, error)
+	FindProgress(ctx context.Context, userID, videoID int64) (*model.WatchProgress, error)
+	RemoveFromHistory(ctx context.Context, userID, videoID int64) error
+	ClearHistory(ctx context.Context, userID int64) error
+	GetHistoryCount(ctx context.Context, userID int64) (int64, error)
+}
+
+var _ WatchHistoryStore = (*WatchHistoryRepository)(nil)
+
 type WatchHistoryRepository struct {
 	db *database.Database
 }
@@ -32,23 +49,48 @@ func (r *WatchHistoryRepository) AddToHistory(ctx context.Context, userID, video
 }
 
 // GetWatchHistory returns user's watch history with pagination
-func (r *WatchHistoryRepository) GetWatchHistory(ctx context.Context, userID int64, limit, offset int) ([]*model.WatchHistory, error) {
+// Videos that have since become private or unavailable are left out
+func (r *WatchHistoryRepository) GetWatchHistory(ctx context.Context, userID int64, page model.PageRequest) ([]*model.WatchHistory, error) {
+	history, err := r.findHistory(ctx, []any{userID}, "TRUE", page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watch history: %w", err)
+	}
+	return history, nil
+}
+
+// GetContinueWatching returns the videos the user has started but not finished, most recently watched first:
+// those watched to at least minPosition seconds and less than maxPercent of their duration
+func (r *WatchHistoryRepository) GetContinueWatching(ctx context.Context, userID int64, minPosition, maxPercent float64, page model.PageRequest) ([]*model.WatchHistory, error) {
+	history, err := r.findHistory(ctx, []any{userID, minPosition, maxPercent},
+		"wh.position_seconds >= $2 AND wh.progress_percent < $3", page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get continue watching: %w", err)
+	}
+	return history, nil
+}
+
+// findHistory returns a page of the watch history of the user in args[0] that matches filter
+func (r *WatchHistoryRepository) findHistory(ctx context.Context, args []any, filter string, page model.PageRequest) ([]*model.WatchHistory, error) {
+	order := keyset{time: "wh.watched_at", id: "wh.id", desc: true}
 	query := `
 		SELECT 
-			wh.id, wh.user_id, wh.video_id, wh.watched_at,
-			v.id, v.user_id, v.title, v.description, v.video_url, v.thumbnail_url, v.duration, v.view_count, v.created_at, v.updated_at,
+			wh.id, wh.user_id, wh.video_id, wh.watched_at, wh.position_seconds, wh.progress_percent,
+			v.id, v.user_id, v.title, v.description, v.video_url, v.thumbnail_url, v.duration, v.view_count,
+			v.like_count, v.dislike_count, v.created_at, v.updated_at,
 			p.id, p.user_id, p.channel_name, p.description, p.icon_url, p.banner_url, p.created_at, p.updated_at
 		FROM watch_history wh
 		JOIN videos v ON wh.video_id = v.id
 		LEFT JOIN profiles p ON v.user_id = p.user_id
 		WHERE wh.user_id = $1
-		ORDER BY wh.watched_at DESC
-		LIMIT $2 OFFSET $3
-	`
+			AND ((v.status = 'ready' AND v.visibility <> 'private') OR v.user_id = $1)
+			AND ` + filter + `
+			AND ` + order.after(page.After, &args) + `
+		ORDER BY ` + order.orderBy() + `
+		` + limitOffset(page, &args)
 
-	rows, err := r.db.Pool.Query(ctx, query, userID, limit, offset)
+	rows, err := r.db.Pool.Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get watch history: %w", err)
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -59,10 +101,10 @@ func (r *WatchHistoryRepository) GetWatchHistory(ctx context.Context, userID int
 		var profile model.Profile
 
 		err := rows.Scan(
-			&h.ID, &h.UserID, &h.VideoID, &h.WatchedAt,
+			&h.ID, &h.UserID, &h.VideoID, &h.WatchedAt, &h.PositionSeconds, &h.ProgressPercent,
 			&video.ID, &video.UserID, &video.Title, &video.Description,
 			&video.VideoURL, &video.ThumbnailURL, &video.Duration, &video.ViewCount,
-			&video.CreatedAt, &video.UpdatedAt,
+			&video.LikeCount, &video.DislikeCount, &video.CreatedAt, &video.UpdatedAt,
 			&profile.ID, &profile.UserID, &profile.ChannelName, &profile.Description,
 			&profile.IconURL, &profile.BannerURL, &profile.CreatedAt, &profile.UpdatedAt,
 		)
@@ -75,7 +117,49 @@ func (r *WatchHistoryRepository) GetWatchHistory(ctx context.Context, userID int
 		history = append(history, &h)
 	}
 
-	return history, nil
+	return history, rows.Err()
+}
+
+// SaveProgress records the user's playback position in the video and moves it to the top of the history
+// The percentage is computed from the video's duration, and positions past the end are clamped to it.
+// It only takes one statement, so videos the user cannot watch are skipped by the query itself and
+// reported as pgx.ErrNoRows
+func (r *WatchHistoryRepository) SaveProgress(ctx context.Context, userID, videoID int64, position float64) (*model.WatchProgress, error) {
+	var progress model.WatchProgress
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO watch_history (user_id, video_id, watched_at, position_seconds, progress_percent)
+		SELECT $1, v.id, NOW(),
+			CASE WHEN v.duration > 0 THEN LEAST($3::double precision, v.duration) ELSE $3::double precision END,
+			CASE WHEN v.duration > 0 THEN LEAST($3::double precision / v.duration * 100, 100) ELSE 0 END
+		FROM videos v
+		WHERE v.id = $2 AND ((v.status = 'ready' AND v.visibility <> 'private') OR v.user_id = $1)
+		ON CONFLICT (user_id, video_id) DO UPDATE SET
+			watched_at = EXCLUDED.watched_at,
+			position_seconds = EXCLUDED.position_seconds,
+			progress_percent = EXCLUDED.progress_percent
+		RETURNING video_id, position_seconds, progress_percent, watched_at
+	`, userID, videoID, position).Scan(&progress.VideoID, &progress.PositionSeconds, &progress.ProgressPercent, &progress.WatchedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save watch progress: %w", err)
+	}
+	return &progress, nil
+}
+
+// FindProgress returns how far the user has watched the video, or nil if it is not in their history
+func (r *WatchHistoryRepository) FindProgress(ctx context.Context, userID, videoID int64) (*model.WatchProgress, error) {
+	var progress model.WatchProgress
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT video_id, position_seconds, progress_percent, watched_at
+		FROM watch_history
+		WHERE user_id = $1 AND video_id = $2
+	`, userID, videoID).Scan(&progress.VideoID, &progress.PositionSeconds, &progress.ProgressPercent, &progress.WatchedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find watch progress: %w", err)
+	}
+	return &progress, nil
 }
 
 // RemoveFromHistory removes a specific video from user's watch history
@@ -98,10 +182,16 @@ func (r *WatchHistoryRepository) ClearHistory(ctx context.Context, userID int64)
 	return nil
 }
 
-// GetHistoryCount returns the total number of videos in user's watch history
+// GetHistoryCount returns the number of videos in user's watch history, counting the same videos as GetWatchHistory
 func (r *WatchHistoryRepository) GetHistoryCount(ctx context.Context, userID int64) (int64, error) {
 	var count int64
-	query := `SELECT COUNT(*) FROM watch_history WHERE user_id = $1`
+	query := `
+		SELECT COUNT(*)
+		FROM watch_history wh
+		JOIN videos v ON wh.video_id = v.id
+		WHERE wh.user_id = $1
+			AND ((v.status = 'ready' AND v.visibility <> 'private') OR v.user_id = $1)
+	`
 	err := r.db.Pool.QueryRow(ctx, query, userID).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get history count: %w", err)
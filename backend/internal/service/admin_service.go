@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/policy"
+	"github.com/yukito/video-platform/internal/repository"
+	"github.com/yukito/video-platform/internal/storage"
+)
+
+var (
+	ErrUserNotFound      = errors.New("user not found")
+	ErrCannotManageUser  = errors.New("not allowed to manage this user")
+	ErrInvalidRole       = errors.New("role must be user, moderator or admin")
+	ErrInvalidStatus     = errors.New("status must be active, suspended or banned")
+	ErrInvalidSuspension = errors.New("suspension must end in the future")
+)
+
+// AdminService lets moderators and admins manage users and remove content
+// Every action, including searches and viewing stats, is written to the admin audit log;
+// changes are saved in one transaction with their entry, so none is made unrecorded
+type AdminService struct {
+	userRepo      repository.UserStore
+	videoRepo     repository.VideoStore
+	commentRepo   repository.CommentStore
+	thumbnailRepo repository.ThumbnailStore
+	transcodeRepo repository.TranscodeStore
+	auditRepo     repository.AdminAuditStore
+	storage       storage.Storage
+	now           func() time.Time
+}
+
+func NewAdminService(userRepo repository.UserStore, videoRepo repository.VideoStore, commentRepo repository.CommentStore, thumbnailRepo repository.ThumbnailStore, transcodeRepo repository.TranscodeStore, auditRepo repository.AdminAuditStore, storage storage.Storage) *AdminService {
+	return &AdminService{
+		userRepo:      userRepo,
+		videoRepo:     videoRepo,
+		commentRepo:   commentRepo,
+		thumbnailRepo: thumbnailRepo,
+		transcodeRepo: transcodeRepo,
+		auditRepo:     auditRepo,
+		storage:       storage,
+		now:           time.Now,
+	}
+}
+
+// SearchUsers returns the users matching the filter, newest first
+func (s *AdminService) SearchUsers(ctx context.Context, actor policy.Actor, filter model.UserFilter, query model.PageQuery) (*model.Page[*model.User], error) {
+	if filter.Role != "" && !policy.ValidRole(filter.Role) {
+		return nil, ErrInvalidRole
+	}
+	if filter.Status != "" && !validAccountStatus(filter.Status) {
+		return nil, ErrInvalidStatus
+	}
+	limit, page, err := pageRequest(query, DefaultPageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.Search(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	details := fmt.Sprintf("q=%q role=%q status=%q", filter.Query, filter.Role, filter.Status)
+	if err := s.record(ctx, actor, model.AdminActionSearchUsers, "", details); err != nil {
+		return nil, err
+	}
+
+	return newPage(users, limit, func(user *model.User) model.PageCursor {
+		return model.PageCursor{Time: user.CreatedAt, ID: user.ID}
+	}), nil
+}
+
+// SuspendUser blocks the user from signing in and using their sessions until req.Until
+// Turning a ban into a suspension takes someone who can lift the ban
+func (s *AdminService) SuspendUser(ctx context.Context, actor policy.Actor, userID int64, req *model.SuspendUserRequest) (*model.User, error) {
+	target, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.canChangeStatus(actor, target) {
+		return nil, ErrCannotManageUser
+	}
+	if !req.Until.After(s.now()) {
+		return nil, ErrInvalidSuspension
+	}
+
+	until := req.Until
+	details := "until " + until.UTC().Format(time.RFC3339)
+	entry := s.entry(actor, model.AdminActionSuspendUser, model.AdminTargetUser, userID, req.Reason, details)
+	if err := s.auditRepo.SetUserStatus(ctx, userID, model.AccountStatusSuspended, &until, entry); err != nil {
+		return nil, err
+	}
+	return s.findUser(ctx, userID)
+}
+
+// BanUser blocks the user from signing in and using their sessions until they are unbanned
+func (s *AdminService) BanUser(ctx context.Context, actor policy.Actor, userID int64, req *model.BanUserRequest) (*model.User, error) {
+	target, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.CanBanUser(actor, target) {
+		return nil, ErrCannotManageUser
+	}
+
+	entry := s.entry(actor, model.AdminActionBanUser, model.AdminTargetUser, userID, req.Reason, "")
+	if err := s.auditRepo.SetUserStatus(ctx, userID, model.AccountStatusBanned, nil, entry); err != nil {
+		return nil, err
+	}
+	return s.findUser(ctx, userID)
+}
+
+// UnbanUser lifts a suspension or a ban; the user's existing sessions work again
+func (s *AdminService) UnbanUser(ctx context.Context, actor policy.Actor, userID int64, req *model.UnbanUserRequest) (*model.User, error) {
+	target, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.canChangeStatus(actor, target) {
+		return nil, ErrCannotManageUser
+	}
+
+	details := "was " + accountStatus(target, s.now())
+	entry := s.entry(actor, model.AdminActionUnbanUser, model.AdminTargetUser, userID, req.Reason, details)
+	if err := s.auditRepo.SetUserStatus(ctx, userID, model.AccountStatusActive, nil, entry); err != nil {
+		return nil, err
+	}
+	return s.findUser(ctx, userID)
+}
+
+// SetRole changes the user's role; it applies to their next request
+func (s *AdminService) SetRole(ctx context.Context, actor policy.Actor, userID int64, req *model.SetRoleRequest) (*model.User, error) {
+	if !policy.ValidRole(req.Role) {
+		return nil, ErrInvalidRole
+	}
+	target, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.CanSetRole(actor, target) {
+		return nil, ErrCannotManageUser
+	}
+
+	details := target.Role + " -> " + req.Role
+	entry := s.entry(actor, model.AdminActionSetRole, model.AdminTargetUser, userID, req.Reason, details)
+	if err := s.auditRepo.SetUserRole(ctx, userID, req.Role, entry); err != nil {
+		return nil, err
+	}
+	return s.findUser(ctx, userID)
+}
+
+// RemoveVideo deletes any user's video and its stored files, recording the reason
+func (s *AdminService) RemoveVideo(ctx context.Context, actor policy.Actor, videoID int64, req *model.RemoveContentRequest) error {
+	video, err := s.videoRepo.FindByID(ctx, videoID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrVideoNotFound
+		}
+		return fmt.Errorf("failed to find video: %w", err)
+	}
+	if !policy.CanDeleteVideo(actor, video) {
+		return ErrNotVideoOwner
+	}
+
+	files, err := storedVideoFiles(ctx, s.thumbnailRepo, s.transcodeRepo, video)
+	if err != nil {
+		return err
+	}
+	details := fmt.Sprintf("%q by user %d", video.Title, video.UserID)
+	entry := s.entry(actor, model.AdminActionRemoveVideo, model.AdminTargetVideo, videoID, req.Reason, details)
+	if err := s.auditRepo.DeleteVideo(ctx, videoID, entry); err != nil {
+		return err
+	}
+	deleteVideoFiles(s.storage, videoID, files)
+	return nil
+}
+
+// RemoveComment deletes any user's comment, recording the reason
+func (s *AdminService) RemoveComment(ctx context.Context, actor policy.Actor, commentID int64, req *model.RemoveContentRequest) error {
+	comment, err := s.commentRepo.FindByID(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCommentNotFound
+		}
+		return err
+	}
+	if !policy.CanDeleteComment(actor, comment) {
+		return ErrCommentForbidden
+	}
+
+	details := fmt.Sprintf("by user %d on video %d: %s", comment.UserID, comment.VideoID, comment.Content)
+	entry := s.entry(actor, model.AdminActionRemoveComment, model.AdminTargetComment, commentID, req.Reason, details)
+	return s.auditRepo.DeleteComment(ctx, commentID, entry)
+}
+
+// Stats counts users and videos and measures file storage
+// Measuring storage lists every stored file, so it is slow on large buckets
+func (s *AdminService) Stats(ctx context.Context, actor policy.Actor) (*model.PlatformStats, error) {
+	users, err := s.userRepo.Stats(ctx, s.now())
+	if err != nil {
+		return nil, err
+	}
+	videos, err := s.videoRepo.Stats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.storage.Usage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.record(ctx, actor, model.AdminActionViewStats, "", ""); err != nil {
+		return nil, err
+	}
+
+	return &model.PlatformStats{Users: *users, Videos: *videos, StorageBytes: usage}, nil
+}
+
+// AuditLog returns the admin audit log, newest first; reading it is not recorded
+func (s *AdminService) AuditLog(ctx context.Context, query model.PageQuery) (*model.Page[*model.AdminAuditEntry], error) {
+	limit, page, err := pageRequest(query, DefaultPageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.auditRepo.List(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	return newPage(entries, limit, func(entry *model.AdminAuditEntry) model.PageCursor {
+		return model.PageCursor{Time: entry.CreatedAt, ID: entry.ID}
+	}), nil
+}
+
+// canChangeStatus reports whether the actor can suspend the user or lift their
+// suspension, which for banned users takes someone who can ban them
+func (s *AdminService) canChangeStatus(actor policy.Actor, target *model.User) bool {
+	if accountStatus(target, s.now()) == model.AccountStatusBanned {
+		return policy.CanBanUser(actor, target)
+	}
+	return policy.CanManageUser(actor, target)
+}
+
+func (s *AdminService) findUser(ctx context.Context, id int64) (*model.User, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// record writes an entry to the admin audit log for an action that changes nothing
+func (s *AdminService) record(ctx context.Context, actor policy.Actor, action, reason, details string) error {
+	return s.auditRepo.Create(ctx, s.entry(actor, action, "", 0, reason, details))
+}
+
+// entry builds an admin audit log entry; targetID is 0 for actions without a target
+func (s *AdminService) entry(actor policy.Actor, action, targetType string, targetID int64, reason, details string) *model.AdminAuditEntry {
+	actorID := actor.UserID
+	entry := &model.AdminAuditEntry{
+		ActorID:    &actorID,
+		Action:     action,
+		TargetType: targetType,
+		Reason:     strings.TrimSpace(reason),
+		Details:    details,
+		CreatedAt:  s.now(),
+	}
+	if targetID != 0 {
+		entry.TargetID = &targetID
+	}
+	return entry
+}
+
+// validAccountStatus reports whether status is one of the account statuses
+func validAccountStatus(status string) bool {
+	switch status {
+	case model.AccountStatusActive, model.AccountStatusSuspended, model.AccountStatusBanned:
+		return true
+	default:
+		return false
+	}
+}
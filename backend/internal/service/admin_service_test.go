@@ -0,0 +1,356 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/policy"
+	"github.com/yukito/video-platform/internal/repository/memory"
+	"github.com/yukito/video-platform/internal/storage"
+)
+
+// adminFixture wires an AdminService and an AuthService to the same in-memory tables
+// with a registered user, a moderator and an admin
+type adminFixture struct {
+	db        *memory.DB
+	storage   *storage.MemoryStorage
+	service   *AdminService
+	auth      *AuthService
+	user      *model.AuthResponse
+	moderator policy.Actor
+	admin     policy.Actor
+}
+
+func newAdminFixture(t *testing.T) *adminFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	db := memory.NewDB()
+	users := memory.NewUserRepository(db)
+	files := storage.NewMemoryStorage()
+	auth := newAuthService(db)
+
+	user := register(t, auth, "user@example.com")
+	moderator := register(t, auth, "moderator@example.com")
+	admin := register(t, auth, "admin@example.com")
+	if err := users.SetRole(ctx, moderator.User.ID, model.RoleModerator); err != nil {
+		t.Fatalf("SetRole returned error: %v", err)
+	}
+	if err := users.SetRole(ctx, admin.User.ID, model.RoleAdmin); err != nil {
+		t.Fatalf("SetRole returned error: %v", err)
+	}
+
+	return &adminFixture{
+		db:      db,
+		storage: files,
+		service: NewAdminService(users, memory.NewVideoRepository(db), memory.NewCommentRepository(db),
+			memory.NewThumbnailRepository(db), memory.NewTranscodeRepository(db), memory.NewAdminAuditRepository(db), files),
+		auth:      auth,
+		user:      user,
+		moderator: policy.Actor{UserID: moderator.User.ID, Role: model.RoleModerator},
+		admin:     policy.Actor{UserID: admin.User.ID, Role: model.RoleAdmin},
+	}
+}
+
+// auditLog returns every entry in the admin audit log, newest first
+func (f *adminFixture) auditLog(t *testing.T) []*model.AdminAuditEntry {
+	t.Helper()
+	page, err := f.service.AuditLog(context.Background(), model.PageQuery{Limit: 100})
+	if err != nil {
+		t.Fatalf("AuditLog returned error: %v", err)
+	}
+	return page.Items
+}
+
+func TestSuspendUser_BlocksSignInUntilExpiry(t *testing.T) {
+	f := newAdminFixture(t)
+	ctx := context.Background()
+	userID := f.user.User.ID
+
+	until := time.Now().Add(time.Hour)
+	user, err := f.service.SuspendUser(ctx, f.moderator, userID, &model.SuspendUserRequest{Until: until, Reason: "spam"})
+	if err != nil {
+		t.Fatalf("SuspendUser returned error: %v", err)
+	}
+	if user.Status != model.AccountStatusSuspended || user.SuspendedUntil == nil {
+		t.Fatalf("expected a suspended user, got %+v", user)
+	}
+
+	login := &model.LoginRequest{Email: "user@example.com", Password: "password123"}
+	var blocked *AccountBlockedError
+	if _, err := f.auth.Login(ctx, login, testClient); !errors.As(err, &blocked) || blocked.Status != model.AccountStatusSuspended {
+		t.Errorf("expected Login to fail with a suspension, got %v", err)
+	}
+	if _, err := f.auth.Refresh(ctx, f.user.RefreshToken, testClient); !errors.Is(err, ErrAccountBlocked) {
+		t.Errorf("expected Refresh to fail with ErrAccountBlocked, got %v", err)
+	}
+	claims, err := f.auth.ValidateToken(ctx, f.user.Token)
+	if err != nil || claims.AccountStatus != model.AccountStatusSuspended {
+		t.Errorf("expected the access token to carry the suspension, got %+v, %v", claims, err)
+	}
+
+	// Once the suspension ends the account works again without an unban
+	f.auth.now = func() time.Time { return until.Add(time.Minute) }
+	if _, err := f.auth.Login(ctx, login, testClient); err != nil {
+		t.Errorf("expected Login to succeed after the suspension, got %v", err)
+	}
+}
+
+func TestSuspendUser_RejectsPastEndAndPeers(t *testing.T) {
+	f := newAdminFixture(t)
+	ctx := context.Background()
+
+	past := &model.SuspendUserRequest{Until: time.Now().Add(-time.Minute), Reason: "spam"}
+	if _, err := f.service.SuspendUser(ctx, f.moderator, f.user.User.ID, past); !errors.Is(err, ErrInvalidSuspension) {
+		t.Errorf("expected ErrInvalidSuspension, got %v", err)
+	}
+
+	future := &model.SuspendUserRequest{Until: time.Now().Add(time.Hour), Reason: "spam"}
+	if _, err := f.service.SuspendUser(ctx, f.moderator, f.admin.UserID, future); !errors.Is(err, ErrCannotManageUser) {
+		t.Errorf("expected a moderator to be unable to suspend an admin, got %v", err)
+	}
+	if _, err := f.service.SuspendUser(ctx, f.moderator, f.moderator.UserID, future); !errors.Is(err, ErrCannotManageUser) {
+		t.Errorf("expected a moderator to be unable to suspend themselves, got %v", err)
+	}
+	if _, err := f.service.SuspendUser(ctx, f.admin, 999, future); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+	if entries := f.auditLog(t); len(entries) != 0 {
+		t.Errorf("expected rejected actions to leave no audit entries, got %d", len(entries))
+	}
+}
+
+func TestBanUser_OnlyAdminsBanAndLiftBans(t *testing.T) {
+	f := newAdminFixture(t)
+	ctx := context.Background()
+	userID := f.user.User.ID
+
+	if _, err := f.service.BanUser(ctx, f.moderator, userID, &model.BanUserRequest{Reason: "abuse"}); !errors.Is(err, ErrCannotManageUser) {
+		t.Fatalf("expected moderators to be unable to ban, got %v", err)
+	}
+	if _, err := f.service.BanUser(ctx, f.admin, userID, &model.BanUserRequest{Reason: "abuse"}); err != nil {
+		t.Fatalf("BanUser returned error: %v", err)
+	}
+
+	login := &model.LoginRequest{Email: "user@example.com", Password: "password123"}
+	if _, err := f.auth.Login(ctx, login, testClient); !errors.Is(err, ErrAccountBlocked) {
+		t.Errorf("expected Login to fail with ErrAccountBlocked, got %v", err)
+	}
+
+	unban := &model.UnbanUserRequest{Reason: "appeal"}
+	if _, err := f.service.UnbanUser(ctx, f.moderator, userID, unban); !errors.Is(err, ErrCannotManageUser) {
+		t.Errorf("expected moderators to be unable to lift a ban, got %v", err)
+	}
+	user, err := f.service.UnbanUser(ctx, f.admin, userID, unban)
+	if err != nil {
+		t.Fatalf("UnbanUser returned error: %v", err)
+	}
+	if user.Status != model.AccountStatusActive {
+		t.Errorf("expected an active user, got %q", user.Status)
+	}
+
+	// Sessions are kept while banned, so the user's existing tokens work again
+	claims, err := f.auth.ValidateToken(ctx, f.user.Token)
+	if err != nil || claims.AccountStatus != model.AccountStatusActive {
+		t.Errorf("expected the access token to be active again, got %+v, %v", claims, err)
+	}
+	if _, err := f.auth.Login(ctx, login, testClient); err != nil {
+		t.Errorf("expected Login to succeed after the unban, got %v", err)
+	}
+
+	entries := f.auditLog(t)
+	if len(entries) != 2 || entries[0].Action != model.AdminActionUnbanUser || entries[1].Action != model.AdminActionBanUser {
+		t.Fatalf("expected ban and unban entries, got %+v", entries)
+	}
+	if entries[0].Details != "was banned" || entries[1].Reason != "abuse" {
+		t.Errorf("unexpected audit entries %+v, %+v", entries[0], entries[1])
+	}
+}
+
+func TestSetRole_RecordsChange(t *testing.T) {
+	f := newAdminFixture(t)
+	ctx := context.Background()
+	userID := f.user.User.ID
+
+	if _, err := f.service.SetRole(ctx, f.admin, userID, &model.SetRoleRequest{Role: "owner"}); !errors.Is(err, ErrInvalidRole) {
+		t.Errorf("expected ErrInvalidRole, got %v", err)
+	}
+	if _, err := f.service.SetRole(ctx, f.moderator, userID, &model.SetRoleRequest{Role: model.RoleModerator}); !errors.Is(err, ErrCannotManageUser) {
+		t.Errorf("expected moderators to be unable to set roles, got %v", err)
+	}
+
+	user, err := f.service.SetRole(ctx, f.admin, userID, &model.SetRoleRequest{Role: model.RoleModerator, Reason: "trusted"})
+	if err != nil {
+		t.Fatalf("SetRole returned error: %v", err)
+	}
+	if user.Role != model.RoleModerator {
+		t.Errorf("expected a moderator, got %q", user.Role)
+	}
+	entries := f.auditLog(t)
+	if len(entries) != 1 || entries[0].Details != "user -> moderator" || *entries[0].TargetID != userID {
+		t.Errorf("expected one role change entry, got %+v", entries)
+	}
+}
+
+func TestRemoveContent_RecordsReason(t *testing.T) {
+	f := newAdminFixture(t)
+	ctx := context.Background()
+	userID := f.user.User.ID
+
+	video, err := memory.NewVideoRepository(f.db).Create(ctx, &model.Video{UserID: userID, Title: "clip"})
+	if err != nil {
+		t.Fatalf("failed to seed video: %v", err)
+	}
+	comment, err := memory.NewCommentRepository(f.db).Create(ctx, &model.Comment{VideoID: video.ID, UserID: userID, Content: "rude"})
+	if err != nil {
+		t.Fatalf("failed to seed comment: %v", err)
+	}
+
+	if err := f.service.RemoveComment(ctx, asUser(999), comment.ID, &model.RemoveContentRequest{Reason: "x"}); !errors.Is(err, ErrCommentForbidden) {
+		t.Errorf("expected plain users to be unable to remove comments, got %v", err)
+	}
+	if err := f.service.RemoveComment(ctx, f.moderator, comment.ID, &model.RemoveContentRequest{Reason: "harassment"}); err != nil {
+		t.Fatalf("RemoveComment returned error: %v", err)
+	}
+	if err := f.service.RemoveVideo(ctx, f.moderator, video.ID, &model.RemoveContentRequest{Reason: " copyright "}); err != nil {
+		t.Fatalf("RemoveVideo returned error: %v", err)
+	}
+	if err := f.service.RemoveVideo(ctx, f.moderator, video.ID, &model.RemoveContentRequest{Reason: "copyright"}); !errors.Is(err, ErrVideoNotFound) {
+		t.Errorf("expected ErrVideoNotFound for a removed video, got %v", err)
+	}
+
+	entries := f.auditLog(t)
+	if len(entries) != 2 {
+		t.Fatalf("expected two removal entries, got %+v", entries)
+	}
+	removedVideo, removedComment := entries[0], entries[1]
+	if removedVideo.Action != model.AdminActionRemoveVideo || removedVideo.Reason != "copyright" ||
+		*removedVideo.TargetID != video.ID || *removedVideo.ActorID != f.moderator.UserID {
+		t.Errorf("unexpected video removal entry %+v", removedVideo)
+	}
+	if removedComment.Action != model.AdminActionRemoveComment || removedComment.Reason != "harassment" ||
+		removedComment.TargetType != model.AdminTargetComment {
+		t.Errorf("unexpected comment removal entry %+v", removedComment)
+	}
+}
+
+func TestRemoveVideo_RemovesStoredFiles(t *testing.T) {
+	f := newAdminFixture(t)
+	ctx := context.Background()
+	video, files := seedTranscodedVideo(t, f.db, f.storage)
+
+	f.db.Fail("AdminAuditRepository.DeleteVideo", errors.New("connection reset"))
+	if err := f.service.RemoveVideo(ctx, f.moderator, video.ID, &model.RemoveContentRequest{Reason: "copyright"}); err == nil {
+		t.Fatal("expected RemoveVideo to fail")
+	}
+	if !f.storage.Has(video.VideoURL) || len(f.auditLog(t)) != 0 {
+		t.Fatal("expected a failed removal to keep the video's files and record nothing")
+	}
+
+	f.db.Fail("AdminAuditRepository.DeleteVideo", nil)
+	if err := f.service.RemoveVideo(ctx, f.moderator, video.ID, &model.RemoveContentRequest{Reason: "copyright"}); err != nil {
+		t.Fatalf("RemoveVideo returned error: %v", err)
+	}
+	for _, url := range files {
+		if f.storage.Has(url) {
+			t.Errorf("expected %s to be deleted", url)
+		}
+	}
+}
+
+func TestAdminActions_FailedAuditWriteChangesNothing(t *testing.T) {
+	f := newAdminFixture(t)
+	ctx := context.Background()
+	userID := f.user.User.ID
+	f.db.Fail("AdminAuditRepository.SetUserStatus", errors.New("connection reset"))
+	f.db.Fail("AdminAuditRepository.SetUserRole", errors.New("connection reset"))
+
+	if _, err := f.service.BanUser(ctx, f.admin, userID, &model.BanUserRequest{Reason: "spam"}); err == nil {
+		t.Error("expected BanUser to fail")
+	}
+	if _, err := f.service.SetRole(ctx, f.admin, userID, &model.SetRoleRequest{Role: model.RoleModerator}); err == nil {
+		t.Error("expected SetRole to fail")
+	}
+
+	user, err := memory.NewUserRepository(f.db).FindByID(ctx, userID)
+	if err != nil {
+		t.Fatalf("FindByID returned error: %v", err)
+	}
+	if user.Status != model.AccountStatusActive || user.Role != model.RoleUser {
+		t.Errorf("expected the user to be unchanged, got status %q and role %q", user.Status, user.Role)
+	}
+	if entries := f.auditLog(t); len(entries) != 0 {
+		t.Errorf("expected no audit entries, got %+v", entries)
+	}
+}
+
+func TestSearchUsers_FiltersAndPages(t *testing.T) {
+	f := newAdminFixture(t)
+	ctx := context.Background()
+
+	page, err := f.service.SearchUsers(ctx, f.moderator, model.UserFilter{Query: "example.com"}, model.PageQuery{Limit: 2})
+	if err != nil {
+		t.Fatalf("SearchUsers returned error: %v", err)
+	}
+	if len(page.Items) != 2 || page.Items[0].Email != "admin@example.com" || page.NextCursor == "" {
+		t.Fatalf("expected the two newest users and a cursor, got %+v", page)
+	}
+	page, err = f.service.SearchUsers(ctx, f.moderator, model.UserFilter{Query: "example.com"},
+		model.PageQuery{Limit: 2, Cursor: page.NextCursor})
+	if err != nil || len(page.Items) != 1 || page.Items[0].Email != "user@example.com" {
+		t.Errorf("expected the oldest user on the second page, got %+v, %v", page, err)
+	}
+
+	page, err = f.service.SearchUsers(ctx, f.moderator, model.UserFilter{Role: model.RoleModerator}, model.PageQuery{})
+	if err != nil || len(page.Items) != 1 || page.Items[0].ID != f.moderator.UserID {
+		t.Errorf("expected only the moderator, got %+v, %v", page, err)
+	}
+
+	if _, err := f.service.SearchUsers(ctx, f.moderator, model.UserFilter{Status: "deleted"}, model.PageQuery{}); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("expected ErrInvalidStatus, got %v", err)
+	}
+	if entries := f.auditLog(t); len(entries) != 3 || entries[0].Action != model.AdminActionSearchUsers {
+		t.Errorf("expected each search to be recorded, got %+v", entries)
+	}
+}
+
+func TestStats_CountsUsersVideosAndStorage(t *testing.T) {
+	f := newAdminFixture(t)
+	ctx := context.Background()
+
+	videos := memory.NewVideoRepository(f.db)
+	_, _ = videos.Create(ctx, &model.Video{UserID: f.user.User.ID, Title: "a", Status: model.VideoStatusReady})
+	_, _ = videos.Create(ctx, &model.Video{UserID: f.user.User.ID, Title: "b", Status: model.VideoStatusProcessing})
+	f.storage.Put("videos/a.mp4", make([]byte, 1000))
+	f.storage.Put("thumbnails/a.jpg", make([]byte, 24))
+
+	until := time.Now().Add(time.Hour)
+	if _, err := f.service.SuspendUser(ctx, f.admin, f.user.User.ID, &model.SuspendUserRequest{Until: until, Reason: "spam"}); err != nil {
+		t.Fatalf("SuspendUser returned error: %v", err)
+	}
+
+	stats, err := f.service.Stats(ctx, f.admin)
+	if err != nil {
+		t.Fatalf("Stats returned error: %v", err)
+	}
+	wantUsers := model.UserStats{Total: 3, Active: 2, Suspended: 1}
+	if stats.Users != wantUsers {
+		t.Errorf("expected user stats %+v, got %+v", wantUsers, stats.Users)
+	}
+	if stats.Videos.Total != 2 || stats.Videos.Ready != 1 || stats.Videos.Processing != 1 {
+		t.Errorf("unexpected video stats %+v", stats.Videos)
+	}
+	if stats.StorageBytes != 1024 {
+		t.Errorf("expected 1024 bytes of storage, got %d", stats.StorageBytes)
+	}
+
+	// Expired suspensions count as active without anyone lifting them
+	f.service.now = func() time.Time { return until.Add(time.Minute) }
+	stats, err = f.service.Stats(ctx, f.admin)
+	if err != nil || stats.Users.Active != 3 || stats.Users.Suspended != 0 {
+		t.Errorf("expected the expired suspension to count as active, got %+v, %v", stats, err)
+	}
+}
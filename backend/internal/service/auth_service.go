@@ -2,72 +2,415 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/mail"
 	"github.com/yukito/video-platform/internal/model"
 	"github.com/yukito/video-platform/internal/repository"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	// AccessTokenTTL is the lifetime of access tokens; clients renew them with their refresh token
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL is how long a session lasts without being refreshed
+	RefreshTokenTTL = 30 * 24 * time.Hour
+	// SessionTouchInterval is how often a session's last-seen time is updated while its access tokens are used
+	SessionTouchInterval = time.Minute
+	// PasswordResetTTL is how long a password reset link works
+	PasswordResetTTL = time.Hour
+	// EmailVerificationTTL is how long an email verification link works
+	EmailVerificationTTL = 48 * time.Hour
+	// MFAChallengeTTL is how long a login waits for the second factor
+	MFAChallengeTTL = 5 * time.Minute
+	// MaxMFAAttempts is how many wrong codes a login accepts before the password has to be entered again
+	MaxMFAAttempts = 5
+)
+
+var (
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrInvalidToken        = errors.New("invalid token")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used; the session has been revoked")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrIncorrectPassword   = errors.New("current password is incorrect")
+
+	ErrInvalidResetToken        = errors.New("invalid or expired password reset token")
+	ErrInvalidVerificationToken = errors.New("invalid or expired email verification token")
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+	ErrInvalidMFAToken          = errors.New("invalid or expired MFA token; log in again")
+
+	ErrAccountBlocked = errors.New("account is blocked")
+)
+
+// AccountBlockedError is returned when a suspended or banned user signs in or refreshes a session
+type AccountBlockedError struct {
+	Status         string
+	SuspendedUntil *time.Time // Set for suspended accounts
+}
+
+func (e *AccountBlockedError) Error() string {
+	if e.SuspendedUntil != nil {
+		return "account is suspended until " + e.SuspendedUntil.UTC().Format(time.RFC3339)
+	}
+	return "account is " + e.Status
+}
+
+func (e *AccountBlockedError) Unwrap() error {
+	return ErrAccountBlocked
+}
+
 type AuthService struct {
-	userRepo         *repository.UserRepository
-	profileRepo      *repository.ProfileRepository
-	playlistRepo     *repository.PlaylistRepository
+	userRepo         repository.UserStore
+	profileRepo      repository.ProfileStore
+	playlistRepo     repository.PlaylistStore
+	sessionRepo      repository.SessionStore
+	tokenRepo        repository.UserTokenStore
+	twoFactor        *TwoFactorService
+	throttle         *LoginThrottle
+	mailer           mail.Mailer
 	jwtSecret        string
+	appURL           string // Base URL of the frontend, for links in emails
 	defaultIconURL   string
 	defaultBannerURL string
+	now              func() time.Time
 }
 
-func NewAuthService(userRepo *repository.UserRepository, profileRepo *repository.ProfileRepository, playlistRepo *repository.PlaylistRepository, jwtSecret, defaultIconURL, defaultBannerURL string) *AuthService {
+func NewAuthService(userRepo repository.UserStore, profileRepo repository.ProfileStore, playlistRepo repository.PlaylistStore, sessionRepo repository.SessionStore, tokenRepo repository.UserTokenStore, twoFactor *TwoFactorService, throttle *LoginThrottle, mailer mail.Mailer, jwtSecret, appURL, defaultIconURL, defaultBannerURL string) *AuthService {
 	return &AuthService{
 		userRepo:         userRepo,
 		profileRepo:      profileRepo,
 		playlistRepo:     playlistRepo,
+		sessionRepo:      sessionRepo,
+		tokenRepo:        tokenRepo,
+		twoFactor:        twoFactor,
+		throttle:         throttle,
+		mailer:           mailer,
 		jwtSecret:        jwtSecret,
+		appURL:           strings.TrimRight(appURL, "/"),
 		defaultIconURL:   defaultIconURL,
 		defaultBannerURL: defaultBannerURL,
+		now:              time.Now,
 	}
 }
 
-func (s *AuthService) Register(ctx context.Context, req *model.RegisterRequest) (*model.AuthResponse, error) {
+func (s *AuthService) Register(ctx context.Context, req *model.RegisterRequest, client model.ClientInfo) (*model.AuthResponse, error) {
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
+	user, err := s.createUser(ctx, req.Email, string(hashedPassword), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.startSession(ctx, user, client)
+}
+
+// Login checks an email and password and starts a session, or returns an MFA challenge
+// Repeated failures make further logins for the email or from the IP address wait, and
+// unknown emails, wrong passwords and throttled logins take the same time and return the
+// same errors whether or not an account uses the email
+func (s *AuthService) Login(ctx context.Context, req *model.LoginRequest, client model.ClientInfo) (*model.AuthResponse, error) {
+	if err := s.throttle.Check(ctx, req.Email, client); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, req.Email)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if user == nil || !checkPassword(user, req.Password) {
+		var userID int64
+		if user != nil {
+			userID = user.ID
+		}
+		if err := s.throttle.RecordFailure(ctx, req.Email, userID, client, model.LoginFailureInvalidCredentials); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	// Only users who know the password learn that their account is blocked
+	resp, err := s.signIn(ctx, user, client)
+	if errors.Is(err, ErrAccountBlocked) {
+		if err := s.throttle.RecordFailure(ctx, req.Email, user.ID, client, model.LoginFailureAccountBlocked); err != nil {
+			return nil, err
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !resp.MFARequired {
+		if err := s.throttle.RecordSuccess(ctx, req.Email, user.ID, client); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// VerifyMFA completes a login that requires a second factor with a code from the
+// authenticator app or a recovery code
+// After MaxMFAAttempts wrong codes the MFA token stops working and the user logs in again
+func (s *AuthService) VerifyMFA(ctx context.Context, req *model.MFALoginRequest, client model.ClientInfo) (*model.AuthResponse, error) {
+	hash := hashToken(req.MFAToken)
+	challenge, err := s.tokenRepo.Find(ctx, model.UserTokenMFAChallenge, hash, s.now())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidMFAToken
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if err := s.throttle.Check(ctx, user.Email, client); err != nil {
+		return nil, err
+	}
+	if err := s.twoFactor.Verify(ctx, user, req.Code); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			if err := s.tokenRepo.RecordFailedAttempt(ctx, challenge.ID, MaxMFAAttempts, s.now()); err != nil {
+				return nil, err
+			}
+			if err := s.throttle.RecordFailure(ctx, user.Email, user.ID, client, model.LoginFailureInvalidMFACode); err != nil {
+				return nil, err
+			}
+		}
+		if errors.Is(err, ErrTwoFactorNotEnabled) {
+			// Turned off since the challenge was issued; the password has to be checked again
+			return nil, ErrInvalidMFAToken
+		}
+		return nil, err
+	}
+	if err := s.checkAccount(user); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.tokenRepo.Consume(ctx, model.UserTokenMFAChallenge, hash, s.now()); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidMFAToken
+		}
+		return nil, err
+	}
+	if err := s.throttle.RecordSuccess(ctx, user.Email, user.ID, client); err != nil {
+		return nil, err
+	}
+	return s.startSession(ctx, user, client)
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh token
+// Each refresh token works once: presenting one that was already exchanged means it
+// leaked or was stolen, so the whole session is revoked
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string, client model.ClientInfo) (*model.AuthResponse, error) {
+	session, err := s.findSession(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if session.RevokedAt != nil || !session.ExpiresAt.After(s.now()) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	hash := hashToken(refreshToken)
+	if session.RefreshTokenHash != hash {
+		return nil, s.revokeReused(ctx, session)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, session.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if err := s.checkAccount(user); err != nil {
+		return nil, err
+	}
+
+	newToken, newHash, err := newRefreshToken(session.Family)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	rotated, err := s.sessionRepo.Rotate(ctx, session.ID, hash, newHash, now, now.Add(RefreshTokenTTL), client)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Another request exchanged the same token first
+		return nil, s.revokeReused(ctx, session)
+	}
+
+	return s.authResponse(user, session.ID, newToken)
+}
+
+// Logout revokes the session an access token was issued for
+func (s *AuthService) Logout(ctx context.Context, sessionID int64) error {
+	if err := s.sessionRepo.Revoke(ctx, sessionID, s.now()); err != nil {
+		return fmt.Errorf("failed to log out: %w", err)
+	}
+	return nil
+}
+
+// LogoutRefreshToken revokes the session of a refresh token
+// Only the session's current token logs it out; an exchanged or made-up token carrying
+// the family is rejected, so it cannot sign the user out
+func (s *AuthService) LogoutRefreshToken(ctx context.Context, refreshToken string) error {
+	session, err := s.findSession(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+	if session.RefreshTokenHash != hashToken(refreshToken) {
+		return ErrInvalidRefreshToken
+	}
+	return s.Logout(ctx, session.ID)
+}
+
+// ValidateToken checks an access token and that its session has not been revoked
+// The role and account status come from the user's current row, not the token, so
+// role changes and suspensions apply to tokens already issued
+func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*model.AccessClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(s.jwtSecret), nil
+	}, jwt.WithTimeFunc(s.now))
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	// Tokens issued before sessions existed have no session and are no longer accepted
+	userID, userOK := claims["user_id"].(float64)
+	sessionID, sessionOK := claims["sid"].(float64)
+	if !userOK || !sessionOK {
+		return nil, ErrInvalidToken
+	}
+	session, err := s.sessionRepo.FindByID(ctx, int64(sessionID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+	if session.RevokedAt != nil || session.UserID != int64(userID) {
+		return nil, ErrInvalidToken
+	}
+	now := s.now()
+	if now.Sub(session.LastSeenAt) >= SessionTouchInterval {
+		if err := s.sessionRepo.Touch(ctx, session.ID, now); err != nil {
+			log.Printf("Sessions: %v", err)
+		}
+	}
+
+	user, err := s.userRepo.FindByID(ctx, session.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	return &model.AccessClaims{
+		UserID:        session.UserID,
+		SessionID:     session.ID,
+		Role:          user.Role,
+		AccountStatus: accountStatus(user, now),
+	}, nil
+}
+
+// ListSessions returns the user's active sessions, most recently seen first, marking the current one
+func (s *AuthService) ListSessions(ctx context.Context, userID, currentSessionID int64) ([]*model.Session, error) {
+	sessions, err := s.sessionRepo.FindActiveByUserID(ctx, userID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		session.Current = session.ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs one of the user's sessions out
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID int64) error {
+	session, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	if session.UserID != userID || session.RevokedAt != nil {
+		return ErrSessionNotFound
+	}
+	return s.Logout(ctx, session.ID)
+}
+
+// RevokeOtherSessions signs out all of the user's sessions except the current one
+// and returns how many were revoked
+func (s *AuthService) RevokeOtherSessions(ctx context.Context, userID, currentSessionID int64) (int64, error) {
+	return s.sessionRepo.RevokeAllByUserID(ctx, userID, currentSessionID, s.now())
+}
+
+// ChangePassword replaces the user's password after checking the current one
+// Every session is revoked, since one of them may belong to whoever learned the old
+// password; the caller continues in a new session returned here
+func (s *AuthService) ChangePassword(ctx context.Context, userID int64, req *model.ChangePasswordRequest, client model.ClientInfo) (*model.AuthResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.CurrentPassword)); err != nil {
+		return nil, ErrIncorrectPassword
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, string(hashedPassword)); err != nil {
+		return nil, err
+	}
+	if _, err := s.sessionRepo.RevokeAllByUserID(ctx, user.ID, 0, s.now()); err != nil {
+		return nil, err
+	}
+
+	return s.startSession(ctx, user, client)
+}
+
+// createUser creates a user with a profile and a "Watch Later" playlist
+// An empty passwordHash makes an account that signs in only through a provider; emailVerified
+// tells whether the provider verified the email, otherwise a verification email is sent
+func (s *AuthService) createUser(ctx context.Context, email, passwordHash string, emailVerified bool) (*model.User, error) {
 	// Create user
-	user, err := s.userRepo.Create(ctx, req.Email, string(hashedPassword))
+	user, err := s.userRepo.Create(ctx, email, passwordHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
 	// Create profile for the new user with empty icon/banner URLs
 	// Default images will be handled by the frontend
-	_, err = s.profileRepo.Create(ctx, user.ID, req.Email, "", "")
+	_, err = s.profileRepo.Create(ctx, user.ID, email, "", "")
 	if err != nil {
 		// If profile creation fails, we still return the user
 		// Profile can be created later
 		fmt.Printf("Warning: failed to create profile for user %d: %v\n", user.ID, err)
 	}
 
-	// Create default "Liked Videos" playlist
-	likedPlaylist := &model.Playlist{
-		UserID:      user.ID,
-		Title:       "高く評価した動画",
-		Description: "いいねした動画が自動的に保存されます",
-		Visibility:  "private",
-	}
-	_, err = s.playlistRepo.Create(ctx, likedPlaylist)
-	if err != nil {
-		// If playlist creation fails, we still return the user
-		// Playlist can be created later
-		fmt.Printf("Warning: failed to create liked playlist for user %d: %v\n", user.ID, err)
-	}
-
 	// Create default "Watch Later" playlist
 	watchLaterPlaylist := &model.Playlist{
 		UserID:      user.ID,
@@ -82,46 +425,247 @@ func (s *AuthService) Register(ctx context.Context, req *model.RegisterRequest)
 		fmt.Printf("Warning: failed to create watch later playlist for user %d: %v\n", user.ID, err)
 	}
 
-	// Generate token
-	token, err := s.generateToken(user.ID)
+	if emailVerified {
+		if err := s.userRepo.SetEmailVerified(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		verifiedAt := s.now()
+		user.EmailVerifiedAt = &verifiedAt
+	} else if err := s.sendEmailVerification(ctx, user); err != nil {
+		// The account works before the email is verified; the link can be sent again later
+		log.Printf("Warning: failed to send verification email to user %d: %v", user.ID, err)
+	}
+
+	return user, nil
+}
+
+// RequestPasswordReset emails a password reset link if an account uses the address
+// Unknown addresses are not reported, so the endpoint cannot be used to find accounts;
+// earlier links of the account stop working
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := s.issueUserToken(ctx, user.ID, model.UserTokenPasswordReset, PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "パスワードの再設定",
+		Body: "パスワードの再設定が申請されました。\n" +
+			"以下のリンクから新しいパスワードを設定してください（有効期限: 1時間）。\n\n" +
+			s.appURL + "/reset-password?token=" + url.QueryEscape(token) + "\n\n" +
+			"心当たりがない場合は、このメールを無視してください。",
+	})
+}
+
+// ResetPassword sets a new password with a token from a reset email
+// The token works once; all sessions are revoked, so the user signs in again with the new password
+func (s *AuthService) ResetPassword(ctx context.Context, req *model.ResetPasswordRequest) error {
+	token, err := s.tokenRepo.Consume(ctx, model.UserTokenPasswordReset, hashToken(req.Token), s.now())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	if err := s.userRepo.UpdatePassword(ctx, token.UserID, string(hashedPassword)); err != nil {
+		return err
+	}
+	if err := s.tokenRepo.InvalidateByUserID(ctx, token.UserID, model.UserTokenPasswordReset, s.now()); err != nil {
+		return err
+	}
+	if _, err := s.sessionRepo.RevokeAllByUserID(ctx, token.UserID, 0, s.now()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RequestEmailVerification emails a new verification link to the user; earlier links stop working
+func (s *AuthService) RequestEmailVerification(ctx context.Context, userID int64) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+	return s.sendEmailVerification(ctx, user)
+}
+
+// VerifyEmail marks the email address of a verification token's user as verified
+func (s *AuthService) VerifyEmail(ctx context.Context, req *model.VerifyEmailRequest) error {
+	token, err := s.tokenRepo.Consume(ctx, model.UserTokenEmailVerification, hashToken(req.Token), s.now())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrInvalidVerificationToken
+		}
+		return err
 	}
+	return s.userRepo.SetEmailVerified(ctx, token.UserID)
+}
+
+func (s *AuthService) sendEmailVerification(ctx context.Context, user *model.User) error {
+	token, err := s.issueUserToken(ctx, user.ID, model.UserTokenEmailVerification, EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "メールアドレスの確認",
+		Body: "ご登録ありがとうございます。\n" +
+			"以下のリンクを開いてメールアドレスを確認してください（有効期限: 48時間）。\n\n" +
+			s.appURL + "/verify-email?token=" + url.QueryEscape(token),
+	})
+}
+
+// issueUserToken replaces the user's unused tokens of a purpose with a new one and returns it
+func (s *AuthService) issueUserToken(ctx context.Context, userID int64, purpose string, ttl time.Duration) (string, error) {
+	now := s.now()
+	if err := s.tokenRepo.InvalidateByUserID(ctx, userID, purpose, now); err != nil {
+		return "", err
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	_, err = s.tokenRepo.Create(ctx, &model.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// dummyPasswordHash is compared against when there is no password to check, so
+// such logins take as long as those with a wrong password
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// checkPassword reports whether the password is the user's; it always runs one bcrypt comparison
+// Accounts created through a provider have no password and never match
+func checkPassword(user *model.User, password string) bool {
+	if user == nil || user.PasswordHash == "" {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) == nil
+}
 
+// signIn starts a session for a user who proved their identity, or returns an MFA
+// challenge instead if the user has two-factor authentication
+// Suspended and banned users get an AccountBlockedError
+func (s *AuthService) signIn(ctx context.Context, user *model.User, client model.ClientInfo) (*model.AuthResponse, error) {
+	if err := s.checkAccount(user); err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabledAt == nil {
+		return s.startSession(ctx, user, client)
+	}
+
+	// Only the latest challenge of a user works, so abandoned logins cannot be collected to guess codes
+	token, err := s.issueUserToken(ctx, user.ID, model.UserTokenMFAChallenge, MFAChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
 	return &model.AuthResponse{
-		Token: token,
-		User:  *user,
+		MFARequired: true,
+		MFAToken:    token,
+		ExpiresIn:   int64(MFAChallengeTTL / time.Second),
 	}, nil
 }
 
-func (s *AuthService) Login(ctx context.Context, req *model.LoginRequest) (*model.AuthResponse, error) {
-	// Find user
-	user, err := s.userRepo.FindByEmail(ctx, req.Email)
+// checkAccount returns an AccountBlockedError unless the user's account is active
+func (s *AuthService) checkAccount(user *model.User) error {
+	status := accountStatus(user, s.now())
+	if status == model.AccountStatusActive {
+		return nil
+	}
+	blocked := &AccountBlockedError{Status: status}
+	if status == model.AccountStatusSuspended {
+		blocked.SuspendedUntil = user.SuspendedUntil
+	}
+	return blocked
+}
+
+// accountStatus returns the user's status at now; suspensions that have ended are active again
+func accountStatus(user *model.User, now time.Time) string {
+	if user.Status == model.AccountStatusSuspended && (user.SuspendedUntil == nil || !user.SuspendedUntil.After(now)) {
+		return model.AccountStatusActive
+	}
+	return user.Status
+}
+
+// startSession creates a session for a user who just signed in
+func (s *AuthService) startSession(ctx context.Context, user *model.User, client model.ClientInfo) (*model.AuthResponse, error) {
+	family := uuid.New().String()
+	refreshToken, hash, err := newRefreshToken(family)
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, err
 	}
 
-	// Compare password
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
-		return nil, errors.New("invalid credentials")
+	now := s.now()
+	session, err := s.sessionRepo.Create(ctx, &model.Session{
+		UserID:           user.ID,
+		Family:           family,
+		RefreshTokenHash: hash,
+		UserAgent:        client.UserAgent,
+		IPAddress:        client.IPAddress,
+		CreatedAt:        now,
+		ExpiresAt:        now.Add(RefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
 	}
 
-	// Generate token
-	token, err := s.generateToken(user.ID)
+	return s.authResponse(user, session.ID, refreshToken)
+}
+
+func (s *AuthService) authResponse(user *model.User, sessionID int64, refreshToken string) (*model.AuthResponse, error) {
+	token, err := s.generateToken(user, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
 	return &model.AuthResponse{
-		Token: token,
-		User:  *user,
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(AccessTokenTTL / time.Second),
+		User:         user,
 	}, nil
 }
 
-func (s *AuthService) generateToken(userID int64) (string, error) {
+// generateToken signs an access token for the session
+// The role is read from the token until it expires, so role changes apply from the next refresh
+func (s *AuthService) generateToken(user *model.User, sessionID int64) (string, error) {
+	now := s.now()
 	claims := jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * 24 * 7).Unix(), // 7 days
+		"user_id": user.ID,
+		"sid":     sessionID,
+		"role":    user.Role,
+		"iat":     now.Unix(),
+		"exp":     now.Add(AccessTokenTTL).Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -133,22 +677,53 @@ func (s *AuthService) generateToken(userID int64) (string, error) {
 	return tokenString, nil
 }
 
-func (s *AuthService) ValidateToken(tokenString string) (int64, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+// findSession returns the session of a refresh token's family, without checking the token itself
+func (s *AuthService) findSession(ctx context.Context, refreshToken string) (*model.Session, error) {
+	family, _, ok := strings.Cut(refreshToken, ".")
+	if !ok || family == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	session, err := s.sessionRepo.FindByFamily(ctx, family)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidRefreshToken
 		}
-		return []byte(s.jwtSecret), nil
-	})
+		return nil, err
+	}
+	return session, nil
+}
+
+// revokeReused revokes a session whose refresh token was presented after it had been exchanged
+func (s *AuthService) revokeReused(ctx context.Context, session *model.Session) error {
+	log.Printf("Refresh token reuse for session %d of user %d; revoking the session", session.ID, session.UserID)
+	if err := s.sessionRepo.Revoke(ctx, session.ID, s.now()); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
 
+// newRefreshToken returns a random refresh token of a family and its hash
+// The family prefix finds the session; only the hash of the whole token is stored
+func newRefreshToken(family string) (token, hash string, err error) {
+	secret, err := randomToken()
 	if err != nil {
-		return 0, err
+		return "", "", err
 	}
+	token = family + "." + secret
+	return token, hashToken(token), nil
+}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userID := int64(claims["user_id"].(float64))
-		return userID, nil
+// randomToken returns 32 random bytes encoded for use in URLs
+func randomToken() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
 	}
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
 
-	return 0, errors.New("invalid token")
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
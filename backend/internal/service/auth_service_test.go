@@ -0,0 +1,580 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yukito/video-platform/internal/mail"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository/memory"
+)
+
+var testClient = model.ClientInfo{UserAgent: "test-agent", IPAddress: "192.0.2.1"}
+
+func newAuthService(db *memory.DB) *AuthService {
+	return newAuthServiceWithMailer(db, mail.NewMemoryMailer())
+}
+
+func newAuthServiceWithMailer(db *memory.DB, mailer mail.Mailer) *AuthService {
+	return NewAuthService(
+		memory.NewUserRepository(db),
+		memory.NewProfileRepository(db),
+		memory.NewPlaylistRepository(db),
+		memory.NewSessionRepository(db),
+		memory.NewUserTokenRepository(db),
+		NewTwoFactorService(memory.NewUserRepository(db), memory.NewRecoveryCodeRepository(db), "Video Platform"),
+		NewLoginThrottle(memory.NewLoginAttemptRepository(db)),
+		mailer,
+		"test-secret", "https://app.example.com/", "", "",
+	)
+}
+
+// mailedToken returns the token in the link of the last message sent to an address
+func mailedToken(t *testing.T, mailer *mail.MemoryMailer, to, path string) string {
+	t.Helper()
+	sent := mailer.Sent()
+	for i := len(sent) - 1; i >= 0; i-- {
+		if sent[i].To != to {
+			continue
+		}
+		prefix := "https://app.example.com" + path + "?token="
+		start := strings.Index(sent[i].Body, prefix)
+		if start < 0 {
+			t.Fatalf("expected a %s link in %q", path, sent[i].Body)
+		}
+		token, _, _ := strings.Cut(sent[i].Body[start+len(prefix):], "\n")
+		return token
+	}
+	t.Fatalf("expected a message to %s", to)
+	return ""
+}
+
+func register(t *testing.T, s *AuthService, email string) *model.AuthResponse {
+	t.Helper()
+	resp, err := s.Register(context.Background(), &model.RegisterRequest{Email: email, Password: "password123"}, testClient)
+	if err != nil {
+		t.Fatalf("Register returned error: %v", err)
+	}
+	return resp
+}
+
+func TestLogin_IssuesShortLivedAccessTokenAndRefreshToken(t *testing.T) {
+	s := newAuthService(memory.NewDB())
+	ctx := context.Background()
+	register(t, s, "alice@example.com")
+
+	resp, err := s.Login(ctx, &model.LoginRequest{Email: "alice@example.com", Password: "password123"}, testClient)
+	if err != nil {
+		t.Fatalf("Login returned error: %v", err)
+	}
+	if resp.RefreshToken == "" || resp.ExpiresIn != int64(AccessTokenTTL/time.Second) {
+		t.Errorf("expected a refresh token and a %v access token, got %+v", AccessTokenTTL, resp)
+	}
+
+	claims, err := s.ValidateToken(ctx, resp.Token)
+	if err != nil {
+		t.Fatalf("ValidateToken returned error: %v", err)
+	}
+	if claims.UserID != resp.User.ID || claims.SessionID == 0 {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+
+	s.now = func() time.Time { return time.Now().Add(AccessTokenTTL + time.Second) }
+	if _, err := s.ValidateToken(ctx, resp.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected expired access token to be rejected, got %v", err)
+	}
+}
+
+func TestRefresh_RotatesRefreshToken(t *testing.T) {
+	s := newAuthService(memory.NewDB())
+	ctx := context.Background()
+	first := register(t, s, "alice@example.com")
+
+	second, err := s.Refresh(ctx, first.RefreshToken, testClient)
+	if err != nil {
+		t.Fatalf("Refresh returned error: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Error("expected a new refresh token")
+	}
+	if second.User.ID != first.User.ID {
+		t.Errorf("expected user %d, got %d", first.User.ID, second.User.ID)
+	}
+
+	third, err := s.Refresh(ctx, second.RefreshToken, testClient)
+	if err != nil {
+		t.Fatalf("Refresh with the rotated token returned error: %v", err)
+	}
+	if _, err := s.ValidateToken(ctx, third.Token); err != nil {
+		t.Errorf("expected the new access token to be valid, got %v", err)
+	}
+}
+
+func TestValidateToken_UsesCurrentRole(t *testing.T) {
+	db := memory.NewDB()
+	s := newAuthService(db)
+	ctx := context.Background()
+	users := memory.NewUserRepository(db)
+	first := register(t, s, "alice@example.com")
+
+	if err := users.SetRole(ctx, first.User.ID, model.RoleAdmin); err != nil {
+		t.Fatalf("SetRole returned error: %v", err)
+	}
+	claims, err := s.ValidateToken(ctx, first.Token)
+	if err != nil || claims.Role != model.RoleAdmin {
+		t.Fatalf("expected a promotion to apply to the issued token, got %+v, %v", claims, err)
+	}
+
+	// A demoted admin loses access with the token they already hold
+	second, err := s.Refresh(ctx, first.RefreshToken, testClient)
+	if err != nil {
+		t.Fatalf("Refresh returned error: %v", err)
+	}
+	if err := users.SetRole(ctx, first.User.ID, model.RoleUser); err != nil {
+		t.Fatalf("SetRole returned error: %v", err)
+	}
+	claims, err = s.ValidateToken(ctx, second.Token)
+	if err != nil || claims.Role != model.RoleUser {
+		t.Errorf("expected a demotion to apply to the issued token, got %+v, %v", claims, err)
+	}
+}
+
+func TestRefresh_ReuseRevokesSession(t *testing.T) {
+	s := newAuthService(memory.NewDB())
+	ctx := context.Background()
+	first := register(t, s, "alice@example.com")
+
+	second, err := s.Refresh(ctx, first.RefreshToken, testClient)
+	if err != nil {
+		t.Fatalf("Refresh returned error: %v", err)
+	}
+
+	// The old token is presented again, e.g. by someone who stole it
+	if _, err := s.Refresh(ctx, first.RefreshToken, testClient); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("expected ErrRefreshTokenReused, got %v", err)
+	}
+
+	// The whole family is revoked, including the legitimate client's latest tokens
+	if _, err := s.Refresh(ctx, second.RefreshToken, testClient); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("expected the rotated refresh token to be revoked, got %v", err)
+	}
+	if _, err := s.ValidateToken(ctx, second.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected the access token to be revoked, got %v", err)
+	}
+}
+
+func TestRefresh_RejectsInvalidAndExpiredTokens(t *testing.T) {
+	s := newAuthService(memory.NewDB())
+	ctx := context.Background()
+	resp := register(t, s, "alice@example.com")
+
+	for _, token := range []string{"", "garbage", "00000000-0000-0000-0000-000000000000.secret"} {
+		if _, err := s.Refresh(ctx, token, testClient); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("Refresh(%q): expected ErrInvalidRefreshToken, got %v", token, err)
+		}
+	}
+
+	s.now = func() time.Time { return time.Now().Add(RefreshTokenTTL + time.Hour) }
+	if _, err := s.Refresh(ctx, resp.RefreshToken, testClient); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("expected expired refresh token to be rejected, got %v", err)
+	}
+}
+
+func TestLogout_RevokesSession(t *testing.T) {
+	s := newAuthService(memory.NewDB())
+	ctx := context.Background()
+	register(t, s, "alice@example.com")
+	resp, err := s.Login(ctx, &model.LoginRequest{Email: "alice@example.com", Password: "password123"}, testClient)
+	if err != nil {
+		t.Fatalf("Login returned error: %v", err)
+	}
+	other, err := s.Login(ctx, &model.LoginRequest{Email: "alice@example.com", Password: "password123"}, testClient)
+	if err != nil {
+		t.Fatalf("Login returned error: %v", err)
+	}
+
+	claims, err := s.ValidateToken(ctx, resp.Token)
+	if err != nil {
+		t.Fatalf("ValidateToken returned error: %v", err)
+	}
+	if err := s.Logout(ctx, claims.SessionID); err != nil {
+		t.Fatalf("Logout returned error: %v", err)
+	}
+
+	if _, err := s.ValidateToken(ctx, resp.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected the access token to be rejected after logout, got %v", err)
+	}
+	if _, err := s.Refresh(ctx, resp.RefreshToken, testClient); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("expected the refresh token to be rejected after logout, got %v", err)
+	}
+	if _, err := s.ValidateToken(ctx, other.Token); err != nil {
+		t.Errorf("expected other sessions to stay signed in, got %v", err)
+	}
+
+	if err := s.LogoutRefreshToken(ctx, other.RefreshToken); err != nil {
+		t.Fatalf("LogoutRefreshToken returned error: %v", err)
+	}
+	if _, err := s.ValidateToken(ctx, other.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected the access token to be rejected after logout, got %v", err)
+	}
+}
+
+func TestLogoutRefreshToken_RequiresCurrentToken(t *testing.T) {
+	s := newAuthService(memory.NewDB())
+	ctx := context.Background()
+	first := register(t, s, "alice@example.com")
+	second, err := s.Refresh(ctx, first.RefreshToken, testClient)
+	if err != nil {
+		t.Fatalf("Refresh returned error: %v", err)
+	}
+
+	family, _, _ := strings.Cut(second.RefreshToken, ".")
+	for _, token := range []string{first.RefreshToken, family + ".made-up"} {
+		if err := s.LogoutRefreshToken(ctx, token); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("expected ErrInvalidRefreshToken for %q, got %v", token, err)
+		}
+	}
+	if _, err := s.ValidateToken(ctx, second.Token); err != nil {
+		t.Errorf("expected the session to stay signed in, got %v", err)
+	}
+
+	if err := s.LogoutRefreshToken(ctx, second.RefreshToken); err != nil {
+		t.Fatalf("LogoutRefreshToken returned error: %v", err)
+	}
+	if _, err := s.ValidateToken(ctx, second.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected the session to be signed out, got %v", err)
+	}
+}
+
+func TestListSessions_ShowsActiveSessionsOfUser(t *testing.T) {
+	db := memory.NewDB()
+	s := newAuthService(db)
+	ctx := context.Background()
+	first := register(t, s, "alice@example.com")
+	second, err := s.Login(ctx, &model.LoginRequest{Email: "alice@example.com", Password: "password123"},
+		model.ClientInfo{UserAgent: "phone", IPAddress: "198.51.100.7"})
+	if err != nil {
+		t.Fatalf("Login returned error: %v", err)
+	}
+	register(t, s, "bob@example.com")
+
+	claims, _ := s.ValidateToken(ctx, second.Token)
+	sessions, err := s.ListSessions(ctx, first.User.ID, claims.SessionID)
+	if err != nil {
+		t.Fatalf("ListSessions returned error: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+	// Most recently seen first
+	if sessions[0].ID != claims.SessionID || !sessions[0].Current || sessions[1].Current {
+		t.Errorf("expected the current session first and marked, got %+v, %+v", sessions[0], sessions[1])
+	}
+	if sessions[0].UserAgent != "phone" || sessions[0].IPAddress != "198.51.100.7" {
+		t.Errorf("expected the device of the session, got %q from %q", sessions[0].UserAgent, sessions[0].IPAddress)
+	}
+
+	if err := s.RevokeSession(ctx, first.User.ID, sessions[1].ID); err != nil {
+		t.Fatalf("RevokeSession returned error: %v", err)
+	}
+	if _, err := s.ValidateToken(ctx, first.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected the revoked session's access token to be rejected, got %v", err)
+	}
+	if sessions, _ := s.ListSessions(ctx, first.User.ID, claims.SessionID); len(sessions) != 1 {
+		t.Errorf("expected 1 session after revoking, got %d", len(sessions))
+	}
+}
+
+func TestRevokeSession_RejectsOtherUsersSessions(t *testing.T) {
+	s := newAuthService(memory.NewDB())
+	ctx := context.Background()
+	alice := register(t, s, "alice@example.com")
+	bob := register(t, s, "bob@example.com")
+
+	claims, _ := s.ValidateToken(ctx, bob.Token)
+	if err := s.RevokeSession(ctx, alice.User.ID, claims.SessionID); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("expected ErrSessionNotFound, got %v", err)
+	}
+	if err := s.RevokeSession(ctx, alice.User.ID, 999); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("expected ErrSessionNotFound for unknown session, got %v", err)
+	}
+	if _, err := s.ValidateToken(ctx, bob.Token); err != nil {
+		t.Errorf("expected bob to stay signed in, got %v", err)
+	}
+}
+
+func TestRevokeOtherSessions_KeepsCurrentSession(t *testing.T) {
+	s := newAuthService(memory.NewDB())
+	ctx := context.Background()
+	first := register(t, s, "alice@example.com")
+	var others []*model.AuthResponse
+	for range 2 {
+		resp, err := s.Login(ctx, &model.LoginRequest{Email: "alice@example.com", Password: "password123"}, testClient)
+		if err != nil {
+			t.Fatalf("Login returned error: %v", err)
+		}
+		others = append(others, resp)
+	}
+	bob := register(t, s, "bob@example.com")
+
+	claims, _ := s.ValidateToken(ctx, first.Token)
+	revoked, err := s.RevokeOtherSessions(ctx, first.User.ID, claims.SessionID)
+	if err != nil {
+		t.Fatalf("RevokeOtherSessions returned error: %v", err)
+	}
+	if revoked != 2 {
+		t.Errorf("expected 2 sessions revoked, got %d", revoked)
+	}
+
+	if _, err := s.ValidateToken(ctx, first.Token); err != nil {
+		t.Errorf("expected the current session to stay signed in, got %v", err)
+	}
+	for _, other := range others {
+		if _, err := s.Refresh(ctx, other.RefreshToken, testClient); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("expected other sessions to be revoked, got %v", err)
+		}
+	}
+	if _, err := s.ValidateToken(ctx, bob.Token); err != nil {
+		t.Errorf("expected other users to stay signed in, got %v", err)
+	}
+}
+
+func TestChangePassword_RevokesAllSessions(t *testing.T) {
+	s := newAuthService(memory.NewDB())
+	ctx := context.Background()
+	first := register(t, s, "alice@example.com")
+	second, err := s.Login(ctx, &model.LoginRequest{Email: "alice@example.com", Password: "password123"}, testClient)
+	if err != nil {
+		t.Fatalf("Login returned error: %v", err)
+	}
+
+	_, err = s.ChangePassword(ctx, first.User.ID, &model.ChangePasswordRequest{CurrentPassword: "wrong-password", NewPassword: "new-password"}, testClient)
+	if !errors.Is(err, ErrIncorrectPassword) {
+		t.Fatalf("expected ErrIncorrectPassword, got %v", err)
+	}
+	if _, err := s.ValidateToken(ctx, first.Token); err != nil {
+		t.Errorf("expected sessions to survive a failed change, got %v", err)
+	}
+
+	resp, err := s.ChangePassword(ctx, first.User.ID, &model.ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "new-password"}, testClient)
+	if err != nil {
+		t.Fatalf("ChangePassword returned error: %v", err)
+	}
+	for _, old := range []*model.AuthResponse{first, second} {
+		if _, err := s.ValidateToken(ctx, old.Token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("expected sessions from before the change to be revoked, got %v", err)
+		}
+	}
+	if _, err := s.ValidateToken(ctx, resp.Token); err != nil {
+		t.Errorf("expected the new session to be valid, got %v", err)
+	}
+
+	if _, err := s.Login(ctx, &model.LoginRequest{Email: "alice@example.com", Password: "password123"}, testClient); err == nil {
+		t.Error("expected the old password to be rejected")
+	}
+	if _, err := s.Login(ctx, &model.LoginRequest{Email: "alice@example.com", Password: "new-password"}, testClient); err != nil {
+		t.Errorf("expected the new password to work, got %v", err)
+	}
+}
+
+func TestValidateToken_UpdatesLastSeen(t *testing.T) {
+	db := memory.NewDB()
+	s := newAuthService(db)
+	ctx := context.Background()
+	resp := register(t, s, "alice@example.com")
+
+	sessions, _ := s.ListSessions(ctx, resp.User.ID, 0)
+	before := sessions[0].LastSeenAt
+
+	s.now = func() time.Time { return time.Now().Add(SessionTouchInterval) }
+	if _, err := s.ValidateToken(ctx, resp.Token); err != nil {
+		t.Fatalf("ValidateToken returned error: %v", err)
+	}
+	sessions, _ = s.ListSessions(ctx, resp.User.ID, 0)
+	if !sessions[0].LastSeenAt.After(before) {
+		t.Error("expected the last-seen time to be updated")
+	}
+
+	db.Fail("SessionRepository.Touch", errors.New("db down"))
+	if _, err := s.ValidateToken(ctx, resp.Token); err != nil {
+		t.Errorf("expected a failed last-seen update not to reject the token, got %v", err)
+	}
+}
+
+func TestSessions_ExpireByServiceClock(t *testing.T) {
+	s := newAuthService(memory.NewDB())
+	ctx := context.Background()
+
+	// The service clock is hours behind the store's, as when they run in different time zones
+	s.now = func() time.Time { return time.Now().Add(-9 * time.Hour) }
+	resp := register(t, s, "alice@example.com")
+
+	sessions, err := s.ListSessions(ctx, resp.User.ID, 0)
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("expected one active session, got %+v, %v", sessions, err)
+	}
+	if _, err := s.Refresh(ctx, resp.RefreshToken, testClient); err != nil {
+		t.Errorf("expected the refresh token to work, got %v", err)
+	}
+
+	s.now = func() time.Time { return time.Now().Add(-9*time.Hour + RefreshTokenTTL + time.Minute) }
+	sessions, _ = s.ListSessions(ctx, resp.User.ID, 0)
+	if len(sessions) != 0 {
+		t.Errorf("expected the session to expire after the refresh token TTL, got %+v", sessions)
+	}
+}
+
+func TestPasswordReset_SetsPasswordAndRevokesSessions(t *testing.T) {
+	mailer := mail.NewMemoryMailer()
+	s := newAuthServiceWithMailer(memory.NewDB(), mailer)
+	ctx := context.Background()
+	resp := register(t, s, "alice@example.com")
+
+	if err := s.RequestPasswordReset(ctx, "alice@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset returned error: %v", err)
+	}
+	token := mailedToken(t, mailer, "alice@example.com", "/reset-password")
+
+	if err := s.ResetPassword(ctx, &model.ResetPasswordRequest{Token: token, NewPassword: "new-password"}); err != nil {
+		t.Fatalf("ResetPassword returned error: %v", err)
+	}
+	if _, err := s.ValidateToken(ctx, resp.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected sessions to be revoked, got %v", err)
+	}
+	if _, err := s.Login(ctx, &model.LoginRequest{Email: "alice@example.com", Password: "new-password"}, testClient); err != nil {
+		t.Errorf("expected the new password to work, got %v", err)
+	}
+
+	// Tokens are single-use
+	err := s.ResetPassword(ctx, &model.ResetPasswordRequest{Token: token, NewPassword: "another-password"})
+	if !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("expected a used token to be rejected, got %v", err)
+	}
+}
+
+func TestPasswordReset_UnknownEmailIsNotReported(t *testing.T) {
+	mailer := mail.NewMemoryMailer()
+	s := newAuthServiceWithMailer(memory.NewDB(), mailer)
+
+	if err := s.RequestPasswordReset(context.Background(), "nobody@example.com"); err != nil {
+		t.Errorf("expected no error for an unknown email, got %v", err)
+	}
+	if sent := mailer.Sent(); len(sent) != 0 {
+		t.Errorf("expected no mail, got %+v", sent)
+	}
+}
+
+func TestPasswordReset_RejectsExpiredAndReplacedTokens(t *testing.T) {
+	mailer := mail.NewMemoryMailer()
+	s := newAuthServiceWithMailer(memory.NewDB(), mailer)
+	ctx := context.Background()
+	register(t, s, "alice@example.com")
+
+	// Issued long enough ago to have expired
+	s.now = func() time.Time { return time.Now().Add(-PasswordResetTTL - time.Minute) }
+	if err := s.RequestPasswordReset(ctx, "alice@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset returned error: %v", err)
+	}
+	expired := mailedToken(t, mailer, "alice@example.com", "/reset-password")
+	s.now = time.Now
+
+	if err := s.RequestPasswordReset(ctx, "alice@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset returned error: %v", err)
+	}
+	first := mailedToken(t, mailer, "alice@example.com", "/reset-password")
+	if err := s.RequestPasswordReset(ctx, "alice@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset returned error: %v", err)
+	}
+	latest := mailedToken(t, mailer, "alice@example.com", "/reset-password")
+
+	for name, token := range map[string]string{"expired": expired, "replaced": first, "unknown": "garbage"} {
+		err := s.ResetPassword(ctx, &model.ResetPasswordRequest{Token: token, NewPassword: "new-password"})
+		if !errors.Is(err, ErrInvalidResetToken) {
+			t.Errorf("%s token: expected ErrInvalidResetToken, got %v", name, err)
+		}
+	}
+	if err := s.ResetPassword(ctx, &model.ResetPasswordRequest{Token: latest, NewPassword: "new-password"}); err != nil {
+		t.Errorf("expected the latest token to work, got %v", err)
+	}
+}
+
+func TestPasswordReset_ExpiresByServiceClock(t *testing.T) {
+	mailer := mail.NewMemoryMailer()
+	s := newAuthServiceWithMailer(memory.NewDB(), mailer)
+	ctx := context.Background()
+	register(t, s, "alice@example.com")
+
+	// The service clock is hours behind the store's, as when they run in different time zones
+	issued := time.Now().Add(-9 * time.Hour)
+	s.now = func() time.Time { return issued }
+	if err := s.RequestPasswordReset(ctx, "alice@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset returned error: %v", err)
+	}
+	token := mailedToken(t, mailer, "alice@example.com", "/reset-password")
+
+	s.now = func() time.Time { return issued.Add(PasswordResetTTL + time.Minute) }
+	err := s.ResetPassword(ctx, &model.ResetPasswordRequest{Token: token, NewPassword: "new-password"})
+	if !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("expected the token to expire after its TTL, got %v", err)
+	}
+
+	s.now = func() time.Time { return issued.Add(PasswordResetTTL - time.Minute) }
+	if err := s.ResetPassword(ctx, &model.ResetPasswordRequest{Token: token, NewPassword: "new-password"}); err != nil {
+		t.Errorf("expected the token to work within its TTL, got %v", err)
+	}
+}
+
+func TestVerifyEmail_MarksEmailVerified(t *testing.T) {
+	mailer := mail.NewMemoryMailer()
+	db := memory.NewDB()
+	s := newAuthServiceWithMailer(db, mailer)
+	ctx := context.Background()
+	resp := register(t, s, "alice@example.com")
+	if resp.User.EmailVerifiedAt != nil {
+		t.Fatal("expected a new account to be unverified")
+	}
+
+	// Registration sends the first link; resending replaces it
+	first := mailedToken(t, mailer, "alice@example.com", "/verify-email")
+	if err := s.RequestEmailVerification(ctx, resp.User.ID); err != nil {
+		t.Fatalf("RequestEmailVerification returned error: %v", err)
+	}
+	token := mailedToken(t, mailer, "alice@example.com", "/verify-email")
+	if err := s.VerifyEmail(ctx, &model.VerifyEmailRequest{Token: first}); !errors.Is(err, ErrInvalidVerificationToken) {
+		t.Errorf("expected the replaced token to be rejected, got %v", err)
+	}
+
+	if err := s.VerifyEmail(ctx, &model.VerifyEmailRequest{Token: token}); err != nil {
+		t.Fatalf("VerifyEmail returned error: %v", err)
+	}
+	user, _ := memory.NewUserRepository(db).FindByID(ctx, resp.User.ID)
+	if user.EmailVerifiedAt == nil {
+		t.Error("expected the email to be verified")
+	}
+	if err := s.RequestEmailVerification(ctx, resp.User.ID); !errors.Is(err, ErrEmailAlreadyVerified) {
+		t.Errorf("expected ErrEmailAlreadyVerified, got %v", err)
+	}
+
+	// A password reset token does not verify an email
+	if err := s.RequestPasswordReset(ctx, "alice@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset returned error: %v", err)
+	}
+	reset := mailedToken(t, mailer, "alice@example.com", "/reset-password")
+	if err := s.VerifyEmail(ctx, &model.VerifyEmailRequest{Token: reset}); !errors.Is(err, ErrInvalidVerificationToken) {
+		t.Errorf("expected a reset token to be rejected, got %v", err)
+	}
+}
+
+func TestRegister_SucceedsWhenVerificationMailFails(t *testing.T) {
+	mailer := mail.NewMemoryMailer()
+	mailer.Fail(errors.New("smtp down"))
+	s := newAuthServiceWithMailer(memory.NewDB(), mailer)
+
+	resp := register(t, s, "alice@example.com")
+	if resp.Token == "" {
+		t.Error("expected a session despite the failed mail")
+	}
+}
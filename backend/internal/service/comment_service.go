@@ -5,16 +5,24 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/policy"
 	"github.com/yukito/video-platform/internal/repository"
 )
 
+var (
+	ErrCommentNotFound = errors.New("comment not found")
+	// ErrCommentForbidden is returned when the user may not change or delete a comment
+	ErrCommentForbidden = errors.New("not allowed to change this comment")
+)
+
 type CommentService struct {
-	commentRepo *repository.CommentRepository
-	videoRepo   *repository.VideoRepository
+	commentRepo repository.CommentStore
+	videoRepo   repository.VideoStore
 }
 
-func NewCommentService(commentRepo *repository.CommentRepository, videoRepo *repository.VideoRepository) *CommentService {
+func NewCommentService(commentRepo repository.CommentStore, videoRepo repository.VideoStore) *CommentService {
 	return &CommentService{
 		commentRepo: commentRepo,
 		videoRepo:   videoRepo,
@@ -22,17 +30,18 @@ func NewCommentService(commentRepo *repository.CommentRepository, videoRepo *rep
 }
 
 func (s *CommentService) Create(ctx context.Context, userID int64, req *model.CreateCommentRequest) (*model.CommentWithProfile, error) {
-	// Verify video exists
-	_, err := s.videoRepo.FindByID(ctx, req.VideoID)
-	if err != nil {
-		return nil, fmt.Errorf("video not found: %w", err)
+	if _, err := s.findWatchableVideo(ctx, req.VideoID, userID); err != nil {
+		return nil, err
 	}
 
-	// If replying to a comment, verify parent comment exists
+	// If replying to a comment, verify parent comment exists on the same video
 	if req.ParentCommentID != nil {
-		parentComment, err := s.commentRepo.FindByID(ctx, *req.ParentCommentID)
+		parentComment, err := s.findComment(ctx, *req.ParentCommentID)
 		if err != nil {
-			return nil, fmt.Errorf("parent comment not found: %w", err)
+			return nil, err
+		}
+		if parentComment.VideoID != req.VideoID {
+			return nil, ErrCommentNotFound
 		}
 		// Don't allow nested replies (only 1 level)
 		if parentComment.ParentCommentID != nil {
@@ -56,8 +65,9 @@ func (s *CommentService) Create(ctx context.Context, userID int64, req *model.Cr
 	}
 
 	// Fetch the comment with profile
-	comments, err := s.commentRepo.FindByVideoIDWithProfile(ctx, createdComment.VideoID, &userID, 1, 0)
-	if err != nil || len(comments) == 0 {
+	// The newest top-level comment is only ours when it isn't a reply and nothing is pinned above it
+	comments, err := s.commentRepo.FindByVideoIDWithProfile(ctx, createdComment.VideoID, &userID, model.PageRequest{Limit: 1})
+	if err != nil || len(comments) == 0 || comments[0].ID != createdComment.ID {
 		// Fallback: return basic comment data
 		return &model.CommentWithProfile{
 			ID:              createdComment.ID,
@@ -77,45 +87,61 @@ func (s *CommentService) Create(ctx context.Context, userID int64, req *model.Cr
 	return comments[0], nil
 }
 
-func (s *CommentService) GetCommentsByVideoID(ctx context.Context, videoID int64, userID *int64, limit, offset int) ([]*model.CommentWithProfile, error) {
-	// Verify video exists
-	_, err := s.videoRepo.FindByID(ctx, videoID)
+// GetCommentsByVideoID returns a page of top-level comments, pinned comments first and then newest first
+func (s *CommentService) GetCommentsByVideoID(ctx context.Context, videoID int64, userID *int64, query model.PageQuery) (*model.Page[*model.CommentWithProfile], error) {
+	limit, page, err := pageRequest(query, DefaultPageLimit)
 	if err != nil {
-		return nil, fmt.Errorf("video not found: %w", err)
+		return nil, err
 	}
 
-	comments, err := s.commentRepo.FindByVideoIDWithProfile(ctx, videoID, userID, limit, offset)
+	if _, err := s.findWatchableVideo(ctx, videoID, optionalUserID(userID)); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.FindByVideoIDWithProfile(ctx, videoID, userID, page)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comments: %w", err)
 	}
 
-	return comments, nil
+	return newPage(comments, limit, func(comment *model.CommentWithProfile) model.PageCursor {
+		return model.PageCursor{Pinned: comment.IsPinned, Time: comment.CreatedAt, ID: comment.ID}
+	}), nil
 }
 
-func (s *CommentService) GetRepliesByParentID(ctx context.Context, parentCommentID int64, userID *int64, limit, offset int) ([]*model.CommentWithProfile, error) {
-	// Verify parent comment exists
-	_, err := s.commentRepo.FindByID(ctx, parentCommentID)
+// GetRepliesByParentID returns a page of replies to a comment, oldest first
+func (s *CommentService) GetRepliesByParentID(ctx context.Context, parentCommentID int64, userID *int64, query model.PageQuery) (*model.Page[*model.CommentWithProfile], error) {
+	limit, page, err := pageRequest(query, DefaultPageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := s.findComment(ctx, parentCommentID)
 	if err != nil {
-		return nil, fmt.Errorf("parent comment not found: %w", err)
+		return nil, err
+	}
+	if _, err := s.findWatchableVideo(ctx, parent.VideoID, optionalUserID(userID)); err != nil {
+		return nil, err
 	}
 
-	replies, err := s.commentRepo.FindRepliesByParentIDWithProfile(ctx, parentCommentID, userID, limit, offset)
+	replies, err := s.commentRepo.FindRepliesByParentIDWithProfile(ctx, parentCommentID, userID, page)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get replies: %w", err)
 	}
 
-	return replies, nil
+	return newPage(replies, limit, func(reply *model.CommentWithProfile) model.PageCursor {
+		return model.PageCursor{Time: reply.CreatedAt, ID: reply.ID}
+	}), nil
 }
 
-func (s *CommentService) Update(ctx context.Context, userID, commentID int64, req *model.UpdateCommentRequest) (*model.Comment, error) {
-	// Check if comment exists and belongs to user
-	existingComment, err := s.commentRepo.FindByID(ctx, commentID)
+// Update changes the text of a comment; only its author can edit it
+func (s *CommentService) Update(ctx context.Context, actor policy.Actor, commentID int64, req *model.UpdateCommentRequest) (*model.Comment, error) {
+	existingComment, err := s.findComment(ctx, commentID)
 	if err != nil {
-		return nil, fmt.Errorf("comment not found: %w", err)
+		return nil, err
 	}
 
-	if existingComment.UserID != userID {
-		return nil, errors.New("unauthorized to update this comment")
+	if !policy.CanEditComment(actor, existingComment) {
+		return nil, ErrCommentForbidden
 	}
 
 	existingComment.Content = req.Content
@@ -128,15 +154,15 @@ func (s *CommentService) Update(ctx context.Context, userID, commentID int64, re
 	return updatedComment, nil
 }
 
-func (s *CommentService) Delete(ctx context.Context, userID, commentID int64) error {
-	// Check if comment exists and belongs to user
-	existingComment, err := s.commentRepo.FindByID(ctx, commentID)
+// Delete deletes a comment; its author and moderators can delete it
+func (s *CommentService) Delete(ctx context.Context, actor policy.Actor, commentID int64) error {
+	existingComment, err := s.findComment(ctx, commentID)
 	if err != nil {
-		return fmt.Errorf("comment not found: %w", err)
+		return err
 	}
 
-	if existingComment.UserID != userID {
-		return errors.New("unauthorized to delete this comment")
+	if !policy.CanDeleteComment(actor, existingComment) {
+		return ErrCommentForbidden
 	}
 
 	if err := s.commentRepo.Delete(ctx, commentID); err != nil {
@@ -160,7 +186,7 @@ func (s *CommentService) PinComment(ctx context.Context, userID, commentID int64
 	}
 
 	// Only video creator can pin comments
-	if video.UserID != userID {
+	if !policy.CanCurateComments(policy.Actor{UserID: userID}, video) {
 		return errors.New("only video creator can pin comments")
 	}
 
@@ -190,7 +216,7 @@ func (s *CommentService) SetCreatorLiked(ctx context.Context, userID, commentID
 	}
 
 	// Only video creator can mark comments as creator liked
-	if video.UserID != userID {
+	if !policy.CanCurateComments(policy.Actor{UserID: userID}, video) {
 		return errors.New("only video creator can mark comments as creator liked")
 	}
 
@@ -234,10 +260,50 @@ func (s *CommentService) UnlikeComment(ctx context.Context, userID, commentID in
 	return nil
 }
 
-func (s *CommentService) GetCommentCount(ctx context.Context, videoID int64) (int64, error) {
+// GetCommentCount returns the number of comments on a video the viewer can watch (viewerID 0 for anonymous viewers)
+func (s *CommentService) GetCommentCount(ctx context.Context, viewerID, videoID int64) (int64, error) {
+	if _, err := s.findWatchableVideo(ctx, videoID, viewerID); err != nil {
+		return 0, err
+	}
+
 	count, err := s.commentRepo.GetCommentCount(ctx, videoID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get comment count: %w", err)
 	}
 	return count, nil
 }
+
+// findComment returns a comment, or ErrCommentNotFound if there is none
+func (s *CommentService) findComment(ctx context.Context, id int64) (*model.Comment, error) {
+	comment, err := s.commentRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("failed to find comment: %w", err)
+	}
+	return comment, nil
+}
+
+// findWatchableVideo returns a video, or ErrVideoNotFound if there is none or the viewer cannot watch it
+func (s *CommentService) findWatchableVideo(ctx context.Context, videoID, viewerID int64) (*model.Video, error) {
+	video, err := s.videoRepo.FindByID(ctx, videoID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrVideoNotFound
+		}
+		return nil, fmt.Errorf("failed to find video: %w", err)
+	}
+	if !canWatch(video, viewerID) {
+		return nil, ErrVideoNotFound
+	}
+	return video, nil
+}
+
+// optionalUserID returns the ID of an optional viewer, 0 for anonymous viewers
+func optionalUserID(userID *int64) int64 {
+	if userID == nil {
+		return 0
+	}
+	return *userID
+}
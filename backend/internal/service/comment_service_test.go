@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/policy"
+	"github.com/yukito/video-platform/internal/repository/memory"
+)
+
+// commentFixture wires a CommentService to in-memory tables with one video owned by creatorID
+type commentFixture struct {
+	db        *memory.DB
+	service   *CommentService
+	creatorID int64
+	viewerID  int64
+	videoID   int64
+}
+
+func newCommentFixture(t *testing.T) *commentFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	db := memory.NewDB()
+	users := memory.NewUserRepository(db)
+	profiles := memory.NewProfileRepository(db)
+	videos := memory.NewVideoRepository(db)
+
+	creator, _ := users.Create(ctx, "creator@example.com", "hash")
+	viewer, _ := users.Create(ctx, "viewer@example.com", "hash")
+	_, _ = profiles.Create(ctx, creator.ID, creator.Email, "", "")
+	_, _ = profiles.Create(ctx, viewer.ID, viewer.Email, "", "")
+
+	video, err := videos.Create(ctx, &model.Video{UserID: creator.ID, Title: "video", Status: model.VideoStatusReady, Visibility: model.VideoVisibilityPublic})
+	if err != nil {
+		t.Fatalf("failed to seed video: %v", err)
+	}
+
+	return &commentFixture{
+		db:        db,
+		service:   NewCommentService(memory.NewCommentRepository(db), videos),
+		creatorID: creator.ID,
+		viewerID:  viewer.ID,
+		videoID:   video.ID,
+	}
+}
+
+// asUser is the actor of a user with the default role
+func asUser(userID int64) policy.Actor {
+	return policy.Actor{UserID: userID, Role: model.RoleUser}
+}
+
+func (f *commentFixture) comment(t *testing.T, userID int64, parentID *int64, content string) *model.CommentWithProfile {
+	t.Helper()
+	comment, err := f.service.Create(context.Background(), userID, &model.CreateCommentRequest{
+		VideoID:         f.videoID,
+		ParentCommentID: parentID,
+		Content:         content,
+	})
+	if err != nil {
+		t.Fatalf("failed to create comment: %v", err)
+	}
+	return comment
+}
+
+func TestCommentCreate_ReturnsCommentWithProfile(t *testing.T) {
+	f := newCommentFixture(t)
+
+	comment := f.comment(t, f.viewerID, nil, "hello")
+
+	if comment.Content != "hello" || comment.UserID != f.viewerID {
+		t.Errorf("unexpected comment: %+v", comment)
+	}
+	if comment.Profile == nil || comment.Profile.ChannelName != "viewer" {
+		t.Errorf("expected commenter profile, got %+v", comment.Profile)
+	}
+	if comment.IsVideoCreator {
+		t.Error("viewer must not be flagged as video creator")
+	}
+}
+
+func TestCommentCreate_RejectsUnknownVideo(t *testing.T) {
+	f := newCommentFixture(t)
+
+	_, err := f.service.Create(context.Background(), f.viewerID, &model.CreateCommentRequest{
+		VideoID: f.videoID + 100,
+		Content: "hello",
+	})
+	if !errors.Is(err, ErrVideoNotFound) {
+		t.Fatalf("expected ErrVideoNotFound commenting on a missing video, got %v", err)
+	}
+}
+
+func TestComments_HiddenOnVideosTheViewerCannotWatch(t *testing.T) {
+	f := newCommentFixture(t)
+	ctx := context.Background()
+	videos := memory.NewVideoRepository(f.db)
+	publishAt := time.Now().Add(24 * time.Hour)
+
+	cases := map[string]*model.Video{
+		"private":    {Visibility: model.VideoVisibilityPrivate},
+		"scheduled":  {Visibility: model.VideoVisibilityPrivate, PublishAt: &publishAt},
+		"processing": {Status: model.VideoStatusProcessing, Visibility: model.VideoVisibilityPublic},
+	}
+	for name, video := range cases {
+		t.Run(name, func(t *testing.T) {
+			video.UserID = f.creatorID
+			video.Title = name
+			if video.Status == "" {
+				video.Status = model.VideoStatusReady
+			}
+			video, err := videos.Create(ctx, video)
+			if err != nil {
+				t.Fatalf("failed to seed video: %v", err)
+			}
+			// The owner can still comment on their own video
+			parent, err := f.service.Create(ctx, f.creatorID, &model.CreateCommentRequest{VideoID: video.ID, Content: "owner"})
+			if err != nil {
+				t.Fatalf("expected the owner to comment, got %v", err)
+			}
+
+			for _, viewer := range []*int64{&f.viewerID, nil} {
+				if _, err := f.service.GetCommentsByVideoID(ctx, video.ID, viewer, model.PageQuery{}); !errors.Is(err, ErrVideoNotFound) {
+					t.Errorf("GetCommentsByVideoID: expected ErrVideoNotFound, got %v", err)
+				}
+				if _, err := f.service.GetRepliesByParentID(ctx, parent.ID, viewer, model.PageQuery{}); !errors.Is(err, ErrVideoNotFound) {
+					t.Errorf("GetRepliesByParentID: expected ErrVideoNotFound, got %v", err)
+				}
+				if _, err := f.service.GetCommentCount(ctx, optionalUserID(viewer), video.ID); !errors.Is(err, ErrVideoNotFound) {
+					t.Errorf("GetCommentCount: expected ErrVideoNotFound, got %v", err)
+				}
+			}
+			for _, parentID := range []*int64{nil, &parent.ID} {
+				_, err := f.service.Create(ctx, f.viewerID, &model.CreateCommentRequest{VideoID: video.ID, ParentCommentID: parentID, Content: "hello"})
+				if !errors.Is(err, ErrVideoNotFound) {
+					t.Errorf("Create: expected ErrVideoNotFound, got %v", err)
+				}
+			}
+
+			if count, err := f.service.GetCommentCount(ctx, f.creatorID, video.ID); err != nil || count != 1 {
+				t.Errorf("expected the owner to see 1 comment, got %d, %v", count, err)
+			}
+		})
+	}
+}
+
+func TestCommentCreate_AllowsReplyToTopLevelComment(t *testing.T) {
+	f := newCommentFixture(t)
+	parent := f.comment(t, f.viewerID, nil, "question")
+
+	reply := f.comment(t, f.creatorID, &parent.ID, "answer")
+
+	if reply.ParentCommentID == nil || *reply.ParentCommentID != parent.ID {
+		t.Errorf("expected reply to reference parent %d, got %v", parent.ID, reply.ParentCommentID)
+	}
+
+	replies, err := f.service.GetRepliesByParentID(context.Background(), parent.ID, nil, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetRepliesByParentID returned error: %v", err)
+	}
+	if len(replies.Items) != 1 || !replies.Items[0].IsVideoCreator {
+		t.Errorf("expected one reply from the video creator, got %+v", replies.Items)
+	}
+}
+
+func TestCommentCreate_CannotReplyToReply(t *testing.T) {
+	f := newCommentFixture(t)
+	parent := f.comment(t, f.viewerID, nil, "question")
+	reply := f.comment(t, f.creatorID, &parent.ID, "answer")
+
+	_, err := f.service.Create(context.Background(), f.viewerID, &model.CreateCommentRequest{
+		VideoID:         f.videoID,
+		ParentCommentID: &reply.ID,
+		Content:         "nested",
+	})
+	if err == nil || err.Error() != "cannot reply to a reply" {
+		t.Fatalf("expected \"cannot reply to a reply\", got %v", err)
+	}
+
+	count, _ := f.service.GetCommentCount(context.Background(), 0, f.videoID)
+	if count != 2 {
+		t.Errorf("expected the nested reply not to be stored, comment count %d", count)
+	}
+}
+
+func TestCommentCreate_RejectsMissingParent(t *testing.T) {
+	f := newCommentFixture(t)
+	missing := int64(999)
+
+	_, err := f.service.Create(context.Background(), f.viewerID, &model.CreateCommentRequest{
+		VideoID:         f.videoID,
+		ParentCommentID: &missing,
+		Content:         "reply",
+	})
+	if !errors.Is(err, ErrCommentNotFound) {
+		t.Fatalf("expected ErrCommentNotFound replying to a missing comment, got %v", err)
+	}
+}
+
+func TestCommentPin_OnlyVideoCreatorOnTopLevel(t *testing.T) {
+	f := newCommentFixture(t)
+	ctx := context.Background()
+	parent := f.comment(t, f.viewerID, nil, "question")
+	reply := f.comment(t, f.creatorID, &parent.ID, "answer")
+
+	if err := f.service.PinComment(ctx, f.viewerID, parent.ID, true); err == nil {
+		t.Error("expected non-creator pin to fail")
+	}
+	if err := f.service.PinComment(ctx, f.creatorID, reply.ID, true); err == nil {
+		t.Error("expected pinning a reply to fail")
+	}
+	if err := f.service.PinComment(ctx, f.creatorID, parent.ID, true); err != nil {
+		t.Fatalf("expected creator pin to succeed, got %v", err)
+	}
+
+	later := f.comment(t, f.viewerID, nil, "newer comment")
+	comments, _ := f.service.GetCommentsByVideoID(ctx, f.videoID, nil, model.PageQuery{})
+	if len(comments.Items) != 2 || comments.Items[0].ID != parent.ID || comments.Items[1].ID != later.ID {
+		t.Errorf("expected pinned comment first, got %+v", comments.Items)
+	}
+}
+
+func TestCommentUpdateDelete_OnlyAuthor(t *testing.T) {
+	f := newCommentFixture(t)
+	ctx := context.Background()
+	comment := f.comment(t, f.viewerID, nil, "original")
+
+	if _, err := f.service.Update(ctx, asUser(f.creatorID), comment.ID, &model.UpdateCommentRequest{Content: "hijacked"}); err == nil {
+		t.Error("expected update by another user to fail")
+	}
+	if err := f.service.Delete(ctx, asUser(f.creatorID), comment.ID); err == nil {
+		t.Error("expected delete by another user to fail")
+	}
+
+	updated, err := f.service.Update(ctx, asUser(f.viewerID), comment.ID, &model.UpdateCommentRequest{Content: "edited"})
+	if err != nil || updated.Content != "edited" {
+		t.Fatalf("expected author update to succeed, got %+v, %v", updated, err)
+	}
+	if err := f.service.Delete(ctx, asUser(f.viewerID), comment.ID); err != nil {
+		t.Fatalf("expected author delete to succeed, got %v", err)
+	}
+	if err := f.service.Delete(ctx, asUser(f.viewerID), comment.ID); !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("expected ErrCommentNotFound for a deleted comment, got %v", err)
+	}
+}
+
+func TestCommentDelete_ModeratorCanDeleteAnyComment(t *testing.T) {
+	f := newCommentFixture(t)
+	ctx := context.Background()
+	comment := f.comment(t, f.viewerID, nil, "spam")
+	moderator := policy.Actor{UserID: f.creatorID + 100, Role: model.RoleModerator}
+
+	if _, err := f.service.Update(ctx, moderator, comment.ID, &model.UpdateCommentRequest{Content: "edited"}); !errors.Is(err, ErrCommentForbidden) {
+		t.Errorf("expected moderators not to edit others' comments, got %v", err)
+	}
+	if err := f.service.Delete(ctx, moderator, comment.ID); err != nil {
+		t.Fatalf("expected moderator delete to succeed, got %v", err)
+	}
+	comments, _ := f.service.GetCommentsByVideoID(ctx, f.videoID, nil, model.PageQuery{})
+	if len(comments.Items) != 0 {
+		t.Errorf("expected the comment to be gone, got %+v", comments.Items)
+	}
+}
+
+func TestCommentLike_CountsAndValidation(t *testing.T) {
+	f := newCommentFixture(t)
+	ctx := context.Background()
+	comment := f.comment(t, f.creatorID, nil, "like me")
+
+	if err := f.service.LikeComment(ctx, f.viewerID, comment.ID, &model.LikeCommentRequest{LikeType: "love"}); err == nil {
+		t.Error("expected invalid like type to be rejected")
+	}
+
+	likeCount := func() int64 {
+		comments, _ := f.service.GetCommentsByVideoID(ctx, f.videoID, &f.viewerID, model.PageQuery{})
+		return comments.Items[0].LikeCount
+	}
+
+	_ = f.service.LikeComment(ctx, f.viewerID, comment.ID, &model.LikeCommentRequest{LikeType: "like"})
+	if got := likeCount(); got != 1 {
+		t.Errorf("expected like count 1 after like, got %d", got)
+	}
+
+	_ = f.service.LikeComment(ctx, f.viewerID, comment.ID, &model.LikeCommentRequest{LikeType: "dislike"})
+	if got := likeCount(); got != 0 {
+		t.Errorf("expected like count 0 after switching to dislike, got %d", got)
+	}
+
+	_ = f.service.LikeComment(ctx, f.viewerID, comment.ID, &model.LikeCommentRequest{LikeType: "like"})
+	_ = f.service.UnlikeComment(ctx, f.viewerID, comment.ID)
+	if got := likeCount(); got != 0 {
+		t.Errorf("expected like count 0 after unlike, got %d", got)
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+const (
+	// LoginFailureWindow is how far back failed logins are counted
+	LoginFailureWindow = time.Hour
+	// LoginBackoffBase is the wait after the first failure beyond the free ones; each further
+	// failure doubles it
+	LoginBackoffBase = time.Second
+
+	// AccountFreeLoginFailures is how many failures an email address has before logins are delayed
+	AccountFreeLoginFailures = 3
+	// AccountLockoutFailures is how many failures lock an email address out for AccountLockoutDuration
+	AccountLockoutFailures = 10
+	AccountLockoutDuration = 15 * time.Minute
+
+	// IPFreeLoginFailures and IPLockoutFailures are higher than the per-account limits,
+	// since many users can share an IP address behind a NAT
+	IPFreeLoginFailures = 20
+	IPLockoutFailures   = 100
+	IPLockoutDuration   = time.Hour
+)
+
+var ErrTooManyLoginAttempts = errors.New("too many login attempts; try again later")
+
+// LoginThrottledError is returned while logins from an email or IP address have to wait
+// It matches ErrTooManyLoginAttempts with errors.Is
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return ErrTooManyLoginAttempts.Error()
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
+
+// throttlePolicy is how failures of one email or IP address delay its next login
+type throttlePolicy struct {
+	free         int
+	lockoutAfter int
+	lockout      time.Duration
+}
+
+var (
+	accountThrottle = throttlePolicy{free: AccountFreeLoginFailures, lockoutAfter: AccountLockoutFailures, lockout: AccountLockoutDuration}
+	ipThrottle      = throttlePolicy{free: IPFreeLoginFailures, lockoutAfter: IPLockoutFailures, lockout: IPLockoutDuration}
+)
+
+// retryAt returns when the next login may be attempted after the failures
+// Waits grow exponentially after the free failures and become a lockout at lockoutAfter
+func (p throttlePolicy) retryAt(failures *model.LoginFailures) time.Time {
+	if failures.Count < p.free {
+		return time.Time{}
+	}
+	if failures.Count >= p.lockoutAfter {
+		return failures.Last.Add(p.lockout)
+	}
+	wait := p.lockout
+	if shift := failures.Count - p.free; shift < 32 {
+		wait = min(LoginBackoffBase<<shift, p.lockout)
+	}
+	return failures.Last.Add(wait)
+}
+
+// LoginThrottle records login attempts and slows down repeated failures, per email
+// address and per IP address
+// Failures are counted by the address as entered, whether or not an account uses it,
+// so throttled logins look the same for unknown and existing accounts
+type LoginThrottle struct {
+	attemptRepo repository.LoginAttemptStore
+	now         func() time.Time
+}
+
+func NewLoginThrottle(attemptRepo repository.LoginAttemptStore) *LoginThrottle {
+	return &LoginThrottle{attemptRepo: attemptRepo, now: time.Now}
+}
+
+// Check returns a *LoginThrottledError if a login for the email from the client has to wait
+// Rejected attempts are recorded but do not extend the wait
+func (t *LoginThrottle) Check(ctx context.Context, email string, client model.ClientInfo) error {
+	now := t.now()
+	since := now.Add(-LoginFailureWindow)
+	email = normalizeEmail(email)
+
+	accountFailures, err := t.attemptRepo.CountFailuresByEmail(ctx, email, since)
+	if err != nil {
+		return err
+	}
+	retryAt := accountThrottle.retryAt(accountFailures)
+
+	if client.IPAddress != "" {
+		ipFailures, err := t.attemptRepo.CountFailuresByIP(ctx, client.IPAddress, since)
+		if err != nil {
+			return err
+		}
+		if ipRetryAt := ipThrottle.retryAt(ipFailures); ipRetryAt.After(retryAt) {
+			retryAt = ipRetryAt
+		}
+	}
+
+	if !retryAt.After(now) {
+		return nil
+	}
+	if err := t.record(ctx, email, 0, client, false, model.LoginFailureThrottled); err != nil {
+		return err
+	}
+	return &LoginThrottledError{RetryAfter: retryAt.Sub(now)}
+}
+
+// RecordFailure records a failed login; userID is 0 when no account uses the email
+func (t *LoginThrottle) RecordFailure(ctx context.Context, email string, userID int64, client model.ClientInfo, reason string) error {
+	return t.record(ctx, normalizeEmail(email), userID, client, false, reason)
+}
+
+// RecordSuccess records a login that started a session, which resets the email's failures
+func (t *LoginThrottle) RecordSuccess(ctx context.Context, email string, userID int64, client model.ClientInfo) error {
+	return t.record(ctx, normalizeEmail(email), userID, client, true, "")
+}
+
+func (t *LoginThrottle) record(ctx context.Context, email string, userID int64, client model.ClientInfo, succeeded bool, reason string) error {
+	attempt := &model.LoginAttempt{
+		Email:         email,
+		IPAddress:     client.IPAddress,
+		UserAgent:     client.UserAgent,
+		Succeeded:     succeeded,
+		FailureReason: reason,
+		CreatedAt:     t.now(),
+	}
+	if userID != 0 {
+		attempt.UserID = &userID
+	}
+	return t.attemptRepo.Create(ctx, attempt)
+}
+
+// normalizeEmail makes differently written forms of an address count as one
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository/memory"
+)
+
+type throttleFixture struct {
+	auth *AuthService
+	now  time.Time
+}
+
+func newThrottleFixture(t *testing.T) *throttleFixture {
+	t.Helper()
+	f := &throttleFixture{
+		auth: newAuthService(memory.NewDB()),
+		now:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	f.auth.throttle.now = func() time.Time { return f.now }
+	register(t, f.auth, "alice@example.com")
+	return f
+}
+
+func (f *throttleFixture) login(email, password, ip string) error {
+	client := model.ClientInfo{UserAgent: "test-agent", IPAddress: ip}
+	_, err := f.auth.Login(context.Background(), &model.LoginRequest{Email: email, Password: password}, client)
+	return err
+}
+
+// retryAfter returns how long a throttled login has to wait, failing the test if it was not throttled
+func retryAfter(t *testing.T, err error) time.Duration {
+	t.Helper()
+	var throttled *LoginThrottledError
+	if !errors.As(err, &throttled) || !errors.Is(err, ErrTooManyLoginAttempts) {
+		t.Fatalf("expected the login to be throttled, got %v", err)
+	}
+	return throttled.RetryAfter
+}
+
+func TestLoginThrottle_BacksOffExponentially(t *testing.T) {
+	f := newThrottleFixture(t)
+
+	for range AccountFreeLoginFailures {
+		if err := f.login("alice@example.com", "wrong-password", "192.0.2.1"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+		}
+	}
+
+	for _, wait := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		if got := retryAfter(t, f.login("alice@example.com", "password123", "192.0.2.1")); got != wait {
+			t.Fatalf("expected to wait %v, got %v", wait, got)
+		}
+		// Attempts while throttled do not extend the wait
+		f.now = f.now.Add(wait / 2)
+		if got := retryAfter(t, f.login("alice@example.com", "wrong-password", "192.0.2.1")); got != wait/2 {
+			t.Fatalf("expected to wait another %v, got %v", wait/2, got)
+		}
+
+		f.now = f.now.Add(wait / 2)
+		if err := f.login("alice@example.com", "wrong-password", "192.0.2.1"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("expected ErrInvalidCredentials after waiting, got %v", err)
+		}
+	}
+}
+
+func TestLoginThrottle_LocksAccountOutAndSuccessResets(t *testing.T) {
+	f := newThrottleFixture(t)
+
+	for range AccountLockoutFailures {
+		f.now = f.now.Add(2 * time.Minute)
+		if err := f.login("alice@example.com", "wrong-password", "192.0.2.1"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+		}
+	}
+
+	// The right password does not help while locked out
+	if got := retryAfter(t, f.login("alice@example.com", "password123", "192.0.2.1")); got != AccountLockoutDuration {
+		t.Fatalf("expected a %v lockout, got %v", AccountLockoutDuration, got)
+	}
+
+	f.now = f.now.Add(AccountLockoutDuration)
+	if err := f.login("alice@example.com", "password123", "192.0.2.1"); err != nil {
+		t.Fatalf("expected the login to work after the lockout, got %v", err)
+	}
+	// The successful login cleared the account's failures
+	for range AccountFreeLoginFailures {
+		if err := f.login("alice@example.com", "wrong-password", "192.0.2.1"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+		}
+	}
+}
+
+func TestLoginThrottle_UnknownAndExistingAccountsLookTheSame(t *testing.T) {
+	f := newThrottleFixture(t)
+
+	type result struct {
+		err  string
+		wait time.Duration
+	}
+	attempt := func(email string) []result {
+		var results []result
+		for range AccountLockoutFailures + 1 {
+			err := f.login(email, "wrong-password", "")
+			var throttled *LoginThrottledError
+			r := result{err: err.Error()}
+			if errors.As(err, &throttled) {
+				r.wait = throttled.RetryAfter
+			}
+			results = append(results, r)
+		}
+		return results
+	}
+
+	start := f.now
+	existing := attempt("alice@example.com")
+	f.now = start
+	unknown := attempt("nobody@example.com")
+
+	for i := range existing {
+		if existing[i] != unknown[i] {
+			t.Errorf("attempt %d: existing account got %+v, unknown got %+v", i+1, existing[i], unknown[i])
+		}
+	}
+}
+
+func TestLoginThrottle_CountsFailuresPerIPAcrossAccounts(t *testing.T) {
+	f := newThrottleFixture(t)
+
+	// Spread over addresses, so no single account is throttled
+	for i := range IPFreeLoginFailures {
+		email := []string{"a@example.com", "b@example.com", "c@example.com"}[i%3]
+		f.now = f.now.Add(time.Minute)
+		if err := f.login(email, "wrong-password", "198.51.100.7"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+		}
+	}
+
+	retryAfter(t, f.login("alice@example.com", "password123", "198.51.100.7"))
+	if err := f.login("alice@example.com", "password123", "192.0.2.1"); err != nil {
+		t.Errorf("expected logins from other addresses to work, got %v", err)
+	}
+}
+
+func TestLoginThrottle_IgnoresEmailCase(t *testing.T) {
+	f := newThrottleFixture(t)
+
+	for _, email := range []string{"Alice@example.com", "ALICE@EXAMPLE.COM", " alice@example.com"} {
+		f.login(email, "wrong-password", "192.0.2.1")
+	}
+	retryAfter(t, f.login("alice@example.com", "password123", "192.0.2.1"))
+}
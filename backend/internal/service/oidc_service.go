@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/oidc"
+	"github.com/yukito/video-platform/internal/repository"
+	"golang.org/x/oauth2"
+)
+
+// OIDCLoginTTL is how long a user has to finish signing in at a provider
+const OIDCLoginTTL = 10 * time.Minute
+
+var (
+	ErrUnknownProvider      = errors.New("unknown sign-in provider")
+	ErrInvalidOIDCState     = errors.New("sign-in expired or was already completed; please try again")
+	ErrOIDCLoginFailed      = errors.New("sign-in with the provider failed")
+	ErrOIDCEmailRequired    = errors.New("the provider did not share an email address")
+	ErrOIDCEmailNotVerified = errors.New("an account with this email already exists; sign in with your password and link the provider from there")
+	ErrIdentityLinked       = errors.New("this provider account is already linked to another user")
+)
+
+// OIDCService signs users in with OpenID Connect providers
+// A provider account is linked to a user on first sign-in: to the account with the same
+// email if both the provider and the account verified it, or to a new account otherwise
+// Signed-in users link more accounts with AuthorizeLink and Link
+type OIDCService struct {
+	providers    map[string]*oidc.Provider
+	stateRepo    repository.OIDCStateStore
+	identityRepo repository.UserIdentityStore
+	userRepo     repository.UserStore
+	auth         *AuthService
+	now          func() time.Time
+}
+
+func NewOIDCService(providers []*oidc.Provider, stateRepo repository.OIDCStateStore, identityRepo repository.UserIdentityStore, userRepo repository.UserStore, auth *AuthService) *OIDCService {
+	byName := make(map[string]*oidc.Provider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+	return &OIDCService{
+		providers:    byName,
+		stateRepo:    stateRepo,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		auth:         auth,
+		now:          time.Now,
+	}
+}
+
+// Providers returns the names of the configured providers
+func (s *OIDCService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Authorize starts signing in at a provider
+func (s *OIDCService) Authorize(ctx context.Context, provider string) (*model.OIDCAuthorization, error) {
+	return s.authorize(ctx, provider, nil)
+}
+
+// AuthorizeLink starts linking an account at a provider to the signed-in user
+func (s *OIDCService) AuthorizeLink(ctx context.Context, provider string, userID int64) (*model.OIDCAuthorization, error) {
+	return s.authorize(ctx, provider, &userID)
+}
+
+func (s *OIDCService) authorize(ctx context.Context, provider string, linkUserID *int64) (*model.OIDCAuthorization, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	authURL, err := p.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	err = s.stateRepo.Create(ctx, &model.OIDCLoginState{
+		StateHash:    hashToken(state),
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		LinkUserID:   linkUserID,
+		ExpiresAt:    now.Add(OIDCLoginTTL),
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.OIDCAuthorization{AuthorizationURL: authURL, State: state}, nil
+}
+
+// Callback finishes signing in with the code the provider redirected back with and starts a session,
+// or returns an MFA challenge if the user has two-factor authentication
+// States started by AuthorizeLink are rejected: they only finish through Link
+func (s *OIDCService) Callback(ctx context.Context, req *model.OIDCCallbackRequest, client model.ClientInfo) (*model.AuthResponse, error) {
+	provider, claims, err := s.exchange(ctx, req, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.resolveUser(ctx, provider, claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.auth.signIn(ctx, user, client)
+}
+
+// Link finishes linking with the code the provider redirected back with
+// Only the user who started linking with AuthorizeLink can finish it
+func (s *OIDCService) Link(ctx context.Context, req *model.OIDCCallbackRequest, userID int64) (*model.UserIdentity, error) {
+	provider, claims, err := s.exchange(ctx, req, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := s.identityRepo.FindByProviderSubject(ctx, provider, claims.Subject)
+	if err == nil {
+		if identity.UserID != userID {
+			return nil, ErrIdentityLinked
+		}
+		if err := s.identityRepo.RecordLogin(ctx, identity.ID, claims.Email); err != nil {
+			return nil, err
+		}
+		return identity, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	identity, err = s.identityRepo.Create(ctx, &model.UserIdentity{
+		UserID:   userID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The provider vouches for the address of the signed-in user, so the account's email is verified too
+	if claims.EmailVerified && claims.Email == user.Email && user.EmailVerifiedAt == nil {
+		if err := s.userRepo.SetEmailVerified(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	return identity, nil
+}
+
+// Identities returns the provider accounts linked to the user
+func (s *OIDCService) Identities(ctx context.Context, userID int64) ([]*model.UserIdentity, error) {
+	return s.identityRepo.FindByUserID(ctx, userID)
+}
+
+// exchange consumes the state and trades the code for the provider account's claims
+// linkUserID is the user finishing a link, or 0 when signing in; it must match who started the flow
+func (s *OIDCService) exchange(ctx context.Context, req *model.OIDCCallbackRequest, linkUserID int64) (string, *oidc.Claims, error) {
+	state, err := s.stateRepo.Consume(ctx, hashToken(req.State), s.now())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil, ErrInvalidOIDCState
+		}
+		return "", nil, err
+	}
+	var startedBy int64
+	if state.LinkUserID != nil {
+		startedBy = *state.LinkUserID
+	}
+	if startedBy != linkUserID {
+		return "", nil, ErrInvalidOIDCState
+	}
+	p, ok := s.providers[state.Provider]
+	if !ok {
+		return "", nil, ErrUnknownProvider
+	}
+
+	claims, err := p.Exchange(ctx, req.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		log.Printf("OIDC sign-in with %s failed: %v", state.Provider, err)
+		return "", nil, ErrOIDCLoginFailed
+	}
+	return state.Provider, claims, nil
+}
+
+// resolveUser returns the user a provider account signs in as, linking it on first sign-in
+func (s *OIDCService) resolveUser(ctx context.Context, provider string, claims *oidc.Claims) (*model.User, error) {
+	identity, err := s.identityRepo.FindByProviderSubject(ctx, provider, claims.Subject)
+	if err == nil {
+		if err := s.identityRepo.RecordLogin(ctx, identity.ID, claims.Email); err != nil {
+			return nil, err
+		}
+		return s.findUser(ctx, identity.UserID)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if claims.Email == "" {
+		return nil, ErrOIDCEmailRequired
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, claims.Email)
+	switch {
+	case err == nil && (!claims.EmailVerified || user.EmailVerifiedAt == nil):
+		// Anyone can claim an unverified address at some providers, so it does not prove the
+		// existing account belongs to them. An account whose email was never verified may have
+		// been registered by someone else, who would keep its password and sessions
+		return nil, ErrOIDCEmailNotVerified
+	case errors.Is(err, pgx.ErrNoRows):
+		user, err = s.auth.createUser(ctx, claims.Email, "", claims.EmailVerified)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.identityRepo.Create(ctx, &model.UserIdentity{
+		UserID:   user.ID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *OIDCService) findUser(ctx context.Context, id int64) (*model.User, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	return user, nil
+}
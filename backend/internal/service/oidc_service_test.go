@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yukito/video-platform/internal/mail"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/oidc"
+	"github.com/yukito/video-platform/internal/oidc/oidctest"
+	"github.com/yukito/video-platform/internal/repository/memory"
+)
+
+type oidcFixture struct {
+	db     *memory.DB
+	idp    *oidctest.Server
+	mailer *mail.MemoryMailer
+	auth   *AuthService
+	oidc   *OIDCService
+}
+
+func newOIDCFixture(t *testing.T) *oidcFixture {
+	t.Helper()
+	idp := oidctest.NewServer("client", "secret")
+	t.Cleanup(idp.Close)
+
+	provider, err := oidc.NewProvider(oidc.Config{
+		Name:         "idp",
+		Issuer:       idp.Issuer(),
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "https://app.example.com/oauth/callback",
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewProvider returned error: %v", err)
+	}
+
+	db := memory.NewDB()
+	mailer := mail.NewMemoryMailer()
+	auth := newAuthServiceWithMailer(db, mailer)
+	return &oidcFixture{
+		db:     db,
+		idp:    idp,
+		mailer: mailer,
+		auth:   auth,
+		oidc: NewOIDCService([]*oidc.Provider{provider}, memory.NewOIDCStateRepository(db),
+			memory.NewUserIdentityRepository(db), memory.NewUserRepository(db), auth),
+	}
+}
+
+// signIn signs in at the provider as the user and returns the code and state to finish with
+func (f *oidcFixture) signIn(t *testing.T, user oidctest.User) *model.OIDCCallbackRequest {
+	t.Helper()
+	authorization, err := f.oidc.Authorize(context.Background(), "idp")
+	if err != nil {
+		t.Fatalf("Authorize returned error: %v", err)
+	}
+	return f.redirect(t, user, authorization)
+}
+
+// startLink starts linking for userID, signs in at the provider as the user and returns
+// the code and state to finish with
+func (f *oidcFixture) startLink(t *testing.T, userID int64, user oidctest.User) *model.OIDCCallbackRequest {
+	t.Helper()
+	authorization, err := f.oidc.AuthorizeLink(context.Background(), "idp", userID)
+	if err != nil {
+		t.Fatalf("AuthorizeLink returned error: %v", err)
+	}
+	return f.redirect(t, user, authorization)
+}
+
+// redirect signs in at the provider as the user and returns what it redirects back with
+func (f *oidcFixture) redirect(t *testing.T, user oidctest.User, authorization *model.OIDCAuthorization) *model.OIDCCallbackRequest {
+	t.Helper()
+	f.idp.SetUser(user)
+	code, state, err := f.idp.Authorize(authorization.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("sign-in at the provider failed: %v", err)
+	}
+	if state != authorization.State {
+		t.Fatalf("expected state %q back, got %q", authorization.State, state)
+	}
+	return &model.OIDCCallbackRequest{Code: code, State: state}
+}
+
+// verifiedUser registers a user and verifies their email
+func (f *oidcFixture) verifiedUser(t *testing.T, email string) *model.AuthResponse {
+	t.Helper()
+	resp := register(t, f.auth, email)
+	token := mailedToken(t, f.mailer, email, "/verify-email")
+	if err := f.auth.VerifyEmail(context.Background(), &model.VerifyEmailRequest{Token: token}); err != nil {
+		t.Fatalf("VerifyEmail returned error: %v", err)
+	}
+	return resp
+}
+
+func TestOIDCCallback_CreatesUser(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+
+	req := f.signIn(t, oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true})
+	resp, err := f.oidc.Callback(ctx, req, testClient)
+	if err != nil {
+		t.Fatalf("Callback returned error: %v", err)
+	}
+	if resp.User.Email != "alice@example.com" || resp.User.EmailVerifiedAt == nil {
+		t.Errorf("expected a verified user for alice, got %+v", resp.User)
+	}
+	if _, err := f.auth.ValidateToken(ctx, resp.Token); err != nil {
+		t.Errorf("expected a valid session, got %v", err)
+	}
+	if _, err := f.auth.Login(ctx, &model.LoginRequest{Email: "alice@example.com", Password: ""}, testClient); err == nil {
+		t.Error("expected accounts created through a provider to have no password")
+	}
+
+	// Signing in again finds the same user
+	again, err := f.oidc.Callback(ctx, f.signIn(t, oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true}), testClient)
+	if err != nil {
+		t.Fatalf("Callback returned error: %v", err)
+	}
+	if again.User.ID != resp.User.ID {
+		t.Errorf("expected user %d, got %d", resp.User.ID, again.User.ID)
+	}
+	identities, _ := f.oidc.Identities(ctx, resp.User.ID)
+	if len(identities) != 1 || identities[0].Provider != "idp" {
+		t.Errorf("expected one idp identity, got %+v", identities)
+	}
+}
+
+func TestOIDCCallback_LinksExistingAccountWithVerifiedEmail(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+	existing := f.verifiedUser(t, "alice@example.com")
+
+	resp, err := f.oidc.Callback(ctx, f.signIn(t, oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true}), testClient)
+	if err != nil {
+		t.Fatalf("Callback returned error: %v", err)
+	}
+	if resp.User.ID != existing.User.ID {
+		t.Errorf("expected to sign in as user %d, got %d", existing.User.ID, resp.User.ID)
+	}
+	// The password keeps working
+	if _, err := f.auth.Login(ctx, &model.LoginRequest{Email: "alice@example.com", Password: "password123"}, testClient); err != nil {
+		t.Errorf("expected the password to keep working, got %v", err)
+	}
+}
+
+func TestOIDCCallback_RejectsUnverifiedEmailOfExistingAccount(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+	existing := register(t, f.auth, "alice@example.com")
+
+	_, err := f.oidc.Callback(ctx, f.signIn(t, oidctest.User{Subject: "sub-1", Email: "alice@example.com"}), testClient)
+	if !errors.Is(err, ErrOIDCEmailNotVerified) {
+		t.Fatalf("expected ErrOIDCEmailNotVerified, got %v", err)
+	}
+
+	// The signed-in owner can link the account explicitly
+	identity, err := f.oidc.Link(ctx, f.startLink(t, existing.User.ID, oidctest.User{Subject: "sub-1", Email: "alice@example.com"}), existing.User.ID)
+	if err != nil {
+		t.Fatalf("Link returned error: %v", err)
+	}
+	if identity.UserID != existing.User.ID {
+		t.Errorf("expected the identity to be linked to user %d, got %d", existing.User.ID, identity.UserID)
+	}
+	user, _ := memory.NewUserRepository(f.db).FindByID(ctx, existing.User.ID)
+	if user.EmailVerifiedAt != nil {
+		t.Error("expected an unverified provider email not to verify the account")
+	}
+}
+
+func TestOIDCCallback_DoesNotTakeOverUnverifiedAccount(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+
+	// Someone registers the victim's address first and never verifies it
+	squatter := register(t, f.auth, "alice@example.com")
+
+	_, err := f.oidc.Callback(ctx, f.signIn(t, oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true}), testClient)
+	if !errors.Is(err, ErrOIDCEmailNotVerified) {
+		t.Fatalf("expected ErrOIDCEmailNotVerified, got %v", err)
+	}
+	identities, _ := f.oidc.Identities(ctx, squatter.User.ID)
+	if len(identities) != 0 {
+		t.Errorf("expected no identity to be linked to the unverified account, got %+v", identities)
+	}
+}
+
+func TestOIDCLink_VerifiesEmailOfSignedInUser(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+	alice := register(t, f.auth, "alice@example.com")
+
+	if _, err := f.oidc.Link(ctx, f.startLink(t, alice.User.ID, oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true}), alice.User.ID); err != nil {
+		t.Fatalf("Link returned error: %v", err)
+	}
+	user, _ := memory.NewUserRepository(f.db).FindByID(ctx, alice.User.ID)
+	if user.EmailVerifiedAt == nil {
+		t.Error("expected the provider's verification to verify the account's email")
+	}
+
+	// The linked account signs in as alice
+	resp, err := f.oidc.Callback(ctx, f.signIn(t, oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true}), testClient)
+	if err != nil || resp.User.ID != alice.User.ID {
+		t.Errorf("expected to sign in as user %d, got %+v, %v", alice.User.ID, resp, err)
+	}
+}
+
+func TestOIDCLink_OnlyUserWhoStartedItCanFinish(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+	attacker := register(t, f.auth, "mallory@example.com")
+	victim := register(t, f.auth, "alice@example.com")
+	provider := oidctest.User{Subject: "sub-mallory", Email: "mallory@example.com", EmailVerified: true}
+
+	// A link started by the attacker cannot be finished by a victim lured to the callback
+	if _, err := f.oidc.Link(ctx, f.startLink(t, attacker.User.ID, provider), victim.User.ID); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Errorf("expected ErrInvalidOIDCState for another user's link, got %v", err)
+	}
+	// Nor can a sign-in be finished as a link, or a link as a sign-in
+	if _, err := f.oidc.Link(ctx, f.signIn(t, provider), victim.User.ID); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Errorf("expected ErrInvalidOIDCState for a sign-in finished as a link, got %v", err)
+	}
+	if _, err := f.oidc.Callback(ctx, f.startLink(t, attacker.User.ID, provider), testClient); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Errorf("expected ErrInvalidOIDCState for a link finished as a sign-in, got %v", err)
+	}
+
+	identities, _ := f.oidc.Identities(ctx, victim.User.ID)
+	if len(identities) != 0 {
+		t.Errorf("expected nothing linked to the victim, got %+v", identities)
+	}
+}
+
+func TestOIDCLink_RejectsIdentityOfAnotherUser(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+	if _, err := f.oidc.Callback(ctx, f.signIn(t, oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true}), testClient); err != nil {
+		t.Fatalf("Callback returned error: %v", err)
+	}
+	bob := register(t, f.auth, "bob@example.com")
+
+	_, err := f.oidc.Link(ctx, f.startLink(t, bob.User.ID, oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true}), bob.User.ID)
+	if !errors.Is(err, ErrIdentityLinked) {
+		t.Errorf("expected ErrIdentityLinked, got %v", err)
+	}
+}
+
+func TestOIDCCallback_NewUserWithUnverifiedEmailGetsVerificationMail(t *testing.T) {
+	f := newOIDCFixture(t)
+
+	resp, err := f.oidc.Callback(context.Background(), f.signIn(t, oidctest.User{Subject: "sub-1", Email: "alice@example.com"}), testClient)
+	if err != nil {
+		t.Fatalf("Callback returned error: %v", err)
+	}
+	if resp.User.EmailVerifiedAt != nil {
+		t.Error("expected the email to be unverified")
+	}
+	mailedToken(t, f.mailer, "alice@example.com", "/verify-email")
+
+	_, err = f.oidc.Callback(context.Background(), f.signIn(t, oidctest.User{Subject: "sub-2"}), testClient)
+	if !errors.Is(err, ErrOIDCEmailRequired) {
+		t.Errorf("expected ErrOIDCEmailRequired without an email, got %v", err)
+	}
+}
+
+func TestOIDCCallback_StateWorksOnce(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+
+	req := f.signIn(t, oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true})
+	if _, err := f.oidc.Callback(ctx, req, testClient); err != nil {
+		t.Fatalf("Callback returned error: %v", err)
+	}
+	if _, err := f.oidc.Callback(ctx, req, testClient); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Errorf("expected ErrInvalidOIDCState for a used state, got %v", err)
+	}
+	if _, err := f.oidc.Callback(ctx, &model.OIDCCallbackRequest{Code: "code", State: "made-up"}, testClient); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Errorf("expected ErrInvalidOIDCState for an unknown state, got %v", err)
+	}
+}
+
+func TestOIDCCallback_StateExpiresByServiceClock(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+
+	// The service clock is hours behind the store's, as when they run in different time zones
+	started := time.Now().Add(-9 * time.Hour)
+	f.oidc.now = func() time.Time { return started }
+	req := f.signIn(t, oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true})
+	expired := f.signIn(t, oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true})
+
+	if _, err := f.oidc.Callback(ctx, req, testClient); err != nil {
+		t.Errorf("expected the state to work within its TTL, got %v", err)
+	}
+	f.oidc.now = func() time.Time { return started.Add(OIDCLoginTTL + time.Minute) }
+	if _, err := f.oidc.Callback(ctx, expired, testClient); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Errorf("expected ErrInvalidOIDCState after the TTL, got %v", err)
+	}
+}
+
+func TestOIDCCallback_RejectsCodeFromAnotherSignIn(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+
+	// A code obtained for one sign-in cannot finish another: its PKCE verifier and nonce differ
+	first := f.signIn(t, oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true})
+	second := f.signIn(t, oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true})
+	_, err := f.oidc.Callback(ctx, &model.OIDCCallbackRequest{Code: first.Code, State: second.State}, testClient)
+	if !errors.Is(err, ErrOIDCLoginFailed) {
+		t.Errorf("expected ErrOIDCLoginFailed, got %v", err)
+	}
+}
+
+func TestOIDCAuthorize_RejectsUnknownProvider(t *testing.T) {
+	f := newOIDCFixture(t)
+	if _, err := f.oidc.Authorize(context.Background(), "unknown"); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("expected ErrUnknownProvider, got %v", err)
+	}
+	if got := f.oidc.Providers(); len(got) != 1 || got[0] != "idp" {
+		t.Errorf("expected providers [idp], got %v", got)
+	}
+}
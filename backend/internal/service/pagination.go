@@ -0,0 +1,79 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/yukito/video-platform/internal/model"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const (
+	DefaultPageLimit      = 20
+	DefaultVideoPageLimit = 15
+	MaxPageLimit          = 100
+)
+
+// pageRequest validates a page query and asks the repository for one row more than
+// the page holds, so newPage can tell whether there is a next page
+func pageRequest(query model.PageQuery, defaultLimit int) (limit int, page model.PageRequest, err error) {
+	limit = query.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+
+	page = model.PageRequest{Limit: limit + 1}
+	if query.Cursor != "" {
+		page.After, err = decodePageCursor(query.Cursor)
+		if err != nil {
+			return 0, model.PageRequest{}, err
+		}
+	} else if query.Offset > 0 {
+		page.Offset = query.Offset
+	}
+
+	return limit, page, nil
+}
+
+// newPage trims the extra row fetched by pageRequest and sets the cursor of the next page
+// from the sort key of the last item
+func newPage[T any](items []T, limit int, key func(T) model.PageCursor) *model.Page[T] {
+	page := &model.Page[T]{Items: items}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.HasMore = true
+		cursor := key(page.Items[limit-1])
+		page.NextCursor = encodePageCursor(&cursor)
+	}
+	return page
+}
+
+// videoWithProfileKey is the sort key of video lists ordered by created_at
+func videoWithProfileKey(video *model.VideoWithProfile) model.PageCursor {
+	return model.PageCursor{Time: video.CreatedAt, ID: video.ID}
+}
+
+func encodePageCursor(cursor *model.PageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageCursor(value string) (*model.PageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor model.PageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID <= 0 || cursor.Time.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
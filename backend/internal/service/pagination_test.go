@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository/memory"
+	"github.com/yukito/video-platform/internal/storage"
+)
+
+func TestList_CursorWalkIsStableWhileVideosArePosted(t *testing.T) {
+	db := memory.NewDB()
+	seedListing(t, db, 5, 1)
+	s := newVideoService(db, storage.NewMemoryStorage())
+	ctx := context.Background()
+
+	var ids []int64
+	query := model.PageQuery{Limit: 2}
+	for pages := 0; ; pages++ {
+		page, err := s.List(ctx, 0, query)
+		if err != nil {
+			t.Fatalf("List returned error: %v", err)
+		}
+		for _, video := range page.Items {
+			ids = append(ids, video.ID)
+		}
+		if !page.HasMore {
+			if page.NextCursor != "" {
+				t.Errorf("expected no cursor on the last page, got %q", page.NextCursor)
+			}
+			break
+		}
+		if pages > 5 {
+			t.Fatal("cursor walk did not end")
+		}
+
+		// A video posted between pages must neither shift nor repeat the following pages
+		_, _ = memory.NewVideoRepository(db).Create(ctx, &model.Video{
+			UserID:     1,
+			Title:      fmt.Sprintf("posted after page %d", pages),
+			Status:     model.VideoStatusReady,
+			Visibility: model.VideoVisibilityPublic,
+		})
+		query.Cursor = page.NextCursor
+	}
+
+	if fmt.Sprint(ids) != "[5 4 3 2 1]" {
+		t.Errorf("expected the seeded videos newest first exactly once, got %v", ids)
+	}
+}
+
+func TestList_OffsetFallbackAndLimits(t *testing.T) {
+	db := memory.NewDB()
+	seedListing(t, db, 5, 1)
+	s := newVideoService(db, storage.NewMemoryStorage())
+	ctx := context.Background()
+
+	page, err := s.List(ctx, 0, model.PageQuery{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(page.Items) != 2 || page.Items[0].ID != 3 || !page.HasMore {
+		t.Errorf("expected videos 3 and 2 with more to come, got %+v", page)
+	}
+
+	page, _ = s.List(ctx, 0, model.PageQuery{})
+	if len(page.Items) != 5 || page.HasMore {
+		t.Errorf("expected the default limit to fit all five videos, got %d, has_more %v", len(page.Items), page.HasMore)
+	}
+
+	if page, _ := newVideoService(memory.NewDB(), storage.NewMemoryStorage()).List(ctx, 0, model.PageQuery{}); page.Items == nil {
+		t.Error("expected an empty page to hold an empty list")
+	}
+}
+
+func TestList_RejectsInvalidCursors(t *testing.T) {
+	s := newVideoService(memory.NewDB(), storage.NewMemoryStorage())
+	forged := encodePageCursor(&model.PageCursor{ID: 1})
+
+	for _, cursor := range []string{"not a cursor", "e30", forged} {
+		if _, err := s.List(context.Background(), 0, model.PageQuery{Cursor: cursor}); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("expected ErrInvalidCursor for %q, got %v", cursor, err)
+		}
+	}
+}
+
+func TestComments_CursorWalkKeepsPinnedCommentFirst(t *testing.T) {
+	f := newCommentFixture(t)
+	ctx := context.Background()
+
+	pinned := f.comment(t, f.viewerID, nil, "first")
+	var want []int64
+	for i := 0; i < 4; i++ {
+		want = append([]int64{f.comment(t, f.viewerID, nil, fmt.Sprintf("comment %d", i)).ID}, want...)
+	}
+	if err := f.service.PinComment(ctx, f.creatorID, pinned.ID, true); err != nil {
+		t.Fatalf("PinComment returned error: %v", err)
+	}
+	want = append([]int64{pinned.ID}, want...)
+
+	var got []int64
+	query := model.PageQuery{Limit: 2}
+	for {
+		page, err := f.service.GetCommentsByVideoID(ctx, f.videoID, nil, query)
+		if err != nil {
+			t.Fatalf("GetCommentsByVideoID returned error: %v", err)
+		}
+		for _, comment := range page.Items {
+			got = append(got, comment.ID)
+		}
+		if !page.HasMore || len(got) > len(want) {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected the pinned comment and then newest first %v, got %v", want, got)
+	}
+}
+
+func TestReplies_CursorWalkOldestFirst(t *testing.T) {
+	f := newCommentFixture(t)
+	ctx := context.Background()
+
+	parent := f.comment(t, f.viewerID, nil, "question")
+	var want []int64
+	for i := 0; i < 3; i++ {
+		want = append(want, f.comment(t, f.creatorID, &parent.ID, fmt.Sprintf("answer %d", i)).ID)
+	}
+
+	first, err := f.service.GetRepliesByParentID(ctx, parent.ID, nil, model.PageQuery{Limit: 2})
+	if err != nil {
+		t.Fatalf("GetRepliesByParentID returned error: %v", err)
+	}
+	second, err := f.service.GetRepliesByParentID(ctx, parent.ID, nil, model.PageQuery{Limit: 2, Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("GetRepliesByParentID returned error: %v", err)
+	}
+
+	got := []int64{}
+	for _, reply := range append(first.Items, second.Items...) {
+		got = append(got, reply.ID)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) || second.HasMore {
+		t.Errorf("expected replies oldest first %v over two pages, got %v", want, got)
+	}
+}
@@ -6,16 +6,17 @@ import (
 	"fmt"
 
 	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/policy"
 	"github.com/yukito/video-platform/internal/repository"
 )
 
 type PlaylistService struct {
-	playlistRepo *repository.PlaylistRepository
-	videoRepo    *repository.VideoRepository
-	profileRepo  *repository.ProfileRepository
+	playlistRepo repository.PlaylistStore
+	videoRepo    repository.VideoStore
+	profileRepo  repository.ProfileStore
 }
 
-func NewPlaylistService(playlistRepo *repository.PlaylistRepository, videoRepo *repository.VideoRepository, profileRepo *repository.ProfileRepository) *PlaylistService {
+func NewPlaylistService(playlistRepo repository.PlaylistStore, videoRepo repository.VideoStore, profileRepo repository.ProfileStore) *PlaylistService {
 	return &PlaylistService{
 		playlistRepo: playlistRepo,
 		videoRepo:    videoRepo,
@@ -75,7 +76,7 @@ func (s *PlaylistService) Update(ctx context.Context, userID, playlistID int64,
 		return nil, fmt.Errorf("playlist not found: %w", err)
 	}
 
-	if existingPlaylist.UserID != userID {
+	if !policy.CanEditPlaylist(policy.Actor{UserID: userID}, existingPlaylist) {
 		return nil, errors.New("unauthorized to update this playlist")
 	}
 
@@ -109,7 +110,7 @@ func (s *PlaylistService) Delete(ctx context.Context, userID, playlistID int64)
 		return fmt.Errorf("playlist not found: %w", err)
 	}
 
-	if existingPlaylist.UserID != userID {
+	if !policy.CanEditPlaylist(policy.Actor{UserID: userID}, existingPlaylist) {
 		return errors.New("unauthorized to delete this playlist")
 	}
 
@@ -127,15 +128,18 @@ func (s *PlaylistService) AddVideo(ctx context.Context, userID, playlistID, vide
 		return fmt.Errorf("playlist not found: %w", err)
 	}
 
-	if playlist.UserID != userID {
+	if !policy.CanEditPlaylist(policy.Actor{UserID: userID}, playlist) {
 		return errors.New("unauthorized to add video to this playlist")
 	}
 
-	// Check if video exists
-	_, err = s.videoRepo.FindByID(ctx, videoID)
+	// Check if video exists and the user can watch it
+	video, err := s.videoRepo.FindByID(ctx, videoID)
 	if err != nil {
 		return fmt.Errorf("video not found: %w", err)
 	}
+	if !canWatch(video, userID) {
+		return ErrVideoNotFound
+	}
 
 	// Add video to playlist
 	if err := s.playlistRepo.AddVideo(ctx, playlistID, videoID); err != nil {
@@ -152,7 +156,7 @@ func (s *PlaylistService) RemoveVideo(ctx context.Context, userID, playlistID, v
 		return fmt.Errorf("playlist not found: %w", err)
 	}
 
-	if playlist.UserID != userID {
+	if !policy.CanEditPlaylist(policy.Actor{UserID: userID}, playlist) {
 		return errors.New("unauthorized to remove video from this playlist")
 	}
 
@@ -164,26 +168,33 @@ func (s *PlaylistService) RemoveVideo(ctx context.Context, userID, playlistID, v
 	return nil
 }
 
-func (s *PlaylistService) GetPlaylistVideos(ctx context.Context, playlistID int64) ([]*model.PlaylistVideo, error) {
+// GetPlaylistVideos returns the videos in a playlist that viewerID (0 for anonymous viewers) can watch
+func (s *PlaylistService) GetPlaylistVideos(ctx context.Context, viewerID, playlistID int64) ([]*model.PlaylistVideo, error) {
 	// Check if playlist exists
 	_, err := s.playlistRepo.FindByID(ctx, playlistID)
 	if err != nil {
 		return nil, fmt.Errorf("playlist not found: %w", err)
 	}
 
-	playlistVideos, err := s.playlistRepo.GetPlaylistVideos(ctx, playlistID)
+	playlistVideos, err := s.playlistRepo.GetPlaylistVideos(ctx, viewerID, playlistID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get playlist videos: %w", err)
 	}
 
-	// Get profiles for all videos
+	// Load the profiles of all owners at once
+	var userIDs []int64
 	for _, pv := range playlistVideos {
 		if pv.Video != nil {
-			profile, err := s.profileRepo.FindByUserID(ctx, pv.Video.UserID)
-			if err != nil {
-				profile = nil
-			}
-			pv.Video.Profile = profile
+			userIDs = append(userIDs, pv.Video.UserID)
+		}
+	}
+	profiles, err := findProfiles(ctx, s.profileRepo, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, pv := range playlistVideos {
+		if pv.Video != nil {
+			pv.Video.Profile = profiles[pv.Video.UserID]
 		}
 	}
 
@@ -198,75 +209,3 @@ func (s *PlaylistService) GetPlaylistsContainingVideo(ctx context.Context, userI
 
 	return playlistIDs, nil
 }
-
-// LikeVideo adds a video to the user's liked videos playlist
-func (s *PlaylistService) LikeVideo(ctx context.Context, userID, videoID int64) error {
-	// Find the liked videos playlist
-	likedPlaylist, err := s.playlistRepo.FindLikedPlaylistByUserID(ctx, userID)
-	if err != nil {
-		return fmt.Errorf("liked playlist not found: %w", err)
-	}
-
-	// Check if video exists
-	_, err = s.videoRepo.FindByID(ctx, videoID)
-	if err != nil {
-		return fmt.Errorf("video not found: %w", err)
-	}
-
-	// Add video to liked playlist
-	if err := s.playlistRepo.AddVideo(ctx, likedPlaylist.ID, videoID); err != nil {
-		return fmt.Errorf("failed to like video: %w", err)
-	}
-
-	return nil
-}
-
-// UnlikeVideo removes a video from the user's liked videos playlist
-func (s *PlaylistService) UnlikeVideo(ctx context.Context, userID, videoID int64) error {
-	// Find the liked videos playlist
-	likedPlaylist, err := s.playlistRepo.FindLikedPlaylistByUserID(ctx, userID)
-	if err != nil {
-		return fmt.Errorf("liked playlist not found: %w", err)
-	}
-
-	// Remove video from liked playlist
-	if err := s.playlistRepo.RemoveVideo(ctx, likedPlaylist.ID, videoID); err != nil {
-		return fmt.Errorf("failed to unlike video: %w", err)
-	}
-
-	return nil
-}
-
-// IsVideoLiked checks if a video is in the user's liked videos playlist
-func (s *PlaylistService) IsVideoLiked(ctx context.Context, userID, videoID int64) (bool, error) {
-	// Find the liked videos playlist
-	likedPlaylist, err := s.playlistRepo.FindLikedPlaylistByUserID(ctx, userID)
-	if err != nil {
-		return false, fmt.Errorf("liked playlist not found: %w", err)
-	}
-
-	// Check if video is in the playlist
-	isLiked, err := s.playlistRepo.IsVideoInPlaylist(ctx, likedPlaylist.ID, videoID)
-	if err != nil {
-		return false, fmt.Errorf("failed to check like status: %w", err)
-	}
-
-	return isLiked, nil
-}
-
-// GetLikedVideos returns all videos in the user's liked videos playlist
-func (s *PlaylistService) GetLikedVideos(ctx context.Context, userID int64) ([]*model.PlaylistVideo, error) {
-	// Find the liked videos playlist
-	likedPlaylist, err := s.playlistRepo.FindLikedPlaylistByUserID(ctx, userID)
-	if err != nil {
-		return nil, fmt.Errorf("liked playlist not found: %w", err)
-	}
-
-	// Get all videos from the playlist
-	videos, err := s.playlistRepo.GetPlaylistVideos(ctx, likedPlaylist.ID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get liked videos: %w", err)
-	}
-
-	return videos, nil
-}
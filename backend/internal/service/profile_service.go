@@ -11,11 +11,11 @@ import (
 )
 
 type ProfileService struct {
-	profileRepo *repository.ProfileRepository
+	profileRepo repository.ProfileStore
 	storage     storage.Storage
 }
 
-func NewProfileService(profileRepo *repository.ProfileRepository, st storage.Storage) *ProfileService {
+func NewProfileService(profileRepo repository.ProfileStore, st storage.Storage) *ProfileService {
 	return &ProfileService{
 		profileRepo: profileRepo,
 		storage:     st,
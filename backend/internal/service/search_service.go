@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+var ErrInvalidSearch = errors.New("invalid search")
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 50
+
+	maxSearchQueryLength = 200 // Characters
+	maxSearchTerms       = 8
+	searchSnippetLength  = 160 // Characters of the description shown around the first match
+)
+
+// uploadDateWindows maps the upload_date filter to how far back it reaches
+var uploadDateWindows = map[string]time.Duration{
+	"hour":  time.Hour,
+	"today": 24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+	"year":  365 * 24 * time.Hour,
+}
+
+type SearchService struct {
+	searchRepo     repository.SearchStore
+	suggestionRepo repository.SuggestionStore
+	now            func() time.Time
+}
+
+func NewSearchService(searchRepo repository.SearchStore, suggestionRepo repository.SuggestionStore) *SearchService {
+	return &SearchService{
+		searchRepo:     searchRepo,
+		suggestionRepo: suggestionRepo,
+		now:            time.Now,
+	}
+}
+
+// SearchVideos returns one page of ranked search results with highlighted titles and snippets
+// The first page of a signed-in viewer's search is logged for suggestions and their recent searches
+// (viewerID 0 for anonymous viewers, whose searches are not logged so they cannot make queries popular)
+func (s *SearchService) SearchVideos(ctx context.Context, viewerID int64, req *model.VideoSearchRequest) (*model.VideoSearchResponse, error) {
+	params, err := s.searchParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra result to know whether there is another page
+	limit := params.Limit
+	params.Limit++
+	results, err := s.searchRepo.SearchVideos(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search videos: %w", err)
+	}
+
+	// A search that cannot be logged still returns its results
+	if req.Cursor == "" && viewerID != 0 {
+		if err := s.suggestionRepo.RecordQuery(ctx, viewerID, normalizeQuery(params.Query), RecentSearchLimit, s.now().UTC()); err != nil {
+			log.Printf("Failed to record search query: %v", err)
+		}
+	}
+
+	response := &model.VideoSearchResponse{Results: results}
+	if len(results) > limit {
+		response.Results = results[:limit]
+		response.HasMore = true
+		last := response.Results[limit-1]
+		response.NextCursor = encodeSearchCursor(&model.SearchCursor{Rank: last.Rank, ID: last.Video.ID})
+	}
+
+	for _, result := range response.Results {
+		result.TitleHighlight = highlight(result.Video.Title, params.Terms)
+		result.Snippet = snippet(result.Video.Description, params.Terms, searchSnippetLength)
+	}
+
+	return response, nil
+}
+
+// searchParams validates a search request
+func (s *SearchService) searchParams(req *model.VideoSearchRequest) (*model.VideoSearchParams, error) {
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: q is required", ErrInvalidSearch)
+	}
+	if utf8.RuneCountInString(query) > maxSearchQueryLength {
+		return nil, fmt.Errorf("%w: q is longer than %d characters", ErrInvalidSearch, maxSearchQueryLength)
+	}
+	terms := strings.Fields(query)
+	if len(terms) > maxSearchTerms {
+		terms = terms[:maxSearchTerms]
+	}
+
+	if req.MinDuration < 0 || req.MaxDuration < 0 || (req.MaxDuration > 0 && req.MinDuration > req.MaxDuration) {
+		return nil, fmt.Errorf("%w: invalid duration range", ErrInvalidSearch)
+	}
+
+	params := &model.VideoSearchParams{
+		Query:       query,
+		Terms:       terms,
+		MinDuration: req.MinDuration,
+		MaxDuration: req.MaxDuration,
+		ChannelID:   req.ChannelID,
+		Limit:       req.Limit,
+	}
+	if params.Limit <= 0 {
+		params.Limit = DefaultSearchLimit
+	}
+	if params.Limit > MaxSearchLimit {
+		params.Limit = MaxSearchLimit
+	}
+
+	if req.UploadDate != "" {
+		window, ok := uploadDateWindows[req.UploadDate]
+		if !ok {
+			return nil, fmt.Errorf("%w: upload_date must be hour, today, week, month or year", ErrInvalidSearch)
+		}
+		after := s.now().Add(-window)
+		params.UploadedAfter = &after
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeSearchCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		params.After = cursor
+	}
+
+	return params, nil
+}
+
+// encodeSearchCursor makes the opaque next_cursor of a search page
+func encodeSearchCursor(cursor *model.SearchCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSearchCursor(value string) (*model.SearchCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidSearch)
+	}
+	var cursor model.SearchCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID <= 0 {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidSearch)
+	}
+	return &cursor, nil
+}
+
+// highlight HTML-escapes text and wraps case-insensitive matches of terms in <mark>
+func highlight(text string, terms []string) string {
+	runes := []rune(text)
+	marked := matchedRunes(runes, terms)
+
+	var b strings.Builder
+	inMark := false
+	for i, r := range runes {
+		if marked[i] != inMark {
+			if marked[i] {
+				b.WriteString("<mark>")
+			} else {
+				b.WriteString("</mark>")
+			}
+			inMark = marked[i]
+		}
+		b.WriteString(html.EscapeString(string(r)))
+	}
+	if inMark {
+		b.WriteString("</mark>")
+	}
+	return b.String()
+}
+
+// snippet returns up to length characters of text around the first match of terms, highlighted
+func snippet(text string, terms []string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return highlight(text, terms)
+	}
+
+	start := 0
+	for i, matched := range matchedRunes(runes, terms) {
+		if matched {
+			// Show some context before the match
+			start = max(0, i-length/4)
+			break
+		}
+	}
+	end := min(len(runes), start+length)
+	start = max(0, end-length)
+
+	result := highlight(string(runes[start:end]), terms)
+	if start > 0 {
+		result = "…" + result
+	}
+	if end < len(runes) {
+		result += "…"
+	}
+	return result
+}
+
+// matchedRunes reports for each rune whether it is part of a case-insensitive match of a term
+func matchedRunes(runes []rune, terms []string) []bool {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	marked := make([]bool, len(runes))
+	for _, term := range terms {
+		needle := []rune(strings.ToLower(term))
+		if len(needle) == 0 {
+			continue
+		}
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if string(lower[i:i+len(needle)]) == string(needle) {
+				for j := i; j < i+len(needle); j++ {
+					marked[j] = true
+				}
+			}
+		}
+	}
+	return marked
+}
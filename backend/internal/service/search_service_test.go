@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository/memory"
+)
+
+// searchFixture has channels "cats" (user 1) and "dogs" (user 2)
+type searchFixture struct {
+	db      *memory.DB
+	service *SearchService
+}
+
+func newSearchFixture(t *testing.T) *searchFixture {
+	db := memory.NewDB()
+	profiles := memory.NewProfileRepository(db)
+	for userID, email := range map[int64]string{1: "cats@example.com", 2: "dogs@example.com"} {
+		if _, err := profiles.Create(context.Background(), userID, email, "", ""); err != nil {
+			t.Fatalf("failed to create profile: %v", err)
+		}
+	}
+	return &searchFixture{
+		db:      db,
+		service: NewSearchService(memory.NewSearchRepository(db), memory.NewSuggestionRepository(db)),
+	}
+}
+
+func (f *searchFixture) video(t *testing.T, userID int64, title, description string, duration int64) *model.Video {
+	t.Helper()
+	video, err := memory.NewVideoRepository(f.db).Create(context.Background(), &model.Video{
+		UserID:      userID,
+		Title:       title,
+		Description: description,
+		Duration:    duration,
+		Status:      model.VideoStatusReady,
+		Visibility:  model.VideoVisibilityPublic,
+	})
+	if err != nil {
+		t.Fatalf("failed to create video: %v", err)
+	}
+	return video
+}
+
+func resultIDs(response *model.VideoSearchResponse) []int64 {
+	var ids []int64
+	for _, result := range response.Results {
+		ids = append(ids, result.Video.ID)
+	}
+	return ids
+}
+
+func TestSearchVideos_RanksTitleMatchesFirst(t *testing.T) {
+	f := newSearchFixture(t)
+	ctx := context.Background()
+
+	inDescription := f.video(t, 2, "散歩の記録", "公園で猫に会いました", 60)
+	inTitle := f.video(t, 2, "かわいい猫の動画", "", 60)
+	f.video(t, 2, "犬の動画", "", 60)
+	hidden := f.video(t, 2, "猫 非公開", "", 60)
+	_ = memory.NewVideoRepository(f.db).UpdateStatus(ctx, hidden.ID, model.VideoStatusProcessing, "")
+
+	response, err := f.service.SearchVideos(ctx, 0, &model.VideoSearchRequest{Query: "猫"})
+	if err != nil {
+		t.Fatalf("SearchVideos returned error: %v", err)
+	}
+	ids := resultIDs(response)
+	if len(ids) != 2 || ids[0] != inTitle.ID || ids[1] != inDescription.ID {
+		t.Fatalf("expected the title match before the description match, got %v", ids)
+	}
+	if got := response.Results[0].TitleHighlight; got != "かわいい<mark>猫</mark>の動画" {
+		t.Errorf("unexpected title highlight %q", got)
+	}
+	if got := response.Results[1].Snippet; got != "公園で<mark>猫</mark>に会いました" {
+		t.Errorf("unexpected snippet %q", got)
+	}
+	if response.HasMore || response.NextCursor != "" {
+		t.Errorf("expected a single page, got %+v", response)
+	}
+}
+
+func TestSearchVideos_MatchesEveryTermIncludingChannelName(t *testing.T) {
+	f := newSearchFixture(t)
+	ctx := context.Background()
+
+	match := f.video(t, 1, "Morning Walk", "", 60)
+	f.video(t, 2, "Morning Walk", "", 60)
+
+	response, _ := f.service.SearchVideos(ctx, 0, &model.VideoSearchRequest{Query: "walk CATS"})
+	if ids := resultIDs(response); len(ids) != 1 || ids[0] != match.ID {
+		t.Errorf("expected only the video on the cats channel, got %v", ids)
+	}
+}
+
+func TestSearchVideos_Filters(t *testing.T) {
+	f := newSearchFixture(t)
+	ctx := context.Background()
+
+	short := f.video(t, 1, "cooking short", "", 30)
+	long := f.video(t, 1, "cooking long", "", 3600)
+	other := f.video(t, 2, "cooking elsewhere", "", 300)
+
+	response, _ := f.service.SearchVideos(ctx, 0, &model.VideoSearchRequest{Query: "cooking", MinDuration: 60, MaxDuration: 600})
+	if ids := resultIDs(response); len(ids) != 1 || ids[0] != other.ID {
+		t.Errorf("expected only the video between 1 and 10 minutes, got %v", ids)
+	}
+	response, _ = f.service.SearchVideos(ctx, 0, &model.VideoSearchRequest{Query: "cooking", ChannelID: 1})
+	if ids := resultIDs(response); len(ids) != 2 || (ids[0] != short.ID && ids[0] != long.ID) {
+		t.Errorf("expected only videos of channel 1, got %v", ids)
+	}
+
+	f.service.now = func() time.Time { return time.Now().Add(48 * time.Hour) }
+	response, _ = f.service.SearchVideos(ctx, 0, &model.VideoSearchRequest{Query: "cooking", UploadDate: "today"})
+	if len(response.Results) != 0 {
+		t.Errorf("expected videos uploaded two days ago to be filtered out, got %v", resultIDs(response))
+	}
+	if _, err := f.service.SearchVideos(ctx, 0, &model.VideoSearchRequest{Query: "cooking", UploadDate: "decade"}); !errors.Is(err, ErrInvalidSearch) {
+		t.Errorf("expected ErrInvalidSearch for an unknown upload_date, got %v", err)
+	}
+}
+
+func TestSearchVideos_CursorPagination(t *testing.T) {
+	f := newSearchFixture(t)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		f.video(t, 1, "guitar lesson", "", 60)
+	}
+	f.video(t, 1, "lesson", "guitar", 60)
+
+	var seen []int64
+	req := &model.VideoSearchRequest{Query: "guitar", Limit: 2}
+	for page := 0; ; page++ {
+		response, err := f.service.SearchVideos(ctx, 0, req)
+		if err != nil {
+			t.Fatalf("SearchVideos returned error: %v", err)
+		}
+		seen = append(seen, resultIDs(response)...)
+		if !response.HasMore {
+			break
+		}
+		if page > 5 {
+			t.Fatal("pagination did not terminate")
+		}
+		req.Cursor = response.NextCursor
+	}
+
+	if len(seen) != 6 {
+		t.Fatalf("expected every result exactly once, got %v", seen)
+	}
+	unique := map[int64]bool{}
+	for _, id := range seen {
+		unique[id] = true
+	}
+	if len(unique) != 6 || seen[5] != 6 {
+		t.Errorf("expected no duplicates and the description match last, got %v", seen)
+	}
+
+	if _, err := f.service.SearchVideos(ctx, 0, &model.VideoSearchRequest{Query: "guitar", Cursor: "not-a-cursor"}); !errors.Is(err, ErrInvalidSearch) {
+		t.Errorf("expected ErrInvalidSearch for a malformed cursor, got %v", err)
+	}
+	if _, err := f.service.SearchVideos(ctx, 0, &model.VideoSearchRequest{Query: "   "}); !errors.Is(err, ErrInvalidSearch) {
+		t.Errorf("expected ErrInvalidSearch for an empty query, got %v", err)
+	}
+}
+
+func TestHighlightAndSnippet(t *testing.T) {
+	if got := highlight("<b>Go</b> & go", []string{"GO"}); got != "&lt;b&gt;<mark>Go</mark>&lt;/b&gt; &amp; <mark>go</mark>" {
+		t.Errorf("unexpected highlight %q", got)
+	}
+
+	text := "aaaaaaaaaa" + "bbbbbbbbbb" + "match" + "cccccccccc"
+	if got := snippet(text, []string{"match"}, 20); got != "…bbbbb<mark>match</mark>cccccccccc" {
+		t.Errorf("unexpected snippet %q", got)
+	}
+	if got := snippet(text, []string{"zzz"}, 10); got != "aaaaaaaaaa…" {
+		t.Errorf("expected the start of the text without a match, got %q", got)
+	}
+}
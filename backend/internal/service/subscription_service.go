@@ -10,16 +10,14 @@ import (
 )
 
 type SubscriptionService struct {
-	subscriptionRepo *repository.SubscriptionRepository
-	userRepo         *repository.UserRepository
-	videoRepo        *repository.VideoRepository
+	subscriptionRepo repository.SubscriptionStore
+	userRepo         repository.UserStore
 }
 
-func NewSubscriptionService(subscriptionRepo *repository.SubscriptionRepository, userRepo *repository.UserRepository, videoRepo *repository.VideoRepository) *SubscriptionService {
+func NewSubscriptionService(subscriptionRepo repository.SubscriptionStore, userRepo repository.UserStore) *SubscriptionService {
 	return &SubscriptionService{
 		subscriptionRepo: subscriptionRepo,
 		userRepo:         userRepo,
-		videoRepo:        videoRepo,
 	}
 }
 
@@ -80,28 +78,16 @@ func (s *SubscriptionService) GetSubscribedChannels(ctx context.Context, subscri
 }
 
 // GetSubscriptionFeed returns videos from subscribed channels
-func (s *SubscriptionService) GetSubscriptionFeed(ctx context.Context, subscriberUserID int64, limit, offset int) ([]*model.VideoWithProfile, error) {
-	// Set default limit if not provided
-	if limit <= 0 {
-		limit = 20
-	}
-	if offset < 0 {
-		offset = 0
+func (s *SubscriptionService) GetSubscriptionFeed(ctx context.Context, subscriberUserID int64, query model.PageQuery) (*model.Page[*model.VideoWithProfile], error) {
+	limit, page, err := pageRequest(query, DefaultPageLimit)
+	if err != nil {
+		return nil, err
 	}
 
-	videos, err := s.subscriptionRepo.GetSubscriptionFeed(ctx, subscriberUserID, limit, offset)
+	videos, err := s.subscriptionRepo.GetSubscriptionFeed(ctx, subscriberUserID, page)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get subscription feed: %w", err)
 	}
 
-	// Add like count for each video
-	for _, video := range videos {
-		likeCount, err := s.videoRepo.GetLikeCount(ctx, video.ID)
-		if err != nil {
-			likeCount = 0
-		}
-		video.LikeCount = likeCount
-	}
-
-	return videos, nil
+	return newPage(videos, limit, videoWithProfileKey), nil
 }
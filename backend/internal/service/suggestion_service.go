@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+var ErrInvalidPrefix = errors.New("prefix must be between 1 and 100 characters")
+
+const (
+	DefaultSuggestionLimit = 10
+	MaxSuggestionLimit     = 20
+	maxPrefixLength        = 100 // Characters
+
+	// Recent searches kept per user
+	RecentSearchLimit = 50
+
+	// Popular queries are counted over this window and need this many searchers
+	PopularQueryWindow   = 7 * 24 * time.Hour
+	minPopularSearches   = 3
+	maxPopularQueryCount = 1000
+)
+
+type SuggestionService struct {
+	suggestionRepo repository.SuggestionStore
+	now            func() time.Time
+}
+
+func NewSuggestionService(suggestionRepo repository.SuggestionStore) *SuggestionService {
+	return &SuggestionService{
+		suggestionRepo: suggestionRepo,
+		now:            time.Now,
+	}
+}
+
+// Suggest returns completions for prefix: the viewer's recent searches first (viewerID 0 for
+// anonymous viewers), then popular queries, video titles and channel names, without duplicates
+func (s *SuggestionService) Suggest(ctx context.Context, viewerID int64, prefix string, limit int) ([]*model.SearchSuggestion, error) {
+	normalized := normalizeQuery(prefix)
+	// A trailing space ends the word, so "cat " does not suggest "category"
+	if normalized != "" && strings.TrimRightFunc(prefix, unicode.IsSpace) != prefix {
+		normalized += " "
+	}
+	prefix = normalized
+	if prefix == "" || utf8.RuneCountInString(prefix) > maxPrefixLength {
+		return nil, ErrInvalidPrefix
+	}
+	if limit <= 0 {
+		limit = DefaultSuggestionLimit
+	}
+	if limit > MaxSuggestionLimit {
+		limit = MaxSuggestionLimit
+	}
+
+	suggestions := []*model.SearchSuggestion{}
+	seen := make(map[string]bool)
+	add := func(source string, texts []string) {
+		for _, text := range texts {
+			key := normalizeQuery(text)
+			if len(suggestions) >= limit || seen[key] {
+				continue
+			}
+			seen[key] = true
+			suggestions = append(suggestions, &model.SearchSuggestion{Text: text, Source: source})
+		}
+	}
+
+	if viewerID != 0 {
+		recent, err := s.suggestionRepo.FindRecentQueries(ctx, viewerID, prefix, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find recent searches: %w", err)
+		}
+		for _, search := range recent {
+			add(model.SuggestionSourceRecent, []string{search.Query})
+		}
+	}
+
+	sources := []struct {
+		source string
+		find   func(ctx context.Context, prefix string, limit int) ([]string, error)
+	}{
+		{model.SuggestionSourcePopular, s.suggestionRepo.FindPopularQueries},
+		{model.SuggestionSourceTitle, s.suggestionRepo.FindTitles},
+		{model.SuggestionSourceChannel, s.suggestionRepo.FindChannelNames},
+	}
+	for _, source := range sources {
+		if len(suggestions) >= limit {
+			break
+		}
+		texts, err := source.find(ctx, prefix, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find suggestions: %w", err)
+		}
+		add(source.source, texts)
+	}
+
+	return suggestions, nil
+}
+
+// GetRecentQueries returns the user's recent searches, newest first
+func (s *SuggestionService) GetRecentQueries(ctx context.Context, userID int64) ([]*model.RecentSearch, error) {
+	searches, err := s.suggestionRepo.FindRecentQueries(ctx, userID, "", RecentSearchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent searches: %w", err)
+	}
+
+	return searches, nil
+}
+
+// ClearRecentQueries removes all of the user's recent searches
+func (s *SuggestionService) ClearRecentQueries(ctx context.Context, userID int64) error {
+	if err := s.suggestionRepo.ClearRecentQueries(ctx, userID); err != nil {
+		return fmt.Errorf("failed to clear recent searches: %w", err)
+	}
+
+	return nil
+}
+
+// RecomputePopularQueries rebuilds the popular queries from the searches of the last PopularQueryWindow
+func (s *SuggestionService) RecomputePopularQueries(ctx context.Context) (int64, error) {
+	now := s.now().UTC()
+	count, err := s.suggestionRepo.RecomputePopularQueries(ctx, now.Add(-PopularQueryWindow), minPopularSearches, maxPopularQueryCount, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to recompute popular queries: %w", err)
+	}
+
+	return count, nil
+}
+
+// normalizeQuery lowercases a query and collapses its whitespace so repeated searches are counted together
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
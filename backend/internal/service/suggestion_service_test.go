@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository/memory"
+)
+
+func suggestionTexts(suggestions []*model.SearchSuggestion) []string {
+	var texts []string
+	for _, suggestion := range suggestions {
+		texts = append(texts, suggestion.Source+":"+suggestion.Text)
+	}
+	return texts
+}
+
+func TestSuggest_MergesSourcesWithoutDuplicates(t *testing.T) {
+	f := newSearchFixture(t)
+	ctx := context.Background()
+	suggestions := NewSuggestionService(memory.NewSuggestionRepository(f.db))
+
+	f.video(t, 2, "Cat videos compilation", "", 60)
+	f.video(t, 2, "Category theory", "", 60)
+	for _, userID := range []int64{3, 4, 5} {
+		if _, err := f.service.SearchVideos(ctx, userID, &model.VideoSearchRequest{Query: "Cat  Videos"}); err != nil {
+			t.Fatalf("SearchVideos returned error: %v", err)
+		}
+	}
+	if _, err := f.service.SearchVideos(ctx, 1, &model.VideoSearchRequest{Query: "cat toys"}); err != nil {
+		t.Fatalf("SearchVideos returned error: %v", err)
+	}
+	if _, err := suggestions.RecomputePopularQueries(ctx); err != nil {
+		t.Fatalf("RecomputePopularQueries returned error: %v", err)
+	}
+
+	got, err := suggestions.Suggest(ctx, 1, "CAT", 0)
+	if err != nil {
+		t.Fatalf("Suggest returned error: %v", err)
+	}
+	want := []string{
+		"recent:cat toys",
+		"popular:cat videos",
+		"title:Category theory",
+		"title:Cat videos compilation",
+		"channel:cats",
+	}
+	if fmt.Sprint(suggestionTexts(got)) != fmt.Sprint(want) {
+		t.Fatalf("expected %v, got %v", want, suggestionTexts(got))
+	}
+
+	// Anonymous viewers get no recent searches and the limit caps the merged list
+	got, err = suggestions.Suggest(ctx, 0, "cat", 2)
+	if err != nil {
+		t.Fatalf("Suggest returned error: %v", err)
+	}
+	want = []string{"popular:cat videos", "title:Category theory"}
+	if fmt.Sprint(suggestionTexts(got)) != fmt.Sprint(want) {
+		t.Fatalf("expected %v, got %v", want, suggestionTexts(got))
+	}
+
+	// A trailing space ends the word
+	got, err = suggestions.Suggest(ctx, 0, "cat ", 0)
+	if err != nil {
+		t.Fatalf("Suggest returned error: %v", err)
+	}
+	want = []string{"popular:cat videos", "title:Cat videos compilation"}
+	if fmt.Sprint(suggestionTexts(got)) != fmt.Sprint(want) {
+		t.Fatalf("expected %v, got %v", want, suggestionTexts(got))
+	}
+
+	for _, prefix := range []string{"", "   "} {
+		if _, err := suggestions.Suggest(ctx, 0, prefix, 0); !errors.Is(err, ErrInvalidPrefix) {
+			t.Errorf("expected ErrInvalidPrefix for %q, got %v", prefix, err)
+		}
+	}
+}
+
+func TestSearchHistory_RecordsFirstPagesAndClears(t *testing.T) {
+	f := newSearchFixture(t)
+	ctx := context.Background()
+	suggestions := NewSuggestionService(memory.NewSuggestionRepository(f.db))
+
+	for i := 0; i < RecentSearchLimit+2; i++ {
+		if _, err := f.service.SearchVideos(ctx, 1, &model.VideoSearchRequest{Query: fmt.Sprintf("query %d", i)}); err != nil {
+			t.Fatalf("SearchVideos returned error: %v", err)
+		}
+	}
+	// Repeating a search moves it to the top instead of adding it again
+	if _, err := f.service.SearchVideos(ctx, 1, &model.VideoSearchRequest{Query: "Query 10"}); err != nil {
+		t.Fatalf("SearchVideos returned error: %v", err)
+	}
+	// Later pages of a search are not recorded
+	cursor := encodeSearchCursor(&model.SearchCursor{Rank: 1, ID: 1})
+	if _, err := f.service.SearchVideos(ctx, 1, &model.VideoSearchRequest{Query: "next page", Cursor: cursor}); err != nil {
+		t.Fatalf("SearchVideos returned error: %v", err)
+	}
+
+	recent, err := suggestions.GetRecentQueries(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetRecentQueries returned error: %v", err)
+	}
+	if len(recent) != RecentSearchLimit {
+		t.Fatalf("expected %d recent searches, got %d", RecentSearchLimit, len(recent))
+	}
+	if recent[0].Query != "query 10" || recent[1].Query != fmt.Sprintf("query %d", RecentSearchLimit+1) {
+		t.Errorf("unexpected order of recent searches: %q, %q", recent[0].Query, recent[1].Query)
+	}
+	for _, search := range recent {
+		if search.Query == "query 0" || search.Query == "next page" {
+			t.Errorf("unexpected recent search %q", search.Query)
+		}
+	}
+
+	if err := suggestions.ClearRecentQueries(ctx, 1); err != nil {
+		t.Fatalf("ClearRecentQueries returned error: %v", err)
+	}
+	if recent, _ := suggestions.GetRecentQueries(ctx, 1); len(recent) != 0 {
+		t.Errorf("expected no recent searches after clearing, got %d", len(recent))
+	}
+}
+
+func TestRecomputePopularQueries_CountsSearchersAndExpires(t *testing.T) {
+	f := newSearchFixture(t)
+	ctx := context.Background()
+	suggestions := NewSuggestionService(memory.NewSuggestionRepository(f.db))
+
+	search := func(viewerID int64, query string) {
+		t.Helper()
+		if _, err := f.service.SearchVideos(ctx, viewerID, &model.VideoSearchRequest{Query: query}); err != nil {
+			t.Fatalf("SearchVideos returned error: %v", err)
+		}
+	}
+	// One user repeating a search counts once
+	for i := 0; i < 5; i++ {
+		search(1, "spam")
+	}
+	search(2, "spam")
+	// Anonymous searches do not count, however many there are
+	for i := 0; i < 5; i++ {
+		search(0, "spam anonymous")
+	}
+	for _, userID := range []int64{1, 2, 3, 4} {
+		search(userID, "shared")
+	}
+
+	count, err := suggestions.RecomputePopularQueries(ctx)
+	if err != nil {
+		t.Fatalf("RecomputePopularQueries returned error: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 popular query, got %d", count)
+	}
+	got, err := suggestions.Suggest(ctx, 0, "s", 0)
+	if err != nil {
+		t.Fatalf("Suggest returned error: %v", err)
+	}
+	if want := []string{"popular:shared"}; fmt.Sprint(suggestionTexts(got)) != fmt.Sprint(want) {
+		t.Fatalf("expected %v, got %v", want, suggestionTexts(got))
+	}
+
+	// Searches older than the window are dropped
+	suggestions.now = func() time.Time { return time.Now().Add(PopularQueryWindow + time.Hour) }
+	count, err = suggestions.RecomputePopularQueries(ctx)
+	if err != nil {
+		t.Fatalf("RecomputePopularQueries returned error: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected expired searches to be dropped, got %d popular queries", count)
+	}
+}
+
+func TestSearchHistory_UsesServiceClock(t *testing.T) {
+	f := newSearchFixture(t)
+	ctx := context.Background()
+	suggestions := NewSuggestionService(memory.NewSuggestionRepository(f.db))
+	searchedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	f.service.now = func() time.Time { return searchedAt }
+
+	if _, err := f.service.SearchVideos(ctx, 1, &model.VideoSearchRequest{Query: "cats"}); err != nil {
+		t.Fatalf("SearchVideos returned error: %v", err)
+	}
+	recent, err := suggestions.GetRecentQueries(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetRecentQueries returned error: %v", err)
+	}
+	if len(recent) != 1 || !recent[0].SearchedAt.Equal(searchedAt) {
+		t.Errorf("expected one search at %v, got %+v", searchedAt, recent)
+	}
+}
@@ -0,0 +1,422 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yukito/video-platform/internal/media"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+	"github.com/yukito/video-platform/internal/storage"
+	"github.com/yukito/video-platform/internal/transcode"
+)
+
+const (
+	// maxTranscodeAttempts is how often a job is tried before it is marked failed
+	maxTranscodeAttempts = 3
+	// transcodeJobTimeout bounds one attempt; running jobs older than this are claimed again
+	transcodeJobTimeout = 2 * time.Hour
+
+	hlsPlaylistContentType = "application/vnd.apple.mpegurl"
+	hlsSegmentContentType  = "video/mp2t"
+)
+
+// thumbnailPositions are where candidate thumbnails are taken, as fractions of the duration
+var thumbnailPositions = []float64{0.25, 0.5, 0.75}
+
+// errSourceReplaced marks a job whose video file was replaced after it was queued
+var errSourceReplaced = errors.New("video file was replaced")
+
+// TranscodeService runs queued transcode jobs in the background
+// Jobs live in the database, so work queued before a restart is picked up again;
+// a finished job marks its video ready, the last failed attempt marks it failed
+type TranscodeService struct {
+	transcodeRepo repository.TranscodeStore
+	videoRepo     repository.VideoStore
+	thumbnailRepo repository.ThumbnailStore
+	storage       storage.Storage
+	transcoder    transcode.Transcoder
+	prober        media.Prober
+	frames        media.FrameExtractor
+
+	workDir      string
+	profiles     []transcode.Profile
+	pollInterval time.Duration
+}
+
+// NewTranscodeService creates the worker; workDir holds temporary files (empty for the OS default)
+// Without a transcoder, jobs publish the uploaded file as is and no renditions are made;
+// without a prober the video's metadata is left as it is, and without frames no thumbnails are generated
+func NewTranscodeService(transcodeRepo repository.TranscodeStore, videoRepo repository.VideoStore, thumbnailRepo repository.ThumbnailStore, st storage.Storage, transcoder transcode.Transcoder, prober media.Prober, frames media.FrameExtractor, workDir string) *TranscodeService {
+	return &TranscodeService{
+		transcodeRepo: transcodeRepo,
+		videoRepo:     videoRepo,
+		thumbnailRepo: thumbnailRepo,
+		storage:       st,
+		transcoder:    transcoder,
+		prober:        prober,
+		frames:        frames,
+		workDir:       workDir,
+		profiles:      transcode.DefaultProfiles,
+		pollInterval:  5 * time.Second,
+	}
+}
+
+// Run processes jobs until ctx is cancelled
+func (s *TranscodeService) Run(ctx context.Context) {
+	for {
+		processed, err := s.ProcessNext(ctx)
+		if err != nil {
+			log.Printf("Transcode: %v", err)
+		}
+		if processed {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.pollInterval):
+		}
+	}
+}
+
+// ProcessNext claims and runs one job; it reports false when no job was waiting
+func (s *TranscodeService) ProcessNext(ctx context.Context) (bool, error) {
+	job, err := s.transcodeRepo.ClaimNext(ctx, time.Now().Add(-transcodeJobTimeout))
+	if err != nil {
+		return false, err
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	if err := s.process(ctx, job); err != nil {
+		replaced := errors.Is(err, errSourceReplaced)
+		retry := job.Attempts < maxTranscodeAttempts && !replaced && !errors.Is(err, media.ErrNotVideo)
+		if failErr := s.transcodeRepo.FailJob(ctx, job.ID, err.Error(), retry); failErr != nil {
+			log.Printf("Transcode: %v", failErr)
+		}
+
+		// The job for the replacing file decides the status of a replaced video
+		if !retry && !replaced {
+			s.setVideoStatus(ctx, job.VideoID, model.VideoStatusFailed, err.Error())
+		}
+		return true, fmt.Errorf("failed to transcode video %d: %w", job.VideoID, err)
+	}
+
+	s.setVideoStatus(ctx, job.VideoID, model.VideoStatusReady, "")
+	return true, nil
+}
+
+func (s *TranscodeService) process(ctx context.Context, job *model.TranscodeJob) error {
+	video, err := s.videoRepo.FindByID(ctx, job.VideoID)
+	if err != nil {
+		return fmt.Errorf("video not found: %w", err)
+	}
+	if video.VideoURL != job.SourceURL {
+		return errSourceReplaced
+	}
+
+	if s.transcoder == nil && s.prober == nil && s.frames == nil {
+		// Drop renditions of an earlier file; they no longer match the video
+		return s.complete(ctx, job, "", nil)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, transcodeJobTimeout)
+	defer cancel()
+
+	workDir, err := os.MkdirTemp(s.workDir, "transcode-*")
+	if err != nil {
+		return fmt.Errorf("failed to create work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	sourcePath := filepath.Join(workDir, "source"+filepath.Ext(job.SourceURL))
+	if err := s.download(ctx, job.SourceURL, sourcePath); err != nil {
+		return err
+	}
+
+	// Probed again so the metadata always matches the file that is transcoded
+	if s.prober != nil {
+		meta, err := s.prober.Probe(ctx, sourcePath)
+		if err != nil {
+			return fmt.Errorf("failed to probe source: %w", err)
+		}
+		applyMetadata(video, meta)
+		if err := s.videoRepo.UpdateMetadata(ctx, video); err != nil {
+			return err
+		}
+	}
+
+	if s.transcoder == nil {
+		s.generateThumbnails(ctx, video, sourcePath, workDir)
+		return s.complete(ctx, job, "", nil)
+	}
+
+	outputs, err := s.transcoder.Transcode(ctx, sourcePath, filepath.Join(workDir, "hls"), s.profiles)
+	if err != nil {
+		return err
+	}
+	if len(outputs) == 0 {
+		return errors.New("transcoder produced no renditions")
+	}
+
+	// Everything uploaded for this job, removed again if the job fails
+	var uploaded []string
+	success := false
+	defer func() {
+		if !success {
+			s.deleteFiles(uploaded)
+		}
+	}()
+
+	renditions := make([]*model.VideoRendition, 0, len(outputs))
+	variants := make([]transcode.Variant, 0, len(outputs))
+	for _, output := range outputs {
+		rendition, err := s.storeRendition(ctx, output, &uploaded)
+		if err != nil {
+			return err
+		}
+		renditions = append(renditions, rendition)
+		variants = append(variants, transcode.Variant{
+			URL:       rendition.PlaylistURL,
+			Bandwidth: rendition.Bandwidth,
+			Width:     rendition.Width,
+			Height:    rendition.Height,
+		})
+	}
+
+	master := transcode.MasterPlaylist(variants)
+	masterURL, err := s.storage.UploadFile(ctx, bytes.NewReader(master), "master.m3u8", hlsPlaylistContentType, int64(len(master)))
+	if err != nil {
+		return fmt.Errorf("failed to upload master playlist: %w", err)
+	}
+	uploaded = append(uploaded, masterURL)
+
+	// Thumbnails are only replaced by a job that succeeds
+	s.generateThumbnails(ctx, video, sourcePath, workDir)
+
+	if err := s.complete(ctx, job, masterURL, renditions); err != nil {
+		return err
+	}
+	success = true
+
+	return nil
+}
+
+// complete stores the job's renditions and removes the files of the ones they replace
+func (s *TranscodeService) complete(ctx context.Context, job *model.TranscodeJob, masterURL string, renditions []*model.VideoRendition) error {
+	oldRenditions, _ := s.transcodeRepo.FindRenditions(ctx, job.VideoID)
+	oldJob, _ := s.transcodeRepo.FindLatestCompletedJob(ctx, job.VideoID)
+
+	if err := s.transcodeRepo.CompleteJob(ctx, job.ID, job.VideoID, masterURL, renditions); err != nil {
+		return err
+	}
+
+	var stale []string
+	for _, rendition := range oldRenditions {
+		stale = append(stale, rendition.PlaylistURL)
+		stale = append(stale, rendition.SegmentURLs...)
+	}
+	if oldJob != nil && oldJob.MasterPlaylistURL != "" {
+		stale = append(stale, oldJob.MasterPlaylistURL)
+	}
+	s.deleteFiles(stale)
+
+	return nil
+}
+
+// generateThumbnails stores candidate frames of the source as the video's generated thumbnails
+// The middle one is selected unless the owner's choice is still a thumbnail of this file;
+// thumbnails are optional, so failures are logged instead of failing the job
+func (s *TranscodeService) generateThumbnails(ctx context.Context, video *model.Video, sourcePath, dir string) {
+	if s.frames == nil {
+		return
+	}
+
+	var offsets []float64
+	for _, position := range thumbnailPositions {
+		offsets = append(offsets, position*float64(video.Duration))
+	}
+	if video.Duration <= 0 {
+		offsets = []float64{0}
+	}
+
+	var uploaded []string
+	var thumbnails []*model.VideoThumbnail
+	for i, at := range offsets {
+		framePath := filepath.Join(dir, fmt.Sprintf("thumbnail_%d.jpg", i))
+		if err := s.frames.ExtractFrame(ctx, sourcePath, at, framePath); err != nil {
+			log.Printf("Transcode: failed to generate thumbnails of video %d: %v", video.ID, err)
+			s.deleteFiles(uploaded)
+			return
+		}
+
+		url, err := s.uploadLocalFile(ctx, framePath, "image/jpeg")
+		if err != nil {
+			log.Printf("Transcode: failed to upload thumbnail of video %d: %v", video.ID, err)
+			s.deleteFiles(uploaded)
+			return
+		}
+		uploaded = append(uploaded, url)
+		thumbnails = append(thumbnails, &model.VideoThumbnail{URL: url, TimeOffset: at})
+	}
+
+	removed, err := s.thumbnailRepo.ReplaceGenerated(ctx, video.ID, thumbnails)
+	if err != nil {
+		log.Printf("Transcode: %v", err)
+		s.deleteFiles(uploaded)
+		return
+	}
+
+	// Frames of a replaced file no longer match the video
+	selectDefault := video.ThumbnailURL == ""
+	var stale []string
+	for _, thumbnail := range removed {
+		stale = append(stale, thumbnail.URL)
+		if thumbnail.URL == video.ThumbnailURL {
+			selectDefault = true
+		}
+	}
+
+	if selectDefault {
+		url := thumbnails[len(thumbnails)/2].URL
+		if err := s.videoRepo.UpdateThumbnail(ctx, video.ID, url); err != nil {
+			log.Printf("Transcode: %v", err)
+		} else {
+			video.ThumbnailURL = url
+		}
+	}
+
+	// Keep the file the video still shows if selecting the new one failed
+	for _, url := range stale {
+		if url != video.ThumbnailURL {
+			s.deleteFiles([]string{url})
+		}
+	}
+}
+
+// setVideoStatus records the outcome of processing on the video, logging failures
+func (s *TranscodeService) setVideoStatus(ctx context.Context, videoID int64, status, reason string) {
+	if err := s.videoRepo.UpdateStatus(ctx, videoID, status, reason); err != nil {
+		log.Printf("Transcode: failed to mark video %d %s: %v", videoID, status, err)
+	}
+}
+
+// storeRendition uploads a rendition's segments, then its playlist rewritten to point at them
+func (s *TranscodeService) storeRendition(ctx context.Context, output transcode.Output, uploaded *[]string) (*model.VideoRendition, error) {
+	playlist, err := os.ReadFile(output.PlaylistPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read playlist: %w", err)
+	}
+
+	dir := filepath.Dir(output.PlaylistPath)
+	segmentURLs := make(map[string]string)
+	var orderedURLs []string
+	for _, uri := range transcode.SegmentURIs(playlist) {
+		// Transcoders write segments next to the playlist
+		if filepath.Base(uri) != uri {
+			return nil, fmt.Errorf("unexpected segment reference: %s", uri)
+		}
+
+		url, err := s.uploadLocalFile(ctx, filepath.Join(dir, uri), hlsSegmentContentType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload segment: %w", err)
+		}
+		*uploaded = append(*uploaded, url)
+		segmentURLs[uri] = url
+		orderedURLs = append(orderedURLs, url)
+	}
+
+	rewritten, err := transcode.RewriteSegmentURIs(playlist, segmentURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	playlistURL, err := s.storage.UploadFile(ctx, bytes.NewReader(rewritten), "index.m3u8", hlsPlaylistContentType, int64(len(rewritten)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload playlist: %w", err)
+	}
+	*uploaded = append(*uploaded, playlistURL)
+
+	return &model.VideoRendition{
+		Name:        output.Profile.Name,
+		Width:       output.Width,
+		Height:      output.Height,
+		Bandwidth:   output.Profile.Bandwidth(),
+		PlaylistURL: playlistURL,
+		SegmentURLs: orderedURLs,
+	}, nil
+}
+
+// download copies a stored file to a local path for the transcoder
+func (s *TranscodeService) download(ctx context.Context, fileURL, path string) error {
+	src, err := s.storage.OpenFile(ctx, fileURL)
+	if err != nil {
+		return fmt.Errorf("failed to open source: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create source file: %w", err)
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return fmt.Errorf("failed to download source: %w", err)
+	}
+
+	return dst.Close()
+}
+
+func (s *TranscodeService) uploadLocalFile(ctx context.Context, path, contentType string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	return s.storage.UploadFile(ctx, f, filepath.Base(path), contentType, info.Size())
+}
+
+// deleteFiles removes stored files, logging failures
+func (s *TranscodeService) deleteFiles(urls []string) {
+	for _, url := range urls {
+		if err := s.storage.DeleteFile(context.Background(), url); err != nil {
+			log.Printf("Transcode: failed to delete %s: %v", url, err)
+		}
+	}
+}
+
+// enqueueTranscode queues HLS transcoding of a video's current file
+// The raw file stays playable, so when the job cannot be queued the video is published as is
+func enqueueTranscode(ctx context.Context, transcodeRepo repository.TranscodeStore, videoRepo repository.VideoStore, video *model.Video) {
+	if video.VideoURL == "" {
+		return
+	}
+	if _, err := transcodeRepo.CreateJob(ctx, video.ID, video.VideoURL); err != nil {
+		log.Printf("Failed to queue transcoding of video %d: %v", video.ID, err)
+
+		if video.Status == model.VideoStatusProcessing {
+			if err := videoRepo.UpdateStatus(ctx, video.ID, model.VideoStatusReady, ""); err != nil {
+				log.Printf("Failed to publish video %d: %v", video.ID, err)
+				return
+			}
+			video.Status = model.VideoStatusReady
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/yukito/video-platform/internal/media"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository/memory"
+	"github.com/yukito/video-platform/internal/storage"
+	"github.com/yukito/video-platform/internal/transcode"
+)
+
+// transcodeFixture wires a VideoService that queues jobs and a worker that runs them with a fake transcoder
+type transcodeFixture struct {
+	db         *memory.DB
+	storage    *storage.MemoryStorage
+	transcoder *transcode.FakeTranscoder
+	prober     *media.FakeProber
+	frames     *media.FakeFrameExtractor
+	videos     *VideoService
+	worker     *TranscodeService
+}
+
+func newTranscodeFixture(t *testing.T) *transcodeFixture {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	transcoder := transcode.NewFakeTranscoder()
+	prober := media.NewFakeProber()
+	frames := media.NewFakeFrameExtractor()
+	transcodeRepo := memory.NewTranscodeRepository(db)
+
+	return &transcodeFixture{
+		db:         db,
+		storage:    st,
+		transcoder: transcoder,
+		prober:     prober,
+		frames:     frames,
+		videos:     newVideoServiceWithProber(db, st, prober),
+		worker: NewTranscodeService(transcodeRepo, memory.NewVideoRepository(db), memory.NewThumbnailRepository(db),
+			st, transcoder, prober, frames, t.TempDir()),
+	}
+}
+
+func TestTranscode_ProducesRenditionsAndMasterPlaylist(t *testing.T) {
+	f := newTranscodeFixture(t)
+	ctx := context.Background()
+
+	video, err := createWithFiles(f.videos, "movie.mp4", "thumb.jpg")
+	if err != nil {
+		t.Fatalf("failed to create video: %v", err)
+	}
+
+	processed, err := f.worker.ProcessNext(ctx)
+	if !processed || err != nil {
+		t.Fatalf("expected queued job to be processed, got %v, %v", processed, err)
+	}
+
+	got, err := f.videos.GetByID(ctx, 1, video.ID)
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+	if len(got.Renditions) != len(transcode.DefaultProfiles) {
+		t.Fatalf("expected %d renditions, got %d", len(transcode.DefaultProfiles), len(got.Renditions))
+	}
+	if got.Renditions[0].Name != "1080p" {
+		t.Errorf("expected highest rendition first, got %s", got.Renditions[0].Name)
+	}
+
+	master, ok := f.storage.Get(got.HLSURL)
+	if !ok {
+		t.Fatalf("expected master playlist at %s", got.HLSURL)
+	}
+	for _, rendition := range got.Renditions {
+		if !strings.Contains(string(master), rendition.PlaylistURL) {
+			t.Errorf("master playlist does not reference %s", rendition.Name)
+		}
+
+		playlist, _ := f.storage.Get(rendition.PlaylistURL)
+		segments := transcode.SegmentURIs(playlist)
+		if len(segments) != 2 {
+			t.Fatalf("expected 2 segments in %s, got %v", rendition.Name, segments)
+		}
+		data, ok := f.storage.Get(segments[0])
+		if !ok || !strings.HasPrefix(string(data), rendition.Name+"/0:video-bytes") {
+			t.Errorf("expected segment of %s to be stored, got %q", rendition.Name, data)
+		}
+	}
+
+	if processed, _ := f.worker.ProcessNext(ctx); processed {
+		t.Error("expected no further jobs")
+	}
+}
+
+func TestTranscode_RetriesThenFailsWithoutLeavingFiles(t *testing.T) {
+	f := newTranscodeFixture(t)
+	ctx := context.Background()
+	f.transcoder.Fail(errors.New("ffmpeg crashed"))
+
+	video, _ := createWithFiles(f.videos, "movie.mp4", "thumb.jpg")
+	before := f.storage.Files()
+
+	for i := 0; i < maxTranscodeAttempts; i++ {
+		if processed, err := f.worker.ProcessNext(ctx); !processed || err == nil {
+			t.Fatalf("attempt %d: expected a failed job, got %v, %v", i+1, processed, err)
+		}
+	}
+	if processed, _ := f.worker.ProcessNext(ctx); processed {
+		t.Error("expected job to be marked failed after the last attempt")
+	}
+	if f.transcoder.Calls() != maxTranscodeAttempts {
+		t.Errorf("expected %d attempts, got %d", maxTranscodeAttempts, f.transcoder.Calls())
+	}
+
+	if after := f.storage.Files(); len(after) != len(before) {
+		t.Errorf("expected no files left behind, before %v after %v", before, after)
+	}
+
+	got, _ := f.videos.GetByID(ctx, 1, video.ID)
+	if got.HLSURL != "" || len(got.Renditions) != 0 {
+		t.Errorf("expected no renditions, got %+v", got)
+	}
+
+	status, _ := f.videos.GetStatus(ctx, 1, video.ID)
+	if status.Status != model.VideoStatusFailed || !strings.Contains(status.FailureReason, "ffmpeg crashed") {
+		t.Errorf("expected video to be failed with the transcoder error, got %+v", status)
+	}
+}
+
+func TestTranscode_WithoutTranscoderPublishesUploadedFile(t *testing.T) {
+	f := newTranscodeFixture(t)
+	ctx := context.Background()
+	worker := NewTranscodeService(memory.NewTranscodeRepository(f.db), memory.NewVideoRepository(f.db), memory.NewThumbnailRepository(f.db),
+		f.storage, nil, nil, nil, t.TempDir())
+
+	video, _ := createWithFiles(f.videos, "movie.mp4", "thumb.jpg")
+	if processed, err := worker.ProcessNext(ctx); !processed || err != nil {
+		t.Fatalf("expected job to be processed, got %v, %v", processed, err)
+	}
+
+	got, err := f.videos.GetByID(ctx, 2, video.ID)
+	if err != nil {
+		t.Fatalf("expected video to be published, got %v", err)
+	}
+	if got.Status != model.VideoStatusReady || got.HLSURL != "" || len(got.Renditions) != 0 {
+		t.Errorf("expected a ready video without renditions, got %+v", got)
+	}
+}
+
+func TestTranscode_ReplacedFileGetsNewRenditions(t *testing.T) {
+	f := newTranscodeFixture(t)
+	ctx := context.Background()
+
+	video, _ := createWithFiles(f.videos, "movie.mp4", "thumb.jpg")
+	_, _ = f.worker.ProcessNext(ctx)
+	first, _ := f.videos.GetByID(ctx, 1, video.ID)
+
+	if _, err := updateWithFiles(f.videos, 1, video.ID, "new.mp4", "new.jpg"); err != nil {
+		t.Fatalf("failed to update video: %v", err)
+	}
+	if processed, err := f.worker.ProcessNext(ctx); !processed || err != nil {
+		t.Fatalf("expected replacement job to be processed, got %v, %v", processed, err)
+	}
+
+	second, _ := f.videos.GetByID(ctx, 1, video.ID)
+	if second.HLSURL == first.HLSURL {
+		t.Fatal("expected a new master playlist")
+	}
+	if f.storage.Has(first.HLSURL) || f.storage.Has(first.Renditions[0].PlaylistURL) {
+		t.Error("expected old renditions to be deleted")
+	}
+	for _, url := range first.Renditions[0].SegmentURLs {
+		if f.storage.Has(url) {
+			t.Errorf("expected old segment %s to be deleted", url)
+		}
+	}
+}
+
+func TestTranscode_SkipsJobForReplacedFile(t *testing.T) {
+	f := newTranscodeFixture(t)
+	ctx := context.Background()
+
+	video, _ := createWithFiles(f.videos, "movie.mp4", "thumb.jpg")
+	_, _ = updateWithFiles(f.videos, 1, video.ID, "new.mp4", "new.jpg")
+
+	// The first job refers to the deleted file and must not be retried
+	if _, err := f.worker.ProcessNext(ctx); !errors.Is(err, errSourceReplaced) {
+		t.Fatalf("expected errSourceReplaced, got %v", err)
+	}
+	if processed, err := f.worker.ProcessNext(ctx); !processed || err != nil {
+		t.Fatalf("expected the replacement job to succeed, got %v, %v", processed, err)
+	}
+	if processed, _ := f.worker.ProcessNext(ctx); processed {
+		t.Error("expected the stale job not to be queued again")
+	}
+	if f.transcoder.Calls() != 1 {
+		t.Errorf("expected one transcode, got %d", f.transcoder.Calls())
+	}
+}
+
+func TestTranscode_ProbesSourceAndRejectsUndecodableFiles(t *testing.T) {
+	f := newTranscodeFixture(t)
+	ctx := context.Background()
+
+	video, _ := createWithFiles(f.videos, "movie.mp4", "thumb.jpg")
+	f.prober.SetMetadata(media.Metadata{VideoCodec: "vp9", Width: 640, Height: 360, Duration: 4})
+	if _, err := f.worker.ProcessNext(ctx); err != nil {
+		t.Fatalf("ProcessNext returned error: %v", err)
+	}
+	if stored, _ := memory.NewVideoRepository(f.db).FindByID(ctx, video.ID); stored.VideoCodec != "vp9" || stored.Duration != 4 {
+		t.Errorf("expected the worker's probe to be stored, got %+v", stored)
+	}
+
+	// A file the upload path could not check, e.g. one uploaded in chunks
+	broken, _ := createWithFiles(f.videos, "broken.mp4", "thumb.jpg")
+	f.prober.Fail(fmt.Errorf("%w: moov atom not found", media.ErrNotVideo))
+	if _, err := f.worker.ProcessNext(ctx); !errors.Is(err, media.ErrNotVideo) {
+		t.Fatalf("expected media.ErrNotVideo, got %v", err)
+	}
+	if processed, _ := f.worker.ProcessNext(ctx); processed {
+		t.Error("expected an undecodable file not to be retried")
+	}
+
+	status, _ := f.videos.GetStatus(ctx, 1, broken.ID)
+	if status.Status != model.VideoStatusFailed || !strings.Contains(status.FailureReason, "not a decodable video") {
+		t.Errorf("expected the video to fail as undecodable, got %+v", status)
+	}
+	if f.transcoder.Calls() != 1 {
+		t.Errorf("expected only the decodable file to be transcoded, got %d calls", f.transcoder.Calls())
+	}
+}
+
+func TestTranscode_GeneratesThumbnailCandidates(t *testing.T) {
+	f := newTranscodeFixture(t)
+	ctx := context.Background()
+
+	video, _ := f.videos.CreateWithFiles(ctx, 1, "title", "description", "", nil,
+		strings.NewReader("video-bytes"), "movie.mp4", "video/mp4", 11, nil, "", "", 0)
+	if _, err := f.worker.ProcessNext(ctx); err != nil {
+		t.Fatalf("ProcessNext returned error: %v", err)
+	}
+
+	thumbnails, err := f.videos.ListThumbnails(ctx, 1, video.ID)
+	if err != nil {
+		t.Fatalf("ListThumbnails returned error: %v", err)
+	}
+	if len(thumbnails) != len(thumbnailPositions) {
+		t.Fatalf("expected %d candidates, got %d", len(thumbnailPositions), len(thumbnails))
+	}
+	if !thumbnails[1].Selected || thumbnails[0].Selected || thumbnails[2].Selected {
+		t.Errorf("expected the middle candidate to be selected, got %+v", thumbnails)
+	}
+	if stored := storedVideo(t, f.db, video.ID); stored.ThumbnailURL != thumbnails[1].URL {
+		t.Errorf("expected the video to show %s, got %s", thumbnails[1].URL, stored.ThumbnailURL)
+	}
+
+	// A replaced file gets new candidates and the old ones are deleted
+	if _, err := updateWithFiles(f.videos, 1, video.ID, "new.mp4", "new.jpg"); err != nil {
+		t.Fatalf("failed to update video: %v", err)
+	}
+	if _, err := f.worker.ProcessNext(ctx); err != nil {
+		t.Fatalf("ProcessNext returned error: %v", err)
+	}
+	for _, thumbnail := range thumbnails {
+		if f.storage.Has(thumbnail.URL) {
+			t.Errorf("expected old candidate %s to be deleted", thumbnail.URL)
+		}
+	}
+	stored := storedVideo(t, f.db, video.ID)
+	if !f.storage.Has(stored.ThumbnailURL) || stored.ThumbnailURL == thumbnails[1].URL {
+		t.Errorf("expected the uploaded thumbnail to be kept, got %s", stored.ThumbnailURL)
+	}
+}
+
+func TestTranscode_ThumbnailFailureDoesNotFailJob(t *testing.T) {
+	f := newTranscodeFixture(t)
+	ctx := context.Background()
+
+	video, _ := createWithFiles(f.videos, "movie.mp4", "thumb.jpg")
+	f.frames.Fail(errors.New("ffmpeg crashed"))
+	if _, err := f.worker.ProcessNext(ctx); err != nil {
+		t.Fatalf("ProcessNext returned error: %v", err)
+	}
+
+	stored := storedVideo(t, f.db, video.ID)
+	if stored.Status != model.VideoStatusReady || !f.storage.Has(stored.ThumbnailURL) {
+		t.Errorf("expected a ready video keeping its thumbnail, got %+v", stored)
+	}
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+	"github.com/yukito/video-platform/internal/totp"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// RecoveryCodeCount is how many recovery codes are generated at a time
+	RecoveryCodeCount = 10
+
+	// recoveryCodeLength is the number of base32 characters of a recovery code, 5 bits each
+	recoveryCodeLength = 10
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTOTPSetupRequired       = errors.New("start TOTP setup before confirming it")
+	ErrInvalidMFACode          = errors.New("invalid authentication code")
+)
+
+// TwoFactorService manages TOTP enrollment and recovery codes and checks second factors
+// A user enables TOTP in two steps: BeginTOTPSetup returns a secret for the authenticator
+// app, and ConfirmTOTP turns it on once a code from the app proves the secret was saved
+type TwoFactorService struct {
+	userRepo     repository.UserStore
+	recoveryRepo repository.RecoveryCodeStore
+	issuer       string // Shown as the account's name in authenticator apps
+	now          func() time.Time
+}
+
+func NewTwoFactorService(userRepo repository.UserStore, recoveryRepo repository.RecoveryCodeStore, issuer string) *TwoFactorService {
+	return &TwoFactorService{
+		userRepo:     userRepo,
+		recoveryRepo: recoveryRepo,
+		issuer:       issuer,
+		now:          time.Now,
+	}
+}
+
+// Status returns whether the user has two-factor authentication and how many recovery codes are left
+func (s *TwoFactorService) Status(ctx context.Context, userID int64) (*model.TwoFactorStatus, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &model.TwoFactorStatus{Enabled: user.TOTPEnabledAt != nil, EnabledAt: user.TOTPEnabledAt}
+	if status.Enabled {
+		status.RecoveryCodesRemaining, err = s.recoveryRepo.CountUnused(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// BeginTOTPSetup generates a new secret for the user; it takes effect once confirmed
+// Starting again replaces the secret of an unconfirmed setup
+func (s *TwoFactorService) BeginTOTPSetup(ctx context.Context, userID int64) (*model.TOTPSetup, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabledAt != nil {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetTOTPSecret(ctx, user.ID, secret); err != nil {
+		return nil, err
+	}
+
+	return &model.TOTPSetup{
+		Secret:          secret,
+		ProvisioningURI: totp.URI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables TOTP with a code from the authenticator app and returns the
+// user's recovery codes, which are not shown again
+func (s *TwoFactorService) ConfirmTOTP(ctx context.Context, userID int64, code string) (*model.RecoveryCodes, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabledAt != nil {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrTOTPSetupRequired
+	}
+
+	step, ok := totp.Validate(user.TOTPSecret, normalizeCode(code), s.now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+	if err := s.userRepo.EnableTOTP(ctx, user.ID, step); err != nil {
+		return nil, err
+	}
+
+	return s.replaceRecoveryCodes(ctx, user.ID)
+}
+
+// Disable turns two-factor authentication off and deletes the recovery codes
+// It needs the password of accounts that have one, and a second factor
+func (s *TwoFactorService) Disable(ctx context.Context, userID int64, req *model.DisableTwoFactorRequest) error {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.PasswordHash != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+			return ErrIncorrectPassword
+		}
+	}
+	if err := s.Verify(ctx, user, req.Code); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.DisableTOTP(ctx, user.ID); err != nil {
+		return err
+	}
+	return s.recoveryRepo.DeleteByUserID(ctx, user.ID)
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes after checking a second factor
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID int64, code string) (*model.RecoveryCodes, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Verify(ctx, user, code); err != nil {
+		return nil, err
+	}
+	return s.replaceRecoveryCodes(ctx, user.ID)
+}
+
+// Verify checks a code from the user's authenticator app or one of their recovery codes
+// Either works only once: a TOTP code cannot be reused and a recovery code is used up
+func (s *TwoFactorService) Verify(ctx context.Context, user *model.User, code string) error {
+	if user.TOTPEnabledAt == nil {
+		return ErrTwoFactorNotEnabled
+	}
+
+	code = normalizeCode(code)
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(user.TOTPSecret, code, s.now())
+		if !ok {
+			return ErrInvalidMFACode
+		}
+		used, err := s.userRepo.UseTOTPStep(ctx, user.ID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	if err := s.recoveryRepo.Consume(ctx, user.ID, hashToken(code)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrInvalidMFACode
+		}
+		return err
+	}
+	return nil
+}
+
+// replaceRecoveryCodes generates new recovery codes for the user; only their hashes are stored
+func (s *TwoFactorService) replaceRecoveryCodes(ctx context.Context, userID int64) (*model.RecoveryCodes, error) {
+	codes := make([]string, RecoveryCodeCount)
+	hashes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		code := newRecoveryCode()
+		codes[i] = code
+		hashes[i] = hashToken(normalizeCode(code))
+	}
+
+	if err := s.recoveryRepo.Replace(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return &model.RecoveryCodes{Codes: codes}, nil
+}
+
+func (s *TwoFactorService) findUser(ctx context.Context, userID int64) (*model.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	return user, nil
+}
+
+// newRecoveryCode returns a random recovery code formatted as "xxxxx-xxxxx"
+func newRecoveryCode() string {
+	code := strings.ToLower(rand.Text()[:recoveryCodeLength])
+	return code[:recoveryCodeLength/2] + "-" + code[recoveryCodeLength/2:]
+}
+
+// normalizeCode removes the separators users may type or paste with a code
+func normalizeCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(code)))
+}
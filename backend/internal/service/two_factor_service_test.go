@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/oidc/oidctest"
+	"github.com/yukito/video-platform/internal/repository/memory"
+	"github.com/yukito/video-platform/internal/totp"
+)
+
+// twoFactorFixture is a user with TOTP enabled and a clock the tests move forward
+type twoFactorFixture struct {
+	auth          *AuthService
+	user          *model.User
+	secret        string
+	recoveryCodes []string
+	now           time.Time
+}
+
+func newTwoFactorFixture(t *testing.T) *twoFactorFixture {
+	t.Helper()
+	f := &twoFactorFixture{
+		auth: newAuthService(memory.NewDB()),
+		now:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	f.auth.twoFactor.now = func() time.Time { return f.now }
+	f.auth.throttle.now = func() time.Time { return f.now }
+	ctx := context.Background()
+
+	f.user = register(t, f.auth, "alice@example.com").User
+	setup, err := f.auth.twoFactor.BeginTOTPSetup(ctx, f.user.ID)
+	if err != nil {
+		t.Fatalf("BeginTOTPSetup returned error: %v", err)
+	}
+	f.secret = setup.Secret
+
+	codes, err := f.auth.twoFactor.ConfirmTOTP(ctx, f.user.ID, f.code())
+	if err != nil {
+		t.Fatalf("ConfirmTOTP returned error: %v", err)
+	}
+	f.recoveryCodes = codes.Codes
+	f.tick()
+	return f
+}
+
+// code returns the current code of the authenticator app
+func (f *twoFactorFixture) code() string {
+	code, _ := totp.Code(f.secret, f.now)
+	return code
+}
+
+// tick moves the clock to the next TOTP period
+func (f *twoFactorFixture) tick() {
+	f.now = f.now.Add(totp.Period)
+}
+
+// login enters the password and returns the MFA token of the challenge
+func (f *twoFactorFixture) login(t *testing.T) string {
+	t.Helper()
+	resp, err := f.auth.Login(context.Background(), &model.LoginRequest{Email: "alice@example.com", Password: "password123"}, testClient)
+	if err != nil {
+		t.Fatalf("Login returned error: %v", err)
+	}
+	if !resp.MFARequired || resp.MFAToken == "" {
+		t.Fatalf("expected an MFA challenge, got %+v", resp)
+	}
+	return resp.MFAToken
+}
+
+func (f *twoFactorFixture) verify(token, code string) (*model.AuthResponse, error) {
+	return f.auth.VerifyMFA(context.Background(), &model.MFALoginRequest{MFAToken: token, Code: code}, testClient)
+}
+
+func TestTwoFactor_LoginRequiresCodeBeforeIssuingTokens(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	ctx := context.Background()
+
+	resp, err := f.auth.Login(ctx, &model.LoginRequest{Email: "alice@example.com", Password: "password123"}, testClient)
+	if err != nil {
+		t.Fatalf("Login returned error: %v", err)
+	}
+	if resp.Token != "" || resp.RefreshToken != "" || resp.User != nil {
+		t.Fatalf("expected no tokens or user before the second factor, got %+v", resp)
+	}
+	if resp.ExpiresIn != int64(MFAChallengeTTL/time.Second) {
+		t.Errorf("expected the challenge to last %v, got %ds", MFAChallengeTTL, resp.ExpiresIn)
+	}
+	if _, err := f.auth.ValidateToken(ctx, resp.MFAToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected the MFA token not to work as an access token, got %v", err)
+	}
+
+	session, err := f.verify(resp.MFAToken, f.code())
+	if err != nil {
+		t.Fatalf("VerifyMFA returned error: %v", err)
+	}
+	if claims, err := f.auth.ValidateToken(ctx, session.Token); err != nil || claims.UserID != f.user.ID {
+		t.Errorf("expected an access token of user %d, got %+v (%v)", f.user.ID, claims, err)
+	}
+	if _, err := f.verify(resp.MFAToken, f.code()); !errors.Is(err, ErrInvalidMFAToken) {
+		t.Errorf("expected the MFA token to work once, got %v", err)
+	}
+}
+
+func TestTwoFactor_CodeCannotBeReused(t *testing.T) {
+	f := newTwoFactorFixture(t)
+
+	code := f.code()
+	if _, err := f.verify(f.login(t), code); err != nil {
+		t.Fatalf("VerifyMFA returned error: %v", err)
+	}
+	if _, err := f.verify(f.login(t), code); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("expected a used code to be rejected, got %v", err)
+	}
+
+	f.tick()
+	if _, err := f.verify(f.login(t), f.code()); err != nil {
+		t.Errorf("expected the next period's code to work, got %v", err)
+	}
+}
+
+func TestTwoFactor_RecoveryCodesWorkOnce(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	ctx := context.Background()
+
+	if len(f.recoveryCodes) != RecoveryCodeCount {
+		t.Fatalf("expected %d recovery codes, got %d", RecoveryCodeCount, len(f.recoveryCodes))
+	}
+	// Codes are accepted however they are typed
+	if _, err := f.verify(f.login(t), " "+f.recoveryCodes[0]+" "); err != nil {
+		t.Fatalf("VerifyMFA with a recovery code returned error: %v", err)
+	}
+	if _, err := f.verify(f.login(t), f.recoveryCodes[0]); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("expected a used recovery code to be rejected, got %v", err)
+	}
+
+	status, err := f.auth.twoFactor.Status(ctx, f.user.ID)
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	if !status.Enabled || status.RecoveryCodesRemaining != RecoveryCodeCount-1 {
+		t.Errorf("expected %d recovery codes left, got %+v", RecoveryCodeCount-1, status)
+	}
+}
+
+func TestTwoFactor_ChallengeExpiresAfterTooManyWrongCodes(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	token := f.login(t)
+
+	// Wrong codes count as failed logins too, so wait out the backoff between them
+	for range MaxMFAAttempts {
+		f.now = f.now.Add(time.Minute)
+		if _, err := f.verify(token, "000000"); !errors.Is(err, ErrInvalidMFACode) {
+			t.Fatalf("expected ErrInvalidMFACode, got %v", err)
+		}
+	}
+	f.now = f.now.Add(time.Minute)
+	if _, err := f.verify(token, f.code()); !errors.Is(err, ErrInvalidMFAToken) {
+		t.Errorf("expected the challenge to stop working, got %v", err)
+	}
+	if _, err := f.verify(f.login(t), f.code()); err != nil {
+		t.Errorf("expected a new login to work, got %v", err)
+	}
+}
+
+func TestTwoFactor_ConfirmRequiresValidCode(t *testing.T) {
+	s := newAuthService(memory.NewDB())
+	ctx := context.Background()
+	user := register(t, s, "alice@example.com").User
+
+	if _, err := s.twoFactor.ConfirmTOTP(ctx, user.ID, "123456"); !errors.Is(err, ErrTOTPSetupRequired) {
+		t.Errorf("expected ErrTOTPSetupRequired, got %v", err)
+	}
+	setup, err := s.twoFactor.BeginTOTPSetup(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("BeginTOTPSetup returned error: %v", err)
+	}
+	if _, err := s.twoFactor.ConfirmTOTP(ctx, user.ID, "not-a-code"); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("expected ErrInvalidMFACode, got %v", err)
+	}
+
+	// Until confirmed, login does not ask for a code
+	resp, err := s.Login(ctx, &model.LoginRequest{Email: "alice@example.com", Password: "password123"}, testClient)
+	if err != nil || resp.MFARequired || resp.Token == "" {
+		t.Errorf("expected tokens without a challenge, got %+v (%v)", resp, err)
+	}
+
+	code, _ := totp.Code(setup.Secret, time.Now())
+	if _, err := s.twoFactor.ConfirmTOTP(ctx, user.ID, code); err != nil {
+		t.Fatalf("ConfirmTOTP returned error: %v", err)
+	}
+	if _, err := s.twoFactor.BeginTOTPSetup(ctx, user.ID); !errors.Is(err, ErrTwoFactorAlreadyEnabled) {
+		t.Errorf("expected ErrTwoFactorAlreadyEnabled, got %v", err)
+	}
+}
+
+func TestTwoFactor_DisableRequiresPasswordAndCode(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	ctx := context.Background()
+
+	err := f.auth.twoFactor.Disable(ctx, f.user.ID, &model.DisableTwoFactorRequest{Password: "wrong-password", Code: f.code()})
+	if !errors.Is(err, ErrIncorrectPassword) {
+		t.Errorf("expected ErrIncorrectPassword, got %v", err)
+	}
+	err = f.auth.twoFactor.Disable(ctx, f.user.ID, &model.DisableTwoFactorRequest{Password: "password123", Code: "000000"})
+	if !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("expected ErrInvalidMFACode, got %v", err)
+	}
+
+	token := f.login(t)
+	err = f.auth.twoFactor.Disable(ctx, f.user.ID, &model.DisableTwoFactorRequest{Password: "password123", Code: f.code()})
+	if err != nil {
+		t.Fatalf("Disable returned error: %v", err)
+	}
+
+	f.tick()
+	if _, err := f.verify(token, f.code()); !errors.Is(err, ErrInvalidMFAToken) {
+		t.Errorf("expected pending challenges to stop working, got %v", err)
+	}
+	resp, err := f.auth.Login(ctx, &model.LoginRequest{Email: "alice@example.com", Password: "password123"}, testClient)
+	if err != nil || resp.MFARequired || resp.Token == "" {
+		t.Errorf("expected tokens without a challenge, got %+v (%v)", resp, err)
+	}
+	if status, _ := f.auth.twoFactor.Status(ctx, f.user.ID); status.Enabled || status.RecoveryCodesRemaining != 0 {
+		t.Errorf("expected two-factor authentication to be off, got %+v", status)
+	}
+}
+
+func TestTwoFactor_RegenerateReplacesRecoveryCodes(t *testing.T) {
+	f := newTwoFactorFixture(t)
+
+	codes, err := f.auth.twoFactor.RegenerateRecoveryCodes(context.Background(), f.user.ID, f.code())
+	if err != nil {
+		t.Fatalf("RegenerateRecoveryCodes returned error: %v", err)
+	}
+	if len(codes.Codes) != RecoveryCodeCount || codes.Codes[0] == f.recoveryCodes[0] {
+		t.Fatalf("expected %d new codes, got %v", RecoveryCodeCount, codes.Codes)
+	}
+
+	if _, err := f.verify(f.login(t), f.recoveryCodes[1]); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("expected old recovery codes to stop working, got %v", err)
+	}
+	if _, err := f.verify(f.login(t), codes.Codes[1]); err != nil {
+		t.Errorf("expected a new recovery code to work, got %v", err)
+	}
+}
+
+func TestTwoFactor_ProviderSignInRequiresCode(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+	user := f.verifiedUser(t, "alice@example.com").User
+
+	setup, err := f.auth.twoFactor.BeginTOTPSetup(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("BeginTOTPSetup returned error: %v", err)
+	}
+	code, _ := totp.Code(setup.Secret, time.Now())
+	if _, err := f.auth.twoFactor.ConfirmTOTP(ctx, user.ID, code); err != nil {
+		t.Fatalf("ConfirmTOTP returned error: %v", err)
+	}
+
+	resp, err := f.oidc.Callback(ctx, f.signIn(t, oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true}), testClient)
+	if err != nil {
+		t.Fatalf("Callback returned error: %v", err)
+	}
+	if !resp.MFARequired || resp.Token != "" {
+		t.Errorf("expected an MFA challenge instead of tokens, got %+v", resp)
+	}
+}
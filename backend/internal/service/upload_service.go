@@ -0,0 +1,441 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/media"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+	"github.com/yukito/video-platform/internal/storage"
+)
+
+const (
+	// MinChunkSize is the smallest chunk accepted before the final one
+	// S3-compatible storage cannot compose parts smaller than 5 MiB
+	MinChunkSize int64 = 5 << 20
+	// MaxChunkSize bounds how much of a request body is read into one part
+	MaxChunkSize int64 = 100 << 20
+	// MaxUploadSize is the largest video file a session can be created for
+	MaxUploadSize int64 = 20 << 30
+	// UploadSessionTTL is how long an idle session is kept before cleanup removes it
+	UploadSessionTTL = 24 * time.Hour
+)
+
+var (
+	ErrUploadNotFound       = errors.New("upload not found")
+	ErrUploadOffsetMismatch = errors.New("upload offset does not match")
+	ErrUploadNotActive      = errors.New("upload is not accepting chunks")
+	ErrUploadIncomplete     = errors.New("upload is incomplete")
+	ErrInvalidChunk         = errors.New("invalid chunk size")
+	ErrUploadTooLarge       = errors.New("file is too large")
+)
+
+// UploadService implements resumable chunked uploads
+// Each chunk is stored as a storage part and recorded in upload_parts, so an
+// upload survives dropped connections and server restarts; completing the
+// session composes the parts into the video file of the session's video,
+// which is created in the uploading status when the session starts
+type UploadService struct {
+	uploadRepo    repository.UploadStore
+	videoRepo     repository.VideoStore
+	transcodeRepo repository.TranscodeStore
+	prober        media.Prober
+	storage       storage.Storage
+
+	minChunkSize int64
+	maxChunkSize int64
+	now          func() time.Time
+}
+
+// NewUploadService creates the service; with a nil prober composed files are stored without being probed
+func NewUploadService(uploadRepo repository.UploadStore, videoRepo repository.VideoStore, transcodeRepo repository.TranscodeStore, prober media.Prober, st storage.Storage) *UploadService {
+	return &UploadService{
+		uploadRepo:    uploadRepo,
+		videoRepo:     videoRepo,
+		transcodeRepo: transcodeRepo,
+		prober:        prober,
+		storage:       st,
+		minChunkSize:  MinChunkSize,
+		maxChunkSize:  MaxChunkSize,
+		now:           time.Now,
+	}
+}
+
+// Create starts a new upload session for the user
+func (s *UploadService) Create(ctx context.Context, userID int64, req *model.CreateUploadRequest) (*model.UploadSession, error) {
+	if req.Size <= 0 {
+		return nil, ErrInvalidChunk
+	}
+	if req.Size > MaxUploadSize {
+		return nil, ErrUploadTooLarge
+	}
+
+	// The video exists from the start so its owner can follow it through every status
+	video := &model.Video{
+		UserID:      userID,
+		Title:       req.Title,
+		Description: req.Description,
+		Status:      model.VideoStatusUploading,
+	}
+	if err := setVisibility(video, req.Visibility, req.PublishAt, s.now()); err != nil {
+		return nil, err
+	}
+
+	video, err := s.videoRepo.Create(ctx, video)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create video: %w", err)
+	}
+
+	session := &model.UploadSession{
+		ID:           uuid.New().String(),
+		UserID:       userID,
+		Filename:     req.Filename,
+		ContentType:  req.ContentType,
+		UploadLength: req.Size,
+		Title:        req.Title,
+		Description:  req.Description,
+		Status:       model.UploadStatusUploading,
+		VideoID:      &video.ID,
+		ExpiresAt:    s.now().Add(UploadSessionTTL),
+	}
+
+	createdSession, err := s.uploadRepo.Create(ctx, session)
+	if err != nil {
+		_ = s.videoRepo.Delete(ctx, video.ID)
+		return nil, fmt.Errorf("failed to create upload: %w", err)
+	}
+
+	return createdSession, nil
+}
+
+// Get returns an upload session owned by the user
+func (s *UploadService) Get(ctx context.Context, userID int64, uploadID string) (*model.UploadSession, error) {
+	session, err := s.uploadRepo.FindByID(ctx, uploadID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, fmt.Errorf("failed to find upload: %w", err)
+	}
+
+	// Other users' sessions are reported as missing
+	if session.UserID != userID {
+		return nil, ErrUploadNotFound
+	}
+
+	return session, nil
+}
+
+// WriteChunk stores size bytes of chunk at offset and returns the updated session
+// offset must equal the session's current offset; a client that lost track
+// of it (e.g. after a dropped connection) reads it again with Get
+func (s *UploadService) WriteChunk(ctx context.Context, userID int64, uploadID string, offset int64, chunk io.Reader, size int64) (*model.UploadSession, error) {
+	session, err := s.Get(ctx, userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Status != model.UploadStatusUploading {
+		return nil, ErrUploadNotActive
+	}
+	if offset != session.UploadOffset {
+		return nil, ErrUploadOffsetMismatch
+	}
+
+	// Only the chunk that ends the file may be smaller than the minimum
+	if size <= 0 || size > s.maxChunkSize || offset+size > session.UploadLength {
+		return nil, ErrInvalidChunk
+	}
+	if offset+size < session.UploadLength && size < s.minChunkSize {
+		return nil, ErrInvalidChunk
+	}
+
+	counter := &countingReader{r: chunk}
+	partNumber := session.PartCount + 1
+	key, err := s.storage.UploadPart(ctx, uploadID, partNumber, io.LimitReader(counter, size), size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store chunk: %w", err)
+	}
+
+	// A short body leaves an orphaned part; it is removed with the session's other parts
+	if counter.n != size {
+		return nil, ErrInvalidChunk
+	}
+
+	updatedSession, err := s.uploadRepo.AddPart(ctx, &model.UploadPart{
+		UploadID:   uploadID,
+		PartNumber: partNumber,
+		PartOffset: offset,
+		Size:       size,
+		StorageKey: key,
+	}, s.now().Add(UploadSessionTTL))
+	if err != nil {
+		if errors.Is(err, repository.ErrUploadConflict) {
+			// Another request wrote this offset first
+			return nil, ErrUploadOffsetMismatch
+		}
+		return nil, fmt.Errorf("failed to record chunk: %w", err)
+	}
+
+	return updatedSession, nil
+}
+
+// Complete composes the uploaded parts into the video file and creates the video
+// Completing an already completed session returns the same video
+func (s *UploadService) Complete(ctx context.Context, userID int64, uploadID string, thumbnailFile io.Reader, thumbnailFilename, thumbnailContentType string, thumbnailSize int64) (*model.Video, error) {
+	session, err := s.Get(ctx, userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Status == model.UploadStatusCompleted && session.VideoID != nil {
+		video, err := s.videoRepo.FindByID(ctx, *session.VideoID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find video: %w", err)
+		}
+		return video, nil
+	}
+	if session.Status != model.UploadStatusUploading {
+		return nil, ErrUploadNotActive
+	}
+	if session.UploadOffset != session.UploadLength {
+		return nil, ErrUploadIncomplete
+	}
+
+	// Claim the session so concurrent completes cannot create two videos
+	claimed, err := s.uploadRepo.UpdateStatus(ctx, uploadID, model.UploadStatusUploading, model.UploadStatusCompleting)
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete upload: %w", err)
+	}
+	if !claimed {
+		return nil, ErrUploadNotActive
+	}
+
+	video, err := s.finalize(ctx, session, thumbnailFile, thumbnailFilename, thumbnailContentType, thumbnailSize)
+	if err != nil {
+		// Let the client retry the completion
+		_, _ = s.uploadRepo.UpdateStatus(ctx, uploadID, model.UploadStatusCompleting, model.UploadStatusUploading)
+		return nil, err
+	}
+
+	// Parts are no longer needed once the video file exists
+	if err := s.storage.DeleteParts(ctx, uploadID); err != nil {
+		log.Printf("Failed to delete parts of upload %s: %v", uploadID, err)
+	}
+
+	return video, nil
+}
+
+func (s *UploadService) finalize(ctx context.Context, session *model.UploadSession, thumbnailFile io.Reader, thumbnailFilename, thumbnailContentType string, thumbnailSize int64) (*model.Video, error) {
+	parts, err := s.uploadRepo.ListParts(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list parts: %w", err)
+	}
+
+	partKeys := make([]string, len(parts))
+	for i, part := range parts {
+		partKeys[i] = part.StorageKey
+	}
+
+	videoURL, err := s.storage.ComposeParts(ctx, partKeys, session.Filename, session.ContentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload video: %w", err)
+	}
+
+	// Probe before the video is stored, so it is never published without its metadata
+	meta, err := s.probe(ctx, videoURL)
+	if err != nil {
+		_ = s.storage.DeleteFile(ctx, videoURL)
+		return nil, err
+	}
+
+	var thumbnailURL string
+	if thumbnailFile != nil {
+		thumbnailURL, err = s.storage.UploadFile(ctx, thumbnailFile, thumbnailFilename, thumbnailContentType, thumbnailSize)
+		if err != nil {
+			_ = s.storage.DeleteFile(ctx, videoURL)
+			return nil, fmt.Errorf("failed to upload thumbnail: %w", err)
+		}
+	}
+
+	video, err := s.storeVideo(ctx, session, videoURL, thumbnailURL, meta)
+	if err != nil {
+		// Cleanup composed files if the database write fails
+		_ = s.storage.DeleteFile(ctx, videoURL)
+		if thumbnailURL != "" {
+			_ = s.storage.DeleteFile(ctx, thumbnailURL)
+		}
+		return nil, err
+	}
+
+	if err := s.uploadRepo.Complete(ctx, session.ID, video.ID); err != nil {
+		// The video has its file; a retry would store it again, so report success
+		log.Printf("Failed to mark upload %s completed: %v", session.ID, err)
+	}
+
+	enqueueTranscode(ctx, s.transcodeRepo, s.videoRepo, video)
+
+	return video, nil
+}
+
+// storeVideo attaches the composed files and their metadata (nil if not probed) to the
+// session's video and queues it for processing
+// Sessions without a video (it was deleted meanwhile) get a new one
+func (s *UploadService) storeVideo(ctx context.Context, session *model.UploadSession, videoURL, thumbnailURL string, meta *media.Metadata) (*model.Video, error) {
+	if session.VideoID != nil {
+		video, err := s.videoRepo.FindByID(ctx, *session.VideoID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("failed to find video: %w", err)
+		}
+		if err == nil {
+			video.VideoURL = videoURL
+			video.ThumbnailURL = thumbnailURL
+			video.Status = model.VideoStatusProcessing
+			video.FailureReason = ""
+			if meta != nil {
+				applyMetadata(video, meta)
+			}
+
+			updatedVideo, err := s.videoRepo.Update(ctx, video)
+			if err != nil {
+				return nil, fmt.Errorf("failed to update video: %w", err)
+			}
+			return updatedVideo, nil
+		}
+	}
+
+	// Sessions without a video predate video visibility, so they publish like they used to
+	video := &model.Video{
+		UserID:       session.UserID,
+		Title:        session.Title,
+		Description:  session.Description,
+		VideoURL:     videoURL,
+		ThumbnailURL: thumbnailURL,
+		ViewCount:    0,
+		Status:       model.VideoStatusProcessing,
+		Visibility:   model.VideoVisibilityPublic,
+	}
+	if meta != nil {
+		applyMetadata(video, meta)
+	}
+	createdVideo, err := s.videoRepo.Create(ctx, video)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create video: %w", err)
+	}
+	return createdVideo, nil
+}
+
+// probe reads the composed video file back and probes it; it returns nil without a prober
+func (s *UploadService) probe(ctx context.Context, videoURL string) (*media.Metadata, error) {
+	if s.prober == nil {
+		return nil, nil
+	}
+
+	file, err := s.storage.OpenFile(ctx, videoURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open video: %w", err)
+	}
+	defer file.Close()
+
+	_, meta, cleanup, err := probeVideo(ctx, s.prober, file)
+	if err != nil {
+		return nil, err
+	}
+	cleanup()
+	return meta, nil
+}
+
+// deleteUploadingVideo removes the session's video while it has no file yet
+func (s *UploadService) deleteUploadingVideo(ctx context.Context, session *model.UploadSession) error {
+	if session.VideoID == nil {
+		return nil
+	}
+
+	video, err := s.videoRepo.FindByID(ctx, *session.VideoID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find video: %w", err)
+	}
+	if video.Status != model.VideoStatusUploading {
+		return nil
+	}
+
+	if err := s.videoRepo.Delete(ctx, video.ID); err != nil {
+		return fmt.Errorf("failed to delete video: %w", err)
+	}
+	return nil
+}
+
+// Abort cancels an upload and removes its parts
+func (s *UploadService) Abort(ctx context.Context, userID int64, uploadID string) error {
+	session, err := s.Get(ctx, userID, uploadID)
+	if err != nil {
+		return err
+	}
+
+	if session.Status == model.UploadStatusCompleting {
+		return ErrUploadNotActive
+	}
+
+	if err := s.storage.DeleteParts(ctx, uploadID); err != nil {
+		return fmt.Errorf("failed to delete parts: %w", err)
+	}
+
+	if err := s.uploadRepo.Delete(ctx, uploadID); err != nil {
+		return fmt.Errorf("failed to delete upload: %w", err)
+	}
+
+	return s.deleteUploadingVideo(ctx, session)
+}
+
+// CleanupExpired removes sessions that have been idle past their expiry, with their parts
+// It returns the number of sessions removed
+func (s *UploadService) CleanupExpired(ctx context.Context) (int, error) {
+	const batchSize = 100
+
+	removed := 0
+	for {
+		sessions, err := s.uploadRepo.FindExpired(ctx, s.now(), batchSize)
+		if err != nil {
+			return removed, fmt.Errorf("failed to find expired uploads: %w", err)
+		}
+
+		for _, session := range sessions {
+			if err := s.storage.DeleteParts(ctx, session.ID); err != nil {
+				return removed, fmt.Errorf("failed to delete parts: %w", err)
+			}
+			if err := s.uploadRepo.Delete(ctx, session.ID); err != nil {
+				return removed, fmt.Errorf("failed to delete upload: %w", err)
+			}
+			if err := s.deleteUploadingVideo(ctx, session); err != nil {
+				return removed, err
+			}
+			removed++
+		}
+
+		if len(sessions) < batchSize {
+			return removed, nil
+		}
+	}
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yukito/video-platform/internal/media"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository/memory"
+	"github.com/yukito/video-platform/internal/storage"
+)
+
+// newUploadService uses a 4 byte minimum chunk so tests can work with tiny files
+func newUploadService(db *memory.DB, st *storage.MemoryStorage) *UploadService {
+	return newUploadServiceWithProber(db, st, media.NewFakeProber())
+}
+
+func newUploadServiceWithProber(db *memory.DB, st *storage.MemoryStorage, prober media.Prober) *UploadService {
+	s := NewUploadService(memory.NewUploadRepository(db), memory.NewVideoRepository(db), memory.NewTranscodeRepository(db), prober, st)
+	s.minChunkSize = 4
+	return s
+}
+
+func createUpload(t *testing.T, s *UploadService, userID int64, size int64) *model.UploadSession {
+	t.Helper()
+	session, err := s.Create(context.Background(), userID, &model.CreateUploadRequest{
+		Filename:    "movie.mp4",
+		ContentType: "video/mp4",
+		Size:        size,
+		Title:       "title",
+		Description: "description",
+	})
+	if err != nil {
+		t.Fatalf("failed to create upload: %v", err)
+	}
+	return session
+}
+
+func writeChunk(s *UploadService, userID int64, uploadID string, offset int64, chunk string) (*model.UploadSession, error) {
+	return s.WriteChunk(context.Background(), userID, uploadID, offset, strings.NewReader(chunk), int64(len(chunk)))
+}
+
+func TestUpload_ChunksAreComposedIntoVideo(t *testing.T) {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	s := newUploadService(db, st)
+	session := createUpload(t, s, 1, 10)
+
+	var offset int64
+	for _, chunk := range []string{"0123", "4567", "89"} {
+		updated, err := writeChunk(s, 1, session.ID, offset, chunk)
+		if err != nil {
+			t.Fatalf("failed to write chunk at %d: %v", offset, err)
+		}
+		offset = updated.UploadOffset
+	}
+	if offset != 10 {
+		t.Fatalf("expected offset 10, got %d", offset)
+	}
+
+	video, err := s.Complete(context.Background(), 1, session.ID, strings.NewReader("thumb"), "thumb.jpg", "image/jpeg", 5)
+	if err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+
+	if video.Title != "title" || video.UserID != 1 {
+		t.Errorf("unexpected video: %+v", video)
+	}
+	if data, _ := st.Get(video.VideoURL); string(data) != "0123456789" {
+		t.Errorf("expected composed video content, got %q", data)
+	}
+	if !st.Has(video.ThumbnailURL) {
+		t.Error("expected thumbnail to be stored")
+	}
+	if files := st.Files(); len(files) != 2 {
+		t.Errorf("expected parts to be removed after completion, files: %v", files)
+	}
+
+	stored, _ := s.Get(context.Background(), 1, session.ID)
+	if stored.Status != model.UploadStatusCompleted || stored.VideoID == nil || *stored.VideoID != video.ID {
+		t.Errorf("expected completed session linked to video %d, got %+v", video.ID, stored)
+	}
+
+	again, err := s.Complete(context.Background(), 1, session.ID, nil, "", "", 0)
+	if err != nil || again.ID != video.ID {
+		t.Errorf("expected repeated complete to return video %d, got %+v, %v", video.ID, again, err)
+	}
+}
+
+func TestUpload_ResumesAfterRestart(t *testing.T) {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	session := createUpload(t, newUploadService(db, st), 1, 8)
+
+	if _, err := writeChunk(newUploadService(db, st), 1, session.ID, 0, "abcd"); err != nil {
+		t.Fatalf("failed to write first chunk: %v", err)
+	}
+
+	// A new service instance only has what was persisted
+	restarted := newUploadService(db, st)
+	current, err := restarted.Get(context.Background(), 1, session.ID)
+	if err != nil || current.UploadOffset != 4 {
+		t.Fatalf("expected offset 4 after restart, got %+v, %v", current, err)
+	}
+
+	if _, err := writeChunk(restarted, 1, session.ID, current.UploadOffset, "efgh"); err != nil {
+		t.Fatalf("failed to resume: %v", err)
+	}
+	video, err := restarted.Complete(context.Background(), 1, session.ID, nil, "", "", 0)
+	if err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+	if data, _ := st.Get(video.VideoURL); string(data) != "abcdefgh" {
+		t.Errorf("expected resumed content, got %q", data)
+	}
+}
+
+func TestUpload_RejectsOffsetMismatch(t *testing.T) {
+	db := memory.NewDB()
+	s := newUploadService(db, storage.NewMemoryStorage())
+	session := createUpload(t, s, 1, 8)
+
+	if _, err := writeChunk(s, 1, session.ID, 4, "efgh"); !errors.Is(err, ErrUploadOffsetMismatch) {
+		t.Fatalf("expected ErrUploadOffsetMismatch for a gap, got %v", err)
+	}
+
+	_, _ = writeChunk(s, 1, session.ID, 0, "abcd")
+	if _, err := writeChunk(s, 1, session.ID, 0, "abcd"); !errors.Is(err, ErrUploadOffsetMismatch) {
+		t.Fatalf("expected ErrUploadOffsetMismatch for a repeated chunk, got %v", err)
+	}
+}
+
+func TestUpload_RejectsInvalidChunks(t *testing.T) {
+	db := memory.NewDB()
+	s := newUploadService(db, storage.NewMemoryStorage())
+	session := createUpload(t, s, 1, 10)
+
+	if _, err := writeChunk(s, 1, session.ID, 0, "ab"); !errors.Is(err, ErrInvalidChunk) {
+		t.Errorf("expected a short non-final chunk to be rejected, got %v", err)
+	}
+	if _, err := writeChunk(s, 1, session.ID, 0, "0123456789X"); !errors.Is(err, ErrInvalidChunk) {
+		t.Errorf("expected a chunk past the end of the file to be rejected, got %v", err)
+	}
+
+	// Declared size larger than the body that actually arrived
+	_, err := s.WriteChunk(context.Background(), 1, session.ID, 0, strings.NewReader("0123"), 6)
+	if !errors.Is(err, ErrInvalidChunk) {
+		t.Errorf("expected a truncated body to be rejected, got %v", err)
+	}
+
+	current, _ := s.Get(context.Background(), 1, session.ID)
+	if current.UploadOffset != 0 {
+		t.Errorf("expected offset to stay 0, got %d", current.UploadOffset)
+	}
+}
+
+func TestUpload_CompleteRequiresAllBytes(t *testing.T) {
+	db := memory.NewDB()
+	s := newUploadService(db, storage.NewMemoryStorage())
+	session := createUpload(t, s, 1, 8)
+	_, _ = writeChunk(s, 1, session.ID, 0, "abcd")
+
+	_, err := s.Complete(context.Background(), 1, session.ID, nil, "", "", 0)
+	if !errors.Is(err, ErrUploadIncomplete) {
+		t.Fatalf("expected ErrUploadIncomplete, got %v", err)
+	}
+}
+
+func TestUpload_CompleteDatabaseFailureCanBeRetried(t *testing.T) {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	s := newUploadService(db, st)
+	session := createUpload(t, s, 1, 4)
+	_, _ = writeChunk(s, 1, session.ID, 0, "abcd")
+
+	db.Fail("VideoRepository.Update", errors.New("db down"))
+	if _, err := s.Complete(context.Background(), 1, session.ID, nil, "", "", 0); err == nil {
+		t.Fatal("expected Complete to fail")
+	}
+	for _, url := range st.Uploads() {
+		if st.Has(url) {
+			t.Errorf("expected composed file %s to be removed", url)
+		}
+	}
+
+	db.Fail("VideoRepository.Update", nil)
+	video, err := s.Complete(context.Background(), 1, session.ID, nil, "", "", 0)
+	if err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if data, _ := st.Get(video.VideoURL); string(data) != "abcd" {
+		t.Errorf("unexpected video content %q", data)
+	}
+}
+
+func TestUpload_CompleteProbesComposedFile(t *testing.T) {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	prober := media.NewFakeProber()
+	s := newUploadServiceWithProber(db, st, prober)
+	session := createUpload(t, s, 1, 4)
+	_, _ = writeChunk(s, 1, session.ID, 0, "abcd")
+
+	prober.Fail(media.ErrNotVideo)
+	if _, err := s.Complete(context.Background(), 1, session.ID, nil, "", "", 0); !errors.Is(err, ErrInvalidVideo) {
+		t.Fatalf("expected ErrInvalidVideo, got %v", err)
+	}
+	for _, url := range st.Uploads() {
+		if st.Has(url) {
+			t.Errorf("expected composed file %s to be removed", url)
+		}
+	}
+
+	// A video published because transcoding could not be queued still has its metadata
+	prober.Fail(nil)
+	db.Fail("TranscodeRepository.CreateJob", errors.New("db down"))
+	video, err := s.Complete(context.Background(), 1, session.ID, nil, "", "", 0)
+	if err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+	stored := storedVideo(t, db, video.ID)
+	if stored.Status != model.VideoStatusReady || stored.Duration != 10 || stored.Width != 1280 || stored.VideoCodec != "h264" {
+		t.Errorf("expected a published video with probed metadata, got %+v", stored)
+	}
+}
+
+func TestUpload_HiddenFromOtherUsers(t *testing.T) {
+	db := memory.NewDB()
+	s := newUploadService(db, storage.NewMemoryStorage())
+	session := createUpload(t, s, 1, 4)
+
+	if _, err := s.Get(context.Background(), 2, session.ID); !errors.Is(err, ErrUploadNotFound) {
+		t.Errorf("expected ErrUploadNotFound for another user, got %v", err)
+	}
+	if _, err := writeChunk(s, 2, session.ID, 0, "abcd"); !errors.Is(err, ErrUploadNotFound) {
+		t.Errorf("expected another user's chunk to be rejected, got %v", err)
+	}
+	if err := s.Abort(context.Background(), 2, session.ID); !errors.Is(err, ErrUploadNotFound) {
+		t.Errorf("expected another user's abort to be rejected, got %v", err)
+	}
+}
+
+func TestUpload_AbortAndCleanupRemoveParts(t *testing.T) {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	s := newUploadService(db, st)
+
+	aborted := createUpload(t, s, 1, 8)
+	_, _ = writeChunk(s, 1, aborted.ID, 0, "abcd")
+	if err := s.Abort(context.Background(), 1, aborted.ID); err != nil {
+		t.Fatalf("Abort returned error: %v", err)
+	}
+	if _, err := s.Get(context.Background(), 1, aborted.ID); !errors.Is(err, ErrUploadNotFound) {
+		t.Errorf("expected aborted upload to be gone, got %v", err)
+	}
+
+	expired := createUpload(t, s, 1, 8)
+	_, _ = writeChunk(s, 1, expired.ID, 0, "abcd")
+	if len(st.Files()) != 1 {
+		t.Fatalf("expected one stored part, got %v", st.Files())
+	}
+
+	s.now = func() time.Time { return time.Now().Add(UploadSessionTTL + time.Minute) }
+	removed, err := s.CleanupExpired(context.Background())
+	if err != nil || removed != 1 {
+		t.Fatalf("expected one expired upload removed, got %d, %v", removed, err)
+	}
+	if files := st.Files(); len(files) != 0 {
+		t.Errorf("expected parts to be removed, files: %v", files)
+	}
+}
+
+func TestUpload_VideoFollowsSessionStatus(t *testing.T) {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	s := newUploadService(db, st)
+	videos := newVideoService(db, st)
+	ctx := context.Background()
+
+	session := createUpload(t, s, 1, 4)
+	if session.VideoID == nil {
+		t.Fatal("expected the session to be linked to a video")
+	}
+	if status, err := videos.GetStatus(ctx, 1, *session.VideoID); err != nil || status.Status != model.VideoStatusUploading {
+		t.Fatalf("expected uploading video, got %+v, %v", status, err)
+	}
+	if list, _ := videos.List(ctx, 2, model.PageQuery{}); len(list.Items) != 0 {
+		t.Errorf("expected uploading video to be hidden from other users, got %d", len(list.Items))
+	}
+
+	_, _ = writeChunk(s, 1, session.ID, 0, "abcd")
+	video, err := s.Complete(ctx, 1, session.ID, nil, "", "", 0)
+	if err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+	if video.ID != *session.VideoID || video.Status != model.VideoStatusProcessing {
+		t.Errorf("expected video %d to be processing, got %+v", *session.VideoID, video)
+	}
+
+	aborted := createUpload(t, s, 1, 4)
+	if err := s.Abort(ctx, 1, aborted.ID); err != nil {
+		t.Fatalf("Abort returned error: %v", err)
+	}
+	if _, err := videos.GetStatus(ctx, 1, *aborted.VideoID); !errors.Is(err, ErrVideoNotFound) {
+		t.Errorf("expected the aborted upload's video to be removed, got %v", err)
+	}
+}
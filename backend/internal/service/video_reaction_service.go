@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+var ErrInvalidReaction = errors.New("like_type must be 'like' or 'dislike'")
+
+type VideoReactionService struct {
+	reactionRepo repository.VideoReactionStore
+	videoRepo    repository.VideoStore
+}
+
+func NewVideoReactionService(reactionRepo repository.VideoReactionStore, videoRepo repository.VideoStore) *VideoReactionService {
+	return &VideoReactionService{
+		reactionRepo: reactionRepo,
+		videoRepo:    videoRepo,
+	}
+}
+
+// React likes or dislikes a video, replacing the user's previous reaction
+func (s *VideoReactionService) React(ctx context.Context, userID, videoID int64, likeType string) error {
+	if likeType != model.VideoReactionLike && likeType != model.VideoReactionDislike {
+		return ErrInvalidReaction
+	}
+
+	// Check if video exists and the user can watch it
+	video, err := s.videoRepo.FindByID(ctx, videoID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrVideoNotFound
+		}
+		return fmt.Errorf("failed to find video: %w", err)
+	}
+	if !canWatch(video, userID) {
+		return ErrVideoNotFound
+	}
+
+	if err := s.reactionRepo.SetReaction(ctx, videoID, userID, likeType); err != nil {
+		return fmt.Errorf("failed to react to video: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveReaction takes back the user's like or dislike
+func (s *VideoReactionService) RemoveReaction(ctx context.Context, userID, videoID int64) error {
+	if err := s.reactionRepo.RemoveReaction(ctx, videoID, userID); err != nil {
+		return fmt.Errorf("failed to remove reaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetReaction returns the user's reaction to a video: "like", "dislike" or "" if there is none
+func (s *VideoReactionService) GetReaction(ctx context.Context, userID, videoID int64) (string, error) {
+	reaction, err := s.reactionRepo.FindReaction(ctx, videoID, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get reaction: %w", err)
+	}
+	if reaction == nil {
+		return "", nil
+	}
+
+	return reaction.LikeType, nil
+}
+
+// GetLikedVideos returns the videos the user has liked, most recently liked first
+func (s *VideoReactionService) GetLikedVideos(ctx context.Context, userID int64, query model.PageQuery) (*model.Page[*model.LikedVideo], error) {
+	limit, page, err := pageRequest(query, DefaultPageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	videos, err := s.reactionRepo.FindLikedVideos(ctx, userID, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get liked videos: %w", err)
+	}
+
+	return newPage(videos, limit, func(liked *model.LikedVideo) model.PageCursor {
+		return model.PageCursor{Time: liked.LikedAt, ID: liked.VideoID}
+	}), nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository/memory"
+	"github.com/yukito/video-platform/internal/storage"
+)
+
+func newVideoReactionService(db *memory.DB) *VideoReactionService {
+	return NewVideoReactionService(memory.NewVideoReactionRepository(db), memory.NewVideoRepository(db))
+}
+
+func TestVideoReactions_CountersFollowReactions(t *testing.T) {
+	db := memory.NewDB()
+	videos := newVideoService(db, storage.NewMemoryStorage())
+	reactions := newVideoReactionService(db)
+	ctx := context.Background()
+
+	video, err := videos.Create(ctx, 1, &model.CreateVideoRequest{Title: "video"})
+	if err != nil {
+		t.Fatalf("failed to create video: %v", err)
+	}
+
+	// A playlist with the old "Liked Videos" title is just a playlist
+	playlists := NewPlaylistService(memory.NewPlaylistRepository(db), memory.NewVideoRepository(db), memory.NewProfileRepository(db))
+	playlist, _ := playlists.Create(ctx, 5, &model.CreatePlaylistRequest{Title: "高く評価した動画"})
+	if err := playlists.AddVideo(ctx, 5, playlist.ID, video.ID); err != nil {
+		t.Fatalf("AddVideo returned error: %v", err)
+	}
+
+	for userID, likeType := range map[int64]string{2: "like", 3: "like", 4: "dislike"} {
+		if err := reactions.React(ctx, userID, video.ID, likeType); err != nil {
+			t.Fatalf("React returned error: %v", err)
+		}
+	}
+	// Reacting the same way twice counts once; switching moves the count
+	if err := reactions.React(ctx, 2, video.ID, model.VideoReactionLike); err != nil {
+		t.Fatalf("React returned error: %v", err)
+	}
+	if err := reactions.React(ctx, 3, video.ID, model.VideoReactionDislike); err != nil {
+		t.Fatalf("React returned error: %v", err)
+	}
+
+	got, err := videos.GetByID(ctx, 2, video.ID)
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+	if got.LikeCount != 1 || got.DislikeCount != 2 {
+		t.Errorf("expected 1 like and 2 dislikes, got %d and %d", got.LikeCount, got.DislikeCount)
+	}
+	if likeType, _ := reactions.GetReaction(ctx, 3, video.ID); likeType != model.VideoReactionDislike {
+		t.Errorf("expected user 3 to dislike the video, got %q", likeType)
+	}
+
+	if err := reactions.RemoveReaction(ctx, 2, video.ID); err != nil {
+		t.Fatalf("RemoveReaction returned error: %v", err)
+	}
+	// Removing a reaction that does not exist is not an error
+	if err := reactions.RemoveReaction(ctx, 2, video.ID); err != nil {
+		t.Fatalf("RemoveReaction returned error for a missing reaction: %v", err)
+	}
+	if stored := storedVideo(t, db, video.ID); stored.LikeCount != 0 || stored.DislikeCount != 2 {
+		t.Errorf("expected 0 likes and 2 dislikes, got %d and %d", stored.LikeCount, stored.DislikeCount)
+	}
+	if likeType, _ := reactions.GetReaction(ctx, 2, video.ID); likeType != "" {
+		t.Errorf("expected no reaction after removing it, got %q", likeType)
+	}
+}
+
+func TestVideoReactions_RejectsInvalidReactions(t *testing.T) {
+	db := memory.NewDB()
+	videos := newVideoService(db, storage.NewMemoryStorage())
+	reactions := newVideoReactionService(db)
+	ctx := context.Background()
+
+	private, _ := videos.Create(ctx, 1, &model.CreateVideoRequest{Title: "private", Visibility: model.VideoVisibilityPrivate})
+
+	if err := reactions.React(ctx, 2, private.ID, "love"); !errors.Is(err, ErrInvalidReaction) {
+		t.Errorf("expected ErrInvalidReaction, got %v", err)
+	}
+	if err := reactions.React(ctx, 2, private.ID, model.VideoReactionLike); !errors.Is(err, ErrVideoNotFound) {
+		t.Errorf("expected ErrVideoNotFound liking a private video, got %v", err)
+	}
+	if err := reactions.React(ctx, 2, 999, model.VideoReactionLike); !errors.Is(err, ErrVideoNotFound) {
+		t.Errorf("expected ErrVideoNotFound liking a missing video, got %v", err)
+	}
+	if err := reactions.React(ctx, 1, private.ID, model.VideoReactionLike); err != nil {
+		t.Errorf("expected the owner to like their private video, got %v", err)
+	}
+}
+
+func TestVideoReactions_LikedVideos(t *testing.T) {
+	db := memory.NewDB()
+	videos := newVideoService(db, storage.NewMemoryStorage())
+	reactions := newVideoReactionService(db)
+	ctx := context.Background()
+
+	first, _ := videos.Create(ctx, 1, &model.CreateVideoRequest{Title: "first"})
+	second, _ := videos.Create(ctx, 1, &model.CreateVideoRequest{Title: "second"})
+	disliked, _ := videos.Create(ctx, 1, &model.CreateVideoRequest{Title: "disliked"})
+	later, _ := videos.Create(ctx, 1, &model.CreateVideoRequest{Title: "made private later"})
+
+	for _, video := range []*model.Video{first, second, later} {
+		if err := reactions.React(ctx, 2, video.ID, model.VideoReactionLike); err != nil {
+			t.Fatalf("React returned error: %v", err)
+		}
+	}
+	_ = reactions.React(ctx, 2, disliked.ID, model.VideoReactionDislike)
+	// Liking again after a dislike moves the video to the top
+	_ = reactions.React(ctx, 2, first.ID, model.VideoReactionDislike)
+	_ = reactions.React(ctx, 2, first.ID, model.VideoReactionLike)
+	_, _ = videos.Update(ctx, asUser(1), later.ID, &model.UpdateVideoRequest{Visibility: model.VideoVisibilityPrivate})
+
+	page, err := reactions.GetLikedVideos(ctx, 2, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetLikedVideos returned error: %v", err)
+	}
+	liked := page.Items
+	if len(liked) != 2 || liked[0].VideoID != first.ID || liked[1].VideoID != second.ID {
+		t.Fatalf("expected the first and second videos, most recently liked first, got %+v", liked)
+	}
+	if liked[0].Video == nil || liked[0].Video.LikeCount != 1 {
+		t.Errorf("expected the liked video with its like count, got %+v", liked[0].Video)
+	}
+
+	if err := videos.Delete(ctx, asUser(1), second.ID); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	if page, _ := reactions.GetLikedVideos(ctx, 2, model.PageQuery{}); len(page.Items) != 1 {
+		t.Errorf("expected a deleted video to drop out of the liked videos, got %d", len(page.Items))
+	}
+}
@@ -5,23 +5,51 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/media"
 	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/policy"
 	"github.com/yukito/video-platform/internal/repository"
 	"github.com/yukito/video-platform/internal/storage"
 )
 
+var (
+	ErrVideoNotFound = errors.New("video not found")
+	ErrNotVideoOwner = errors.New("not the owner of this video")
+	ErrInvalidVideo  = errors.New("file is not a decodable video")
+
+	ErrInvalidVisibility = errors.New("visibility must be public, unlisted or private")
+	ErrInvalidPublishAt  = errors.New("publish_at must be in the future and can only schedule a public release")
+
+	ErrThumbnailNotFound = errors.New("thumbnail not found")
+	ErrInvalidThumbnail  = errors.New("thumbnail must be an image")
+)
+
 type VideoService struct {
-	videoRepo   *repository.VideoRepository
-	profileRepo *repository.ProfileRepository
-	storage     storage.Storage
+	videoRepo     repository.VideoStore
+	profileRepo   repository.ProfileStore
+	transcodeRepo repository.TranscodeStore
+	thumbnailRepo repository.ThumbnailStore
+	historyRepo   repository.WatchHistoryStore
+	prober        media.Prober
+	storage       storage.Storage
 }
 
-func NewVideoService(videoRepo *repository.VideoRepository, profileRepo *repository.ProfileRepository, st storage.Storage) *VideoService {
+// NewVideoService creates the service; with a nil prober uploads are stored without being probed
+func NewVideoService(videoRepo repository.VideoStore, profileRepo repository.ProfileStore, transcodeRepo repository.TranscodeStore, thumbnailRepo repository.ThumbnailStore, historyRepo repository.WatchHistoryStore, prober media.Prober, st storage.Storage) *VideoService {
 	return &VideoService{
-		videoRepo:   videoRepo,
-		profileRepo: profileRepo,
-		storage:     st,
+		videoRepo:     videoRepo,
+		profileRepo:   profileRepo,
+		transcodeRepo: transcodeRepo,
+		thumbnailRepo: thumbnailRepo,
+		historyRepo:   historyRepo,
+		prober:        prober,
+		storage:       st,
 	}
 }
 
@@ -33,6 +61,11 @@ func (s *VideoService) Create(ctx context.Context, userID int64, req *model.Crea
 		VideoURL:     req.VideoURL,
 		ThumbnailURL: req.ThumbnailURL,
 		ViewCount:    0,
+		// The URL points at an already hosted file, so there is nothing to process
+		Status: model.VideoStatusReady,
+	}
+	if err := setVisibility(video, req.Visibility, req.PublishAt, time.Now()); err != nil {
+		return nil, err
 	}
 
 	createdVideo, err := s.videoRepo.Create(ctx, video)
@@ -43,12 +76,32 @@ func (s *VideoService) Create(ctx context.Context, userID int64, req *model.Crea
 	return createdVideo, nil
 }
 
-func (s *VideoService) CreateWithFiles(ctx context.Context, userID int64, title, description string, duration int64, videoFile io.Reader, videoFilename, videoContentType string, videoSize int64, thumbnailFile io.Reader, thumbnailFilename, thumbnailContentType string, thumbnailSize int64) (*model.Video, error) {
+// CreateWithFiles stores an uploaded video; its duration and other metadata are probed from the file
+func (s *VideoService) CreateWithFiles(ctx context.Context, userID int64, title, description, visibility string, publishAt *time.Time, videoFile io.Reader, videoFilename, videoContentType string, videoSize int64, thumbnailFile io.Reader, thumbnailFilename, thumbnailContentType string, thumbnailSize int64) (*model.Video, error) {
 	var videoURL, thumbnailURL string
+	var meta *media.Metadata
 	var err error
 
+	video := &model.Video{
+		UserID:      userID,
+		Title:       title,
+		Description: description,
+		ViewCount:   0,
+		Status:      model.VideoStatusProcessing,
+	}
+	if err := setVisibility(video, visibility, publishAt, time.Now()); err != nil {
+		return nil, err
+	}
+
 	// Upload video file
 	if videoFile != nil {
+		var cleanup func()
+		videoFile, meta, cleanup, err = s.probeUpload(ctx, videoFile)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+
 		videoURL, err = s.storage.UploadFile(ctx, videoFile, videoFilename, videoContentType, videoSize)
 		if err != nil {
 			return nil, fmt.Errorf("failed to upload video: %w", err)
@@ -67,14 +120,13 @@ func (s *VideoService) CreateWithFiles(ctx context.Context, userID int64, title,
 		}
 	}
 
-	video := &model.Video{
-		UserID:       userID,
-		Title:        title,
-		Description:  description,
-		VideoURL:     videoURL,
-		ThumbnailURL: thumbnailURL,
-		Duration:     duration,
-		ViewCount:    0,
+	video.VideoURL = videoURL
+	video.ThumbnailURL = thumbnailURL
+	if meta != nil {
+		applyMetadata(video, meta)
+	}
+	if videoURL == "" {
+		video.Status = model.VideoStatusReady
 	}
 
 	createdVideo, err := s.videoRepo.Create(ctx, video)
@@ -89,13 +141,22 @@ func (s *VideoService) CreateWithFiles(ctx context.Context, userID int64, title,
 		return nil, fmt.Errorf("failed to create video: %w", err)
 	}
 
+	enqueueTranscode(ctx, s.transcodeRepo, s.videoRepo, createdVideo)
+
 	return createdVideo, nil
 }
 
-func (s *VideoService) GetByID(ctx context.Context, id int64) (*model.VideoWithProfile, error) {
-	video, err := s.videoRepo.FindByID(ctx, id)
+// GetByID returns a video for viewerID (0 for anonymous viewers)
+// Videos that are not ready yet or private are only visible to their owner
+// Reading a video does not count a view; players report views to ViewService
+// Signed-in viewers also get the position to resume playback from
+func (s *VideoService) GetByID(ctx context.Context, viewerID, id int64) (*model.VideoWithProfile, error) {
+	video, err := s.findVideo(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find video: %w", err)
+		return nil, err
+	}
+	if !canWatch(video, viewerID) {
+		return nil, ErrVideoNotFound
 	}
 
 	// Get profile
@@ -105,15 +166,6 @@ func (s *VideoService) GetByID(ctx context.Context, id int64) (*model.VideoWithP
 		profile = nil
 	}
 
-	// Get like count
-	likeCount, err := s.videoRepo.GetLikeCount(ctx, id)
-	if err != nil {
-		likeCount = 0
-	}
-
-	// Increment view count
-	_ = s.videoRepo.IncrementViewCount(ctx, id)
-
 	videoWithProfile := &model.VideoWithProfile{
 		ID:           video.ID,
 		UserID:       video.UserID,
@@ -123,34 +175,61 @@ func (s *VideoService) GetByID(ctx context.Context, id int64) (*model.VideoWithP
 		ThumbnailURL: video.ThumbnailURL,
 		Duration:     video.Duration,
 		ViewCount:    video.ViewCount,
-		LikeCount:    likeCount,
+		LikeCount:    video.LikeCount,
+		DislikeCount: video.DislikeCount,
+		Width:        video.Width,
+		Height:       video.Height,
+		Status:       video.Status,
+		Visibility:   video.Visibility,
 		CreatedAt:    video.CreatedAt,
 		UpdatedAt:    video.UpdatedAt,
 		Profile:      profile,
 	}
 
+	// Attach HLS renditions once transcoding has finished
+	if job, err := s.transcodeRepo.FindLatestCompletedJob(ctx, id); err == nil {
+		renditions, err := s.transcodeRepo.FindRenditions(ctx, id)
+		if err == nil && len(renditions) > 0 {
+			videoWithProfile.HLSURL = job.MasterPlaylistURL
+			videoWithProfile.Renditions = renditions
+		}
+	}
+
+	// A missing resume position only means playback starts from the beginning
+	if viewerID != 0 {
+		if progress, err := s.historyRepo.FindProgress(ctx, viewerID, id); err == nil && progress != nil {
+			position := resumePosition(progress)
+			videoWithProfile.ResumePositionSeconds = &position
+		}
+	}
+
 	return videoWithProfile, nil
 }
 
-func (s *VideoService) List(ctx context.Context, limit, offset int) ([]*model.VideoWithProfile, error) {
-	videos, err := s.videoRepo.FindAll(ctx, limit, offset)
+// List returns a page of ready public videos, plus the viewer's own videos in any status and visibility, newest first
+func (s *VideoService) List(ctx context.Context, viewerID int64, query model.PageQuery) (*model.Page[*model.VideoWithProfile], error) {
+	limit, page, err := pageRequest(query, DefaultVideoPageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	videos, err := s.videoRepo.FindAll(ctx, viewerID, page)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find videos: %w", err)
 	}
 
-	// Get profiles and like counts for all videos
-	videosWithProfile := make([]*model.VideoWithProfile, len(videos))
+	// Load the profiles of all owners at once; like counts are stored on the videos
+	userIDs := make([]int64, len(videos))
 	for i, video := range videos {
-		profile, err := s.profileRepo.FindByUserID(ctx, video.UserID)
-		if err != nil {
-			profile = nil
-		}
-
-		likeCount, err := s.videoRepo.GetLikeCount(ctx, video.ID)
-		if err != nil {
-			likeCount = 0
-		}
+		userIDs[i] = video.UserID
+	}
+	profiles, err := findProfiles(ctx, s.profileRepo, userIDs)
+	if err != nil {
+		return nil, err
+	}
 
+	videosWithProfile := make([]*model.VideoWithProfile, len(videos))
+	for i, video := range videos {
 		videosWithProfile[i] = &model.VideoWithProfile{
 			ID:           video.ID,
 			UserID:       video.UserID,
@@ -160,25 +239,25 @@ func (s *VideoService) List(ctx context.Context, limit, offset int) ([]*model.Vi
 			ThumbnailURL: video.ThumbnailURL,
 			Duration:     video.Duration,
 			ViewCount:    video.ViewCount,
-			LikeCount:    likeCount,
+			LikeCount:    video.LikeCount,
+			DislikeCount: video.DislikeCount,
+			Width:        video.Width,
+			Height:       video.Height,
+			Status:       video.Status,
+			Visibility:   video.Visibility,
 			CreatedAt:    video.CreatedAt,
 			UpdatedAt:    video.UpdatedAt,
-			Profile:      profile,
+			Profile:      profiles[video.UserID],
 		}
 	}
 
-	return videosWithProfile, nil
+	return newPage(videosWithProfile, limit, videoWithProfileKey), nil
 }
 
-func (s *VideoService) Update(ctx context.Context, userID, videoID int64, req *model.UpdateVideoRequest) (*model.Video, error) {
-	// Check if video exists and belongs to user
-	existingVideo, err := s.videoRepo.FindByID(ctx, videoID)
+func (s *VideoService) Update(ctx context.Context, actor policy.Actor, videoID int64, req *model.UpdateVideoRequest) (*model.Video, error) {
+	existingVideo, err := s.findEditableVideo(ctx, actor, videoID)
 	if err != nil {
-		return nil, fmt.Errorf("video not found: %w", err)
-	}
-
-	if existingVideo.UserID != userID {
-		return nil, errors.New("unauthorized to update this video")
+		return nil, err
 	}
 
 	// Update only provided fields
@@ -194,6 +273,9 @@ func (s *VideoService) Update(ctx context.Context, userID, videoID int64, req *m
 	if req.ThumbnailURL != "" {
 		existingVideo.ThumbnailURL = req.ThumbnailURL
 	}
+	if err := setVisibility(existingVideo, req.Visibility, req.PublishAt, time.Now()); err != nil {
+		return nil, err
+	}
 
 	updatedVideo, err := s.videoRepo.Update(ctx, existingVideo)
 	if err != nil {
@@ -203,15 +285,13 @@ func (s *VideoService) Update(ctx context.Context, userID, videoID int64, req *m
 	return updatedVideo, nil
 }
 
-func (s *VideoService) UpdateWithFiles(ctx context.Context, userID, videoID int64, title, description string, videoFile io.Reader, videoFilename, videoContentType string, videoSize int64, thumbnailFile io.Reader, thumbnailFilename, thumbnailContentType string, thumbnailSize int64) (*model.Video, error) {
-	// Check if video exists and belongs to user
-	existingVideo, err := s.videoRepo.FindByID(ctx, videoID)
+func (s *VideoService) UpdateWithFiles(ctx context.Context, actor policy.Actor, videoID int64, title, description, visibility string, publishAt *time.Time, videoFile io.Reader, videoFilename, videoContentType string, videoSize int64, thumbnailFile io.Reader, thumbnailFilename, thumbnailContentType string, thumbnailSize int64) (*model.Video, error) {
+	existingVideo, err := s.findEditableVideo(ctx, actor, videoID)
 	if err != nil {
-		return nil, fmt.Errorf("video not found: %w", err)
+		return nil, err
 	}
-
-	if existingVideo.UserID != userID {
-		return nil, errors.New("unauthorized to update this video")
+	if err := setVisibility(existingVideo, visibility, publishAt, time.Now()); err != nil {
+		return nil, err
 	}
 
 	// Store old URLs for cleanup
@@ -220,6 +300,17 @@ func (s *VideoService) UpdateWithFiles(ctx context.Context, userID, videoID int6
 
 	// Upload new video file if provided
 	if videoFile != nil {
+		probed, meta, cleanup, err := s.probeUpload(ctx, videoFile)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		videoFile = probed
+
+		if meta != nil {
+			applyMetadata(existingVideo, meta)
+		}
+
 		newVideoURL, err := s.storage.UploadFile(ctx, videoFile, videoFilename, videoContentType, videoSize)
 		if err != nil {
 			return nil, fmt.Errorf("failed to upload video: %w", err)
@@ -240,6 +331,12 @@ func (s *VideoService) UpdateWithFiles(ctx context.Context, userID, videoID int6
 		existingVideo.ThumbnailURL = newThumbnailURL
 	}
 
+	// A new file has to be processed again; a video that is already live stays visible meanwhile
+	if videoFile != nil && existingVideo.Status != model.VideoStatusReady {
+		existingVideo.Status = model.VideoStatusProcessing
+		existingVideo.FailureReason = ""
+	}
+
 	// Update text fields
 	existingVideo.Title = title
 	existingVideo.Description = description
@@ -261,27 +358,365 @@ func (s *VideoService) UpdateWithFiles(ctx context.Context, userID, videoID int6
 	if videoFile != nil && oldVideoURL != "" {
 		_ = s.storage.DeleteFile(ctx, oldVideoURL)
 	}
-	if thumbnailFile != nil && oldThumbnailURL != "" {
-		_ = s.storage.DeleteFile(ctx, oldThumbnailURL)
+
+	// The renditions of the old file are replaced once the new one is transcoded
+	if videoFile != nil {
+		enqueueTranscode(ctx, s.transcodeRepo, s.videoRepo, updatedVideo)
+	}
+	if thumbnailFile != nil {
+		s.releaseThumbnail(ctx, videoID, oldThumbnailURL)
 	}
 
 	return updatedVideo, nil
 }
 
-func (s *VideoService) Delete(ctx context.Context, userID, videoID int64) error {
-	// Check if video exists and belongs to user
-	existingVideo, err := s.videoRepo.FindByID(ctx, videoID)
+// Delete deletes a video; its owner and moderators can delete it
+func (s *VideoService) Delete(ctx context.Context, actor policy.Actor, videoID int64) error {
+	existingVideo, err := s.findVideo(ctx, videoID)
 	if err != nil {
-		return fmt.Errorf("video not found: %w", err)
+		return err
 	}
-
-	if existingVideo.UserID != userID {
-		return errors.New("unauthorized to delete this video")
+	if !policy.CanDeleteVideo(actor, existingVideo) {
+		return ErrNotVideoOwner
 	}
 
+	files, err := storedVideoFiles(ctx, s.thumbnailRepo, s.transcodeRepo, existingVideo)
+	if err != nil {
+		return err
+	}
 	if err := s.videoRepo.Delete(ctx, videoID); err != nil {
 		return fmt.Errorf("failed to delete video: %w", err)
 	}
+	deleteVideoFiles(s.storage, videoID, files)
 
 	return nil
 }
+
+// storedVideoFiles returns the URLs of everything stored for a video: its file, its thumbnail
+// and thumbnail candidates, and the HLS playlists and segments of its renditions
+// They are looked up before the video is deleted, which deletes their rows too
+func storedVideoFiles(ctx context.Context, thumbnailRepo repository.ThumbnailStore, transcodeRepo repository.TranscodeStore, video *model.Video) ([]string, error) {
+	urls := []string{video.VideoURL, video.ThumbnailURL}
+
+	thumbnails, err := thumbnailRepo.FindByVideoID(ctx, video.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find thumbnails: %w", err)
+	}
+	for _, thumbnail := range thumbnails {
+		urls = append(urls, thumbnail.URL)
+	}
+
+	renditions, err := transcodeRepo.FindRenditions(ctx, video.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find renditions: %w", err)
+	}
+	for _, rendition := range renditions {
+		urls = append(urls, rendition.PlaylistURL)
+		urls = append(urls, rendition.SegmentURLs...)
+	}
+	job, err := transcodeRepo.FindLatestCompletedJob(ctx, video.ID)
+	switch {
+	case err == nil:
+		urls = append(urls, job.MasterPlaylistURL)
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, err
+	}
+
+	return urls, nil
+}
+
+// deleteVideoFiles removes the stored files of a deleted video, logging failures
+// The video is gone either way, so a file that cannot be deleted is only left behind
+func deleteVideoFiles(st storage.Storage, videoID int64, urls []string) {
+	deleted := make(map[string]bool)
+	for _, url := range urls {
+		if url == "" || deleted[url] {
+			continue
+		}
+		deleted[url] = true
+		if err := st.DeleteFile(context.Background(), url); err != nil {
+			log.Printf("Failed to delete %s of video %d: %v", url, videoID, err)
+		}
+	}
+}
+
+// GetStatus returns the processing status of one of the user's videos
+func (s *VideoService) GetStatus(ctx context.Context, userID, videoID int64) (*model.VideoStatus, error) {
+	video, err := s.findOwnVideo(ctx, userID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &model.VideoStatus{
+		VideoID:       video.ID,
+		Status:        video.Status,
+		FailureReason: video.FailureReason,
+		UpdatedAt:     video.UpdatedAt,
+	}
+
+	job, err := s.transcodeRepo.FindLatestJob(ctx, videoID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to find transcode job: %w", err)
+	}
+	status.Transcode = job
+
+	return status, nil
+}
+
+// findVideo maps a missing row to ErrVideoNotFound
+func (s *VideoService) findVideo(ctx context.Context, id int64) (*model.Video, error) {
+	video, err := s.videoRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrVideoNotFound
+		}
+		return nil, fmt.Errorf("failed to find video: %w", err)
+	}
+	return video, nil
+}
+
+// probeUpload buffers an uploaded video in a temporary file and probes it
+// It returns a reader over the same content and a cleanup func removing the file
+func (s *VideoService) probeUpload(ctx context.Context, file io.Reader) (io.Reader, *media.Metadata, func(), error) {
+	return probeVideo(ctx, s.prober, file)
+}
+
+// probeVideo buffers a video in a temporary file and probes it with prober, if any
+// It returns a reader over the same content and a cleanup func removing the file
+func probeVideo(ctx context.Context, prober media.Prober, file io.Reader) (io.Reader, *media.Metadata, func(), error) {
+	if prober == nil {
+		return file, nil, func() {}, nil
+	}
+
+	tmp, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to buffer video: %w", err)
+	}
+	cleanup := func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+
+	if _, err := io.Copy(tmp, file); err != nil {
+		cleanup()
+		return nil, nil, nil, fmt.Errorf("failed to buffer video: %w", err)
+	}
+
+	meta, err := prober.Probe(ctx, tmp.Name())
+	if err != nil {
+		cleanup()
+		if errors.Is(err, media.ErrNotVideo) {
+			return nil, nil, nil, ErrInvalidVideo
+		}
+		return nil, nil, nil, fmt.Errorf("failed to probe video: %w", err)
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, nil, nil, fmt.Errorf("failed to buffer video: %w", err)
+	}
+
+	return tmp, meta, cleanup, nil
+}
+
+// PublishScheduled makes scheduled videos public once their publish_at has passed
+func (s *VideoService) PublishScheduled(ctx context.Context) (int64, error) {
+	published, err := s.videoRepo.PublishScheduled(ctx, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to publish scheduled videos: %w", err)
+	}
+	return published, nil
+}
+
+// setVisibility applies a requested visibility to video
+// A publishAt schedules a public release, keeping the video private until then;
+// an explicit visibility cancels the schedule and an empty one keeps the current setting
+func setVisibility(video *model.Video, visibility string, publishAt *time.Time, now time.Time) error {
+	if publishAt != nil {
+		if (visibility != "" && visibility != model.VideoVisibilityPublic) || !publishAt.After(now) {
+			return ErrInvalidPublishAt
+		}
+		at := publishAt.UTC()
+		video.Visibility = model.VideoVisibilityPrivate
+		video.PublishAt = &at
+		return nil
+	}
+
+	switch visibility {
+	case "":
+		if video.Visibility == "" {
+			video.Visibility = model.VideoVisibilityPublic
+		}
+	case model.VideoVisibilityPublic, model.VideoVisibilityUnlisted, model.VideoVisibilityPrivate:
+		video.Visibility = visibility
+		video.PublishAt = nil
+	default:
+		return ErrInvalidVisibility
+	}
+	return nil
+}
+
+// canWatch reports whether viewerID can open the video: ready videos that are not private,
+// and any of the viewer's own videos
+func canWatch(video *model.Video, viewerID int64) bool {
+	return policy.CanWatchVideo(policy.Actor{UserID: viewerID}, video)
+}
+
+// findProfiles loads the profiles of the given users in one round trip, keyed by user ID
+func findProfiles(ctx context.Context, profileRepo repository.ProfileStore, userIDs []int64) (map[int64]*model.Profile, error) {
+	seen := make(map[int64]bool, len(userIDs))
+	unique := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if !seen[userID] {
+			seen[userID] = true
+			unique = append(unique, userID)
+		}
+	}
+
+	profiles, err := profileRepo.FindByUserIDs(ctx, unique)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// applyMetadata copies probed metadata onto a video
+func applyMetadata(video *model.Video, meta *media.Metadata) {
+	video.Duration = meta.DurationSeconds()
+	video.Container = meta.Container
+	video.VideoCodec = meta.VideoCodec
+	video.AudioCodec = meta.AudioCodec
+	video.Width = meta.Width
+	video.Height = meta.Height
+	video.Bitrate = meta.Bitrate
+	video.FrameRate = meta.FrameRate
+}
+
+// ListThumbnails returns the thumbnail candidates of one of the user's videos
+func (s *VideoService) ListThumbnails(ctx context.Context, userID, videoID int64) ([]*model.VideoThumbnail, error) {
+	video, err := s.findOwnVideo(ctx, userID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnails, err := s.thumbnailRepo.FindByVideoID(ctx, videoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find thumbnails: %w", err)
+	}
+	for _, thumbnail := range thumbnails {
+		thumbnail.Selected = thumbnail.URL == video.ThumbnailURL
+	}
+
+	return thumbnails, nil
+}
+
+// SelectThumbnail makes one of the video's candidates its thumbnail
+func (s *VideoService) SelectThumbnail(ctx context.Context, userID, videoID, thumbnailID int64) (*model.Video, error) {
+	video, err := s.findOwnVideo(ctx, userID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnail, err := s.thumbnailRepo.FindByID(ctx, thumbnailID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrThumbnailNotFound
+		}
+		return nil, fmt.Errorf("failed to find thumbnail: %w", err)
+	}
+	if thumbnail.VideoID != videoID {
+		return nil, ErrThumbnailNotFound
+	}
+
+	return s.setThumbnail(ctx, video, thumbnail.URL)
+}
+
+// UploadThumbnail stores a custom thumbnail, replacing an earlier upload, and selects it
+func (s *VideoService) UploadThumbnail(ctx context.Context, userID, videoID int64, file io.Reader, filename, contentType string, size int64) (*model.Video, error) {
+	video, err := s.findOwnVideo(ctx, userID, videoID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, ErrInvalidThumbnail
+	}
+
+	url, err := s.storage.UploadFile(ctx, file, filename, contentType, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload thumbnail: %w", err)
+	}
+
+	removed, err := s.thumbnailRepo.ReplaceCustom(ctx, &model.VideoThumbnail{
+		VideoID: videoID,
+		URL:     url,
+	})
+	if err != nil {
+		_ = s.storage.DeleteFile(ctx, url)
+		return nil, fmt.Errorf("failed to save thumbnail: %w", err)
+	}
+
+	updatedVideo, err := s.setThumbnail(ctx, video, url)
+	if err != nil {
+		return nil, err
+	}
+
+	// The previous upload is gone unless it is still shown
+	for _, thumbnail := range removed {
+		if thumbnail.URL != updatedVideo.ThumbnailURL {
+			_ = s.storage.DeleteFile(ctx, thumbnail.URL)
+		}
+	}
+
+	return updatedVideo, nil
+}
+
+// setThumbnail points the video at url and releases the thumbnail it showed before
+func (s *VideoService) setThumbnail(ctx context.Context, video *model.Video, url string) (*model.Video, error) {
+	oldURL := video.ThumbnailURL
+	if err := s.videoRepo.UpdateThumbnail(ctx, video.ID, url); err != nil {
+		return nil, fmt.Errorf("failed to update thumbnail: %w", err)
+	}
+	if oldURL != url {
+		s.releaseThumbnail(ctx, video.ID, oldURL)
+	}
+
+	return s.findVideo(ctx, video.ID)
+}
+
+// releaseThumbnail deletes a thumbnail file that is no longer shown, unless it is
+// still one of the video's candidates the owner can go back to
+func (s *VideoService) releaseThumbnail(ctx context.Context, videoID int64, url string) {
+	if url == "" {
+		return
+	}
+
+	thumbnails, err := s.thumbnailRepo.FindByVideoID(ctx, videoID)
+	if err != nil {
+		// Keeping an unused file is better than deleting a candidate
+		return
+	}
+	for _, thumbnail := range thumbnails {
+		if thumbnail.URL == url {
+			return
+		}
+	}
+
+	_ = s.storage.DeleteFile(ctx, url)
+}
+
+// findOwnVideo returns a video that belongs to the user
+func (s *VideoService) findOwnVideo(ctx context.Context, userID, videoID int64) (*model.Video, error) {
+	return s.findEditableVideo(ctx, policy.Actor{UserID: userID}, videoID)
+}
+
+// findEditableVideo returns a video the actor can edit, or ErrNotVideoOwner
+func (s *VideoService) findEditableVideo(ctx context.Context, actor policy.Actor, videoID int64) (*model.Video, error) {
+	video, err := s.findVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.CanEditVideo(actor, video) {
+		return nil, ErrNotVideoOwner
+	}
+	return video, nil
+}
@@ -0,0 +1,675 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yukito/video-platform/internal/media"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/policy"
+	"github.com/yukito/video-platform/internal/repository/memory"
+	"github.com/yukito/video-platform/internal/storage"
+)
+
+func newVideoService(db *memory.DB, st *storage.MemoryStorage) *VideoService {
+	return newVideoServiceWithProber(db, st, media.NewFakeProber())
+}
+
+func newVideoServiceWithProber(db *memory.DB, st *storage.MemoryStorage, prober media.Prober) *VideoService {
+	return NewVideoService(memory.NewVideoRepository(db), memory.NewProfileRepository(db), memory.NewTranscodeRepository(db), memory.NewThumbnailRepository(db), memory.NewWatchHistoryRepository(db), prober, st)
+}
+
+func createWithFiles(s *VideoService, videoName, thumbnailName string) (*model.Video, error) {
+	return s.CreateWithFiles(
+		context.Background(), 1, "title", "description", "", nil,
+		strings.NewReader("video-bytes"), videoName, "video/mp4", 11,
+		strings.NewReader("thumb-bytes"), thumbnailName, "image/jpeg", 11,
+	)
+}
+
+func updateWithFiles(s *VideoService, userID, videoID int64, videoName, thumbnailName string) (*model.Video, error) {
+	return s.UpdateWithFiles(
+		context.Background(), asUser(userID), videoID, "new title", "new description", "", nil,
+		strings.NewReader("new-video"), videoName, "video/mp4", 9,
+		strings.NewReader("new-thumb"), thumbnailName, "image/jpeg", 9,
+	)
+}
+
+// seedVideo stores an existing video owned by user 1 with files in st
+func seedVideo(db *memory.DB, st *storage.MemoryStorage) *model.Video {
+	st.Put("memory://old.mp4", []byte("old-video"))
+	st.Put("memory://old.jpg", []byte("old-thumb"))
+	video, _ := memory.NewVideoRepository(db).Create(context.Background(), &model.Video{
+		UserID:       1,
+		Title:        "old title",
+		VideoURL:     "memory://old.mp4",
+		Status:       model.VideoStatusReady,
+		Visibility:   model.VideoVisibilityPublic,
+		ThumbnailURL: "memory://old.jpg",
+	})
+	return video
+}
+
+// seedTranscodedVideo stores an existing video like seedVideo, with a thumbnail
+// candidate and an HLS rendition; it returns the video and all of its stored files
+func seedTranscodedVideo(t *testing.T, db *memory.DB, st *storage.MemoryStorage) (*model.Video, []string) {
+	t.Helper()
+	ctx := context.Background()
+	video := seedVideo(db, st)
+	files := []string{video.VideoURL, video.ThumbnailURL,
+		"memory://candidate.jpg", "memory://master.m3u8", "memory://720p.m3u8", "memory://720p_0.ts"}
+	for _, url := range files[2:] {
+		st.Put(url, []byte("data"))
+	}
+
+	_, err := memory.NewThumbnailRepository(db).ReplaceGenerated(ctx, video.ID, []*model.VideoThumbnail{
+		{VideoID: video.ID, URL: "memory://candidate.jpg", Source: model.ThumbnailSourceGenerated, TimeOffset: 5},
+	})
+	if err != nil {
+		t.Fatalf("failed to seed thumbnail: %v", err)
+	}
+	transcodes := memory.NewTranscodeRepository(db)
+	job, err := transcodes.CreateJob(ctx, video.ID, video.VideoURL)
+	if err != nil {
+		t.Fatalf("failed to seed transcode job: %v", err)
+	}
+	err = transcodes.CompleteJob(ctx, job.ID, video.ID, "memory://master.m3u8", []*model.VideoRendition{
+		{Name: "720p", PlaylistURL: "memory://720p.m3u8", SegmentURLs: []string{"memory://720p_0.ts"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to seed renditions: %v", err)
+	}
+	return video, files
+}
+
+// storedVideo reads a video straight from the in-memory tables
+func storedVideo(t *testing.T, db *memory.DB, id int64) *model.Video {
+	t.Helper()
+	video, err := memory.NewVideoRepository(db).FindByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to read video %d: %v", id, err)
+	}
+	return video
+}
+
+func TestCreateWithFiles_StoresFiles(t *testing.T) {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	s := newVideoService(db, st)
+
+	video, err := createWithFiles(s, "clip.mp4", "thumb.jpg")
+	if err != nil {
+		t.Fatalf("CreateWithFiles returned error: %v", err)
+	}
+
+	if !st.Has(video.VideoURL) || !st.Has(video.ThumbnailURL) {
+		t.Fatalf("expected video and thumbnail to be stored, got files %v", st.Files())
+	}
+	if len(st.Deletes()) != 0 {
+		t.Errorf("expected no deletes, got %v", st.Deletes())
+	}
+	// The duration comes from probing the file (10.2s with the fake prober)
+	if video.Duration != 10 {
+		t.Errorf("expected probed duration 10, got %d", video.Duration)
+	}
+}
+
+func TestCreateWithFiles_ThumbnailFailureRemovesVideo(t *testing.T) {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	st.FailUpload("thumb.jpg", errors.New("bucket unavailable"))
+	s := newVideoService(db, st)
+
+	if _, err := createWithFiles(s, "clip.mp4", "thumb.jpg"); err == nil {
+		t.Fatal("expected error when thumbnail upload fails")
+	}
+
+	if files := st.Files(); len(files) != 0 {
+		t.Errorf("expected uploaded video to be cleaned up, still stored: %v", files)
+	}
+	if uploads, deletes := st.Uploads(), st.Deletes(); len(deletes) != 1 || deletes[0] != uploads[0] {
+		t.Errorf("expected the uploaded video to be deleted, uploads %v deletes %v", uploads, deletes)
+	}
+	if page, _ := s.List(context.Background(), 1, model.PageQuery{}); len(page.Items) != 0 {
+		t.Errorf("expected no database insert, got %d videos", len(page.Items))
+	}
+}
+
+func TestCreateWithFiles_DatabaseFailureRemovesFiles(t *testing.T) {
+	db := memory.NewDB()
+	db.Fail("VideoRepository.Create", errors.New("connection refused"))
+	st := storage.NewMemoryStorage()
+	s := newVideoService(db, st)
+
+	if _, err := createWithFiles(s, "clip.mp4", "thumb.jpg"); err == nil {
+		t.Fatal("expected error when database insert fails")
+	}
+
+	if files := st.Files(); len(files) != 0 {
+		t.Errorf("expected uploaded files to be cleaned up, still stored: %v", files)
+	}
+	if len(st.Deletes()) != 2 {
+		t.Errorf("expected video and thumbnail to be deleted, got %v", st.Deletes())
+	}
+}
+
+func TestUpdateWithFiles_DeletesOldFiles(t *testing.T) {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	existing := seedVideo(db, st)
+	s := newVideoService(db, st)
+
+	video, err := updateWithFiles(s, 1, existing.ID, "new.mp4", "new.jpg")
+	if err != nil {
+		t.Fatalf("UpdateWithFiles returned error: %v", err)
+	}
+
+	if st.Has("memory://old.mp4") || st.Has("memory://old.jpg") {
+		t.Errorf("expected old files to be deleted, still stored: %v", st.Files())
+	}
+	if !st.Has(video.VideoURL) || !st.Has(video.ThumbnailURL) {
+		t.Errorf("expected new files to be stored, got %v", st.Files())
+	}
+	if video.Title != "new title" {
+		t.Errorf("expected title to be updated, got %q", video.Title)
+	}
+}
+
+func TestUpdateWithFiles_ThumbnailFailureKeepsOldFiles(t *testing.T) {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	existing := seedVideo(db, st)
+	st.FailUpload("new.jpg", errors.New("bucket unavailable"))
+	s := newVideoService(db, st)
+
+	if _, err := updateWithFiles(s, 1, existing.ID, "new.mp4", "new.jpg"); err == nil {
+		t.Fatal("expected error when thumbnail upload fails")
+	}
+
+	files := st.Files()
+	if len(files) != 2 || !st.Has("memory://old.mp4") || !st.Has("memory://old.jpg") {
+		t.Errorf("expected only the old files to remain, got %v", files)
+	}
+	if video := storedVideo(t, db, existing.ID); video.Title != "old title" || video.VideoURL != "memory://old.mp4" {
+		t.Errorf("expected stored video to be unchanged, got %+v", video)
+	}
+}
+
+func TestUpdateWithFiles_DatabaseFailureRemovesNewFiles(t *testing.T) {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	existing := seedVideo(db, st)
+	db.Fail("VideoRepository.Update", errors.New("connection refused"))
+	s := newVideoService(db, st)
+
+	if _, err := updateWithFiles(s, 1, existing.ID, "new.mp4", "new.jpg"); err == nil {
+		t.Fatal("expected error when database update fails")
+	}
+
+	files := st.Files()
+	if len(files) != 2 || !st.Has("memory://old.mp4") || !st.Has("memory://old.jpg") {
+		t.Errorf("expected new files removed and old files kept, got %v", files)
+	}
+	for _, url := range st.Deletes() {
+		if url == "memory://old.mp4" || url == "memory://old.jpg" {
+			t.Errorf("old file %s must not be deleted when the update fails", url)
+		}
+	}
+}
+
+func TestUpdateWithFiles_RejectsOtherUser(t *testing.T) {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	existing := seedVideo(db, st)
+	s := newVideoService(db, st)
+
+	if _, err := updateWithFiles(s, 2, existing.ID, "new.mp4", "new.jpg"); err == nil {
+		t.Fatal("expected error when updating another user's video")
+	}
+
+	if len(st.Uploads()) != 0 || len(st.Deletes()) != 0 {
+		t.Errorf("expected storage to be untouched, uploads %v deletes %v", st.Uploads(), st.Deletes())
+	}
+}
+
+func TestDelete_OwnerAndModeratorsCanDelete(t *testing.T) {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	s := newVideoService(db, st)
+	ctx := context.Background()
+
+	existing := seedVideo(db, st)
+	if err := s.Delete(ctx, asUser(2), existing.ID); !errors.Is(err, ErrNotVideoOwner) {
+		t.Fatalf("expected ErrNotVideoOwner for another user, got %v", err)
+	}
+	moderator := policy.Actor{UserID: 2, Role: model.RoleModerator}
+	if _, err := s.Update(ctx, moderator, existing.ID, &model.UpdateVideoRequest{Title: "renamed"}); !errors.Is(err, ErrNotVideoOwner) {
+		t.Errorf("expected moderators not to edit others' videos, got %v", err)
+	}
+	if err := s.Delete(ctx, moderator, existing.ID); err != nil {
+		t.Fatalf("expected moderator delete to succeed, got %v", err)
+	}
+	if err := s.Delete(ctx, asUser(1), existing.ID); !errors.Is(err, ErrVideoNotFound) {
+		t.Errorf("expected the video to be gone, got %v", err)
+	}
+}
+
+func TestDelete_RemovesStoredFiles(t *testing.T) {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	s := newVideoService(db, st)
+
+	video, files := seedTranscodedVideo(t, db, st)
+	if err := s.Delete(context.Background(), asUser(1), video.ID); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	for _, url := range files {
+		if st.Has(url) {
+			t.Errorf("expected %s to be deleted", url)
+		}
+	}
+}
+
+func TestVideoStatus_OnlyOwnerSeesVideoUntilReady(t *testing.T) {
+	f := newTranscodeFixture(t)
+	ctx := context.Background()
+
+	video, err := createWithFiles(f.videos, "movie.mp4", "thumb.jpg")
+	if err != nil {
+		t.Fatalf("failed to create video: %v", err)
+	}
+	if video.Status != model.VideoStatusProcessing {
+		t.Fatalf("expected new video to be processing, got %s", video.Status)
+	}
+
+	if page, _ := f.videos.List(ctx, 0, model.PageQuery{}); len(page.Items) != 0 {
+		t.Errorf("expected processing video to be hidden from anonymous viewers, got %d", len(page.Items))
+	}
+	if page, _ := f.videos.List(ctx, 1, model.PageQuery{}); len(page.Items) != 1 || page.Items[0].Status != model.VideoStatusProcessing {
+		t.Errorf("expected owner to see the processing video, got %+v", page.Items)
+	}
+	if _, err := f.videos.GetByID(ctx, 2, video.ID); !errors.Is(err, ErrVideoNotFound) {
+		t.Errorf("expected ErrVideoNotFound for another user, got %v", err)
+	}
+
+	status, err := f.videos.GetStatus(ctx, 1, video.ID)
+	if err != nil {
+		t.Fatalf("GetStatus returned error: %v", err)
+	}
+	if status.Status != model.VideoStatusProcessing || status.Transcode == nil || status.Transcode.Status != model.TranscodeStatusPending {
+		t.Errorf("expected processing video with a pending job, got %+v", status)
+	}
+	if _, err := f.videos.GetStatus(ctx, 2, video.ID); !errors.Is(err, ErrNotVideoOwner) {
+		t.Errorf("expected ErrNotVideoOwner for another user, got %v", err)
+	}
+
+	if _, err := f.worker.ProcessNext(ctx); err != nil {
+		t.Fatalf("ProcessNext returned error: %v", err)
+	}
+
+	got, err := f.videos.GetByID(ctx, 2, video.ID)
+	if err != nil || got.Status != model.VideoStatusReady {
+		t.Fatalf("expected ready video to be visible to others, got %+v, %v", got, err)
+	}
+	if page, _ := f.videos.List(ctx, 0, model.PageQuery{}); len(page.Items) != 1 {
+		t.Errorf("expected ready video to be listed, got %d", len(page.Items))
+	}
+}
+
+func TestVideoStatus_PublishedWhenTranscodingCannotBeQueued(t *testing.T) {
+	db := memory.NewDB()
+	db.Fail("TranscodeRepository.CreateJob", errors.New("db down"))
+	s := newVideoService(db, storage.NewMemoryStorage())
+
+	video, err := createWithFiles(s, "movie.mp4", "thumb.jpg")
+	if err != nil {
+		t.Fatalf("failed to create video: %v", err)
+	}
+	if video.Status != model.VideoStatusReady || storedVideo(t, db, video.ID).Status != model.VideoStatusReady {
+		t.Errorf("expected the raw file to be published, got %s", storedVideo(t, db, video.ID).Status)
+	}
+}
+
+func TestCreateWithFiles_StoresProbedMetadata(t *testing.T) {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	s := newVideoService(db, st)
+
+	video, err := createWithFiles(s, "movie.mp4", "thumb.jpg")
+	if err != nil {
+		t.Fatalf("failed to create video: %v", err)
+	}
+
+	stored := storedVideo(t, db, video.ID)
+	if stored.Duration != 10 || stored.Width != 1280 || stored.Height != 720 || stored.VideoCodec != "h264" || stored.FrameRate != 30 {
+		t.Errorf("expected probed metadata to be stored, got %+v", stored)
+	}
+	if data, _ := st.Get(stored.VideoURL); string(data) != "video-bytes" {
+		t.Errorf("expected the probed file to be uploaded unchanged, got %q", data)
+	}
+}
+
+func TestCreateWithFiles_RejectsFilesThatAreNotVideo(t *testing.T) {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	prober := media.NewFakeProber()
+	prober.Fail(media.ErrNotVideo)
+	s := newVideoServiceWithProber(db, st, prober)
+
+	if _, err := createWithFiles(s, "notes.mp4", "thumb.jpg"); !errors.Is(err, ErrInvalidVideo) {
+		t.Fatalf("expected ErrInvalidVideo, got %v", err)
+	}
+	if len(st.Uploads()) != 0 {
+		t.Errorf("expected nothing to be stored, got %v", st.Uploads())
+	}
+	if page, _ := s.List(context.Background(), 1, model.PageQuery{}); len(page.Items) != 0 {
+		t.Errorf("expected no video, got %d", len(page.Items))
+	}
+}
+
+func TestThumbnail_SelectAndUpload(t *testing.T) {
+	db := memory.NewDB()
+	st := storage.NewMemoryStorage()
+	s := newVideoService(db, st)
+	ctx := context.Background()
+	video := seedVideo(db, st)
+
+	st.Put("memory://candidate.jpg", []byte("frame"))
+	_, _ = memory.NewThumbnailRepository(db).ReplaceGenerated(ctx, video.ID, []*model.VideoThumbnail{
+		{VideoID: video.ID, URL: "memory://candidate.jpg", Source: model.ThumbnailSourceGenerated, TimeOffset: 5},
+	})
+	thumbnails, _ := s.ListThumbnails(ctx, 1, video.ID)
+	if len(thumbnails) != 1 || thumbnails[0].Selected {
+		t.Fatalf("expected one unselected candidate, got %+v", thumbnails)
+	}
+
+	if _, err := s.SelectThumbnail(ctx, 2, video.ID, thumbnails[0].ID); !errors.Is(err, ErrNotVideoOwner) {
+		t.Errorf("expected ErrNotVideoOwner, got %v", err)
+	}
+	if _, err := s.SelectThumbnail(ctx, 1, video.ID, 999); !errors.Is(err, ErrThumbnailNotFound) {
+		t.Errorf("expected ErrThumbnailNotFound, got %v", err)
+	}
+	updated, err := s.SelectThumbnail(ctx, 1, video.ID, thumbnails[0].ID)
+	if err != nil || updated.ThumbnailURL != "memory://candidate.jpg" {
+		t.Fatalf("expected the candidate to be selected, got %+v, %v", updated, err)
+	}
+	if st.Has("memory://old.jpg") {
+		t.Error("expected the replaced thumbnail to be deleted")
+	}
+
+	if _, err := s.UploadThumbnail(ctx, 1, video.ID, strings.NewReader("x"), "a.txt", "text/plain", 1); !errors.Is(err, ErrInvalidThumbnail) {
+		t.Errorf("expected ErrInvalidThumbnail, got %v", err)
+	}
+	first, err := s.UploadThumbnail(ctx, 1, video.ID, strings.NewReader("custom"), "a.jpg", "image/jpeg", 6)
+	if err != nil {
+		t.Fatalf("UploadThumbnail returned error: %v", err)
+	}
+	if !st.Has("memory://candidate.jpg") {
+		t.Error("expected the generated candidate to be kept")
+	}
+	second, _ := s.UploadThumbnail(ctx, 1, video.ID, strings.NewReader("custom2"), "b.jpg", "image/jpeg", 7)
+	if st.Has(first.ThumbnailURL) || !st.Has(second.ThumbnailURL) {
+		t.Error("expected the earlier custom upload to be replaced")
+	}
+}
+
+func TestVideoVisibility_UnlistedAndPrivate(t *testing.T) {
+	db := memory.NewDB()
+	s := newVideoService(db, storage.NewMemoryStorage())
+	ctx := context.Background()
+
+	unlisted, err := s.Create(ctx, 1, &model.CreateVideoRequest{Title: "unlisted", Visibility: model.VideoVisibilityUnlisted})
+	if err != nil {
+		t.Fatalf("failed to create video: %v", err)
+	}
+	private, _ := s.Create(ctx, 1, &model.CreateVideoRequest{Title: "private", Visibility: model.VideoVisibilityPrivate})
+	public, _ := s.Create(ctx, 1, &model.CreateVideoRequest{Title: "public"})
+	if public.Visibility != model.VideoVisibilityPublic {
+		t.Errorf("expected videos to be public by default, got %q", public.Visibility)
+	}
+
+	if page, _ := s.List(ctx, 2, model.PageQuery{}); len(page.Items) != 1 || page.Items[0].ID != public.ID {
+		t.Errorf("expected only the public video to be listed, got %+v", page.Items)
+	}
+	if page, _ := s.List(ctx, 1, model.PageQuery{}); len(page.Items) != 3 {
+		t.Errorf("expected the owner to see all videos, got %d", len(page.Items))
+	}
+	if _, err := s.GetByID(ctx, 0, unlisted.ID); err != nil {
+		t.Errorf("expected an unlisted video to open by ID, got %v", err)
+	}
+	if _, err := s.GetByID(ctx, 2, private.ID); !errors.Is(err, ErrVideoNotFound) {
+		t.Errorf("expected ErrVideoNotFound for a private video, got %v", err)
+	}
+	if _, err := s.GetByID(ctx, 1, private.ID); err != nil {
+		t.Errorf("expected the owner to open a private video, got %v", err)
+	}
+
+	if _, err := s.Create(ctx, 1, &model.CreateVideoRequest{Title: "x", Visibility: "friends"}); !errors.Is(err, ErrInvalidVisibility) {
+		t.Errorf("expected ErrInvalidVisibility, got %v", err)
+	}
+	if _, err := s.Update(ctx, asUser(1), public.ID, &model.UpdateVideoRequest{Visibility: model.VideoVisibilityPrivate}); err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	if _, err := s.GetByID(ctx, 2, public.ID); !errors.Is(err, ErrVideoNotFound) {
+		t.Errorf("expected the video to be hidden after making it private, got %v", err)
+	}
+}
+
+func TestVideoVisibility_ScheduledPublishing(t *testing.T) {
+	db := memory.NewDB()
+	s := newVideoService(db, storage.NewMemoryStorage())
+	ctx := context.Background()
+
+	past := time.Now().Add(-time.Minute)
+	if _, err := s.Create(ctx, 1, &model.CreateVideoRequest{Title: "late", PublishAt: &past}); !errors.Is(err, ErrInvalidPublishAt) {
+		t.Errorf("expected ErrInvalidPublishAt for a past time, got %v", err)
+	}
+	publishAt := time.Now().Add(time.Hour)
+	if _, err := s.Create(ctx, 1, &model.CreateVideoRequest{Title: "x", Visibility: model.VideoVisibilityUnlisted, PublishAt: &publishAt}); !errors.Is(err, ErrInvalidPublishAt) {
+		t.Errorf("expected ErrInvalidPublishAt when scheduling an unlisted video, got %v", err)
+	}
+
+	video, err := s.Create(ctx, 1, &model.CreateVideoRequest{Title: "premiere", PublishAt: &publishAt})
+	if err != nil {
+		t.Fatalf("failed to create video: %v", err)
+	}
+	if video.Visibility != model.VideoVisibilityPrivate || video.PublishAt == nil {
+		t.Fatalf("expected a private video with publish_at, got %+v", video)
+	}
+	if _, err := s.GetByID(ctx, 2, video.ID); !errors.Is(err, ErrVideoNotFound) {
+		t.Errorf("expected a scheduled video to be hidden, got %v", err)
+	}
+	if published, err := s.PublishScheduled(ctx); err != nil || published != 0 {
+		t.Errorf("expected nothing to be published yet, got %d, %v", published, err)
+	}
+
+	// Once publish_at has passed the scheduler makes the video public
+	published, err := memory.NewVideoRepository(db).PublishScheduled(ctx, publishAt.Add(time.Second))
+	if err != nil || published != 1 {
+		t.Fatalf("expected one video to be published, got %d, %v", published, err)
+	}
+	if stored := storedVideo(t, db, video.ID); stored.Visibility != model.VideoVisibilityPublic || stored.PublishAt != nil {
+		t.Errorf("expected the video to be public without a schedule, got %+v", stored)
+	}
+	if page, _ := s.List(ctx, 2, model.PageQuery{}); len(page.Items) != 1 {
+		t.Errorf("expected the published video to be listed, got %d", len(page.Items))
+	}
+}
+
+func TestVideoVisibility_EnforcedInFeedPlaylistsAndHistory(t *testing.T) {
+	db := memory.NewDB()
+	s := newVideoService(db, storage.NewMemoryStorage())
+	playlists := NewPlaylistService(memory.NewPlaylistRepository(db), memory.NewVideoRepository(db), memory.NewProfileRepository(db))
+	history := NewWatchHistoryService(memory.NewWatchHistoryRepository(db), memory.NewVideoRepository(db))
+	subscriptions := NewSubscriptionService(memory.NewSubscriptionRepository(db), memory.NewUserRepository(db))
+	ctx := context.Background()
+
+	public, _ := s.Create(ctx, 1, &model.CreateVideoRequest{Title: "public"})
+	unlisted, _ := s.Create(ctx, 1, &model.CreateVideoRequest{Title: "unlisted", Visibility: model.VideoVisibilityUnlisted})
+	later, _ := s.Create(ctx, 1, &model.CreateVideoRequest{Title: "made private later"})
+	private, _ := s.Create(ctx, 1, &model.CreateVideoRequest{Title: "private", Visibility: model.VideoVisibilityPrivate})
+
+	playlist, _ := playlists.Create(ctx, 2, &model.CreatePlaylistRequest{Title: "mix", Visibility: "public"})
+	for _, video := range []*model.Video{public, unlisted, later} {
+		if err := playlists.AddVideo(ctx, 2, playlist.ID, video.ID); err != nil {
+			t.Fatalf("AddVideo returned error: %v", err)
+		}
+		if err := history.AddToHistory(ctx, 2, video.ID); err != nil {
+			t.Fatalf("AddToHistory returned error: %v", err)
+		}
+	}
+	if err := playlists.AddVideo(ctx, 2, playlist.ID, private.ID); !errors.Is(err, ErrVideoNotFound) {
+		t.Errorf("expected ErrVideoNotFound adding a private video, got %v", err)
+	}
+	if err := history.AddToHistory(ctx, 2, private.ID); !errors.Is(err, ErrVideoNotFound) {
+		t.Errorf("expected ErrVideoNotFound recording a private video, got %v", err)
+	}
+	_, _ = s.Update(ctx, asUser(1), later.ID, &model.UpdateVideoRequest{Visibility: model.VideoVisibilityPrivate})
+
+	if videos, _ := playlists.GetPlaylistVideos(ctx, 0, playlist.ID); len(videos) != 2 {
+		t.Errorf("expected the public and unlisted videos in the playlist, got %d", len(videos))
+	}
+	if videos, _ := playlists.GetPlaylistVideos(ctx, 1, playlist.ID); len(videos) != 3 {
+		t.Errorf("expected the owner to see their private video in the playlist, got %d", len(videos))
+	}
+	if page, _ := history.GetWatchHistory(ctx, 2, model.PageQuery{}); len(page.Items) != 2 {
+		t.Errorf("expected the private video to drop out of the history, got %d", len(page.Items))
+	}
+	if count, _ := history.GetHistoryCount(ctx, 2); count != 2 {
+		t.Errorf("expected the history count to match, got %d", count)
+	}
+
+	_ = memory.NewSubscriptionRepository(db).Subscribe(ctx, 2, 1)
+	page, err := subscriptions.GetSubscriptionFeed(ctx, 2, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetSubscriptionFeed returned error: %v", err)
+	}
+	if feed := page.Items; len(feed) != 1 || feed[0].ID != public.ID {
+		t.Errorf("expected only the public video in the feed, got %+v", feed)
+	}
+}
+
+// seedListing creates count public videos spread over owners channels, and a playlist holding all of them
+func seedListing(tb testing.TB, db *memory.DB, count, owners int) *model.Playlist {
+	tb.Helper()
+	ctx := context.Background()
+	profiles := memory.NewProfileRepository(db)
+	for userID := int64(1); userID <= int64(owners); userID++ {
+		if _, err := profiles.Create(ctx, userID, fmt.Sprintf("channel%d@example.com", userID), "", ""); err != nil {
+			tb.Fatalf("failed to create profile: %v", err)
+		}
+	}
+
+	playlists := memory.NewPlaylistRepository(db)
+	playlist, err := playlists.Create(ctx, &model.Playlist{UserID: 1, Title: "all", Visibility: "public"})
+	if err != nil {
+		tb.Fatalf("failed to create playlist: %v", err)
+	}
+	for i := 0; i < count; i++ {
+		video, err := memory.NewVideoRepository(db).Create(ctx, &model.Video{
+			UserID:     int64(i%owners) + 1,
+			Title:      fmt.Sprintf("video %d", i),
+			Status:     model.VideoStatusReady,
+			Visibility: model.VideoVisibilityPublic,
+		})
+		if err != nil {
+			tb.Fatalf("failed to create video: %v", err)
+		}
+		if err := playlists.AddVideo(ctx, playlist.ID, video.ID); err != nil {
+			tb.Fatalf("failed to add video: %v", err)
+		}
+	}
+	return playlist
+}
+
+func TestListings_UseConstantRoundTrips(t *testing.T) {
+	for _, count := range []int{10, 100} {
+		db := memory.NewDB()
+		playlist := seedListing(t, db, count, 30)
+		s := newVideoService(db, storage.NewMemoryStorage())
+		playlists := NewPlaylistService(memory.NewPlaylistRepository(db), memory.NewVideoRepository(db), memory.NewProfileRepository(db))
+		ctx := context.Background()
+
+		before := db.Calls()
+		page, err := s.List(ctx, 0, model.PageQuery{Limit: count})
+		if err != nil {
+			t.Fatalf("List returned error: %v", err)
+		}
+		videos := page.Items
+		if calls := db.Calls() - before; calls != 2 {
+			t.Errorf("expected List of %d videos to take 2 round trips, took %d", count, calls)
+		}
+		if len(videos) != count || videos[0].Profile == nil || videos[0].Profile.ChannelName == "" {
+			t.Fatalf("expected %d videos with profiles, got %d", count, len(videos))
+		}
+
+		before = db.Calls()
+		entries, err := playlists.GetPlaylistVideos(ctx, 0, playlist.ID)
+		if err != nil {
+			t.Fatalf("GetPlaylistVideos returned error: %v", err)
+		}
+		if calls := db.Calls() - before; calls != 3 {
+			t.Errorf("expected GetPlaylistVideos of %d videos to take 3 round trips, took %d", count, calls)
+		}
+		if len(entries) != count {
+			t.Fatalf("expected %d playlist videos, got %d", count, len(entries))
+		}
+	}
+}
+
+func TestList_ReturnsVideosWithoutProfiles(t *testing.T) {
+	db := memory.NewDB()
+	s := newVideoService(db, storage.NewMemoryStorage())
+	seedVideo(db, storage.NewMemoryStorage())
+
+	page, err := s.List(context.Background(), 0, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if videos := page.Items; len(videos) != 1 || videos[0].Profile != nil {
+		t.Errorf("expected the video without a profile, got %+v", videos)
+	}
+
+	db.Fail("ProfileRepository.FindByUserIDs", errors.New("db down"))
+	if _, err := s.List(context.Background(), 0, model.PageQuery{}); err == nil {
+		t.Error("expected List to fail when profiles cannot be loaded")
+	}
+}
+
+// BenchmarkVideoList reports the repository round trips of a 100-video page as queries/op
+func BenchmarkVideoList(b *testing.B) {
+	db := memory.NewDB()
+	seedListing(b, db, 100, 30)
+	s := newVideoService(db, storage.NewMemoryStorage())
+	ctx := context.Background()
+
+	b.ResetTimer()
+	before := db.Calls()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.List(ctx, 0, model.PageQuery{Limit: 100}); err != nil {
+			b.Fatalf("List returned error: %v", err)
+		}
+	}
+	b.ReportMetric(float64(db.Calls()-before)/float64(b.N), "queries/op")
+}
+
+// BenchmarkPlaylistVideos reports the repository round trips of a 100-video playlist as queries/op
+func BenchmarkPlaylistVideos(b *testing.B) {
+	db := memory.NewDB()
+	playlist := seedListing(b, db, 100, 30)
+	playlists := NewPlaylistService(memory.NewPlaylistRepository(db), memory.NewVideoRepository(db), memory.NewProfileRepository(db))
+	ctx := context.Background()
+
+	b.ResetTimer()
+	before := db.Calls()
+	for i := 0; i < b.N; i++ {
+		if _, err := playlists.GetPlaylistVideos(ctx, 0, playlist.ID); err != nil {
+			b.Fatalf("GetPlaylistVideos returned error: %v", err)
+		}
+	}
+	b.ReportMetric(float64(db.Calls()-before)/float64(b.N), "queries/op")
+}
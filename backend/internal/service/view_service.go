@@ -0,0 +1,272 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository"
+)
+
+const (
+	// MinViewDuration is how long a video has to be watched before a view counts;
+	// videos shorter than twice this count after half of their duration
+	MinViewDuration = 30 * time.Second
+	// ViewDedupWindow is how long a counted viewer is not counted again for the same video
+	ViewDedupWindow = 24 * time.Hour
+	// MaxAnonymousViewsPerIP bounds the anonymous views counted for one video from one
+	// IP address within the window, so rotating session IDs does not inflate views
+	MaxAnonymousViewsPerIP = 5
+	// ViewFlushInterval is how often buffered views are written to the database
+	ViewFlushInterval = 10 * time.Second
+	// MaxStartedViewers bounds the viewers tracked before their view counts; beyond it the
+	// viewers who started longest ago are forgotten and start over on their next event
+	MaxStartedViewers = 100_000
+	// MaxCountedViewers bounds the counted viewers and the anonymous IP addresses remembered
+	// for dedup; beyond it the ones counted longest ago are forgotten and may count again
+	MaxCountedViewers = 1_000_000
+
+	// maxPlaybackRate is the fastest playback speed players offer; reported watch time
+	// cannot grow faster than this compared to the time since the first event
+	maxPlaybackRate = 2
+)
+
+var ErrInvalidViewEvent = errors.New("invalid view event")
+
+// viewKey identifies a viewer of a video; viewer is "user:<id>", "session:<ip>/<session>" or "ip:<ip>"
+type viewKey struct {
+	videoID int64
+	viewer  string
+}
+
+// recentViewers holds per-viewer state with the time it was set, oldest first
+// It forgets the oldest beyond its capacity, so clients making up session IDs or
+// rotating IP addresses cannot grow it without bound
+type recentViewers[V any] struct {
+	capacity int
+	order    *list.List // of *recentViewer[V]
+	byKey    map[viewKey]*list.Element
+}
+
+type recentViewer[V any] struct {
+	key   viewKey
+	at    time.Time
+	value V
+}
+
+func newRecentViewers[V any](capacity int) *recentViewers[V] {
+	return &recentViewers[V]{capacity: capacity, order: list.New(), byKey: make(map[viewKey]*list.Element)}
+}
+
+func (v *recentViewers[V]) get(key viewKey) (*recentViewer[V], bool) {
+	if e, ok := v.byKey[key]; ok {
+		return e.Value.(*recentViewer[V]), true
+	}
+	return nil, false
+}
+
+// set records the viewer at the newest time seen, forgetting the oldest viewer when full
+func (v *recentViewers[V]) set(key viewKey, at time.Time, value V) *recentViewer[V] {
+	v.delete(key)
+	viewer := &recentViewer[V]{key: key, at: at, value: value}
+	v.byKey[key] = v.order.PushBack(viewer)
+	if v.order.Len() > v.capacity {
+		v.delete(v.order.Front().Value.(*recentViewer[V]).key)
+	}
+	return viewer
+}
+
+func (v *recentViewers[V]) delete(key viewKey) {
+	if e, ok := v.byKey[key]; ok {
+		v.order.Remove(e)
+		delete(v.byKey, key)
+	}
+}
+
+// prune forgets viewers set longer than the window ago
+func (v *recentViewers[V]) prune(now time.Time) {
+	for e := v.order.Front(); e != nil && now.Sub(e.Value.(*recentViewer[V]).at) >= ViewDedupWindow; e = v.order.Front() {
+		v.delete(e.Value.(*recentViewer[V]).key)
+	}
+}
+
+func (v *recentViewers[V]) len() int {
+	return v.order.Len()
+}
+
+// ViewService counts video views from playback events
+// A view counts once the viewer has watched long enough, and each viewer counts at most
+// once per video within ViewDedupWindow. Counted views are buffered in memory and
+// written in batches by Flush, so the dedup state and up to one flush interval of views
+// are lost on restart, and instances behind a load balancer deduplicate separately
+type ViewService struct {
+	videoRepo repository.VideoStore
+
+	mu      sync.Mutex
+	started *recentViewers[struct{}] // first event of viewers not counted yet
+	counted *recentViewers[struct{}] // when viewers were last counted
+	ips     *recentViewers[int]      // anonymous views per video and IP address since the first one
+	pending map[int64]int64          // views not written yet, by video ID
+
+	flushInterval time.Duration
+	now           func() time.Time
+}
+
+func NewViewService(videoRepo repository.VideoStore) *ViewService {
+	return &ViewService{
+		videoRepo:     videoRepo,
+		started:       newRecentViewers[struct{}](MaxStartedViewers),
+		counted:       newRecentViewers[struct{}](MaxCountedViewers),
+		ips:           newRecentViewers[int](MaxCountedViewers),
+		pending:       make(map[int64]int64),
+		flushInterval: ViewFlushInterval,
+		now:           time.Now,
+	}
+}
+
+// RecordView handles a playback event and reports whether it counted a view
+// Events are expected periodically while the video plays; the view counts on the first
+// event at which both the reported watch time and the time since the viewer's first
+// event (allowing for faster playback) reach the minimum view duration
+func (s *ViewService) RecordView(ctx context.Context, viewer model.Viewer, videoID int64, req *model.RecordViewRequest) (bool, error) {
+	if req.WatchedSeconds < 0 || math.IsNaN(req.WatchedSeconds) || math.IsInf(req.WatchedSeconds, 0) {
+		return false, ErrInvalidViewEvent
+	}
+	if viewer.UserID == 0 && viewer.IP == "" {
+		return false, ErrInvalidViewEvent
+	}
+
+	video, err := s.videoRepo.FindByID(ctx, videoID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, ErrVideoNotFound
+		}
+		return false, fmt.Errorf("failed to find video: %w", err)
+	}
+	if !canWatch(video, viewer.UserID) {
+		return false, ErrVideoNotFound
+	}
+
+	minWatched := minViewDuration(video.Duration)
+	watched := time.Duration(req.WatchedSeconds * float64(time.Second))
+	key := viewKey{videoID: videoID, viewer: viewerKey(viewer)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if counted, ok := s.counted.get(key); ok && now.Sub(counted.at) < ViewDedupWindow {
+		return false, nil
+	}
+
+	started, ok := s.started.get(key)
+	if !ok || now.Sub(started.at) >= ViewDedupWindow {
+		started = s.started.set(key, now, struct{}{})
+	}
+	if watched < minWatched || now.Sub(started.at)*maxPlaybackRate < minWatched {
+		return false, nil
+	}
+
+	if viewer.UserID == 0 {
+		ipKey := viewKey{videoID: videoID, viewer: "ip:" + viewer.IP}
+		views, ok := s.ips.get(ipKey)
+		if !ok || now.Sub(views.at) >= ViewDedupWindow {
+			views = s.ips.set(ipKey, now, 0)
+		}
+		if views.value >= MaxAnonymousViewsPerIP {
+			return false, nil
+		}
+		views.value++
+	}
+
+	s.started.delete(key)
+	s.counted.set(key, now, struct{}{})
+	s.pending[videoID]++
+	return true, nil
+}
+
+// Flush writes the buffered views in one batch and returns how many were written
+// If the write fails, the views stay buffered for the next flush
+func (s *ViewService) Flush(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[int64]int64)
+	s.prune(s.now())
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	if err := s.videoRepo.AddViewCounts(ctx, pending); err != nil {
+		s.mu.Lock()
+		for videoID, count := range pending {
+			s.pending[videoID] += count
+		}
+		s.mu.Unlock()
+		return 0, fmt.Errorf("failed to flush views: %w", err)
+	}
+
+	var total int64
+	for _, count := range pending {
+		total += count
+	}
+	return total, nil
+}
+
+// Run flushes buffered views periodically until ctx is cancelled, then flushes once more
+func (s *ViewService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if _, err := s.Flush(context.Background()); err != nil {
+				log.Printf("Views: %v", err)
+			}
+			return
+		case <-ticker.C:
+			if _, err := s.Flush(ctx); err != nil {
+				log.Printf("Views: %v", err)
+			}
+		}
+	}
+}
+
+// prune drops dedup state older than the window; callers must hold s.mu
+func (s *ViewService) prune(now time.Time) {
+	s.started.prune(now)
+	s.counted.prune(now)
+	s.ips.prune(now)
+}
+
+// minViewDuration returns how long a video of the given duration in seconds has to be watched
+// Videos of unknown duration use MinViewDuration
+func minViewDuration(durationSeconds int64) time.Duration {
+	duration := time.Duration(durationSeconds) * time.Second
+	if duration > 0 && duration < 2*MinViewDuration {
+		return duration / 2
+	}
+	return MinViewDuration
+}
+
+// viewerKey returns the dedup key of a viewer
+// Anonymous viewers without a session are keyed by their IP address alone
+func viewerKey(viewer model.Viewer) string {
+	switch {
+	case viewer.UserID != 0:
+		return fmt.Sprintf("user:%d", viewer.UserID)
+	case viewer.SessionID != "":
+		return "session:" + viewer.IP + "/" + viewer.SessionID
+	default:
+		return "ip:" + viewer.IP
+	}
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository/memory"
+	"github.com/yukito/video-platform/internal/storage"
+)
+
+type viewFixture struct {
+	db      *memory.DB
+	service *ViewService
+	now     time.Time
+	videoID int64
+}
+
+func newViewFixture(t *testing.T, duration int64) *viewFixture {
+	t.Helper()
+	db := memory.NewDB()
+	video, err := memory.NewVideoRepository(db).Create(context.Background(), &model.Video{
+		UserID:     1,
+		Title:      "video",
+		Duration:   duration,
+		Status:     model.VideoStatusReady,
+		Visibility: model.VideoVisibilityPublic,
+	})
+	if err != nil {
+		t.Fatalf("failed to seed video: %v", err)
+	}
+
+	f := &viewFixture{db: db, now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), videoID: video.ID}
+	f.service = NewViewService(memory.NewVideoRepository(db))
+	f.service.now = func() time.Time { return f.now }
+	return f
+}
+
+// watch reports an event at watched seconds after advancing the clock by the same amount
+// since the previous event of the viewer
+func (f *viewFixture) watch(t *testing.T, viewer model.Viewer, from, to float64) bool {
+	t.Helper()
+	if _, err := f.service.RecordView(context.Background(), viewer, f.videoID, &model.RecordViewRequest{SessionID: viewer.SessionID, WatchedSeconds: from}); err != nil {
+		t.Fatalf("RecordView returned error: %v", err)
+	}
+	f.now = f.now.Add(time.Duration(to-from) * time.Second)
+	counted, err := f.service.RecordView(context.Background(), viewer, f.videoID, &model.RecordViewRequest{SessionID: viewer.SessionID, WatchedSeconds: to})
+	if err != nil {
+		t.Fatalf("RecordView returned error: %v", err)
+	}
+	return counted
+}
+
+func (f *viewFixture) viewCount(t *testing.T) int64 {
+	t.Helper()
+	if _, err := f.service.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+	return storedVideo(t, f.db, f.videoID).ViewCount
+}
+
+func TestRecordView_RequiresMinimumWatchTime(t *testing.T) {
+	f := newViewFixture(t, 600)
+	viewer := model.Viewer{UserID: 2}
+
+	if f.watch(t, viewer, 0, 10) {
+		t.Error("expected 10 seconds not to count a view")
+	}
+
+	// Reporting the minimum right away is not enough; the time since the first event has to follow
+	f.now = f.now.Add(time.Second)
+	if counted, _ := f.service.RecordView(context.Background(), model.Viewer{UserID: 3}, f.videoID, &model.RecordViewRequest{WatchedSeconds: 600}); counted {
+		t.Error("expected a first event claiming the whole video not to count")
+	}
+
+	if !f.watch(t, viewer, 10, 30) {
+		t.Error("expected 30 seconds to count a view")
+	}
+	if got := f.viewCount(t); got != 1 {
+		t.Errorf("expected 1 view, got %d", got)
+	}
+}
+
+func TestRecordView_ShortVideosCountAfterHalf(t *testing.T) {
+	f := newViewFixture(t, 20)
+	if !f.watch(t, model.Viewer{IP: "192.0.2.1", SessionID: "a"}, 0, 10) {
+		t.Error("expected half of a 20 second video to count a view")
+	}
+}
+
+func TestRecordView_DeduplicatesWithinWindow(t *testing.T) {
+	f := newViewFixture(t, 0)
+	user := model.Viewer{UserID: 2, IP: "192.0.2.1", SessionID: "a"}
+
+	if !f.watch(t, user, 0, 30) {
+		t.Fatal("expected the first view to count")
+	}
+	if f.watch(t, model.Viewer{UserID: 2, IP: "198.51.100.7", SessionID: "b"}, 0, 60) {
+		t.Error("expected the same user not to count again from another device")
+	}
+	if !f.watch(t, model.Viewer{IP: "192.0.2.1", SessionID: "a"}, 0, 30) {
+		t.Error("expected an anonymous session to count separately from the user")
+	}
+	if f.watch(t, model.Viewer{IP: "192.0.2.1", SessionID: "a"}, 30, 60) {
+		t.Error("expected the same anonymous session not to count again")
+	}
+
+	f.now = f.now.Add(ViewDedupWindow)
+	if !f.watch(t, user, 0, 30) {
+		t.Error("expected the user to count again after the window")
+	}
+	if got := f.viewCount(t); got != 3 {
+		t.Errorf("expected 3 views, got %d", got)
+	}
+}
+
+func TestRecordView_LimitsAnonymousViewsPerIP(t *testing.T) {
+	f := newViewFixture(t, 0)
+
+	counted := 0
+	for i := 0; i < MaxAnonymousViewsPerIP+3; i++ {
+		viewer := model.Viewer{IP: "192.0.2.1", SessionID: string(rune('a' + i))}
+		if f.watch(t, viewer, 0, 30) {
+			counted++
+		}
+	}
+	if counted != MaxAnonymousViewsPerIP {
+		t.Errorf("expected %d views from one IP address, got %d", MaxAnonymousViewsPerIP, counted)
+	}
+	if !f.watch(t, model.Viewer{IP: "192.0.2.2", SessionID: "a"}, 0, 30) {
+		t.Error("expected another IP address to count")
+	}
+}
+
+func TestRecordView_BoundsViewersNotCountedYet(t *testing.T) {
+	f := newViewFixture(t, 0)
+	f.service.started = newRecentViewers[struct{}](2)
+	ctx := context.Background()
+	event := func(viewer model.Viewer, watched float64) bool {
+		t.Helper()
+		counted, err := f.service.RecordView(ctx, viewer, f.videoID, &model.RecordViewRequest{SessionID: viewer.SessionID, WatchedSeconds: watched})
+		if err != nil {
+			t.Fatalf("RecordView returned error: %v", err)
+		}
+		return counted
+	}
+
+	first := model.Viewer{IP: "192.0.2.1", SessionID: "first"}
+	second := model.Viewer{IP: "192.0.2.1", SessionID: "second"}
+	event(first, 0)
+	f.now = f.now.Add(time.Second)
+	event(second, 0)
+	// Made-up sessions push out the viewers who started longest ago instead of piling up
+	for i := 0; i < 10; i++ {
+		f.now = f.now.Add(time.Second)
+		event(model.Viewer{IP: "192.0.2.9", SessionID: string(rune('a' + i))}, 0)
+	}
+	if n := f.service.started.len(); n != 2 {
+		t.Fatalf("expected 2 tracked viewers, got %d", n)
+	}
+
+	f.now = f.now.Add(MinViewDuration)
+	if event(first, 30) {
+		t.Error("expected a forgotten viewer to start over")
+	}
+	f.now = f.now.Add(MinViewDuration)
+	if !event(first, 60) {
+		t.Error("expected the viewer to count after starting over")
+	}
+}
+
+func TestRecordView_BoundsCountedViewers(t *testing.T) {
+	f := newViewFixture(t, 0)
+	f.service.counted = newRecentViewers[struct{}](2)
+	f.service.ips = newRecentViewers[int](2)
+
+	first := model.Viewer{UserID: 2}
+	if !f.watch(t, first, 0, 30) {
+		t.Fatal("expected the first view to count")
+	}
+	// Anonymous views from rotating IP addresses push out the viewers counted longest ago
+	for i := 0; i < 10; i++ {
+		if !f.watch(t, model.Viewer{IP: fmt.Sprintf("192.0.2.%d", i+1)}, 0, 30) {
+			t.Fatalf("expected the view from IP address %d to count", i+1)
+		}
+	}
+	if n, m := f.service.counted.len(), f.service.ips.len(); n != 2 || m != 2 {
+		t.Fatalf("expected 2 counted viewers and 2 IP addresses, got %d and %d", n, m)
+	}
+
+	if !f.watch(t, first, 0, 30) {
+		t.Error("expected a forgotten viewer to count again")
+	}
+}
+
+func TestRecordView_RejectsInvalidEvents(t *testing.T) {
+	f := newViewFixture(t, 0)
+	ctx := context.Background()
+
+	if _, err := f.service.RecordView(ctx, model.Viewer{UserID: 2}, f.videoID, &model.RecordViewRequest{WatchedSeconds: -1}); !errors.Is(err, ErrInvalidViewEvent) {
+		t.Errorf("expected ErrInvalidViewEvent for negative watch time, got %v", err)
+	}
+	if _, err := f.service.RecordView(ctx, model.Viewer{}, f.videoID, &model.RecordViewRequest{}); !errors.Is(err, ErrInvalidViewEvent) {
+		t.Errorf("expected ErrInvalidViewEvent without a viewer, got %v", err)
+	}
+	if _, err := f.service.RecordView(ctx, model.Viewer{UserID: 2}, f.videoID+1, &model.RecordViewRequest{}); !errors.Is(err, ErrVideoNotFound) {
+		t.Errorf("expected ErrVideoNotFound, got %v", err)
+	}
+}
+
+func TestFlush_WritesBatchAndKeepsViewsOnFailure(t *testing.T) {
+	f := newViewFixture(t, 0)
+	for _, session := range []string{"a", "b", "c"} {
+		f.watch(t, model.Viewer{IP: "192.0.2.1", SessionID: session}, 0, 30)
+	}
+
+	f.db.Fail("VideoRepository.AddViewCounts", errors.New("db down"))
+	if _, err := f.service.Flush(context.Background()); err == nil {
+		t.Fatal("expected Flush to fail")
+	}
+	f.db.Fail("VideoRepository.AddViewCounts", nil)
+
+	before := f.db.Calls()
+	flushed, err := f.service.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+	if flushed != 3 || f.db.Calls()-before != 1 {
+		t.Errorf("expected 3 views in one write, got %d in %d", flushed, f.db.Calls()-before)
+	}
+	if got := storedVideo(t, f.db, f.videoID).ViewCount; got != 3 {
+		t.Errorf("expected 3 views, got %d", got)
+	}
+	if flushed, _ := f.service.Flush(context.Background()); flushed != 0 {
+		t.Errorf("expected nothing left to flush, got %d", flushed)
+	}
+}
+
+func TestGetByID_DoesNotCountViews(t *testing.T) {
+	db := memory.NewDB()
+	video := seedVideo(db, storage.NewMemoryStorage())
+	s := newVideoService(db, storage.NewMemoryStorage())
+
+	for i := 0; i < 3; i++ {
+		if _, err := s.GetByID(context.Background(), 0, video.ID); err != nil {
+			t.Fatalf("GetByID returned error: %v", err)
+		}
+	}
+	if got := storedVideo(t, db, video.ID).ViewCount; got != 0 {
+		t.Errorf("expected reading a video not to count views, got %d", got)
+	}
+}
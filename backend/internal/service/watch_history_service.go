@@ -2,18 +2,31 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/yukito/video-platform/internal/model"
 	"github.com/yukito/video-platform/internal/repository"
 )
 
+const (
+	// WatchCompletedPercent is how much of a video has to be watched for it to count as finished;
+	// finished videos resume from the start and leave continue watching
+	WatchCompletedPercent = 95
+	// MinResumePosition is the position in seconds below which a video starts over instead of resuming
+	MinResumePosition = 10
+)
+
+var ErrInvalidProgress = errors.New("position_seconds must be a non-negative number")
+
 type WatchHistoryService struct {
-	historyRepo *repository.WatchHistoryRepository
-	videoRepo   *repository.VideoRepository
+	historyRepo repository.WatchHistoryStore
+	videoRepo   repository.VideoStore
 }
 
-func NewWatchHistoryService(historyRepo *repository.WatchHistoryRepository, videoRepo *repository.VideoRepository) *WatchHistoryService {
+func NewWatchHistoryService(historyRepo repository.WatchHistoryStore, videoRepo repository.VideoStore) *WatchHistoryService {
 	return &WatchHistoryService{
 		historyRepo: historyRepo,
 		videoRepo:   videoRepo,
@@ -22,11 +35,14 @@ func NewWatchHistoryService(historyRepo *repository.WatchHistoryRepository, vide
 
 // AddToHistory adds a video to user's watch history
 func (s *WatchHistoryService) AddToHistory(ctx context.Context, userID, videoID int64) error {
-	// Check if video exists
-	_, err := s.videoRepo.FindByID(ctx, videoID)
+	// Check if video exists and the user can watch it
+	video, err := s.videoRepo.FindByID(ctx, videoID)
 	if err != nil {
 		return fmt.Errorf("video not found: %w", err)
 	}
+	if !canWatch(video, userID) {
+		return ErrVideoNotFound
+	}
 
 	// Add to history
 	if err := s.historyRepo.AddToHistory(ctx, userID, videoID); err != nil {
@@ -37,13 +53,62 @@ func (s *WatchHistoryService) AddToHistory(ctx context.Context, userID, videoID
 }
 
 // GetWatchHistory returns user's watch history with pagination
-func (s *WatchHistoryService) GetWatchHistory(ctx context.Context, userID int64, limit, offset int) ([]*model.WatchHistory, error) {
-	history, err := s.historyRepo.GetWatchHistory(ctx, userID, limit, offset)
+func (s *WatchHistoryService) GetWatchHistory(ctx context.Context, userID int64, query model.PageQuery) (*model.Page[*model.WatchHistory], error) {
+	limit, page, err := pageRequest(query, DefaultPageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.historyRepo.GetWatchHistory(ctx, userID, page)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get watch history: %w", err)
 	}
 
-	return history, nil
+	return newPage(history, limit, func(h *model.WatchHistory) model.PageCursor {
+		return model.PageCursor{Time: h.WatchedAt, ID: h.ID}
+	}), nil
+}
+
+// SaveProgress records a playback heartbeat of the user and returns their progress in the video
+func (s *WatchHistoryService) SaveProgress(ctx context.Context, userID, videoID int64, req *model.SaveProgressRequest) (*model.WatchProgress, error) {
+	if req.PositionSeconds < 0 || math.IsNaN(req.PositionSeconds) || math.IsInf(req.PositionSeconds, 0) {
+		return nil, ErrInvalidProgress
+	}
+
+	progress, err := s.historyRepo.SaveProgress(ctx, userID, videoID, req.PositionSeconds)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrVideoNotFound
+		}
+		return nil, err
+	}
+	return progress, nil
+}
+
+// GetContinueWatching returns a page of the videos the user has started but not finished, most recently watched first
+func (s *WatchHistoryService) GetContinueWatching(ctx context.Context, userID int64, query model.PageQuery) (*model.Page[*model.WatchHistory], error) {
+	limit, page, err := pageRequest(query, DefaultPageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.historyRepo.GetContinueWatching(ctx, userID, MinResumePosition, WatchCompletedPercent, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get continue watching: %w", err)
+	}
+
+	return newPage(history, limit, func(h *model.WatchHistory) model.PageCursor {
+		return model.PageCursor{Time: h.WatchedAt, ID: h.ID}
+	}), nil
+}
+
+// resumePosition returns where playback of a video continues: the saved position,
+// or the start when the video was finished or barely started
+func resumePosition(progress *model.WatchProgress) float64 {
+	if progress.ProgressPercent >= WatchCompletedPercent || progress.PositionSeconds < MinResumePosition {
+		return 0
+	}
+	return progress.PositionSeconds
 }
 
 // RemoveFromHistory removes a specific video from user's watch history
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/yukito/video-platform/internal/model"
+	"github.com/yukito/video-platform/internal/repository/memory"
+	"github.com/yukito/video-platform/internal/storage"
+)
+
+// seedWatchVideos creates public videos of user 1 with the given durations in seconds
+func seedWatchVideos(t *testing.T, db *memory.DB, durations ...int64) []*model.Video {
+	t.Helper()
+	var videos []*model.Video
+	for i, duration := range durations {
+		video, err := memory.NewVideoRepository(db).Create(context.Background(), &model.Video{
+			UserID:     1,
+			Title:      fmt.Sprintf("video %d", i),
+			Duration:   duration,
+			Status:     model.VideoStatusReady,
+			Visibility: model.VideoVisibilityPublic,
+		})
+		if err != nil {
+			t.Fatalf("failed to seed video: %v", err)
+		}
+		videos = append(videos, video)
+	}
+	return videos
+}
+
+func TestSaveProgress_ComputesPercentAndResumePosition(t *testing.T) {
+	db := memory.NewDB()
+	video := seedWatchVideos(t, db, 200)[0]
+	history := NewWatchHistoryService(memory.NewWatchHistoryRepository(db), memory.NewVideoRepository(db))
+	videos := newVideoService(db, storage.NewMemoryStorage())
+	ctx := context.Background()
+
+	if got, _ := videos.GetByID(ctx, 2, video.ID); got.ResumePositionSeconds != nil {
+		t.Errorf("expected no resume position before watching, got %v", *got.ResumePositionSeconds)
+	}
+
+	progress, err := history.SaveProgress(ctx, 2, video.ID, &model.SaveProgressRequest{PositionSeconds: 50})
+	if err != nil {
+		t.Fatalf("SaveProgress returned error: %v", err)
+	}
+	if progress.PositionSeconds != 50 || progress.ProgressPercent != 25 {
+		t.Errorf("expected 50 seconds at 25%%, got %+v", progress)
+	}
+
+	got, _ := videos.GetByID(ctx, 2, video.ID)
+	if got.ResumePositionSeconds == nil || *got.ResumePositionSeconds != 50 {
+		t.Errorf("expected to resume at 50 seconds, got %v", got.ResumePositionSeconds)
+	}
+	if got, _ := videos.GetByID(ctx, 0, video.ID); got.ResumePositionSeconds != nil {
+		t.Error("expected no resume position for anonymous viewers")
+	}
+
+	// Positions past the end are clamped, and finished videos start over
+	progress, _ = history.SaveProgress(ctx, 2, video.ID, &model.SaveProgressRequest{PositionSeconds: 500})
+	if progress.PositionSeconds != 200 || progress.ProgressPercent != 100 {
+		t.Errorf("expected the position clamped to the duration, got %+v", progress)
+	}
+	if got, _ := videos.GetByID(ctx, 2, video.ID); got.ResumePositionSeconds == nil || *got.ResumePositionSeconds != 0 {
+		t.Errorf("expected a finished video to resume from the start, got %v", got.ResumePositionSeconds)
+	}
+}
+
+func TestSaveProgress_RejectsInvalidRequests(t *testing.T) {
+	db := memory.NewDB()
+	video := seedWatchVideos(t, db, 100)[0]
+	history := NewWatchHistoryService(memory.NewWatchHistoryRepository(db), memory.NewVideoRepository(db))
+	ctx := context.Background()
+
+	for _, position := range []float64{-1, math.NaN(), math.Inf(1)} {
+		if _, err := history.SaveProgress(ctx, 2, video.ID, &model.SaveProgressRequest{PositionSeconds: position}); !errors.Is(err, ErrInvalidProgress) {
+			t.Errorf("expected ErrInvalidProgress for %v, got %v", position, err)
+		}
+	}
+
+	private, _ := memory.NewVideoRepository(db).Create(ctx, &model.Video{UserID: 1, Title: "private", Status: model.VideoStatusReady, Visibility: model.VideoVisibilityPrivate})
+	if _, err := history.SaveProgress(ctx, 2, private.ID, &model.SaveProgressRequest{PositionSeconds: 10}); !errors.Is(err, ErrVideoNotFound) {
+		t.Errorf("expected ErrVideoNotFound for another user's private video, got %v", err)
+	}
+	if _, err := history.SaveProgress(ctx, 2, private.ID+1, &model.SaveProgressRequest{PositionSeconds: 10}); !errors.Is(err, ErrVideoNotFound) {
+		t.Errorf("expected ErrVideoNotFound for a missing video, got %v", err)
+	}
+}
+
+func TestContinueWatching_ListsPartiallyWatchedVideos(t *testing.T) {
+	db := memory.NewDB()
+	videos := seedWatchVideos(t, db, 100, 100, 100, 100)
+	history := NewWatchHistoryService(memory.NewWatchHistoryRepository(db), memory.NewVideoRepository(db))
+	ctx := context.Background()
+
+	positions := []float64{40, 99, 3, 60} // partial, finished, barely started, partial
+	for i, position := range positions {
+		if _, err := history.SaveProgress(ctx, 2, videos[i].ID, &model.SaveProgressRequest{PositionSeconds: position}); err != nil {
+			t.Fatalf("SaveProgress returned error: %v", err)
+		}
+	}
+	_ = history.AddToHistory(ctx, 2, videos[1].ID)
+
+	page, err := history.GetContinueWatching(ctx, 2, model.PageQuery{Limit: 1})
+	if err != nil {
+		t.Fatalf("GetContinueWatching returned error: %v", err)
+	}
+	next, _ := history.GetContinueWatching(ctx, 2, model.PageQuery{Limit: 1, Cursor: page.NextCursor})
+
+	if len(page.Items) != 1 || page.Items[0].VideoID != videos[3].ID || len(next.Items) != 1 || next.Items[0].VideoID != videos[0].ID || next.HasMore {
+		t.Fatalf("expected the two partially watched videos most recent first, got %+v then %+v", page.Items, next.Items)
+	}
+	if next.Items[0].PositionSeconds != 40 || next.Items[0].ProgressPercent != 40 || next.Items[0].Video == nil {
+		t.Errorf("expected the entry with its progress and video, got %+v", next.Items[0])
+	}
+
+	all, _ := history.GetWatchHistory(ctx, 2, model.PageQuery{})
+	if len(all.Items) != 4 || all.Items[0].VideoID != videos[1].ID || all.Items[0].ProgressPercent != 99 {
+		t.Errorf("expected the whole history with progress, got %+v", all.Items)
+	}
+}
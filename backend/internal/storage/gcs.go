@@ -3,13 +3,13 @@ package storage
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"path/filepath"
-	"time"
 
 	"cloud.google.com/go/storage"
-	"github.com/google/uuid"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
@@ -50,8 +50,7 @@ func NewGCSStorage(ctx context.Context, projectID, bucketName, credentialsJSON s
 // UploadFile uploads a file to GCS and returns the public URL
 func (s *GCSStorage) UploadFile(ctx context.Context, file io.Reader, filename string, contentType string, fileSize int64) (string, error) {
 	// Generate unique filename
-	ext := filepath.Ext(filename)
-	uniqueFilename := fmt.Sprintf("%s_%d%s", uuid.New().String(), time.Now().Unix(), ext)
+	uniqueFilename := newObjectName(filename)
 
 	// Get bucket handle
 	bucket := s.client.Bucket(s.bucketName)
@@ -98,7 +97,117 @@ func (s *GCSStorage) DeleteFile(ctx context.Context, fileURL string) error {
 	return nil
 }
 
+// OpenFile returns a reader for a stored object
+func (s *GCSStorage) OpenFile(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	objectName := filepath.Base(fileURL)
+
+	reader, err := s.client.Bucket(s.bucketName).Object(objectName).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file from GCS: %w", err)
+	}
+
+	return reader, nil
+}
+
+// gcsMaxComposeSources is the most source objects a single GCS compose request accepts
+const gcsMaxComposeSources = 32
+
+// UploadPart stores one chunk of a multipart upload and returns its object name
+func (s *GCSStorage) UploadPart(ctx context.Context, uploadID string, partNumber int, part io.Reader, size int64) (string, error) {
+	key := newPartKey(uploadID, partNumber)
+
+	writer := s.client.Bucket(s.bucketName).Object(key).NewWriter(ctx)
+	writer.ContentType = "application/octet-stream"
+
+	if _, err := io.Copy(writer, part); err != nil {
+		writer.Close()
+		return "", fmt.Errorf("failed to write part to GCS: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to close GCS writer: %w", err)
+	}
+
+	return key, nil
+}
+
+// ComposeParts concatenates the parts into a new object and returns its public URL
+// GCS composes at most 32 objects per request, so larger uploads are folded
+// into the destination object batch by batch
+func (s *GCSStorage) ComposeParts(ctx context.Context, partKeys []string, filename string, contentType string) (string, error) {
+	if len(partKeys) == 0 {
+		return "", fmt.Errorf("failed to compose file: no parts")
+	}
+
+	uniqueFilename := newObjectName(filename)
+	bucket := s.client.Bucket(s.bucketName)
+	dst := bucket.Object(uniqueFilename)
+
+	for next := 0; next < len(partKeys); {
+		var srcs []*storage.ObjectHandle
+		if next > 0 {
+			// Continue from what has been composed so far
+			srcs = append(srcs, dst)
+		}
+		for len(srcs) < gcsMaxComposeSources && next < len(partKeys) {
+			srcs = append(srcs, bucket.Object(partKeys[next]))
+			next++
+		}
+
+		composer := dst.ComposerFrom(srcs...)
+		composer.ContentType = contentType
+		if _, err := composer.Run(ctx); err != nil {
+			_ = dst.Delete(ctx)
+			return "", fmt.Errorf("failed to compose file in GCS: %w", err)
+		}
+	}
+
+	url := fmt.Sprintf("https://storage.googleapis.com/%s/%s", s.bucketName, uniqueFilename)
+
+	return url, nil
+}
+
+// DeleteParts removes every stored part of an upload
+func (s *GCSStorage) DeleteParts(ctx context.Context, uploadID string) error {
+	bucket := s.client.Bucket(s.bucketName)
+	it := bucket.Objects(ctx, &storage.Query{Prefix: partsPrefix(uploadID)})
+
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list parts in GCS: %w", err)
+		}
+		if err := bucket.Object(attrs.Name).Delete(ctx); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			return fmt.Errorf("failed to delete part from GCS: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // Close closes the GCS client
 func (s *GCSStorage) Close() error {
 	return s.client.Close()
 }
+
+// Usage sums the sizes of all objects in the bucket
+func (s *GCSStorage) Usage(ctx context.Context) (int64, error) {
+	it := s.client.Bucket(s.bucketName).Objects(ctx, nil)
+
+	var total int64
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to list objects in GCS: %w", err)
+		}
+		total += attrs.Size
+	}
+
+	return total, nil
+}
@@ -0,0 +1,270 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalFilesRoute is the URL path under which LocalStorage files are served
+const LocalFilesRoute = "/files"
+
+func init() {
+	// Go's built-in MIME table has no video types, so register the ones we
+	// serve to keep Content-Type correct on hosts without /etc/mime.types
+	for ext, contentType := range map[string]string{
+		".mp4":  "video/mp4",
+		".m4v":  "video/x-m4v",
+		".mov":  "video/quicktime",
+		".webm": "video/webm",
+		".mkv":  "video/x-matroska",
+		".ogv":  "video/ogg",
+		".m3u8": "application/vnd.apple.mpegurl",
+		".ts":   "video/mp2t",
+	} {
+		_ = mime.AddExtensionType(ext, contentType)
+	}
+}
+
+type LocalStorage struct {
+	baseDir string
+	baseURL string
+}
+
+// NewLocalStorage creates a storage that keeps files under baseDir
+// baseURL is the public origin of the API (e.g. http://localhost:8080)
+func NewLocalStorage(baseDir, baseURL string) (*LocalStorage, error) {
+	absDir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve storage directory: %w", err)
+	}
+
+	// Create directory if it doesn't exist
+	if err := os.MkdirAll(absDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	return &LocalStorage{
+		baseDir: absDir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}, nil
+}
+
+// Dir returns the directory files are stored in, for serving them over HTTP
+func (s *LocalStorage) Dir() string {
+	return s.baseDir
+}
+
+// PublishedFiles returns the files to serve under LocalFilesRoute: the published objects
+// (videos, thumbnails and HLS playlists and segments) at the top of the storage directory
+// Upload parts in parts/ and files still being written are not served
+func (s *LocalStorage) PublishedFiles() http.FileSystem {
+	return publishedFS{dir: s.baseDir}
+}
+
+type publishedFS struct {
+	dir string
+}
+
+// Open opens a top-level regular file whose name does not start with a dot
+func (fs publishedFS) Open(name string) (http.File, error) {
+	objectName := strings.TrimPrefix(path.Clean("/"+name), "/")
+	if objectName == "" || strings.Contains(objectName, "/") || strings.HasPrefix(objectName, ".") {
+		return nil, os.ErrNotExist
+	}
+
+	f, err := os.Open(filepath.Join(fs.dir, objectName))
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		f.Close()
+		return nil, os.ErrNotExist
+	}
+	return f, nil
+}
+
+// UploadFile writes a file to the storage directory and returns its public URL
+func (s *LocalStorage) UploadFile(ctx context.Context, file io.Reader, filename string, contentType string, fileSize int64) (string, error) {
+	// Generate unique filename
+	uniqueFilename := newObjectName(filename)
+
+	if err := s.writeFile(filepath.Join(s.baseDir, uniqueFilename), &contextReader{ctx: ctx, r: file}); err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s%s/%s", s.baseURL, LocalFilesRoute, uniqueFilename)
+
+	return url, nil
+}
+
+// writeFile copies r into path
+// It writes to a temp file first so a failed upload never leaves a partial file behind
+func (s *LocalStorage) writeFile(path string, r io.Reader) error {
+	tmp, err := os.CreateTemp(s.baseDir, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	tmpName := tmp.Name()
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to store file: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteFile removes a file from the storage directory
+func (s *LocalStorage) DeleteFile(ctx context.Context, fileURL string) error {
+	// Extract file name from URL
+	// URL format: http://host/files/filename
+	objectName := filepath.Base(fileURL)
+	if objectName == "." || objectName == "/" || strings.HasPrefix(objectName, ".") {
+		return fmt.Errorf("invalid file URL: %s", fileURL)
+	}
+
+	if err := os.Remove(filepath.Join(s.baseDir, objectName)); err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+
+	return nil
+}
+
+// OpenFile opens a stored file for reading
+func (s *LocalStorage) OpenFile(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	objectName := filepath.Base(fileURL)
+	if objectName == "." || objectName == "/" || strings.HasPrefix(objectName, ".") {
+		return nil, fmt.Errorf("invalid file URL: %s", fileURL)
+	}
+
+	f, err := os.Open(filepath.Join(s.baseDir, objectName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	return f, nil
+}
+
+// UploadPart writes one chunk of a multipart upload under parts/<uploadID>/ and returns its key
+func (s *LocalStorage) UploadPart(ctx context.Context, uploadID string, partNumber int, part io.Reader, size int64) (string, error) {
+	if !validUploadID(uploadID) {
+		return "", fmt.Errorf("invalid upload ID: %s", uploadID)
+	}
+
+	if err := os.MkdirAll(filepath.Join(s.baseDir, filepath.FromSlash(partsPrefix(uploadID))), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create parts directory: %w", err)
+	}
+
+	key := newPartKey(uploadID, partNumber)
+	if err := s.writeFile(filepath.Join(s.baseDir, filepath.FromSlash(key)), &contextReader{ctx: ctx, r: part}); err != nil {
+		return "", fmt.Errorf("failed to upload part: %w", err)
+	}
+
+	return key, nil
+}
+
+// ComposeParts concatenates the parts in order into a new file and returns its public URL
+func (s *LocalStorage) ComposeParts(ctx context.Context, partKeys []string, filename string, contentType string) (string, error) {
+	if len(partKeys) == 0 {
+		return "", fmt.Errorf("failed to compose file: no parts")
+	}
+
+	readers := make([]io.Reader, 0, len(partKeys))
+	for _, key := range partKeys {
+		if !strings.HasPrefix(key, "parts/") || strings.Contains(key, "..") {
+			return "", fmt.Errorf("invalid part key: %s", key)
+		}
+
+		f, err := os.Open(filepath.Join(s.baseDir, filepath.FromSlash(key)))
+		if err != nil {
+			return "", fmt.Errorf("failed to open part: %w", err)
+		}
+		defer f.Close()
+		readers = append(readers, f)
+	}
+
+	uniqueFilename := newObjectName(filename)
+	if err := s.writeFile(filepath.Join(s.baseDir, uniqueFilename), &contextReader{ctx: ctx, r: io.MultiReader(readers...)}); err != nil {
+		return "", fmt.Errorf("failed to compose file: %w", err)
+	}
+
+	url := fmt.Sprintf("%s%s/%s", s.baseURL, LocalFilesRoute, uniqueFilename)
+
+	return url, nil
+}
+
+// DeleteParts removes the parts directory of an upload
+func (s *LocalStorage) DeleteParts(ctx context.Context, uploadID string) error {
+	if !validUploadID(uploadID) {
+		return fmt.Errorf("invalid upload ID: %s", uploadID)
+	}
+
+	if err := os.RemoveAll(filepath.Join(s.baseDir, filepath.FromSlash(partsPrefix(uploadID)))); err != nil {
+		return fmt.Errorf("failed to delete parts: %w", err)
+	}
+
+	return nil
+}
+
+// validUploadID rejects IDs that could escape the parts directory
+func validUploadID(uploadID string) bool {
+	return uploadID != "" && !strings.HasPrefix(uploadID, ".") && !strings.ContainsAny(uploadID, `/\`)
+}
+
+// contextReader stops a copy as soon as the request context is cancelled
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
+
+// Usage sums the sizes of all files under the base directory
+func (s *LocalStorage) Usage(ctx context.Context) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(s.baseDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure storage usage: %w", err)
+	}
+	return total, nil
+}
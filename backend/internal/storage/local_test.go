@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorage_ComposeParts(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := NewLocalStorage(dir, "http://localhost:8080")
+	if err != nil {
+		t.Fatalf("NewLocalStorage returned error: %v", err)
+	}
+
+	var keys []string
+	for i, chunk := range []string{"hello ", "local ", "storage"} {
+		key, err := s.UploadPart(ctx, "upload-1", i+1, strings.NewReader(chunk), int64(len(chunk)))
+		if err != nil {
+			t.Fatalf("UploadPart returned error: %v", err)
+		}
+		keys = append(keys, key)
+	}
+
+	url, err := s.ComposeParts(ctx, keys, "movie.mp4", "video/mp4")
+	if err != nil {
+		t.Fatalf("ComposeParts returned error: %v", err)
+	}
+	if !strings.HasPrefix(url, "http://localhost:8080/files/") || filepath.Ext(url) != ".mp4" {
+		t.Errorf("unexpected URL: %s", url)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, filepath.Base(url)))
+	if err != nil || string(data) != "hello local storage" {
+		t.Errorf("unexpected composed file: %q, %v", data, err)
+	}
+
+	if err := s.DeleteParts(ctx, "upload-1"); err != nil {
+		t.Fatalf("DeleteParts returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "parts", "upload-1")); !os.IsNotExist(err) {
+		t.Errorf("expected parts directory to be removed, got %v", err)
+	}
+}
+
+func TestLocalStorage_PublishedFilesHideUploadParts(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStorage(t.TempDir(), "http://localhost:8080")
+	if err != nil {
+		t.Fatalf("NewLocalStorage returned error: %v", err)
+	}
+
+	url, err := s.UploadFile(ctx, strings.NewReader("video"), "movie.mp4", "video/mp4", 5)
+	if err != nil {
+		t.Fatalf("UploadFile returned error: %v", err)
+	}
+	key, err := s.UploadPart(ctx, "upload-1", 1, strings.NewReader("chunk"), 5)
+	if err != nil {
+		t.Fatalf("UploadPart returned error: %v", err)
+	}
+
+	server := http.StripPrefix(LocalFilesRoute, http.FileServer(s.PublishedFiles()))
+	get := func(path string) int {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	if code := get(LocalFilesRoute + "/" + filepath.Base(url)); code != http.StatusOK {
+		t.Errorf("expected the published file to be served, got %d", code)
+	}
+	for _, path := range []string{"/" + key, "/parts/upload-1/", "/parts/", "/"} {
+		if code := get(LocalFilesRoute + path); code != http.StatusNotFound {
+			t.Errorf("expected %s not to be served, got %d", path, code)
+		}
+	}
+}
+
+func TestLocalStorage_RejectsUnsafeUploadIDs(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir(), "http://localhost:8080")
+	if err != nil {
+		t.Fatalf("NewLocalStorage returned error: %v", err)
+	}
+
+	for _, id := range []string{"", "..", "../escape", "a/b"} {
+		if _, err := s.UploadPart(context.Background(), id, 1, strings.NewReader("x"), 1); err == nil {
+			t.Errorf("expected upload ID %q to be rejected", id)
+		}
+	}
+	if _, err := s.ComposeParts(context.Background(), []string{"parts/../../etc/passwd"}, "x.mp4", ""); err == nil {
+		t.Error("expected a part key outside the parts directory to be rejected")
+	}
+}
+
+func TestLocalStorage_Usage(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStorage(t.TempDir(), "http://localhost:8080")
+	if err != nil {
+		t.Fatalf("NewLocalStorage returned error: %v", err)
+	}
+
+	if _, err := s.UploadFile(ctx, strings.NewReader("12345"), "movie.mp4", "video/mp4", 5); err != nil {
+		t.Fatalf("UploadFile returned error: %v", err)
+	}
+	if _, err := s.UploadPart(ctx, "upload-1", 1, strings.NewReader("abc"), 3); err != nil {
+		t.Fatalf("UploadPart returned error: %v", err)
+	}
+
+	usage, err := s.Usage(ctx)
+	if err != nil {
+		t.Fatalf("Usage returned error: %v", err)
+	}
+	if usage != 8 {
+		t.Errorf("expected 8 bytes in use, got %d", usage)
+	}
+}
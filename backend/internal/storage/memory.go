@@ -0,0 +1,225 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// MemoryStorage keeps files in memory and records every call
+// It is meant for tests: failures can be injected per original filename
+type MemoryStorage struct {
+	mu         sync.Mutex
+	files      map[string][]byte
+	uploads    []string
+	deletes    []string
+	uploadErrs map[string]error
+	deleteErr  error
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		files:      make(map[string][]byte),
+		uploadErrs: make(map[string]error),
+	}
+}
+
+// UploadFile stores the file content and returns a memory:// URL
+func (s *MemoryStorage) UploadFile(ctx context.Context, file io.Reader, filename string, contentType string, fileSize int64) (string, error) {
+	s.mu.Lock()
+	uploadErr := s.uploadErrs[filename]
+	s.mu.Unlock()
+
+	if uploadErr != nil {
+		return "", fmt.Errorf("failed to upload file: %w", uploadErr)
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	url := fmt.Sprintf("memory://%s%s", uuid.New().String(), filepath.Ext(filename))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[url] = data
+	s.uploads = append(s.uploads, url)
+
+	return url, nil
+}
+
+// DeleteFile removes a stored file; the call is recorded even if it fails
+func (s *MemoryStorage) DeleteFile(ctx context.Context, fileURL string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deletes = append(s.deletes, fileURL)
+
+	if s.deleteErr != nil {
+		return fmt.Errorf("failed to delete file: %w", s.deleteErr)
+	}
+
+	if _, ok := s.files[fileURL]; !ok {
+		return fmt.Errorf("failed to delete file: %s not found", fileURL)
+	}
+	delete(s.files, fileURL)
+
+	return nil
+}
+
+// OpenFile returns a reader over the stored content
+func (s *MemoryStorage) OpenFile(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	data, ok := s.Get(fileURL)
+	if !ok {
+		return nil, fmt.Errorf("failed to open file: %s not found", fileURL)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// UploadPart stores one chunk of a multipart upload under its part key
+func (s *MemoryStorage) UploadPart(ctx context.Context, uploadID string, partNumber int, part io.Reader, size int64) (string, error) {
+	data, err := io.ReadAll(part)
+	if err != nil {
+		return "", fmt.Errorf("failed to read part: %w", err)
+	}
+
+	key := newPartKey(uploadID, partNumber)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[key] = data
+
+	return key, nil
+}
+
+// ComposeParts concatenates the stored parts into a new memory:// file
+// A compose can be made to fail with FailUpload using the target filename
+func (s *MemoryStorage) ComposeParts(ctx context.Context, partKeys []string, filename string, contentType string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.uploadErrs[filename]; err != nil {
+		return "", fmt.Errorf("failed to compose file: %w", err)
+	}
+	if len(partKeys) == 0 {
+		return "", fmt.Errorf("failed to compose file: no parts")
+	}
+
+	var data []byte
+	for _, key := range partKeys {
+		part, ok := s.files[key]
+		if !ok {
+			return "", fmt.Errorf("failed to compose file: part %s not found", key)
+		}
+		data = append(data, part...)
+	}
+
+	url := fmt.Sprintf("memory://%s%s", uuid.New().String(), filepath.Ext(filename))
+	s.files[url] = data
+	s.uploads = append(s.uploads, url)
+
+	return url, nil
+}
+
+// DeleteParts removes every stored part of an upload
+func (s *MemoryStorage) DeleteParts(ctx context.Context, uploadID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prefix := partsPrefix(uploadID)
+	for key := range s.files {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.files, key)
+		}
+	}
+
+	return nil
+}
+
+// Usage sums the sizes of the stored files and parts
+func (s *MemoryStorage) Usage(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var total int64
+	for _, data := range s.files {
+		total += int64(len(data))
+	}
+	return total, nil
+}
+
+// FailUpload makes every upload of the given original filename return err
+// Passing a nil err clears the failure
+func (s *MemoryStorage) FailUpload(filename string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		delete(s.uploadErrs, filename)
+		return
+	}
+	s.uploadErrs[filename] = err
+}
+
+// FailDelete makes every delete return err; passing nil clears the failure
+func (s *MemoryStorage) FailDelete(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleteErr = err
+}
+
+// Put stores content under a fixed URL, e.g. to seed files referenced by existing rows
+func (s *MemoryStorage) Put(fileURL string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[fileURL] = data
+}
+
+// Get returns the content stored under fileURL
+func (s *MemoryStorage) Get(fileURL string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.files[fileURL]
+	return data, ok
+}
+
+// Has reports whether a file is currently stored under fileURL
+func (s *MemoryStorage) Has(fileURL string) bool {
+	_, ok := s.Get(fileURL)
+	return ok
+}
+
+// Files returns the URLs and part keys of all currently stored files, sorted
+func (s *MemoryStorage) Files() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	urls := make([]string, 0, len(s.files))
+	for url := range s.files {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+// Uploads returns the URLs of all successful uploads in call order
+func (s *MemoryStorage) Uploads() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.uploads...)
+}
+
+// Deletes returns the URLs passed to DeleteFile in call order
+func (s *MemoryStorage) Deletes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.deletes...)
+}
@@ -5,9 +5,7 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
-	"time"
 
-	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 )
@@ -86,8 +84,7 @@ func (s *MinIOStorage) ensureBucket(ctx context.Context) error {
 
 func (s *MinIOStorage) UploadFile(ctx context.Context, file io.Reader, filename string, contentType string, fileSize int64) (string, error) {
 	// Generate unique filename
-	ext := filepath.Ext(filename)
-	uniqueFilename := fmt.Sprintf("%s_%d%s", uuid.New().String(), time.Now().Unix(), ext)
+	uniqueFilename := newObjectName(filename)
 
 	// Upload file
 	_, err := s.client.PutObject(ctx, s.bucketName, uniqueFilename, file, fileSize, minio.PutObjectOptions{
@@ -97,14 +94,16 @@ func (s *MinIOStorage) UploadFile(ctx context.Context, file io.Reader, filename
 		return "", fmt.Errorf("failed to upload file: %w", err)
 	}
 
-	// Generate URL using public endpoint
+	return s.objectURL(uniqueFilename), nil
+}
+
+// objectURL generates the public URL of an object using the public endpoint
+func (s *MinIOStorage) objectURL(objectName string) string {
 	protocol := "http"
 	if s.useSSL {
 		protocol = "https"
 	}
-	url := fmt.Sprintf("%s://%s/%s/%s", protocol, s.publicEndpoint, s.bucketName, uniqueFilename)
-
-	return url, nil
+	return fmt.Sprintf("%s://%s/%s/%s", protocol, s.publicEndpoint, s.bucketName, objectName)
 }
 
 func (s *MinIOStorage) DeleteFile(ctx context.Context, fileURL string) error {
@@ -119,3 +118,94 @@ func (s *MinIOStorage) DeleteFile(ctx context.Context, fileURL string) error {
 
 	return nil
 }
+
+// OpenFile returns a reader for a stored object
+func (s *MinIOStorage) OpenFile(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	objectName := filepath.Base(fileURL)
+
+	object, err := s.client.GetObject(ctx, s.bucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	// GetObject is lazy; Stat surfaces a missing object before the caller starts reading
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	return object, nil
+}
+
+// UploadPart stores one chunk of a multipart upload and returns its object key
+func (s *MinIOStorage) UploadPart(ctx context.Context, uploadID string, partNumber int, part io.Reader, size int64) (string, error) {
+	key := newPartKey(uploadID, partNumber)
+
+	_, err := s.client.PutObject(ctx, s.bucketName, key, part, size, minio.PutObjectOptions{
+		ContentType: "application/octet-stream",
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload part: %w", err)
+	}
+
+	return key, nil
+}
+
+// ComposeParts concatenates the parts server-side into a new object and returns its URL
+// Every part except the last must be at least 5 MiB (S3 multipart limit)
+func (s *MinIOStorage) ComposeParts(ctx context.Context, partKeys []string, filename string, contentType string) (string, error) {
+	if len(partKeys) == 0 {
+		return "", fmt.Errorf("failed to compose file: no parts")
+	}
+
+	uniqueFilename := newObjectName(filename)
+
+	srcs := make([]minio.CopySrcOptions, len(partKeys))
+	for i, key := range partKeys {
+		srcs[i] = minio.CopySrcOptions{Bucket: s.bucketName, Object: key}
+	}
+
+	_, err := s.client.ComposeObject(ctx, minio.CopyDestOptions{
+		Bucket:      s.bucketName,
+		Object:      uniqueFilename,
+		ContentType: contentType,
+	}, srcs...)
+	if err != nil {
+		return "", fmt.Errorf("failed to compose file: %w", err)
+	}
+
+	return s.objectURL(uniqueFilename), nil
+}
+
+// DeleteParts removes every stored part of an upload
+func (s *MinIOStorage) DeleteParts(ctx context.Context, uploadID string) error {
+	objects := s.client.ListObjects(ctx, s.bucketName, minio.ListObjectsOptions{
+		Prefix:    partsPrefix(uploadID),
+		Recursive: true,
+	})
+
+	// Drain the error channel so the remover goroutine can finish
+	var firstErr error
+	for removeErr := range s.client.RemoveObjects(ctx, s.bucketName, objects, minio.RemoveObjectsOptions{}) {
+		if firstErr == nil {
+			firstErr = removeErr.Err
+		}
+	}
+	if firstErr != nil {
+		return fmt.Errorf("failed to delete parts: %w", firstErr)
+	}
+
+	return nil
+}
+
+// Usage sums the sizes of all objects in the bucket
+func (s *MinIOStorage) Usage(ctx context.Context) (int64, error) {
+	var total int64
+	for object := range s.client.ListObjects(ctx, s.bucketName, minio.ListObjectsOptions{Recursive: true}) {
+		if object.Err != nil {
+			return 0, fmt.Errorf("failed to list objects: %w", object.Err)
+		}
+		total += object.Size
+	}
+	return total, nil
+}
@@ -2,11 +2,41 @@ package storage
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 // Storage is an interface for file storage operations
 type Storage interface {
 	UploadFile(ctx context.Context, file io.Reader, filename string, contentType string, fileSize int64) (string, error)
 	DeleteFile(ctx context.Context, fileURL string) error
+	OpenFile(ctx context.Context, fileURL string) (io.ReadCloser, error)
+
+	// Multipart uploads: chunks are stored as parts, then composed into one file
+	UploadPart(ctx context.Context, uploadID string, partNumber int, part io.Reader, size int64) (string, error)
+	ComposeParts(ctx context.Context, partKeys []string, filename string, contentType string) (string, error)
+	DeleteParts(ctx context.Context, uploadID string) error
+
+	// Usage returns the total size in bytes of everything stored, including upload parts
+	Usage(ctx context.Context) (int64, error)
+}
+
+// partsPrefix is the object key prefix under which an upload's parts are stored
+func partsPrefix(uploadID string) string {
+	return "parts/" + uploadID + "/"
+}
+
+// newPartKey returns a unique object key for one part of an upload
+// The random suffix keeps a retried chunk from overwriting a part that is already recorded
+func newPartKey(uploadID string, partNumber int) string {
+	return fmt.Sprintf("%s%05d_%s", partsPrefix(uploadID), partNumber, uuid.New().String())
+}
+
+// newObjectName generates a unique object name that keeps the original file extension
+func newObjectName(filename string) string {
+	return fmt.Sprintf("%s_%d%s", uuid.New().String(), time.Now().Unix(), filepath.Ext(filename))
 }
@@ -0,0 +1,87 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 key of the RFC 6238 test vectors ("12345678901234567890")
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode_MatchesRFC6238Vectors(t *testing.T) {
+	// The RFC lists eight digit codes; six digit codes are their last six digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("Code returned error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("at %d: expected %s, got %s", tt.unix, tt.want, got)
+		}
+	}
+}
+
+func TestValidate_AcceptsAdjacentPeriodsOnly(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret returned error: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+
+	for offset, accepted := range map[time.Duration]bool{
+		-2 * Period: false,
+		-Period:     true,
+		0:           true,
+		Period:      true,
+		2 * Period:  false,
+	} {
+		code, _ := Code(secret, now.Add(offset))
+		step, ok := Validate(secret, code, now)
+		if ok != accepted {
+			t.Errorf("code of %v: expected accepted=%v", offset, accepted)
+		}
+		if ok && step != Step(now.Add(offset)) {
+			t.Errorf("code of %v: expected step %d, got %d", offset, Step(now.Add(offset)), step)
+		}
+	}
+
+	for _, code := range []string{"", "12345", "1234567", "abcdef"} {
+		if _, ok := Validate(secret, code, now); ok {
+			t.Errorf("expected %q to be rejected", code)
+		}
+	}
+	if _, ok := Validate("not base32!", "123456", now); ok {
+		t.Error("expected an invalid secret to be rejected")
+	}
+}
+
+func TestURI_CarriesSecretAndIssuer(t *testing.T) {
+	uri := URI("Video Platform", "alice@example.com", rfcSecret)
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", uri, err)
+	}
+	if parsed.Scheme != "otpauth" || parsed.Host != "totp" {
+		t.Errorf("unexpected URI %q", uri)
+	}
+	if parsed.Path != "/Video Platform:alice@example.com" {
+		t.Errorf("unexpected label %q", parsed.Path)
+	}
+	query := parsed.Query()
+	if query.Get("secret") != rfcSecret || query.Get("issuer") != "Video Platform" || query.Get("digits") != "6" || query.Get("period") != "30" {
+		t.Errorf("unexpected parameters %v", query)
+	}
+}
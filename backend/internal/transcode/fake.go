@@ -0,0 +1,87 @@
+package transcode
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FakeTranscoder writes small placeholder playlists and segments without running ffmpeg
+// It is meant for tests: every call is recorded and a failure can be injected
+type FakeTranscoder struct {
+	mu       sync.Mutex
+	err      error
+	sources  []string
+	segments int
+}
+
+func NewFakeTranscoder() *FakeTranscoder {
+	return &FakeTranscoder{segments: 2}
+}
+
+// Transcode writes <profile>/index.m3u8 with two segments for every profile
+// Each segment contains the profile name and the source file content
+func (t *FakeTranscoder) Transcode(ctx context.Context, sourcePath, outputDir string, profiles []Profile) ([]Output, error) {
+	t.mu.Lock()
+	err := t.err
+	t.sources = append(t.sources, sourcePath)
+	t.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source: %w", err)
+	}
+
+	var outputs []Output
+	for _, profile := range profiles {
+		dir := filepath.Join(outputDir, profile.Name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+
+		playlist := "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n#EXT-X-PLAYLIST-TYPE:VOD\n"
+		for i := 0; i < t.segments; i++ {
+			name := fmt.Sprintf("segment_%04d.ts", i)
+			content := fmt.Sprintf("%s/%d:%s", profile.Name, i, source)
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+				return nil, err
+			}
+			playlist += "#EXTINF:6.000000,\n" + name + "\n"
+		}
+		playlist += "#EXT-X-ENDLIST\n"
+
+		playlistPath := filepath.Join(dir, "index.m3u8")
+		if err := os.WriteFile(playlistPath, []byte(playlist), 0o644); err != nil {
+			return nil, err
+		}
+
+		outputs = append(outputs, Output{
+			Profile:      profile,
+			Width:        profile.Height * 16 / 9,
+			Height:       profile.Height,
+			PlaylistPath: playlistPath,
+		})
+	}
+
+	return outputs, nil
+}
+
+// Fail makes every Transcode call return err; passing nil clears the failure
+func (t *FakeTranscoder) Fail(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.err = err
+}
+
+// Calls returns how many times Transcode was called
+func (t *FakeTranscoder) Calls() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.sources)
+}
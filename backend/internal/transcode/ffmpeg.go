@@ -0,0 +1,126 @@
+package transcode
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/yukito/video-platform/internal/media"
+)
+
+// FFmpegTranscoder produces HLS renditions by running the ffmpeg and ffprobe binaries
+type FFmpegTranscoder struct {
+	ffmpegPath     string
+	ffprobePath    string
+	segmentSeconds int
+}
+
+// NewFFmpegTranscoder creates a transcoder; empty paths fall back to "ffmpeg" and "ffprobe" on PATH
+func NewFFmpegTranscoder(ffmpegPath, ffprobePath string) *FFmpegTranscoder {
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+	if ffprobePath == "" {
+		ffprobePath = "ffprobe"
+	}
+	return &FFmpegTranscoder{
+		ffmpegPath:     ffmpegPath,
+		ffprobePath:    ffprobePath,
+		segmentSeconds: 6,
+	}
+}
+
+// Available reports whether the ffmpeg and ffprobe binaries can be found
+func (t *FFmpegTranscoder) Available() error {
+	for _, path := range []string{t.ffmpegPath, t.ffprobePath} {
+		if _, err := exec.LookPath(path); err != nil {
+			return fmt.Errorf("%s not found: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Transcode encodes one H.264/AAC HLS rendition per profile that fits the source resolution
+func (t *FFmpegTranscoder) Transcode(ctx context.Context, sourcePath, outputDir string, profiles []Profile) ([]Output, error) {
+	width, height, err := t.probeResolution(ctx, sourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var outputs []Output
+	for i, profile := range profiles {
+		// Never upscale, but always keep the smallest rendition
+		if profile.Height > height && i < len(profiles)-1 {
+			continue
+		}
+
+		outHeight := profile.Height
+		if outHeight > height {
+			outHeight = height &^ 1
+		}
+		// Keep the aspect ratio with an even width, as libx264 requires
+		outWidth := (width*outHeight/height + 1) &^ 1
+
+		dir := filepath.Join(outputDir, profile.Name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create output directory: %w", err)
+		}
+		playlistPath := filepath.Join(dir, "index.m3u8")
+
+		args := []string{
+			"-hide_banner", "-loglevel", "error", "-y",
+			"-i", sourcePath,
+			"-vf", fmt.Sprintf("scale=%d:%d", outWidth, outHeight),
+			"-c:v", "libx264", "-preset", "veryfast", "-profile:v", "main",
+			"-b:v", fmt.Sprintf("%dk", profile.VideoBitrate),
+			"-maxrate", fmt.Sprintf("%dk", profile.VideoBitrate),
+			"-bufsize", fmt.Sprintf("%dk", profile.VideoBitrate*2),
+			// Keyframe at every segment boundary so segments start cleanly
+			"-force_key_frames", fmt.Sprintf("expr:gte(t,n_forced*%d)", t.segmentSeconds),
+			"-c:a", "aac", "-b:a", fmt.Sprintf("%dk", profile.AudioBitrate), "-ac", "2",
+			"-f", "hls",
+			"-hls_time", strconv.Itoa(t.segmentSeconds),
+			"-hls_playlist_type", "vod",
+			"-hls_segment_filename", filepath.Join(dir, "segment_%04d.ts"),
+			playlistPath,
+		}
+
+		if err := t.run(ctx, t.ffmpegPath, args...); err != nil {
+			return nil, fmt.Errorf("failed to transcode %s: %w", profile.Name, err)
+		}
+
+		outputs = append(outputs, Output{
+			Profile:      profile,
+			Width:        outWidth,
+			Height:       outHeight,
+			PlaylistPath: playlistPath,
+		})
+	}
+
+	return outputs, nil
+}
+
+// probeResolution reads the width and height of the first video stream
+func (t *FFmpegTranscoder) probeResolution(ctx context.Context, sourcePath string) (int, int, error) {
+	meta, err := media.NewFFProbe(t.ffprobePath).Probe(ctx, sourcePath)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to probe source: %w", err)
+	}
+	return meta.Width, meta.Height, nil
+}
+
+// run executes a command and includes its stderr in the error
+func (t *FFmpegTranscoder) run(ctx context.Context, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Package transcode converts uploaded videos into HLS renditions.
+//
+// A Transcoder writes one media playlist per profile into an output
+// directory; the helpers in this package read those playlists back and build
+// the master playlist that players load.
+package transcode
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Profile describes one output rendition
+type Profile struct {
+	Name         string // also the output subdirectory, e.g. "720p"
+	Height       int
+	VideoBitrate int // kbps
+	AudioBitrate int // kbps
+}
+
+// Bandwidth is the peak bits per second advertised in the master playlist
+func (p Profile) Bandwidth() int64 {
+	return int64(p.VideoBitrate+p.AudioBitrate) * 1000
+}
+
+// DefaultProfiles is the rendition ladder produced for every upload, highest first
+var DefaultProfiles = []Profile{
+	{Name: "1080p", Height: 1080, VideoBitrate: 5000, AudioBitrate: 192},
+	{Name: "720p", Height: 720, VideoBitrate: 2800, AudioBitrate: 128},
+	{Name: "480p", Height: 480, VideoBitrate: 1400, AudioBitrate: 128},
+	{Name: "360p", Height: 360, VideoBitrate: 800, AudioBitrate: 96},
+}
+
+// Output is one rendition written by a Transcoder
+type Output struct {
+	Profile      Profile
+	Width        int
+	Height       int
+	PlaylistPath string // media playlist; segments are referenced relative to it
+}
+
+// Transcoder converts the source file into HLS renditions under outputDir
+// Profiles above the source resolution may be skipped, but at least one
+// rendition must be produced
+type Transcoder interface {
+	Transcode(ctx context.Context, sourcePath, outputDir string, profiles []Profile) ([]Output, error)
+}
+
+// Variant is one entry of a master playlist
+type Variant struct {
+	URL       string
+	Bandwidth int64
+	Width     int
+	Height    int
+}
+
+// MasterPlaylist builds an HLS master playlist listing the variants
+func MasterPlaylist(variants []Variant) []byte {
+	var b bytes.Buffer
+	b.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	for _, v := range variants {
+		fmt.Fprintf(&b, "#EXT-X-STREAM-INF:BANDWIDTH=%d", v.Bandwidth)
+		if v.Width > 0 && v.Height > 0 {
+			fmt.Fprintf(&b, ",RESOLUTION=%dx%d", v.Width, v.Height)
+		}
+		fmt.Fprintf(&b, "\n%s\n", v.URL)
+	}
+	return b.Bytes()
+}
+
+// SegmentURIs returns the segment references of a media playlist in order
+func SegmentURIs(playlist []byte) []string {
+	var uris []string
+	scanner := bufio.NewScanner(bytes.NewReader(playlist))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			uris = append(uris, line)
+		}
+	}
+	return uris
+}
+
+// RewriteSegmentURIs replaces segment references using urls, keyed by the original reference
+// Every segment must have a replacement
+func RewriteSegmentURIs(playlist []byte, urls map[string]string) ([]byte, error) {
+	var b bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(playlist))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			url, ok := urls[line]
+			if !ok {
+				return nil, fmt.Errorf("no URL for segment %s", line)
+			}
+			line = url
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
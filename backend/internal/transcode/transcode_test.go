@@ -0,0 +1,58 @@
+package transcode
+
+import (
+	"reflect"
+	"testing"
+)
+
+const mediaPlaylist = `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:6
+#EXTINF:6.000000,
+segment_0000.ts
+#EXTINF:3.500000,
+segment_0001.ts
+#EXT-X-ENDLIST
+`
+
+func TestSegmentURIs(t *testing.T) {
+	got := SegmentURIs([]byte(mediaPlaylist))
+	want := []string{"segment_0000.ts", "segment_0001.ts"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestRewriteSegmentURIs(t *testing.T) {
+	rewritten, err := RewriteSegmentURIs([]byte(mediaPlaylist), map[string]string{
+		"segment_0000.ts": "https://cdn.example.com/a.ts",
+		"segment_0001.ts": "https://cdn.example.com/b.ts",
+	})
+	if err != nil {
+		t.Fatalf("RewriteSegmentURIs returned error: %v", err)
+	}
+
+	got := SegmentURIs(rewritten)
+	want := []string{"https://cdn.example.com/a.ts", "https://cdn.example.com/b.ts"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if _, err := RewriteSegmentURIs([]byte(mediaPlaylist), map[string]string{}); err == nil {
+		t.Error("expected an error for a segment without URL")
+	}
+}
+
+func TestMasterPlaylist(t *testing.T) {
+	got := string(MasterPlaylist([]Variant{
+		{URL: "https://cdn.example.com/720.m3u8", Bandwidth: 2928000, Width: 1280, Height: 720},
+		{URL: "https://cdn.example.com/audio.m3u8", Bandwidth: 128000},
+	}))
+
+	want := "#EXTM3U\n#EXT-X-VERSION:3\n" +
+		"#EXT-X-STREAM-INF:BANDWIDTH=2928000,RESOLUTION=1280x720\nhttps://cdn.example.com/720.m3u8\n" +
+		"#EXT-X-STREAM-INF:BANDWIDTH=128000\nhttps://cdn.example.com/audio.m3u8\n"
+	if got != want {
+		t.Errorf("unexpected master playlist:\n%s", got)
+	}
+}
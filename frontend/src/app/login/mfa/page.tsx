@@ -0,0 +1,93 @@
+'use client';
+
+import { useState } from 'react';
+import { useRouter } from 'next/navigation';
+import Link from 'next/link';
+import { api } from '@/lib/api';
+import { useAuth } from '@/contexts/AuthContext';
+
+export default function MFALoginPage() {
+  const [code, setCode] = useState('');
+  const [error, setError] = useState('');
+  const [loading, setLoading] = useState(false);
+  const { completeLogin } = useAuth();
+  const router = useRouter();
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError('');
+
+    const mfaToken = sessionStorage.getItem('mfa_token');
+    if (!mfaToken) {
+      setError('ログインの有効期限が切れました。もう一度ログインしてください。');
+      return;
+    }
+
+    setLoading(true);
+    try {
+      await completeLogin(await api.verifyMFA(mfaToken, code));
+      sessionStorage.removeItem('mfa_token');
+      router.push('/');
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'コードが正しくありません');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-gray-50 py-12 px-4 sm:px-6 lg:px-8">
+      <div className="max-w-md w-full space-y-8">
+        <div>
+          <h2 className="mt-6 text-center text-3xl font-extrabold text-gray-900">
+            2段階認証
+          </h2>
+          <p className="mt-2 text-center text-sm text-gray-600">
+            認証アプリに表示されている6桁のコード、またはリカバリーコードを入力してください
+          </p>
+        </div>
+        <form className="mt-8 space-y-6" onSubmit={handleSubmit}>
+          {error && (
+            <div className="bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded">
+              {error}
+            </div>
+          )}
+          <div>
+            <label htmlFor="code" className="sr-only">
+              認証コード
+            </label>
+            <input
+              id="code"
+              name="code"
+              type="text"
+              inputMode="text"
+              autoComplete="one-time-code"
+              required
+              autoFocus
+              className="appearance-none rounded-md relative block w-full px-3 py-2 border border-gray-300 placeholder-gray-500 text-gray-900 focus:outline-none focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm"
+              placeholder="123456"
+              value={code}
+              onChange={(e) => setCode(e.target.value)}
+            />
+          </div>
+
+          <div>
+            <button
+              type="submit"
+              disabled={loading}
+              className="group relative w-full flex justify-center py-2 px-4 border border-transparent text-sm font-medium rounded-md text-white bg-indigo-600 hover:bg-indigo-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-indigo-500 disabled:bg-indigo-300"
+            >
+              {loading ? '確認中...' : '確認'}
+            </button>
+          </div>
+
+          <div className="text-center">
+            <Link href="/login" className="text-indigo-600 hover:text-indigo-500">
+              ログインに戻る
+            </Link>
+          </div>
+        </form>
+      </div>
+    </div>
+  );
+}
@@ -1,26 +1,46 @@
 'use client';
 
-import { useState } from 'react';
+import { useEffect, useState } from 'react';
 import { useRouter } from 'next/navigation';
 import Link from 'next/link';
 import { useAuth } from '@/contexts/AuthContext';
+import { api } from '@/lib/api';
 
 export default function LoginPage() {
   const [email, setEmail] = useState('');
   const [password, setPassword] = useState('');
   const [error, setError] = useState('');
   const [loading, setLoading] = useState(false);
+  const [providers, setProviders] = useState<string[]>([]);
   const { login } = useAuth();
   const router = useRouter();
 
+  useEffect(() => {
+    api
+      .getOIDCProviders()
+      .then((data) => setProviders(data.providers))
+      .catch(() => setProviders([]));
+  }, []);
+
+  // Sign in at a provider; the callback page checks the state when the provider redirects back
+  const handleProviderLogin = async (provider: string) => {
+    setError('');
+    try {
+      const authorization = await api.authorizeOIDC(provider);
+      sessionStorage.setItem('oidc_state', authorization.state);
+      window.location.href = authorization.authorization_url;
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'ログインに失敗しました');
+    }
+  };
+
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault();
     setError('');
     setLoading(true);
 
     try {
-      await login({ email, password });
-      router.push('/');
+      router.push((await login({ email, password })) ? '/' : '/login/mfa');
     } catch (err) {
       setError(err instanceof Error ? err.message : 'ログインに失敗しました');
     } finally {
@@ -85,10 +105,32 @@ export default function LoginPage() {
             </button>
           </div>
 
-          <div className="text-center">
-            <Link href="/register" className="text-indigo-600 hover:text-indigo-500">
-              アカウントをお持ちでない方はこちら
-            </Link>
+          {providers.length > 0 && (
+            <div className="space-y-2">
+              {providers.map((provider) => (
+                <button
+                  key={provider}
+                  type="button"
+                  onClick={() => handleProviderLogin(provider)}
+                  className="w-full flex justify-center py-2 px-4 border border-gray-300 text-sm font-medium rounded-md text-gray-700 bg-white hover:bg-gray-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-indigo-500"
+                >
+                  {provider.charAt(0).toUpperCase() + provider.slice(1)} でログイン
+                </button>
+              ))}
+            </div>
+          )}
+
+          <div className="text-center space-y-2">
+            <div>
+              <Link href="/forgot-password" className="text-indigo-600 hover:text-indigo-500">
+                パスワードをお忘れの方はこちら
+              </Link>
+            </div>
+            <div>
+              <Link href="/register" className="text-indigo-600 hover:text-indigo-500">
+                アカウントをお持ちでない方はこちら
+              </Link>
+            </div>
           </div>
         </form>
       </div>
@@ -0,0 +1,76 @@
+'use client';
+
+import { Suspense, useEffect, useRef, useState } from 'react';
+import { useRouter, useSearchParams } from 'next/navigation';
+import Link from 'next/link';
+import { api } from '@/lib/api';
+import { useAuth } from '@/contexts/AuthContext';
+
+function OAuthCallback() {
+  const params = useSearchParams();
+  const router = useRouter();
+  const { completeLogin } = useAuth();
+  const [error, setError] = useState('');
+  const requested = useRef(false);
+
+  useEffect(() => {
+    // The code works once, so do not send it twice (e.g. in strict mode)
+    if (requested.current) return;
+    requested.current = true;
+
+    const code = params.get('code');
+    const state = params.get('state');
+    const expectedState = sessionStorage.getItem('oidc_state');
+    const linking = sessionStorage.getItem('oidc_intent') === 'link';
+    sessionStorage.removeItem('oidc_state');
+    sessionStorage.removeItem('oidc_intent');
+
+    if (params.get('error')) {
+      setError('プロバイダーでのログインがキャンセルされました');
+      return;
+    }
+    // Only finish sign-ins this browser started
+    if (!code || !state || state !== expectedState) {
+      setError('ログインの状態が一致しません。もう一度お試しください。');
+      return;
+    }
+
+    if (linking) {
+      api
+        .completeOIDCLink(code, state)
+        .then(() => router.push('/'))
+        .catch((err) => setError(err instanceof Error ? err.message : '連携に失敗しました'));
+      return;
+    }
+
+    api
+      .completeOIDC(code, state)
+      .then((response) => completeLogin(response))
+      .then((completed) => router.push(completed ? '/' : '/login/mfa'))
+      .catch((err) => setError(err instanceof Error ? err.message : 'ログインに失敗しました'));
+  }, [params, router, completeLogin]);
+
+  if (!error) {
+    return <p className="text-center text-gray-600">ログイン中...</p>;
+  }
+  return (
+    <div className="space-y-4 text-center">
+      <div className="bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded">{error}</div>
+      <Link href="/login" className="text-indigo-600 hover:text-indigo-500">
+        ログインに戻る
+      </Link>
+    </div>
+  );
+}
+
+export default function OAuthCallbackPage() {
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-gray-50 py-12 px-4 sm:px-6 lg:px-8">
+      <div className="max-w-md w-full space-y-8">
+        <Suspense fallback={null}>
+          <OAuthCallback />
+        </Suspense>
+      </div>
+    </div>
+  );
+}
@@ -0,0 +1,100 @@
+'use client';
+
+import { Suspense, useState } from 'react';
+import { useSearchParams } from 'next/navigation';
+import Link from 'next/link';
+import { api } from '@/lib/api';
+
+function ResetPasswordForm() {
+  const token = useSearchParams().get('token') ?? '';
+  const [password, setPassword] = useState('');
+  const [error, setError] = useState('');
+  const [done, setDone] = useState(false);
+  const [loading, setLoading] = useState(false);
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError('');
+    setLoading(true);
+
+    try {
+      await api.resetPassword(token, password);
+      setDone(true);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'パスワードの再設定に失敗しました');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  if (done) {
+    return (
+      <div className="space-y-4 text-center">
+        <div className="bg-green-100 border border-green-400 text-green-700 px-4 py-3 rounded">
+          パスワードを再設定しました。新しいパスワードでログインしてください。
+        </div>
+        <Link href="/login" className="text-indigo-600 hover:text-indigo-500">
+          ログイン
+        </Link>
+      </div>
+    );
+  }
+
+  return (
+    <form className="mt-8 space-y-6" onSubmit={handleSubmit}>
+      {!token && (
+        <div className="bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded">
+          リンクが正しくありません。メールのリンクを開き直してください。
+        </div>
+      )}
+      {error && (
+        <div className="bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded">
+          {error}
+        </div>
+      )}
+      <div>
+        <label htmlFor="password" className="sr-only">
+          新しいパスワード
+        </label>
+        <input
+          id="password"
+          name="password"
+          type="password"
+          required
+          minLength={8}
+          className="appearance-none rounded-md relative block w-full px-3 py-2 border border-gray-300 placeholder-gray-500 text-gray-900 focus:outline-none focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm"
+          placeholder="新しいパスワード（8文字以上）"
+          value={password}
+          onChange={(e) => setPassword(e.target.value)}
+        />
+      </div>
+
+      <div>
+        <button
+          type="submit"
+          disabled={loading || !token}
+          className="group relative w-full flex justify-center py-2 px-4 border border-transparent text-sm font-medium rounded-md text-white bg-indigo-600 hover:bg-indigo-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-indigo-500 disabled:bg-indigo-300"
+        >
+          {loading ? '設定中...' : 'パスワードを設定'}
+        </button>
+      </div>
+    </form>
+  );
+}
+
+export default function ResetPasswordPage() {
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-gray-50 py-12 px-4 sm:px-6 lg:px-8">
+      <div className="max-w-md w-full space-y-8">
+        <div>
+          <h2 className="mt-6 text-center text-3xl font-extrabold text-gray-900">
+            新しいパスワードの設定
+          </h2>
+        </div>
+        <Suspense fallback={null}>
+          <ResetPasswordForm />
+        </Suspense>
+      </div>
+    </div>
+  );
+}
@@ -0,0 +1,70 @@
+'use client';
+
+import { Suspense, useEffect, useRef, useState } from 'react';
+import { useSearchParams } from 'next/navigation';
+import Link from 'next/link';
+import { api } from '@/lib/api';
+
+function VerifyEmailStatus() {
+  const token = useSearchParams().get('token') ?? '';
+  const [status, setStatus] = useState<'verifying' | 'verified' | 'failed'>('verifying');
+  const [error, setError] = useState('');
+  const requested = useRef(false);
+
+  useEffect(() => {
+    // The token works once, so do not send it twice (e.g. in strict mode)
+    if (requested.current) return;
+    requested.current = true;
+
+    if (!token) {
+      setStatus('failed');
+      setError('リンクが正しくありません。メールのリンクを開き直してください。');
+      return;
+    }
+    api
+      .verifyEmail(token)
+      .then(() => setStatus('verified'))
+      .catch((err) => {
+        setStatus('failed');
+        setError(err instanceof Error ? err.message : 'メールアドレスの確認に失敗しました');
+      });
+  }, [token]);
+
+  if (status === 'verifying') {
+    return <p className="text-center text-gray-600">確認中...</p>;
+  }
+  if (status === 'failed') {
+    return (
+      <div className="bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded">
+        {error}
+      </div>
+    );
+  }
+  return (
+    <div className="bg-green-100 border border-green-400 text-green-700 px-4 py-3 rounded">
+      メールアドレスを確認しました。
+    </div>
+  );
+}
+
+export default function VerifyEmailPage() {
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-gray-50 py-12 px-4 sm:px-6 lg:px-8">
+      <div className="max-w-md w-full space-y-8">
+        <div>
+          <h2 className="mt-6 text-center text-3xl font-extrabold text-gray-900">
+            メールアドレスの確認
+          </h2>
+        </div>
+        <Suspense fallback={null}>
+          <VerifyEmailStatus />
+        </Suspense>
+        <div className="text-center">
+          <Link href="/" className="text-indigo-600 hover:text-indigo-500">
+            トップへ戻る
+          </Link>
+        </div>
+      </div>
+    </div>
+  );
+}
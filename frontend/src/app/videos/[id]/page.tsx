@@ -725,6 +725,7 @@ export default function VideoDetailPage() {
             videoCreatorProfile={video.profile}
             currentUserId={user?.id}
             currentUserProfile={userProfile}
+            canModerate={user?.role === "moderator" || user?.role === "admin"}
           />
         </Box>
 
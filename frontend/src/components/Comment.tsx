@@ -29,6 +29,7 @@ import CommentInput from "./CommentInput";
 interface CommentProps {
   comment: CommentType;
   currentUserId?: number;
+  canModerate?: boolean; // Moderators can delete any comment
   videoCreatorId: number;
   videoCreatorProfile?: Profile;
   onReply?: (commentId: number, content: string) => Promise<void>;
@@ -78,6 +79,7 @@ function getRelativeTime(dateString: string): string {
 export default function Comment({
   comment,
   currentUserId,
+  canModerate = false,
   videoCreatorId,
   videoCreatorProfile,
   onReply,
@@ -107,6 +109,7 @@ export default function Comment({
 
   const isOwner = currentUserId === comment.user_id;
   const isVideoCreator = currentUserId === videoCreatorId;
+  const canDelete = isOwner || canModerate;
   const isCommentByCreator = comment.is_video_creator;
 
   const handleMenuOpen = (event: React.MouseEvent<HTMLElement>) => {
@@ -378,7 +381,7 @@ export default function Comment({
             </Button>
           )}
 
-          {(isOwner || isVideoCreator) && (
+          {(canDelete || isVideoCreator) && (
             <>
               <IconButton size="small" onClick={handleMenuOpen}>
                 <MoreVertIcon fontSize="small" />
@@ -400,7 +403,7 @@ export default function Comment({
                       : "ハートをつける"}
                   </MenuItem>
                 )}
-                {isOwner && <MenuItem onClick={handleDelete}>削除</MenuItem>}
+                {canDelete && <MenuItem onClick={handleDelete}>削除</MenuItem>}
               </Menu>
             </>
           )}
@@ -449,6 +452,7 @@ export default function Comment({
                 key={reply.id}
                 comment={reply}
                 currentUserId={currentUserId}
+                canModerate={canModerate}
                 videoCreatorId={videoCreatorId}
                 videoCreatorProfile={videoCreatorProfile}
                 onUpdate={onUpdate}
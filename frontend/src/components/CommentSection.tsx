@@ -13,6 +13,7 @@ interface CommentSectionProps {
   videoCreatorProfile?: Profile;
   currentUserId?: number;
   currentUserProfile?: Profile;
+  canModerate?: boolean;
 }
 
 export default function CommentSection({
@@ -21,6 +22,7 @@ export default function CommentSection({
   videoCreatorProfile,
   currentUserId,
   currentUserProfile,
+  canModerate = false,
 }: CommentSectionProps) {
   const [comments, setComments] = useState<CommentType[]>([]);
   const [commentCount, setCommentCount] = useState(0);
@@ -255,6 +257,7 @@ export default function CommentSection({
           key={comment.id}
           comment={comment}
           currentUserId={currentUserId}
+          canModerate={canModerate}
           videoCreatorId={videoCreatorId}
           videoCreatorProfile={videoCreatorProfile}
           onReply={handleCreateReply}
@@ -1,15 +1,16 @@
 'use client';
 
 import React, { createContext, useContext, useState, useEffect, ReactNode } from 'react';
-import { User, Profile, LoginRequest, RegisterRequest } from '@/types';
+import { User, Profile, LoginRequest, RegisterRequest, AuthResponse } from '@/types';
 import { api, setToken, removeToken } from '@/lib/api';
 
 interface AuthContextType {
   user: User | null;
   profile: Profile | null;
   loading: boolean;
-  login: (data: LoginRequest) => Promise<void>;
-  register: (data: RegisterRequest) => Promise<void>;
+  login: (data: LoginRequest) => Promise<boolean>;
+  register: (data: RegisterRequest) => Promise<boolean>;
+  completeLogin: (response: AuthResponse) => Promise<boolean>;
   logout: () => void;
   isAuthenticated: boolean;
 }
@@ -43,15 +44,20 @@ export function AuthProvider({ children }: { children: ReactNode }) {
     }
   }, []);
 
-  const login = async (data: LoginRequest) => {
-    const response = await api.login(data);
-    setToken(response.token);
+  // Store a new session's tokens and user, then fetch the profile
+  // Returns false when the login still needs a second factor; the MFA token is kept for /login/mfa
+  const completeLogin = async (response: AuthResponse) => {
+    if (response.mfa_required && response.mfa_token) {
+      sessionStorage.setItem('mfa_token', response.mfa_token);
+      return false;
+    }
+
+    setToken(response.token, response.refresh_token);
     setUser(response.user);
     if (typeof window !== 'undefined') {
       localStorage.setItem('user', JSON.stringify(response.user));
     }
 
-    // Fetch profile after login
     try {
       const profileData = await api.getMyProfile();
       setProfile(profileData);
@@ -61,26 +67,15 @@ export function AuthProvider({ children }: { children: ReactNode }) {
     } catch (err) {
       console.error('Failed to fetch profile:', err);
     }
+    return true;
   };
 
-  const register = async (data: RegisterRequest) => {
-    const response = await api.register(data);
-    setToken(response.token);
-    setUser(response.user);
-    if (typeof window !== 'undefined') {
-      localStorage.setItem('user', JSON.stringify(response.user));
-    }
+  const login = async (data: LoginRequest) => {
+    return completeLogin(await api.login(data));
+  };
 
-    // Fetch profile after registration
-    try {
-      const profileData = await api.getMyProfile();
-      setProfile(profileData);
-      if (typeof window !== 'undefined') {
-        localStorage.setItem('profile', JSON.stringify(profileData));
-      }
-    } catch (err) {
-      console.error('Failed to fetch profile:', err);
-    }
+  const register = async (data: RegisterRequest) => {
+    return completeLogin(await api.register(data));
   };
 
   const logout = () => {
@@ -97,7 +92,7 @@ export function AuthProvider({ children }: { children: ReactNode }) {
   const isAuthenticated = !!user;
 
   return (
-    <AuthContext.Provider value={{ user, profile, loading, login, register, logout, isAuthenticated }}>
+    <AuthContext.Provider value={{ user, profile, loading, login, register, completeLogin, logout, isAuthenticated }}>
       {children}
     </AuthContext.Provider>
   );
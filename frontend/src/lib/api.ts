@@ -2,6 +2,12 @@ import {
   AuthResponse,
   LoginRequest,
   RegisterRequest,
+  ChangePasswordRequest,
+  Session,
+  OIDCAuthorization,
+  UserIdentity,
+  TwoFactorStatus,
+  TOTPSetup,
   Video,
   Profile,
   Playlist,
@@ -27,25 +33,72 @@ const getToken = (): string | null => {
   return null;
 };
 
-// Set token to localStorage
-export const setToken = (token: string) => {
+// Get refresh token from localStorage
+const getRefreshToken = (): string | null => {
+  if (typeof window !== "undefined") {
+    return localStorage.getItem("refresh_token");
+  }
+  return null;
+};
+
+// Set token (and refresh token) to localStorage
+export const setToken = (token: string, refreshToken?: string) => {
   if (typeof window !== "undefined") {
     localStorage.setItem("token", token);
+    if (refreshToken) {
+      localStorage.setItem("refresh_token", refreshToken);
+    }
   }
 };
 
-// Remove token from localStorage
+// Remove tokens from localStorage
 export const removeToken = () => {
   if (typeof window !== "undefined") {
     localStorage.removeItem("token");
+    localStorage.removeItem("refresh_token");
   }
 };
 
+// Refresh requests in flight share one refresh, since each refresh token works only once
+let refreshing: Promise<boolean> | null = null;
+
+// Exchange the refresh token for new tokens; returns false if the session has ended
+const refreshTokens = (): Promise<boolean> => {
+  if (!refreshing) {
+    refreshing = (async () => {
+      const refreshToken = getRefreshToken();
+      if (!refreshToken) {
+        return false;
+      }
+      try {
+        const response = await fetch(`${API_URL}/api/auth/refresh`, {
+          method: "POST",
+          headers: { "Content-Type": "application/json" },
+          body: JSON.stringify({ refresh_token: refreshToken }),
+        });
+        if (!response.ok) {
+          removeToken();
+          return false;
+        }
+        const data: AuthResponse = await response.json();
+        setToken(data.token, data.refresh_token);
+        return true;
+      } catch {
+        return false;
+      }
+    })().finally(() => {
+      refreshing = null;
+    });
+  }
+  return refreshing;
+};
+
 // API client
 class ApiClient {
   private async request<T>(
     endpoint: string,
-    options: RequestInit = {}
+    options: RequestInit = {},
+    retry: boolean = true
   ): Promise<T> {
     const token = getToken();
     const headers: Record<string, string> = {
@@ -67,6 +120,11 @@ class ApiClient {
       headers,
     });
 
+    // Access tokens are short-lived; refresh once and retry
+    if (response.status === 401 && token && retry && (await refreshTokens())) {
+      return this.request(endpoint, options, false);
+    }
+
     if (!response.ok) {
       const error = await response
         .json()
@@ -92,9 +150,143 @@ class ApiClient {
     });
   }
 
+  // Second step of a login that returned mfa_required; code is a TOTP or recovery code
+  async verifyMFA(mfaToken: string, code: string): Promise<AuthResponse> {
+    return this.request("/api/auth/login/mfa", {
+      method: "POST",
+      body: JSON.stringify({ mfa_token: mfaToken, code }),
+    });
+  }
+
   async logout(): Promise<void> {
-    return this.request("/api/auth/logout", {
+    return this.request(
+      "/api/auth/logout",
+      {
+        method: "POST",
+        body: JSON.stringify({ refresh_token: getRefreshToken() ?? "" }),
+      },
+      false
+    );
+  }
+
+  async getSessions(): Promise<{ sessions: Session[] }> {
+    return this.request("/api/auth/sessions");
+  }
+
+  async revokeSession(id: number): Promise<void> {
+    return this.request(`/api/auth/sessions/${id}`, {
+      method: "DELETE",
+    });
+  }
+
+  async revokeOtherSessions(): Promise<{ revoked: number }> {
+    return this.request("/api/auth/sessions", {
+      method: "DELETE",
+    });
+  }
+
+  // Changing the password signs out every session; the response is a new session
+  async changePassword(data: ChangePasswordRequest): Promise<AuthResponse> {
+    const response: AuthResponse = await this.request("/api/auth/password", {
+      method: "PUT",
+      body: JSON.stringify(data),
+    });
+    setToken(response.token, response.refresh_token);
+    return response;
+  }
+
+  async forgotPassword(email: string): Promise<void> {
+    return this.request("/api/auth/password/forgot", {
+      method: "POST",
+      body: JSON.stringify({ email }),
+    });
+  }
+
+  async resetPassword(token: string, newPassword: string): Promise<void> {
+    return this.request("/api/auth/password/reset", {
+      method: "POST",
+      body: JSON.stringify({ token, new_password: newPassword }),
+    });
+  }
+
+  async verifyEmail(token: string): Promise<void> {
+    return this.request("/api/auth/email/verify", {
+      method: "POST",
+      body: JSON.stringify({ token }),
+    });
+  }
+
+  async resendEmailVerification(): Promise<void> {
+    return this.request("/api/auth/email/verification", {
+      method: "POST",
+    });
+  }
+
+  async getOIDCProviders(): Promise<{ providers: string[] }> {
+    return this.request("/api/auth/oidc/providers");
+  }
+
+  async authorizeOIDC(provider: string): Promise<OIDCAuthorization> {
+    return this.request(`/api/auth/oidc/${encodeURIComponent(provider)}/authorize`, {
+      method: "POST",
+    });
+  }
+
+  // Finishes signing in at a provider
+  async completeOIDC(code: string, state: string): Promise<AuthResponse> {
+    return this.request("/api/auth/oidc/callback", {
+      method: "POST",
+      body: JSON.stringify({ code, state }),
+    });
+  }
+
+  async getIdentities(): Promise<{ identities: UserIdentity[] }> {
+    return this.request("/api/auth/identities");
+  }
+
+  async authorizeOIDCLink(provider: string): Promise<OIDCAuthorization> {
+    return this.request(`/api/auth/identities/${encodeURIComponent(provider)}/authorize`, {
+      method: "POST",
+    });
+  }
+
+  // Finishes linking a provider account to the signed-in user
+  async completeOIDCLink(code: string, state: string): Promise<UserIdentity> {
+    return this.request("/api/auth/identities", {
+      method: "POST",
+      body: JSON.stringify({ code, state }),
+    });
+  }
+
+  async getTwoFactorStatus(): Promise<TwoFactorStatus> {
+    return this.request("/api/auth/2fa");
+  }
+
+  // Starts TOTP enrollment; show provisioning_uri as a QR code, then confirm with a code
+  async setupTOTP(): Promise<TOTPSetup> {
+    return this.request("/api/auth/2fa/totp", {
+      method: "POST",
+    });
+  }
+
+  async confirmTOTP(code: string): Promise<{ recovery_codes: string[] }> {
+    return this.request("/api/auth/2fa/totp/confirm", {
+      method: "POST",
+      body: JSON.stringify({ code }),
+    });
+  }
+
+  async disableTwoFactor(password: string, code: string): Promise<void> {
+    return this.request("/api/auth/2fa/disable", {
+      method: "POST",
+      body: JSON.stringify({ password, code }),
+    });
+  }
+
+  async regenerateRecoveryCodes(code: string): Promise<{ recovery_codes: string[] }> {
+    return this.request("/api/auth/2fa/recovery-codes", {
       method: "POST",
+      body: JSON.stringify({ code }),
     });
   }
 
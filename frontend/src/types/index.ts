@@ -1,6 +1,14 @@
+export type UserRole = 'user' | 'moderator' | 'admin';
+
+export type AccountStatus = 'active' | 'suspended' | 'banned';
+
 export interface User {
   id: number;
   email: string;
+  role: UserRole;
+  status: AccountStatus;
+  suspended_until?: string;
+  email_verified_at: string | null;
   created_at: string;
   updated_at: string;
 }
@@ -31,9 +39,26 @@ export interface Video {
   profile?: Profile;
 }
 
+// With two-factor authentication, login returns only mfa_required and mfa_token
+// (valid for expires_in seconds), which is exchanged for a session at /api/auth/login/mfa
 export interface AuthResponse {
   token: string;
+  refresh_token: string;
+  expires_in: number;
   user: User;
+  mfa_required?: boolean;
+  mfa_token?: string;
+}
+
+export interface TwoFactorStatus {
+  enabled: boolean;
+  enabled_at?: string;
+  recovery_codes_remaining: number;
+}
+
+export interface TOTPSetup {
+  secret: string;
+  provisioning_uri: string;
 }
 
 export interface LoginRequest {
@@ -46,6 +71,36 @@ export interface RegisterRequest {
   password: string;
 }
 
+export interface OIDCAuthorization {
+  authorization_url: string;
+  state: string;
+}
+
+export interface UserIdentity {
+  id: number;
+  user_id: number;
+  provider: string;
+  email: string;
+  created_at: string;
+  last_login_at: string;
+}
+
+export interface ChangePasswordRequest {
+  current_password: string;
+  new_password: string;
+}
+
+export interface Session {
+  id: number;
+  user_id: number;
+  user_agent: string;
+  ip_address: string;
+  created_at: string;
+  last_seen_at: string;
+  expires_at: string;
+  current: boolean;
+}
+
 export interface Playlist {
   id: number;
   user_id: number;